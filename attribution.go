@@ -0,0 +1,181 @@
+package license
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// AttributionTemplate renders an attribution document from an *Attribution.
+// Both text/template and html/template templates implement it.
+type AttributionTemplate interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Attribution is a third-party attribution document, which groups the
+// components by the license they are distributed under.
+type Attribution struct {
+	Groups []*AttributionGroup `json:"groups" yaml:"groups"`
+}
+
+// AttributionGroup is a license, and the components distributed under it.
+type AttributionGroup struct {
+	Type       string                  `json:"type" yaml:"type"`                     // The license type
+	Name       string                  `json:"name,omitempty" yaml:"name,omitempty"` // The full name of the license, if known
+	Text       string                  `json:"text" yaml:"text"`                     // The full text of the license
+	Components []*AttributionComponent `json:"components" yaml:"components"`
+}
+
+// AttributionComponent is a component of an attribution document.
+type AttributionComponent struct {
+	Name       string   `json:"name" yaml:"name"`                                 // The directory or URL of the component
+	File       string   `json:"file,omitempty" yaml:"file,omitempty"`             // The license file of the component
+	Copyrights []string `json:"copyrights,omitempty" yaml:"copyrights,omitempty"` // The copyright statements of the component
+	Notice     string   `json:"notice,omitempty" yaml:"notice,omitempty"`         // The text of the NOTICE file of the component, if any
+}
+
+// TextAttributionTemplate is the plain text template used by
+// GenerateAttribution.
+var TextAttributionTemplate AttributionTemplate = texttemplate.Must(texttemplate.New("attribution").Parse(
+	`THIRD-PARTY SOFTWARE NOTICES AND INFORMATION
+{{range .Groups}}
+================================================================================
+{{.Type}}{{with .Name}}: {{.}}{{end}}
+================================================================================
+{{range .Components}}
+{{.Name}}
+{{- range .Copyrights}}
+    {{.}}
+{{- end}}
+{{- with .Notice}}
+
+{{.}}
+{{- end}}
+{{end}}
+--------------------------------------------------------------------------------
+
+{{.Text}}
+{{end}}`))
+
+// HTMLAttributionTemplate is an HTML template for GenerateAttributionWithTemplate.
+var HTMLAttributionTemplate AttributionTemplate = htmltemplate.Must(htmltemplate.New("attribution").Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Third-Party Software Notices and Information</title>
+</head>
+<body>
+<h1>Third-Party Software Notices and Information</h1>
+{{range .Groups}}
+<section>
+<h2>{{.Type}}{{with .Name}}: {{.}}{{end}}</h2>
+<ul>
+{{- range .Components}}
+<li><strong>{{.Name}}</strong>
+{{- range .Copyrights}}<br>{{.}}{{end}}
+{{- with .Notice}}<pre>{{.}}</pre>{{end}}</li>
+{{- end}}
+</ul>
+<pre>{{.Text}}</pre>
+</section>
+{{end}}
+</body>
+</html>
+`))
+
+// GenerateAttribution produces a plain text third-party attribution document
+// for licenses, grouped by license type, with the copyright statements of each
+// component, the text of its NOTICE file, and the full license text, as
+// described by NewAttribution.
+func GenerateAttribution(results []*License) ([]byte, error) {
+	return GenerateAttributionWithTemplate(results, TextAttributionTemplate)
+}
+
+// GenerateAttributionWithTemplate produces an attribution document the way
+// GenerateAttribution does, rendered by the given template instead, such as
+// HTMLAttributionTemplate.
+func GenerateAttributionWithTemplate(results []*License, tmpl AttributionTemplate) ([]byte, error) {
+	a, err := NewAttribution(results)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, a); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewAttribution groups licenses by type, ordered by type with unrecognized
+// licenses last. Each license is a component named after the directory of its
+// file, or its URL. The NOTICE file next to the license file is included, if
+// the license file is on disk and there is one. The text of a group is the
+// text of the first license of its type.
+func NewAttribution(results []*License) (*Attribution, error) {
+	groups := make(map[string]*AttributionGroup)
+	a := &Attribution{}
+	for _, l := range results {
+		if l == nil {
+			continue
+		}
+		licenseType := l.Type
+		if licenseType == "" {
+			licenseType = LicenseUnrecognized
+		}
+
+		c := &AttributionComponent{Name: l.URL, File: l.File}
+		if l.File != "" {
+			dir := filepath.Dir(l.File)
+			if c.Name == "" {
+				c.Name = dir
+			}
+		}
+		if l.File != "" && l.URL == "" {
+			if _, err := os.Stat(l.File); err == nil {
+				n, err := NewNoticeFromDir(filepath.Dir(l.File))
+				switch {
+				case err == nil:
+					c.Notice = strings.TrimSpace(n.Text)
+				case err != ErrNoNoticeFile:
+					return nil, err
+				}
+			}
+		}
+		for _, copyright := range ExtractCopyrights(l.Text) {
+			if !containsType(c.Copyrights, copyright.Statement) {
+				c.Copyrights = append(c.Copyrights, copyright.Statement)
+			}
+		}
+
+		g, ok := groups[licenseType]
+		if !ok {
+			g = &AttributionGroup{Type: licenseType, Text: strings.TrimSpace(l.Text)}
+			if licenseType != LicenseUnrecognized {
+				g.Name = Info(licenseType).Name
+			}
+			groups[licenseType] = g
+			a.Groups = append(a.Groups, g)
+		}
+		g.Components = append(g.Components, c)
+	}
+
+	sort.SliceStable(a.Groups, func(i, j int) bool {
+		gi, gj := a.Groups[i], a.Groups[j]
+		if (gi.Type == LicenseUnrecognized) != (gj.Type == LicenseUnrecognized) {
+			return gj.Type == LicenseUnrecognized
+		}
+		return gi.Type < gj.Type
+	})
+	for _, g := range a.Groups {
+		sort.SliceStable(g.Components, func(i, j int) bool {
+			return g.Components[i].Name < g.Components[j].Name
+		})
+	}
+	return a, nil
+}
@@ -0,0 +1,84 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGenerateAttribution(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	lang := filepath.Join(d, "lang")
+	if err := os.Mkdir(lang, 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for name, text := range map[string]string{"NOTICE": apacheNotice, "LICENSE": "Apache License"} {
+		if err := ioutil.WriteFile(filepath.Join(lang, name), []byte(text), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	results := []*license.License{
+		{Type: license.LicenseMIT, File: filepath.Join(d, "b", "LICENSE"), Text: "Copyright (c) 2020 Bea\n\nPermission is hereby granted..."},
+		{Type: license.LicenseApache20, File: filepath.Join(lang, "LICENSE"), Text: "Apache License\nVersion 2.0, January 2004"},
+		{Type: license.LicenseUnrecognized, URL: "https://example.com/repo", Text: "Some license"},
+		{Type: license.LicenseMIT, File: filepath.Join(d, "a", "LICENSE"), Text: "Copyright (c) 2019 Al\n\nPermission is hereby granted..."},
+	}
+
+	a, err := license.NewAttribution(results)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var types []string
+	for _, g := range a.Groups {
+		types = append(types, g.Type)
+	}
+	if strings.Join(types, ",") != "Apache-2.0,MIT,Unrecognized" {
+		t.Fatalf("unexpected groups: %v", types)
+	}
+	mit := a.Groups[1]
+	if mit.Name != "MIT License" || len(mit.Components) != 2 || mit.Components[0].Name != filepath.Join(d, "a") {
+		t.Fatalf("unexpected group: %#v", mit)
+	}
+	if c := mit.Components[1]; len(c.Copyrights) != 1 || c.Copyrights[0] != "Copyright (c) 2020 Bea" {
+		t.Fatalf("unexpected copyrights: %q", c.Copyrights)
+	}
+	if !strings.HasPrefix(mit.Text, "Copyright (c) 2020 Bea") {
+		t.Fatalf("unexpected text: %s", mit.Text)
+	}
+	if c := a.Groups[0].Components[0]; !strings.Contains(c.Notice, "This product includes software developed at") {
+		t.Fatalf("missing notice: %#v", c)
+	}
+	if c := a.Groups[2].Components[0]; c.Name != "https://example.com/repo" {
+		t.Fatalf("unexpected component: %#v", c)
+	}
+
+	text, err := license.GenerateAttribution(results)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, s := range []string{"MIT: MIT License", filepath.Join(d, "a") + "\n    Copyright (c) 2019 Al", "Apache Commons Lang", "Some license"} {
+		if !strings.Contains(string(text), s) {
+			t.Fatalf("missing %q in:\n%s", s, text)
+		}
+	}
+
+	html, err := license.GenerateAttributionWithTemplate([]*license.License{
+		{Type: license.LicenseMIT, Text: "Copyright (c) 2020 <Bea>"},
+	}, license.HTMLAttributionTemplate)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(html), "<h2>MIT: MIT License</h2>") || !strings.Contains(string(html), "&lt;Bea&gt;") {
+		t.Fatalf("unexpected HTML:\n%s", html)
+	}
+}
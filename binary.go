@@ -0,0 +1,89 @@
+package license
+
+import (
+	"context"
+	"debug/buildinfo"
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// NewFromBinary reads the module information embedded in a compiled Go
+// executable, and resolves the licenses of the modules it was built from, as
+// done by NewFromBuildInfo.
+func NewFromBinary(ctx context.Context, path string, opts ...Option) (map[string][]*License, error) {
+	bi, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("license: %s: %w", path, err)
+	}
+	return NewFromBuildInfo(ctx, bi, opts...)
+}
+
+// NewFromBuildInfo resolves the licenses of the main module and dependencies
+// of a Go executable, as reported by debug/buildinfo or debug.ReadBuildInfo.
+// The result maps each module, as "path@version", to the licenses found for
+// it. Replaced modules are resolved by their replacement.
+//
+// Modules are searched for license files in the module cache, at $GOMODCACHE
+// or in the pkg/mod directory of $GOPATH, and are downloaded from the module
+// proxy as done by NewFromModule if they are not there. Modules without a
+// version, such as a main module built from a source checkout, are left out,
+// and modules without a license file map to no licenses.
+func NewFromBuildInfo(ctx context.Context, bi *debug.BuildInfo, opts ...Option) (map[string][]*License, error) {
+	o := newOptions(opts)
+	modules := append([]*debug.Module{&bi.Main}, bi.Deps...)
+
+	results := make(map[string][]*License, len(modules))
+	for _, m := range modules {
+		key := m.Path + "@" + m.Version
+		if m.Replace != nil {
+			m = m.Replace
+		}
+		if m.Path == "" || m.Version == "" || m.Version == "(devel)" {
+			continue
+		}
+
+		ls, err := moduleCacheLicenses(ctx, m.Path, m.Version, o)
+		if os.IsNotExist(err) {
+			ls, err = NewFromModule(ctx, m.Path, m.Version, opts...)
+		}
+		switch {
+		case err == nil:
+		case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, err == ErrModuleNotFound:
+			ls = nil
+		default:
+			return nil, fmt.Errorf("license: %s: %w", key, err)
+		}
+		results[key] = ls
+	}
+	return results, nil
+}
+
+// moduleCacheLicenses searches the directory of a module version in the
+// module cache for license files.
+func moduleCacheLicenses(ctx context.Context, path, version string, o *options) ([]*License, error) {
+	cache := os.Getenv("GOMODCACHE")
+	if cache == "" {
+		gopath := filepath.SplitList(build.Default.GOPATH)
+		if len(gopath) == 0 {
+			return nil, os.ErrNotExist
+		}
+		cache = filepath.Join(gopath[0], "pkg", "mod")
+	}
+	escaped, err := escapeModulePath(path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := escapeModulePath(version)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(cache, filepath.FromSlash(escaped)+"@"+escapedVersion)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	return guessFromDir(ctx, dir, o)
+}
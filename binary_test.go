@@ -0,0 +1,104 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromBuildInfo(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	text := func(ltype string) string {
+		data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return string(data)
+	}
+	for dir, ltype := range map[string]string{
+		"github.com/!example/lib@v1.0.0": license.LicenseMIT,
+		"example.com/fork@v2.0.0":        license.LicenseApache20,
+	} {
+		dir = filepath.Join(d, "mod", filepath.FromSlash(dir))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "LICENSE"), []byte(text(ltype)), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Modules missing from the module cache are downloaded
+	zipData := buildZip(t, map[string]string{
+		"example.com/remote@v0.1.0/LICENSE": license.LicenseBSD3Clause,
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/example.com/remote/@v/v0.1.0.zip" {
+			w.Write(zipData)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("GOPROXY", server.URL)
+	t.Setenv("GOMODCACHE", filepath.Join(d, "mod"))
+
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/Example/lib", Version: "v1.0.0"},
+			{Path: "example.com/orig", Version: "v1.0.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v2.0.0"}},
+			{Path: "example.com/remote", Version: "v0.1.0"},
+			{Path: "example.com/missing", Version: "v1.0.0"},
+		},
+	}
+	found, err := license.NewFromBuildInfo(context.Background(), bi, license.WithCacheDir(filepath.Join(d, "cache")))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"github.com/Example/lib@v1.0.0": license.LicenseMIT,
+		"example.com/orig@v1.0.0":       license.LicenseApache20,
+		"example.com/remote@v0.1.0":     license.LicenseBSD3Clause,
+		"example.com/missing@v1.0.0":    "",
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("unexpected modules: %v", found)
+	}
+	for key, ltype := range expected {
+		ls, ok := found[key]
+		switch {
+		case !ok:
+			t.Fatalf("missing module %s", key)
+		case ltype == "" && len(ls) != 0:
+			t.Fatalf("%s: unexpected licenses: %v", key, ls)
+		case ltype != "" && (len(ls) != 1 || ls[0].Type != ltype):
+			t.Fatalf("%s: unexpected licenses: %v", key, ls)
+		}
+	}
+}
+
+func TestNewFromBinary(t *testing.T) {
+	// The test binary is a Go executable
+	if _, err := license.NewFromBinary(context.Background(), os.Args[0]); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Fails properly on other files
+	if _, err := license.NewFromBinary(context.Background(), filepath.Join("fixtures", "licenses", "MIT")); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,160 @@
+package license
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Headers naming the component whose license follows, as found in bundles
+	componentRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`^(?i)(?:component|package|module|library|project|dependency|name)\s*:\s*(.+?)$`),
+		regexp.MustCompile(`^(?i)the following software may be included in this product:\s*(.+?)\.?(?:\s+(?:a copy of|this software contains)\b.*)?$`),
+		regexp.MustCompile(`^(?i)licen[cs]es?\s+(?:for|of)\s+(.+?):?$`),
+	}
+
+	// A bare package name, such as "lodash 4.17.21" or "@babel/core"
+	packageNameRegexp = regexp.MustCompile(`^[@\w][\w@./+-]*[\w](?:\s+v?\d[\w.+-]*)?$`)
+)
+
+// The maximum length of a line which names a component on its own.
+const maxComponentName = 100
+
+// BundleEntry is a license found in a bundle of third-party licenses.
+type BundleEntry struct {
+	Component string `json:"component,omitempty" yaml:"component,omitempty"` // The name of the component the license is for, if stated
+	Type      string `json:"type" yaml:"type"`                               // The license type, or LicenseUnrecognized
+	Text      string `json:"text" yaml:"text"`                               // The text of the license
+}
+
+// NewBundleFromFile reads a bundle of third-party licenses, and splits it as
+// SplitBundle does.
+func NewBundleFromFile(path string) ([]*BundleEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return SplitBundle(string(data)), nil
+}
+
+// SplitBundle will split a bundle of third-party licenses, such as a
+// THIRD_PARTY_LICENSES file, into its licenses, and guess the type of each.
+// Licenses are separated by separator lines, such as a row of dashes, by
+// headers naming a component, or by a paragraph which on its own is guessed
+// to be a different license, as done by GuessTypes.
+//
+// Each license is associated with the component named by the header which
+// precedes it, if any. Headers may be of the form "Component: name", "License
+// for name", "The following software may be included in this product: name",
+// or a bare package name such as "lodash 4.17.21". Text which is neither
+// recognized as a license nor preceded by a header, such as an introduction,
+// is left out, while unrecognized licenses of named components are included
+// with the type LicenseUnrecognized.
+func SplitBundle(text string) []*BundleEntry {
+	b := &bundleSplitter{}
+	for _, block := range separatorRegexp.Split(text, -1) {
+		paragraphs := paragraphRegexp.Split(block, -1)
+		for _, paragraph := range paragraphs {
+			if strings.TrimSpace(paragraph) == "" {
+				continue
+			}
+			if name, ok := componentName(paragraph); ok {
+				b.flush()
+				b.component = name
+				continue
+			}
+			if len(paragraphs) == 1 && b.text == "" && isComponentHeader(paragraph) {
+				// A line on its own between separators
+				b.component = strings.TrimSpace(paragraph)
+				continue
+			}
+
+			p := &License{Text: paragraph}
+			if p.GuessType() == nil {
+				if b.licenseType != "" && p.Type != b.licenseType {
+					b.flush()
+				}
+				if b.licenseType == "" {
+					b.licenseType = p.Type
+				}
+			}
+			b.text += paragraph + "\n\n"
+		}
+		b.flush()
+	}
+	return b.entries
+}
+
+// bundleSplitter holds the license being read by SplitBundle.
+type bundleSplitter struct {
+	entries     []*BundleEntry
+	component   string
+	licenseType string
+	text        string
+}
+
+// flush ends the license being read.
+func (b *bundleSplitter) flush() {
+	text := strings.TrimSpace(b.text)
+	component := b.component
+	b.text, b.licenseType = "", ""
+	if text == "" {
+		return
+	}
+	b.component = ""
+
+	l := &License{Text: text}
+	if l.GuessType() != nil {
+		if component == "" {
+			return
+		}
+		l.Type = LicenseUnrecognized
+	}
+	b.entries = append(b.entries, &BundleEntry{Component: component, Type: l.Type, Text: text})
+}
+
+// componentName returns the name of the component of a header paragraph.
+func componentName(paragraph string) (string, bool) {
+	line := strings.Join(strings.Fields(paragraph), " ")
+	if len(line) > 2*maxComponentName {
+		return "", false
+	}
+	for _, re := range componentRegexps {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return strings.TrimSpace(m[1]), true
+		}
+	}
+	if isPackageName(line) {
+		return line, true
+	}
+	return "", false
+}
+
+// isPackageName determines if a line is the bare name of a package, leaving
+// out single words such as "Preamble", which appear in license texts on their
+// own.
+func isPackageName(line string) bool {
+	if !packageNameRegexp.MatchString(line) || strings.Contains(line, "://") {
+		return false
+	}
+	return strings.ContainsAny(line, "@./_-0123456789") || strings.Contains(line, " ")
+}
+
+// isComponentHeader determines if a paragraph separated from the rest of the
+// text is a single line naming a component, rather than a license title or a
+// sentence.
+func isComponentHeader(paragraph string) bool {
+	line := strings.TrimSpace(paragraph)
+	if line == "" || strings.Contains(line, "\n") || len(line) > maxComponentName ||
+		strings.HasSuffix(line, ".") {
+		return false
+	}
+	lower := strings.ToLower(line)
+	for _, word := range []string{"license", "licence", "copyright", "(c)", "©", "notice"} {
+		if strings.Contains(lower, word) {
+			return false
+		}
+	}
+	return (&License{Text: line}).GuessType() != nil
+}
@@ -0,0 +1,95 @@
+package license_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestSplitBundle(t *testing.T) {
+	fixture := func(ltype string) string {
+		data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return string(data)
+	}
+
+	var bundle bytes.Buffer
+	bundle.WriteString("THIRD-PARTY SOFTWARE NOTICES AND INFORMATION\n\n" +
+		"This project incorporates the components listed below.\n\n")
+	// Every known license, each named by a bare package name
+	for i, ltype := range license.KnownLicenses {
+		bundle.WriteString("--------------------------------------------------------------------------------\n")
+		bundle.WriteString("component-" + string(rune('a'+i)) + " 1.0.0\n\n")
+		bundle.WriteString(fixture(ltype) + "\n")
+	}
+	// Headers of other forms, and licenses without separators
+	bundle.WriteString("================================================================================\n")
+	bundle.WriteString("Package: lodash\n\n" + fixture(license.LicenseMIT) + "\n\n")
+	bundle.WriteString("License for react:\n\n" + fixture(license.LicenseMIT) + "\n\n")
+	bundle.WriteString("The following software may be included in this product: left-pad. " +
+		"This software contains the following license and notice below:\n\n" + fixture(license.LicenseISC) + "\n")
+	bundle.WriteString("--------------------------------------------------------------------------------\n")
+	bundle.WriteString("Internal Tool\n")
+	bundle.WriteString("--------------------------------------------------------------------------------\n")
+	bundle.WriteString("All rights reserved. Do not redistribute.\n")
+
+	entries := license.SplitBundle(bundle.String())
+
+	type expected struct{ component, ltype string }
+	var want []expected
+	for i, ltype := range license.KnownLicenses {
+		want = append(want, expected{"component-" + string(rune('a'+i)) + " 1.0.0", ltype})
+	}
+	want = append(want,
+		expected{"lodash", license.LicenseMIT},
+		expected{"react", license.LicenseMIT},
+		expected{"left-pad", license.LicenseISC},
+		expected{"Internal Tool", license.LicenseProprietary},
+	)
+
+	if len(entries) != len(want) {
+		for _, e := range entries {
+			t.Logf("%s: %s", e.Component, e.Type)
+		}
+		t.Fatalf("unexpected entries: %d", len(entries))
+	}
+	for i, e := range entries {
+		if e.Component != want[i].component || e.Type != want[i].ltype {
+			t.Fatalf("\nexpected: %s (%s)\ngot: %s (%s)", want[i].component, want[i].ltype, e.Component, e.Type)
+		}
+	}
+}
+
+func TestNewBundleFromFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseBSD3Clause))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := f.WriteString("Component: github.com/pkg/errors\n\n" + string(data)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	entries, err := license.NewBundleFromFile(f.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(entries) != 1 || entries[0].Component != "github.com/pkg/errors" || entries[0].Type != license.LicenseBSD3Clause {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	if _, err := license.NewBundleFromFile("/tmp/go-license-nonexistent"); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
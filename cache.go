@@ -0,0 +1,184 @@
+package license
+
+import (
+	"container/list"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// GuessCache caches the guessed types of license texts by the key returned by
+// GuessCacheKey, so that copies of a license are only guessed once. Caches
+// must be safe for concurrent use, and should only be shared by guesses with
+// the same configuration, such as the licenses recognized by a Scanner.
+type GuessCache interface {
+	// Get returns the cached guess for a key, if any.
+	Get(key string) (*CachedGuess, bool)
+	// Put caches the guess for a key.
+	Put(key string, guess *CachedGuess)
+}
+
+// CachedGuess is the result of guessing the type of a license text.
+type CachedGuess struct {
+	Type      string `json:"type,omitempty" yaml:"type,omitempty"`           // The license type, or "" if the license is unrecognized
+	Language  string `json:"language,omitempty" yaml:"language,omitempty"`   // The language of the text, if it is a translation
+	Rider     string `json:"rider,omitempty" yaml:"rider,omitempty"`         // The rider appended to the text, if any
+	Reference bool   `json:"reference,omitempty" yaml:"reference,omitempty"` // Whether the text only links to the license
+}
+
+// GuessCacheKey returns the key of a license text in a GuessCache: the hex
+// SHA-256 hash of its normalized text, leaving out the copyright statements
+// starting its lines, but not the license terms following them on the same
+// line, so that copies of a license which differ only in their copyright
+// holders, case or whitespace share a key.
+func GuessCacheKey(text string) string {
+	return fingerprint(text)
+}
+
+// cachedGuess wraps guess to look up the guesses of license texts in the
+// cache of o, and to cache them, if o has one.
+func (o *options) cachedGuess(guess func(*License) error) func(*License) error {
+	if o.guessCache == nil {
+		return guess
+	}
+	return func(l *License) error {
+		key := GuessCacheKey(l.Text)
+		if g, ok := o.guessCache.Get(key); ok {
+			if g.Type == "" {
+				return ErrUnrecognizedLicense
+			}
+			l.Type, l.Language, l.Rider, l.Reference = g.Type, g.Language, g.Rider, g.Reference
+			return nil
+		}
+
+		err := guess(l)
+		switch err {
+		case nil:
+			o.guessCache.Put(key, &CachedGuess{Type: l.Type, Language: l.Language, Rider: l.Rider, Reference: l.Reference})
+		case ErrUnrecognizedLicense:
+			o.guessCache.Put(key, &CachedGuess{})
+		}
+		return err
+	}
+}
+
+// lruCache is an in-memory GuessCache which evicts its least recently used
+// guesses, in front of an optional backend.
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List               // Of lruEntry, most recently used first
+	entries map[string]*list.Element // By key
+	backend GuessCache
+}
+
+type lruEntry struct {
+	key   string
+	guess *CachedGuess
+}
+
+// NewLRUCache creates an in-memory GuessCache holding up to size guesses,
+// evicting the least recently used ones. If backend is not nil, such as a
+// cache created by NewDirCache, the guesses missing from memory are looked up
+// in the backend, and new guesses are written through to it.
+func NewLRUCache(size int, backend GuessCache) GuessCache {
+	if size < 1 {
+		size = 1
+	}
+	return &lruCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		backend: backend,
+	}
+}
+
+func (c *lruCache) Get(key string) (*CachedGuess, bool) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*lruEntry).guess, true
+	}
+	c.mu.Unlock()
+
+	if c.backend == nil {
+		return nil, false
+	}
+	g, ok := c.backend.Get(key)
+	if ok {
+		c.add(key, g)
+	}
+	return g, ok
+}
+
+func (c *lruCache) Put(key string, guess *CachedGuess) {
+	c.add(key, guess)
+	if c.backend != nil {
+		c.backend.Put(key, guess)
+	}
+}
+
+// add caches a guess in memory, evicting the least recently used one if the
+// cache is full.
+func (c *lruCache) add(key string, guess *CachedGuess) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).guess = guess
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, guess: guess})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// dirCache is a GuessCache persisted as JSON files in a directory.
+type dirCache struct {
+	dir string
+}
+
+// NewDirCache creates a GuessCache which persists guesses across processes
+// as JSON files in a directory, which is created as needed. Guesses which
+// cannot be read or written are treated as missing.
+func NewDirCache(dir string) GuessCache {
+	return &dirCache{dir: dir}
+}
+
+func (c *dirCache) Get(key string) (*CachedGuess, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	g := new(CachedGuess)
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, false
+	}
+	return g, true
+}
+
+func (c *dirCache) Put(key string, guess *CachedGuess) {
+	data, err := json.Marshal(guess)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	path := c.path(key)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err == nil {
+		os.Rename(tmp, path)
+	}
+}
+
+func (c *dirCache) path(key string) string {
+	return filepath.Join(c.dir, url.PathEscape(key)+".json")
+}
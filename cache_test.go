@@ -0,0 +1,178 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// countingCache is a GuessCache which counts its hits and misses.
+type countingCache struct {
+	mu      sync.Mutex
+	cache   license.GuessCache
+	hits    int
+	misses  int
+	entries []*license.CachedGuess
+}
+
+func (c *countingCache) Get(key string) (*license.CachedGuess, bool) {
+	g, ok := c.cache.Get(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return g, ok
+}
+
+func (c *countingCache) Put(key string, guess *license.CachedGuess) {
+	c.mu.Lock()
+	c.entries = append(c.entries, guess)
+	c.mu.Unlock()
+	c.cache.Put(key, guess)
+}
+
+func TestGuessCacheKey(t *testing.T) {
+	text, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	key := license.GuessCacheKey(string(text))
+	if len(key) != 64 {
+		t.Fatalf("unexpected key: %s", key)
+	}
+
+	other := "Copyright (c) 2020 Someone Else\n\n" + strings.ToUpper(strings.Replace(string(text), "\n", "\n\n", -1))
+	if got := license.GuessCacheKey(other); got != key {
+		t.Fatalf("\nexpected: %s\ngot: %s", key, got)
+	}
+	if got := license.GuessCacheKey(string(text) + "\nExcept for evil.\n"); got == key {
+		t.Fatalf("expected a different key for a modified text")
+	}
+}
+
+func TestWithGuessCache_OneLineNotices(t *testing.T) {
+	mit, err := license.CanonicalText(license.LicenseMIT)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	// Notices whose license terms follow the copyright statement on its line
+	permission := strings.Join(strings.Fields(mit[strings.Index(mit, "Permission is hereby granted"):]), " ")
+	texts := []struct {
+		text, licenseType string
+	}{
+		{"Copyright (c) 2020 Foo Inc. " + permission, license.LicenseMIT},
+		{"Copyright (c) 2020 Bar Corp. All rights reserved. Proprietary and confidential. " +
+			"Unauthorized copying of this file is strictly prohibited.", license.LicenseProprietary},
+	}
+	if license.GuessCacheKey(texts[0].text) == license.GuessCacheKey(texts[1].text) {
+		t.Fatalf("expected different keys for different notices")
+	}
+
+	s, err := license.NewScanner(license.WithGuessCache(license.NewLRUCache(10, nil)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, c := range texts {
+		l := &license.License{Text: c.text}
+		if err := s.GuessType(l); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.licenseType {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.licenseType, l.Type)
+		}
+	}
+}
+
+func TestNewLRUCache(t *testing.T) {
+	c := license.NewLRUCache(2, nil)
+	c.Put("a", &license.CachedGuess{Type: license.LicenseMIT})
+	c.Put("b", &license.CachedGuess{Type: license.LicenseISC})
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a cached guess")
+	}
+
+	// Evicts "b", the least recently used guess
+	c.Put("c", &license.CachedGuess{Type: license.LicenseZlib})
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected an evicted guess")
+	}
+	if g, ok := c.Get("a"); !ok || g.Type != license.LicenseMIT {
+		t.Fatalf("unexpected guess: %v", g)
+	}
+}
+
+func TestNewLRUCache_Backend(t *testing.T) {
+	dir := t.TempDir()
+	c := license.NewLRUCache(1, license.NewDirCache(dir))
+	c.Put("a", &license.CachedGuess{Type: license.LicenseMIT, Rider: "No evil."})
+	c.Put("b", &license.CachedGuess{})
+
+	// Guesses evicted from memory are read back from the backend, as are those
+	// of other processes
+	for _, c := range []license.GuessCache{c, license.NewLRUCache(1, license.NewDirCache(dir))} {
+		if g, ok := c.Get("a"); !ok || g.Type != license.LicenseMIT || g.Rider != "No evil." {
+			t.Fatalf("unexpected guess: %v", g)
+		}
+		if g, ok := c.Get("b"); !ok || g.Type != "" {
+			t.Fatalf("unexpected guess: %v", g)
+		}
+	}
+	if _, ok := license.NewDirCache(dir).Get("c"); ok {
+		t.Fatalf("expected no cached guess")
+	}
+}
+
+func TestWithGuessCache(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"a/LICENSE": {Data: append([]byte("Copyright (c) 2019 A\n\n"), mit...)},
+		"b/LICENSE": {Data: append([]byte("Copyright (c) 2020 B\n\n"), mit...)},
+		"c/LICENSE": {Data: mit},
+		"d/LICENSE": {Data: []byte("All rights reserved.")},
+		"e/LICENSE": {Data: []byte("All rights reserved.")},
+	}
+	c := &countingCache{cache: license.NewLRUCache(10, nil)}
+	s, err := license.NewScanner(license.WithFS(fsys), license.WithGuessCache(c), license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	results, err := s.FromDirRecursive(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, dir := range []string{"a", "b", "c"} {
+		if ls := results[dir]; len(ls) != 1 || ls[0].Type != license.LicenseMIT {
+			t.Fatalf("unexpected licenses in %s: %v", dir, ls)
+		}
+	}
+	for _, dir := range []string{"d", "e"} {
+		if ls := results[dir]; len(ls) != 1 || ls[0].Type != license.LicenseUnrecognized {
+			t.Fatalf("unexpected licenses in %s: %v", dir, ls)
+		}
+	}
+	if c.misses != 2 || c.hits != 3 || len(c.entries) != 2 {
+		t.Fatalf("unexpected cache use: %d misses, %d hits, %v", c.misses, c.hits, c.entries)
+	}
+
+	// The Scanner looks up texts guessed directly
+	l := &license.License{Text: string(mit)}
+	if err := s.GuessType(l); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || c.hits != 4 {
+		t.Fatalf("unexpected guess: %s, %d hits", l.Type, c.hits)
+	}
+}
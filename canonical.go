@@ -0,0 +1,139 @@
+package license
+
+import (
+	"embed"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// ErrNoCanonicalText is returned when the canonical text of a license is not
+// available.
+var ErrNoCanonicalText = errors.New("license: no canonical text for license")
+
+// The number of lines at the top of a text in which a copyright statement
+// with placeholders means that the text is to be filled in. Placeholders
+// further down, such as in the instructions of the GNU and Apache licenses,
+// are part of the license itself.
+const placeholderLines = 5
+
+// refTextFS holds the texts of the LicenseRef licenses GuessType detects,
+// which the SPDX license list has no text for.
+//
+//go:embed text
+var refTextFS embed.FS
+
+// CanonicalText returns the official text of a license, as embedded from the
+// SPDX license list, or the text of a registered license. The "-only" and
+// "-or-later" variants of the GNU licenses have the text of the license. The
+// text of an expression is composed of the texts of its licenses, in order,
+// and that of a license WITH an exception ends with the exception. Public
+// domain and proprietary licenses have no canonical text.
+//
+// WithCopyright fills in the placeholders of texts which begin with a
+// copyright statement, such as that of the MIT license. Texts which carry
+// their own copyright, such as those of the GNU licenses, are left as is.
+func CanonicalText(id string, opts ...Option) (string, error) {
+	o := newOptions(opts)
+
+	text, err := licenseText(id)
+	if err != nil {
+		return "", err
+	}
+
+	if (o.holder != "" || o.year != 0) && hasCopyrightPlaceholder(text) {
+		text = fillPlaceholders(text, o.holder, o.year)
+	}
+	return text, nil
+}
+
+// licenseText returns the text of a license, or composes that of an
+// expression.
+func licenseText(id string) (string, error) {
+	if d, ok := definedLicense(id); ok && d.Text != "" {
+		return d.Text, nil
+	}
+	for _, candidate := range []string{id, baseLicenseID(id)} {
+		if l, ok := spdx.Get(candidate); ok && l.Text != "" {
+			return l.Text, nil
+		}
+	}
+	if text, err := refTextFS.ReadFile("text/" + id + ".txt"); err == nil {
+		return string(text), nil
+	}
+
+	e, err := spdx.Parse(id)
+	if err != nil {
+		return "", ErrNoCanonicalText
+	}
+	switch e := e.(type) {
+	case *spdx.With:
+		text, err := licenseText(e.License.String())
+		if err != nil {
+			return "", err
+		}
+		x, ok := spdx.GetException(e.Exception)
+		if !ok || x.Text == "" {
+			return "", ErrNoCanonicalText
+		}
+		return joinTexts(text, x.Text), nil
+	case *spdx.And:
+		return expressionText(e.Left, e.Right)
+	case *spdx.Or:
+		return expressionText(e.Left, e.Right)
+	}
+	return "", ErrNoCanonicalText
+}
+
+// expressionText composes the text of an expression from the texts of its
+// operands.
+func expressionText(left, right spdx.Expr) (string, error) {
+	leftText, err := licenseText(left.String())
+	if err != nil {
+		return "", err
+	}
+	rightText, err := licenseText(right.String())
+	if err != nil {
+		return "", err
+	}
+	return joinTexts(leftText, rightText), nil
+}
+
+// joinTexts appends a text to another, separated by a blank line.
+func joinTexts(text, next string) string {
+	return strings.TrimRight(text, "\n") + "\n\n" + next
+}
+
+// hasCopyrightPlaceholder determines if a copyright statement near the top of
+// the text has placeholders.
+func hasCopyrightPlaceholder(text string) bool {
+	lines := strings.SplitN(text, "\n", placeholderLines+1)
+	if len(lines) > placeholderLines {
+		lines = lines[:placeholderLines]
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "copyright") &&
+			placeholderRegexp.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// fillPlaceholders replaces the year and holder placeholders of a text, such as
+// <year>, [yyyy] and [copyright holder].
+func fillPlaceholders(text, holder string, year int) string {
+	return placeholderRegexp.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := strings.ToLower(strings.Trim(placeholder, "<>[]'"))
+		switch {
+		case year != 0 && (strings.Contains(name, "year") || name == "yyyy"):
+			return strconv.Itoa(year)
+		case holder != "" && (strings.Contains(name, "holder") || strings.Contains(name, "owner") ||
+			strings.Contains(name, "author") || name == "organization"):
+			return holder
+		}
+		return placeholder
+	})
+}
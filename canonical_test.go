@@ -0,0 +1,173 @@
+package license_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestCanonicalText(t *testing.T) {
+	for _, ltype := range license.KnownLicenses {
+		text, err := license.CanonicalText(ltype)
+		if err != nil {
+			t.Fatalf("%s: err: %s", ltype, err)
+		}
+		l := license.New("", text)
+		if err := l.GuessType(); err != nil || l.Type != ltype {
+			t.Fatalf("%s: unexpected type of canonical text: %s (%v)", ltype, l.Type, err)
+		}
+	}
+
+	text, err := license.CanonicalText("MIT", license.WithCopyright("Acme Inc.", 2024))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(text, "Copyright (c) 2024 Acme Inc.\n") {
+		t.Fatalf("placeholders were not filled:\n%s", text)
+	}
+
+	text, err = license.CanonicalText("BSD-2-Clause", license.WithCopyright("", 2024))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(text, "Copyright (c) 2024, <OWNER>\n") {
+		t.Fatalf("unexpected placeholders:\n%s", text)
+	}
+
+	// The instructions of the GNU licenses are left alone
+	text, err = license.CanonicalText(license.LicenseGPL30OrLater, license.WithCopyright("Acme Inc.", 2024))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(text, "Acme") || !strings.Contains(text, "Copyright (C) <year>  <name of author>") {
+		t.Fatalf("unexpected GPL-3.0 text")
+	}
+
+	if _, err := license.CanonicalText(license.LicensePublicDomain); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+	if _, err := license.CanonicalText("MyLicense"); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+}
+
+// canonicalRule is the part of a rule of rules.json which tells the license
+// types it detects.
+type canonicalRule struct {
+	Type     string           `json:"type"`
+	Grant    string           `json:"grant"`
+	Variants []*canonicalRule `json:"variants"`
+}
+
+// detectedTypes returns the license types the rule and its variants detect.
+func (r *canonicalRule) detectedTypes() []string {
+	types := []string{r.Type}
+	switch r.Grant {
+	case "gnu":
+		types = append(types, r.Type+"-only", r.Type+"-or-later")
+	case "gfdl":
+		for _, invariants := range []string{"", "-invariants", "-no-invariants"} {
+			types = append(types, r.Type+invariants+"-only", r.Type+invariants+"-or-later")
+		}
+	}
+	for _, v := range r.Variants {
+		types = append(types, v.detectedTypes()...)
+	}
+	return types
+}
+
+func TestCanonicalText_Detected(t *testing.T) {
+	// Every license type GuessType detects has a canonical text, but for the
+	// public domain and proprietary ones, and so do the expressions with the
+	// exceptions and the Commons Clause it detects
+	data, err := ioutil.ReadFile("rules.json")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var table struct {
+		Rules []*canonicalRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &table); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var types []string
+	for _, r := range table.Rules {
+		types = append(types, r.detectedTypes()...)
+	}
+	types = append(types,
+		"Apache-2.0 WITH LLVM-exception",
+		"Apache-2.0 WITH SHL-2.1",
+		"GPL-2.0-only WITH Classpath-exception-2.0",
+		"GPL-2.0-only WITH Linux-syscall-note",
+		"GPL-3.0-or-later WITH Classpath-exception-2.0",
+		"GPL-3.0-or-later WITH GCC-exception-3.1",
+		"MIT AND "+license.LicenseCommonsClause,
+	)
+	for _, ltype := range types {
+		if ltype == license.LicensePublicDomain || ltype == license.LicenseProprietary {
+			continue
+		}
+		if _, err := license.CanonicalText(ltype); err != nil {
+			t.Fatalf("%s: err: %s", ltype, err)
+		}
+	}
+
+	// The text of an exception follows that of its license, and is guessed
+	// as both
+	text, err := license.CanonicalText("Apache-2.0 WITH LLVM-exception")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(text, "\n                                 Apache License") ||
+		!strings.Contains(text, "\n\n---- LLVM Exceptions to the Apache 2.0 License ----") {
+		t.Fatalf("unexpected text:\n%s", text)
+	}
+	for _, ltype := range []string{"Apache-2.0 WITH LLVM-exception", "GPL-2.0 WITH Classpath-exception-2.0", "MIT AND " + license.LicenseCommonsClause} {
+		text, err := license.CanonicalText(ltype)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l := license.New("", text)
+		if err := l.GuessType(); err != nil || l.Type != ltype {
+			t.Fatalf("%s: unexpected type of canonical text: %s (%v)", ltype, l.Type, err)
+		}
+	}
+
+	if _, err := license.CanonicalText(license.LicenseProprietary); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+	if _, err := license.CanonicalText("MIT WITH MyException"); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+	if _, err := license.CanonicalText("MIT OR MyLicense"); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+}
+
+func TestCanonicalText_EUPL(t *testing.T) {
+	// The embedded texts of both versions are read, and guessed as their own
+	// version, whether as is or reflowed
+	for _, c := range []struct {
+		ltype, copyright string
+	}{
+		{license.LicenseEUPL11, "EUPL © the European Community 2007"},
+		{license.LicenseEUPL12, "EUPL © the European Union 2007, 2016"},
+	} {
+		text, err := license.CanonicalText(c.ltype)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.ltype, err)
+		}
+		if !strings.Contains(text, c.copyright) || !strings.Contains(text, "Compatible Licence") {
+			t.Fatalf("%s: unexpected text:\n%s", c.ltype, text)
+		}
+		for _, text := range []string{text, strings.Join(strings.Fields(text), " ")} {
+			l := license.New("", text)
+			if err := l.GuessType(); err != nil || l.Type != c.ltype {
+				t.Fatalf("%s: unexpected type of canonical text: %s (%v)", c.ltype, l.Type, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package license
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cargoPackage is a package of a Cargo.lock file.
+type cargoPackage struct {
+	name, version string
+	source        string     // The registry or repository of the package, or "" for those of the workspace
+	dependencies  []cargoDep // The packages depended on
+}
+
+// cargoDep is a dependency of a package of a Cargo.lock file, which only has a
+// version if several versions of the package are locked.
+type cargoDep struct {
+	name, version string
+}
+
+// matches determines if the dependency is on the given package.
+func (d cargoDep) matches(p *cargoPackage) bool {
+	return d.name == p.name && (d.version == "" || d.version == p.version)
+}
+
+// NewCargoReport reads the Cargo.lock file of a Rust project, and reports the
+// licenses of each crate of a registry it locks in the same form as
+// NewDependencyReport, from their checkouts in the registry/src directory of
+// $CARGO_HOME, or ~/.cargo: the license declared by the license field of their
+// Cargo.toml, or else that of the file of its license-file field, or those of
+// their license files. Crates are direct dependencies if a package of the
+// workspace depends on them. Crates which were not downloaded have no
+// licenses, and neither have those of git repositories.
+func NewCargoReport(ctx context.Context, dir string, opts ...Option) (*DependencyReport, error) {
+	o := newOptions(opts)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "Cargo.lock"))
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := readCargoLock(data)
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid lock file %s: %w", filepath.Join(dir, "Cargo.lock"), err)
+	}
+	registries, err := cargoRegistries()
+	if err != nil {
+		return nil, err
+	}
+
+	// The packages importing each package, by the versions they lock
+	byName := make(map[string][]*cargoPackage)
+	for _, p := range pkgs {
+		byName[p.name] = append(byName[p.name], p)
+	}
+	importers := make(map[*cargoPackage][]*cargoPackage)
+	for _, q := range pkgs {
+		for _, dep := range q.dependencies {
+			for _, p := range byName[dep.name] {
+				if dep.matches(p) {
+					importers[p] = append(importers[p], q)
+				}
+			}
+		}
+	}
+
+	report := &DependencyReport{Modules: []*ModuleDependency{}}
+	for _, p := range pkgs {
+		if p.source == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		d := &ModuleDependency{
+			Path:      p.name,
+			Version:   p.version,
+			Ecosystem: "cargo",
+			Packages:  []string{p.name},
+		}
+		for _, q := range importers[p] {
+			d.Direct = d.Direct || q.source == ""
+			d.ImportedBy = appendUnique(d.ImportedBy, q.name)
+		}
+		sort.Strings(d.ImportedBy)
+		report.Modules = append(report.Modules, d)
+
+		if !strings.HasPrefix(p.source, "registry+") && !strings.HasPrefix(p.source, "sparse+") {
+			continue
+		}
+		for _, registry := range registries {
+			crate := filepath.Join(registry, p.name+"-"+p.version)
+			if info, err := os.Stat(crate); err == nil && info.IsDir() {
+				d.Dir = crate
+				break
+			}
+		}
+		if d.Dir == "" {
+			continue
+		}
+		if d.Licenses, err = crateLicenses(ctx, d.Dir, o); err != nil {
+			return nil, fmt.Errorf("license: %s@%s: %w", p.name, p.version, err)
+		}
+	}
+	sort.SliceStable(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	return report, nil
+}
+
+// crateLicenses returns the licenses of the checkout of a crate, as described
+// by NewCargoReport. Licenses separated by a slash, as allowed by older
+// versions of Cargo, are a choice of them.
+func crateLicenses(ctx context.Context, dir string, o *options) ([]*License, error) {
+	manifest := filepath.Join(dir, "Cargo.toml")
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	declared, err := readTOMLManifest("package")(data)
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid manifest %s: %w", manifest, err)
+	}
+	for i, license := range declared {
+		declared[i] = strings.Replace(license, "/", " OR ", -1)
+	}
+
+	if declaredType(declared) == "" {
+		values, err := tomlValues(data, "license-file")
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := values["package"]; ok {
+			file, err := tomlString(value)
+			if err != nil {
+				return nil, fmt.Errorf("license: invalid manifest %s: %w", manifest, err)
+			}
+			l, err := guessFromFile(ctx, filepath.Join(dir, filepath.FromSlash(file)), o)
+			switch {
+			case err == nil:
+				return []*License{l}, nil
+			case err == ErrUnrecognizedLicense, err == ErrFileBudget, os.IsNotExist(err):
+			default:
+				return nil, err
+			}
+		}
+	}
+	return packageLicenses(ctx, dir, manifest, declared, o)
+}
+
+// cargoRegistries returns the directories of the checkouts of the crates of
+// each registry in $CARGO_HOME, or ~/.cargo, in order.
+func cargoRegistries() ([]string, error) {
+	home := os.Getenv("CARGO_HOME")
+	if home == "" {
+		user, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		home = filepath.Join(user, ".cargo")
+	}
+	src := filepath.Join(home, "registry", "src")
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var registries []string
+	for _, e := range entries {
+		if e.IsDir() {
+			registries = append(registries, filepath.Join(src, e.Name()))
+		}
+	}
+	return registries, nil
+}
+
+// addDependencies adds the dependencies of a line of the dependencies array
+// of a package, and reports whether the array goes on.
+func (p *cargoPackage) addDependencies(line string) bool {
+	end := strings.HasSuffix(line, "]")
+	for _, dep := range strings.Split(strings.TrimSuffix(line, "]"), ",") {
+		fields := strings.Fields(strings.Trim(strings.TrimSpace(dep), `"`))
+		switch {
+		case len(fields) > 1:
+			p.dependencies = append(p.dependencies, cargoDep{name: fields[0], version: fields[1]})
+		case len(fields) > 0:
+			p.dependencies = append(p.dependencies, cargoDep{name: fields[0]})
+		}
+	}
+	return !end
+}
+
+// readCargoLock reads the packages of a Cargo.lock file, whose dependencies
+// are "name", "name version" or "name version (source)".
+func readCargoLock(data []byte) ([]*cargoPackage, error) {
+	var pkgs []*cargoPackage
+	var p *cargoPackage
+	inDependencies := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case inDependencies:
+			inDependencies = p.addDependencies(line)
+			continue
+		case line == "[[package]]":
+			p = new(cargoPackage)
+			pkgs = append(pkgs, p)
+			continue
+		case line[0] == '[':
+			p = nil
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if p == nil || eq < 0 {
+			continue
+		}
+		key, value := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+		if key == "dependencies" {
+			inDependencies = p.addDependencies(strings.TrimPrefix(value, "["))
+			continue
+		}
+		var err error
+		switch key {
+		case "name":
+			p.name, err = tomlString(value)
+		case "version":
+			p.version, err = tomlString(value)
+		case "source":
+			p.source, err = tomlString(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	for _, p := range pkgs {
+		if p.name == "" {
+			return nil, fmt.Errorf("package without a name")
+		}
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,109 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewCargoReport(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	t.Setenv("CARGO_HOME", filepath.Join(d, "cargo"))
+
+	registry := "cargo/registry/src/index.crates.io-6f17d22bba15001f/"
+	writeFiles(t, d, map[string]string{
+		"app/Cargo.lock": `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+ "rand 0.8.5",
+ "tool",
+]
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "34af8d1a0e25924bf5b7c79c4d6d6d05f700b351e6e2b7852c0eeb80a4bb44c"
+dependencies = ["libc"]
+
+[[package]]
+name = "rand"
+version = "0.7.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "libc"
+version = "0.2.150"
+source = "sparse+https://index.crates.io/"
+
+[[package]]
+name = "serde"
+version = "1.0.190"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "tool"
+version = "0.3.0"
+source = "git+https://github.com/example/tool#4f2c6e7"
+dependencies = ["rand 0.7.3 (registry+https://github.com/rust-lang/crates.io-index)"]
+
+[[package]]
+name = "missing"
+version = "1.0.0"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+		registry + "rand-0.8.5/Cargo.toml":    "[package]\nname = \"rand\"\nversion = \"0.8.5\"\nlicense = \"MIT OR Apache-2.0\"\n",
+		registry + "libc-0.2.150/Cargo.toml":  "[package]\nname = \"libc\"\nlicense = \"MIT/Apache-2.0\"\n",
+		registry + "serde-1.0.190/Cargo.toml": "[package]\nname = \"serde\"\nlicense-file = \"COPYRIGHT.txt\"\n",
+	})
+	copyFixture(t, "ISC", filepath.Join(d, registry, "serde-1.0.190", "COPYRIGHT.txt"))
+
+	r, err := license.NewCargoReport(context.Background(), filepath.Join(d, "app"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type summary struct {
+		Path, Version string
+		Direct        bool
+		ImportedBy    []string
+		Type          string
+	}
+	expected := []summary{
+		{"libc", "0.2.150", false, []string{"rand"}, "MIT OR Apache-2.0"},
+		{"missing", "1.0.0", false, nil, ""},
+		{"rand", "0.8.5", true, []string{"app"}, "MIT OR Apache-2.0"},
+		{"rand", "0.7.3", false, []string{"tool"}, ""},
+		{"serde", "1.0.190", true, []string{"app"}, "ISC"},
+		{"tool", "0.3.0", true, []string{"app"}, ""},
+	}
+	var got []summary
+	for _, m := range r.Modules {
+		s := summary{m.Path, m.Version, m.Direct, m.ImportedBy, ""}
+		if len(m.Licenses) > 0 {
+			s.Type = m.Licenses[0].Type
+		}
+		got = append(got, s)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, got)
+	}
+
+	if _, err := license.NewCargoReport(context.Background(), d); !os.IsNotExist(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// A grant of patent rights, as by Apache-2.0, the GPL-3.0 and the MPL-2.0,
+	// or a patent license included in the license, as by Artistic-2.0
+	patentGrantRegexp = regexp.MustCompile(`\bgrants?\b.*\bpatent|\bpatent licen[cs]e to\b`)
+
+	// The termination of rights upon patent litigation, or its prohibition, as
+	// by GPL-3.0
+	patentLitigationRegexp  = regexp.MustCompile(`\b(litigation|lawsuit|infringement claim)\b`)
+	patentRetaliationRegexp = regexp.MustCompile(`\bterminat|\bmay not initiate\b`)
+
+	// The withholding of rights to trademarks
+	trademarkRegexp            = regexp.MustCompile(`\btrade ?marks?\b|\btrade names?\b|\bservice marks?\b`)
+	trademarkRestrictionRegexp = regexp.MustCompile(`\b(not grant|not granted|not licensed|no rights?|no trademark|` +
+		`nothing in|without (specific )?(prior )?(written )?permission)\b`)
+
+	// The end of a sentence, other than that of a list item such as "a."
+	clauseEndRegexp = regexp.MustCompile(`(\w\w|\))\. `)
+)
+
+// Clauses flags the clauses of a license text which legal review commonly
+// looks for.
+type Clauses struct {
+	PatentGrant          bool `json:"patentGrant" yaml:"patentGrant"`                   // Whether the license explicitly grants patent rights
+	PatentRetaliation    bool `json:"patentRetaliation" yaml:"patentRetaliation"`       // Whether patent litigation terminates the license
+	TrademarkRestriction bool `json:"trademarkRestriction" yaml:"trademarkRestriction"` // Whether the license withholds rights to trademarks
+}
+
+// Clauses analyzes the text of the license, or the canonical text of its type
+// if it has no text, for an explicit patent grant, a patent retaliation clause
+// and a trademark restriction. The clauses are found by the sentences which
+// state them, so the analysis is no substitute for reading a modified text.
+func (l *License) Clauses() Clauses {
+	text := l.Text
+	if strings.TrimSpace(text) == "" {
+		licenseType := l.Type
+		if license, _, ok := withException(licenseType); ok {
+			licenseType = license
+		}
+		text, _ = CanonicalText(licenseType)
+	}
+
+	var c Clauses
+	comp := collapseSpace(strings.ToLower(prepareText(text)))
+	for _, sentence := range splitClauses(comp) {
+		if strings.Contains(sentence, "patent") {
+			c.PatentGrant = c.PatentGrant || patentGrantRegexp.MatchString(sentence)
+			c.PatentRetaliation = c.PatentRetaliation ||
+				patentLitigationRegexp.MatchString(sentence) && patentRetaliationRegexp.MatchString(sentence)
+		}
+		if trademarkRegexp.MatchString(sentence) && trademarkRestrictionRegexp.MatchString(sentence) {
+			c.TrademarkRestriction = true
+		}
+	}
+	return c
+}
+
+// splitClauses splits normalized text into its sentences.
+func splitClauses(comp string) []string {
+	var sentences []string
+	start := 0
+	for _, loc := range clauseEndRegexp.FindAllStringIndex(comp, -1) {
+		sentences = append(sentences, comp[start:loc[1]-1])
+		start = loc[1]
+	}
+	return append(sentences, comp[start:])
+}
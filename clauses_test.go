@@ -0,0 +1,45 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseClauses(t *testing.T) {
+	cases := []struct {
+		ltype    string
+		expected license.Clauses
+	}{
+		{license.LicenseMIT, license.Clauses{}},
+		{license.LicenseBSD3Clause, license.Clauses{}},
+		{license.LicenseGPL20, license.Clauses{}},
+		{license.LicenseApache20, license.Clauses{PatentGrant: true, PatentRetaliation: true, TrademarkRestriction: true}},
+		{license.LicenseMPL20, license.Clauses{PatentGrant: true, PatentRetaliation: true, TrademarkRestriction: true}},
+		{license.LicenseGPL30, license.Clauses{PatentGrant: true, PatentRetaliation: true}},
+		{license.LicenseEPL10, license.Clauses{PatentGrant: true, PatentRetaliation: true}},
+	}
+	for _, c := range cases {
+		text, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", c.ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if clauses := license.New(c.ltype, string(text)).Clauses(); clauses != c.expected {
+			t.Fatalf("%s\nexpected: %+v\ngot: %+v", c.ltype, c.expected, clauses)
+		}
+	}
+
+	// Licenses without text are analyzed by their canonical text
+	if clauses := license.New("Apache-2.0 WITH LLVM-exception", "").Clauses(); !clauses.PatentGrant {
+		t.Fatalf("unexpected clauses: %+v", clauses)
+	}
+	expected := license.Clauses{PatentGrant: true, PatentRetaliation: true}
+	if clauses := license.New(license.LicenseCDDL10, "").Clauses(); clauses != expected {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, clauses)
+	}
+	if clauses := license.New("Beerware", "").Clauses(); clauses != (license.Clauses{}) {
+		t.Fatalf("unexpected clauses: %+v", clauses)
+	}
+}
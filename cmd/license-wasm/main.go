@@ -0,0 +1,41 @@
+// Command license-wasm exposes license classification to JavaScript, for web
+// tools which classify licenses client-side, without a backend. It is built
+// for WebAssembly, and run with the wasm_exec.js support file of Go:
+//
+//	GOOS=js GOARCH=wasm go build -o license.wasm ./cmd/license-wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// Once loaded, it defines the goLicense.guess function, which classifies a
+// license text:
+//
+//	goLicense.guess(text)
+//	// {type: "MIT", confidence: 0.97, recognized: true, expression: "MIT"}
+//
+// The confidence is scored as done by License.GuessTypeWithConfidence, and
+// texts which are only similar to a license are returned with recognized set
+// to false. An error is returned as {error: "..."}.
+package main
+
+import (
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// classify guesses the type of a license text, as returned to JavaScript.
+func classify(text string) map[string]interface{} {
+	l := &license.License{Text: text}
+	licenseType, confidence, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	result := map[string]interface{}{
+		"type":       licenseType,
+		"confidence": confidence,
+		"recognized": l.Type == licenseType,
+	}
+	if e, err := spdx.Parse(licenseType); err == nil && spdx.Validate(e) == nil {
+		result["expression"] = e.String()
+	}
+	return result
+}
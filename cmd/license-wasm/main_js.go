@@ -0,0 +1,18 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+func main() {
+	guess := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return map[string]interface{}{"error": "license: goLicense.guess expects a license text"}
+		}
+		return classify(args[0].String())
+	})
+	js.Global().Set("goLicense", map[string]interface{}{"guess": guess})
+
+	// The functions are called until the page is closed
+	select {}
+}
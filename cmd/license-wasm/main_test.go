@@ -0,0 +1,22 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("..", "..", "fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	result := classify(string(mit))
+	if result["type"] != "MIT" || result["expression"] != "MIT" || result["recognized"] != true || result["confidence"] != 1.0 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+
+	if result := classify(""); result["error"] == nil {
+		t.Fatalf("expected an error, got: %v", result)
+	}
+}
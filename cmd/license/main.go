@@ -0,0 +1,418 @@
+// Command license detects the licenses of directories, and checks them
+// against a license policy.
+//
+// Usage:
+//
+//	license detect [-r] [-timeout d] [-licenses file] [-format text|json|yaml] <dir>...
+//	license check -policy <file> [-r] [-timeout d] [-licenses file] [-format text|json|yaml] <dir>...
+//	license init [-holder name] [-year n] [-dir dir] [-force] <expression>
+//	license headers [-holder name] [-year n] [-dir dir] [-dry-run] <expression>
+//	license serve [-addr host:port] [-repo-hosts hosts]
+//
+// The detect command prints the type of every license file found. The check
+// command evaluates each license against a policy file, as read by
+// license.LoadPolicy, and prints the verdict and its reason. Additional
+// licenses may be defined by a file, as read by license.LoadLicenseDefinitions.
+// License files in the PDF and RTF formats are read as text. With -format json
+// or yaml, both commands print the license.Report of the directories instead.
+// The init command writes the LICENSE files for an SPDX license expression, as
+// generated by license.GenerateFiles, and prints their paths. The headers
+// command adds or normalizes the license headers of the source files of a
+// directory, as done by license.FixHeaders, and prints the files changed, or
+// with -dry-run, the patch of the changes, exiting with 1 if there are any.
+// The serve command
+// serves license detection over HTTP, as done by the server package, detecting
+// the repositories of the hosts given by -repo-hosts only.
+//
+// The exit code is suitable for use in CI:
+//
+//	0  every license was detected, or is allowed by the policy
+//	1  no license was detected, or a license is denied by the policy
+//	2  the command failed, or was used incorrectly
+//	3  a license needs review, but none is denied by the policy
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+	_ "github.com/nfukasawa/go-license/extract"
+	"github.com/nfukasawa/go-license/internal/yaml"
+	"github.com/nfukasawa/go-license/server"
+)
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitError  = 2
+	exitReview = 3
+)
+
+const usage = `Usage:
+	license detect [-r] [-timeout d] [-licenses file] [-format text|json|yaml] <dir>...
+	license check -policy <file> [-r] [-timeout d] [-licenses file] [-format text|json|yaml] <dir>...
+	license init [-holder name] [-year n] [-dir dir] [-force] <expression>
+	license headers [-holder name] [-year n] [-dir dir] [-dry-run] <expression>
+	license serve [-addr host:port] [-repo-hosts hosts]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line, and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	switch args[0] {
+	case "detect":
+		return detect(args[1:], stdout, stderr)
+	case "check":
+		return check(args[1:], stdout, stderr)
+	case "init":
+		return initLicense(args[1:], stdout, stderr)
+	case "headers":
+		return headers(args[1:], stdout, stderr)
+	case "serve":
+		return serve(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "license: unknown command %q\n%s", args[0], usage)
+		return exitError
+	}
+}
+
+// scanFlags are the flags shared by all commands.
+type scanFlags struct {
+	recursive bool
+	timeout   time.Duration
+	licenses  string
+	format    string
+}
+
+func (f *scanFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.recursive, "r", false, "scan directories recursively")
+	fs.DurationVar(&f.timeout, "timeout", 0, "stop scanning after this long, or never if 0")
+	fs.StringVar(&f.licenses, "licenses", "", "file defining additional licenses, in YAML or JSON")
+	fs.StringVar(&f.format, "format", "text", "output format: text, json or yaml")
+}
+
+func (f *scanFlags) validate() error {
+	if f.format != "text" && f.format != "json" && f.format != "yaml" {
+		return fmt.Errorf("license: unknown format %q", f.format)
+	}
+	return nil
+}
+
+// scan finds the licenses of each directory, including a result without a
+// license for directories where none was found.
+func scan(dirs []string, flags scanFlags) (*license.Report, error) {
+	var opts []license.Option
+	if flags.licenses != "" {
+		defs, err := license.ReadLicenseDefinitions(flags.licenses)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, license.WithLicenses(defs...))
+	}
+	scanner, err := license.NewScanner(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if flags.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.timeout)
+		defer cancel()
+	}
+
+	report := new(license.Report)
+	for _, dir := range dirs {
+		found := make(map[string][]*license.License)
+		var err error
+		if flags.recursive {
+			found, err = scanner.FromDirRecursive(ctx, dir)
+		} else {
+			var ls []*license.License
+			if ls, err = scanner.LicensesFromDir(ctx, dir); err == nil {
+				found[dir] = ls
+			}
+		}
+		switch err {
+		case nil:
+		case license.ErrNoLicenseFile, license.ErrUnrecognizedLicense:
+			report.Add(dir)
+			continue
+		case context.DeadlineExceeded:
+			return nil, fmt.Errorf("license: scanning %s timed out after %s", dir, flags.timeout)
+		default:
+			return nil, err
+		}
+		report.Results = append(report.Results, license.NewReport(found).Results...)
+	}
+	return report, nil
+}
+
+func detect(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("detect", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags scanFlags
+	flags.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if err := flags.validate(); err != nil || fs.NArg() == 0 {
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+		}
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	report, err := scan(fs.Args(), flags)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	code := exitOK
+	for _, r := range report.Results {
+		if r.Type == "" || r.Type == license.LicenseUnrecognized {
+			code = exitFailed
+		}
+	}
+
+	if flags.format != "text" {
+		return writeReport(stdout, stderr, flags.format, report, code)
+	}
+	for _, r := range report.Results {
+		if r.File == "" {
+			fmt.Fprintf(stdout, "%s\tno license found\n", r.Dir)
+			continue
+		}
+		if r.Rider != "" {
+			fmt.Fprintf(stdout, "%s\t%s + rider\n", r.File, r.Type)
+			continue
+		}
+		if r.Reference {
+			fmt.Fprintf(stdout, "%s\t%s (reference)\n", r.File, r.Type)
+			continue
+		}
+		fmt.Fprintf(stdout, "%s\t%s\n", r.File, r.Type)
+	}
+	return code
+}
+
+func check(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags scanFlags
+	flags.register(fs)
+	policyFile := fs.String("policy", "", "policy file, in YAML or JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if err := flags.validate(); err != nil || fs.NArg() == 0 || *policyFile == "" {
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+		}
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	policy, err := license.LoadPolicy(*policyFile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	report, err := scan(fs.Args(), flags)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	code := exitOK
+	switch report.Evaluate(policy) {
+	case license.VerdictDeny:
+		code = exitFailed
+	case license.VerdictReview:
+		code = exitReview
+	}
+
+	if flags.format != "text" {
+		return writeReport(stdout, stderr, flags.format, report, code)
+	}
+	for _, r := range report.Results {
+		name := r.File
+		if name == "" {
+			name = r.Dir
+		}
+		fmt.Fprintf(stdout, "%s\t%s\t%s\n", name, r.Decision.Verdict, r.Decision.Reason)
+	}
+	return code
+}
+
+func initLicense(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	holder := fs.String("holder", "", "copyright holder")
+	year := fs.Int("year", time.Now().Year(), "copyright year")
+	dir := fs.String("dir", ".", "directory to write the license files to")
+	force := fs.Bool("force", false, "overwrite existing license files")
+
+	exprs, ok := parseExpression(fs, args)
+	if !ok {
+		return exitError
+	}
+	if len(exprs) != 1 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	files, err := license.GenerateFiles(exprs[0], *holder, *year)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		path := filepath.Join(*dir, name)
+		if _, err := os.Stat(path); err == nil && !*force {
+			fmt.Fprintf(stderr, "license: %s already exists\n", path)
+			return exitError
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(*dir, name)
+		if err := ioutil.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		fmt.Fprintln(stdout, path)
+	}
+	return exitOK
+}
+
+// headers runs the headers command.
+func headers(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("headers", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	holder := fs.String("holder", "", "copyright holder")
+	year := fs.Int("year", time.Now().Year(), "copyright year")
+	dir := fs.String("dir", ".", "directory of the source files")
+	dryRun := fs.Bool("dry-run", false, "print the patch of the changes instead of making them")
+
+	exprs, ok := parseExpression(fs, args)
+	if !ok {
+		return exitError
+	}
+	if len(exprs) != 1 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	var opts []license.Option
+	if *dryRun {
+		opts = append(opts, license.WithDryRun())
+	}
+	changes, err := license.FixHeaders(*dir, exprs[0], *holder, *year, opts...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	for _, c := range changes {
+		if *dryRun {
+			fmt.Fprint(stdout, c.Patch)
+		} else {
+			fmt.Fprintf(stdout, "%s: %s\n", c.File, c.Action)
+		}
+	}
+	if *dryRun && len(changes) > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// parseExpression parses the flags of a command taking a license expression,
+// which may come before the flags, as in "license init MIT -holder Acme", and
+// returns the arguments.
+func parseExpression(fs *flag.FlagSet, args []string) ([]string, bool) {
+	var exprs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, false
+		}
+		if fs.NArg() == 0 {
+			return exprs, true
+		}
+		exprs = append(exprs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func serve(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	hosts := fs.String("repo-hosts", "", "comma-separated hosts of the repositories detected, if any")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	var opts []server.Option
+	if *hosts != "" {
+		opts = append(opts, server.WithRepoHosts(strings.Split(*hosts, ",")...))
+	}
+	fmt.Fprintf(stdout, "listening on %s\n", *addr)
+	if err := http.ListenAndServe(*addr, server.NewHandler(opts...)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func writeReport(stdout, stderr io.Writer, format string, report *license.Report, code int) int {
+	if format == "yaml" {
+		data, err := yaml.Marshal(report)
+		if err == nil {
+			_, err = stdout.Write(data)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return code
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return code
+}
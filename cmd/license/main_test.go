@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/internal/yaml"
+)
+
+// setupTree creates a directory tree with an MIT license at its root and a
+// GPL-3.0 license in a subdirectory.
+func setupTree(t *testing.T) string {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for file, ltype := range map[string]string{
+		"LICENSE":         "MIT",
+		"sub/COPYING":     "GPL-3.0",
+		"empty/README.md": "",
+	} {
+		var data []byte
+		if ltype != "" {
+			data, err = ioutil.ReadFile(filepath.Join("..", "..", "fixtures", "licenses", ltype))
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+		path := filepath.Join(d, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	return d
+}
+
+func TestDetect(t *testing.T) {
+	d := setupTree(t)
+	defer os.RemoveAll(d)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"detect", d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); out != filepath.Join(d, "LICENSE")+"\tMIT\n" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"detect", "-r", "-format", "json", d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	var report license.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if results := report.Results; len(results) != 2 || results[0].Type != "MIT" || results[1].Type != "GPL-3.0" {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"detect", "-r", "-format", "yaml", d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	var yamlReport license.Report
+	if err := yaml.Unmarshal(stdout.Bytes(), &yamlReport); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(yamlReport, report) {
+		t.Fatalf("\nexpected: %v\ngot: %v", report.Results, yamlReport.Results)
+	}
+
+	// Additional licenses can be defined
+	definitions := filepath.Join(d, "licenses.yaml")
+	if err := ioutil.WriteFile(definitions, []byte("licenses:\n  - id: LicenseRef-Custom\n    patterns: [custom terms]\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Join(d, "custom"), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(d, "custom", "LICENSE"), []byte("Custom terms"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	stdout.Reset()
+	if code := run([]string{"detect", "-licenses", definitions, filepath.Join(d, "custom")}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.HasSuffix(out, "\tLicenseRef-Custom\n") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// Fails properly when the scan times out
+	stderr.Reset()
+	if code := run([]string{"detect", "-r", "-timeout", "1ns", d}, &stdout, &stderr); code != exitError {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if !strings.Contains(stderr.String(), "timed out") {
+		t.Fatalf("unexpected error output: %s", stderr.String())
+	}
+
+	// Exits with failure if no license is found
+	stdout.Reset()
+	if code := run([]string{"detect", filepath.Join(d, "empty")}, &stdout, &stderr); code != exitFailed {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "no license found") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	d := setupTree(t)
+	defer os.RemoveAll(d)
+
+	policy := filepath.Join(d, "policy.yaml")
+	if err := ioutil.WriteFile(policy, []byte("allow: [MIT]\ndeny: [GPL-3.0]\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"check", "-policy", policy, d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); out != filepath.Join(d, "LICENSE")+"\tallow\tMIT is allowed\n" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// Exits with failure if a license is denied
+	stdout.Reset()
+	if code := run([]string{"check", "-policy", policy, "-r", d}, &stdout, &stderr); code != exitFailed {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "GPL-3.0 is denied") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+
+	// Exits with review if a license is not covered
+	stdout.Reset()
+	if code := run([]string{"check", "-policy", policy, "-format", "json", filepath.Join(d, "empty")}, &stdout, &stderr); code != exitReview {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	var report license.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if results := report.Results; len(results) != 1 || results[0].Decision == nil || results[0].Decision.Reason != "no license" {
+		t.Fatalf("unexpected results: %v", results)
+	}
+}
+
+func TestInit(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init", "MIT OR Apache-2.0", "-holder", "Acme Inc.", "-year", "2024", "-dir", d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	expected := filepath.Join(d, "LICENSE-APACHE") + "\n" + filepath.Join(d, "LICENSE-MIT") + "\n"
+	if out := stdout.String(); out != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, out)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(d, "LICENSE-MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(data), "Copyright (c) 2024 Acme Inc.") {
+		t.Fatalf("unexpected LICENSE-MIT:\n%s", data)
+	}
+
+	// Existing files are only overwritten with -force
+	if code := run([]string{"init", "-dir", d, "MIT OR Apache-2.0"}, &stdout, &stderr); code != exitError {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if code := run([]string{"init", "-dir", d, "-force", "MIT OR Apache-2.0"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+
+	// Any license the package recognizes can be written
+	d = t.TempDir()
+	stdout.Reset()
+	if code := run([]string{"init", "-dir", d, "EPL-2.0"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	data, err = ioutil.ReadFile(filepath.Join(d, "LICENSE"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(string(data), "Eclipse Public License - v 2.0") {
+		t.Fatalf("unexpected LICENSE:\n%s", data)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	d := t.TempDir()
+	path := filepath.Join(d, "main.go")
+	if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"headers", "-dry-run", "-dir", d, "MIT"}, &stdout, &stderr); code != exitFailed {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "+// SPDX-License-Identifier: MIT\n") {
+		t.Fatalf("unexpected patch:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"headers", "MIT", "-holder", "Acme Inc.", "-year", "2024", "-dir", d}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	if expected := path + ": added\n"; stdout.String() != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, stdout.String())
+	}
+	if code := run([]string{"headers", "-dry-run", "-dir", d, "MIT"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"detect"},
+		{"detect", "-format", "xml", "."},
+		{"check", "."},
+		{"check", "-policy", "/tmp/go-license-nonexistent.yaml", "."},
+		{"init"},
+		{"init", "MIT", "Apache-2.0"},
+		{"init", "-dir", "/tmp/go-license-nonexistent", "MIT"},
+		{"headers"},
+		{"headers", "-dir", "/tmp/go-license-nonexistent", "MIT"},
+		{"headers", "MIT AND"},
+		{"serve", "extra"},
+		{"serve", "-addr", "invalid address"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != exitError {
+			t.Fatalf("%v: unexpected exit code %d", args, code)
+		}
+		if stderr.Len() == 0 {
+			t.Fatalf("%v: expected error output", args)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Reason explains why two licenses are, or are not, compatible.
+type Reason string
+
+// licenseKind groups licenses by how their terms conflict with others.
+type licenseKind int
+
+const (
+	kindPermissive   licenseKind = iota // Only requires attribution
+	kindApache                          // Permissive, with patent terms
+	kindAdvertising                     // Permissive, with an advertising clause
+	kindWeakCopyleft                    // Copyleft limited to the files of the work
+	kindMPL                             // Weak copyleft, with GPL secondary licenses
+	kindGPL                             // Copyleft of the GPL family
+)
+
+// compatInfo is the entry of a license in the compatibility matrix. Licenses
+// of the GPL family list the versions of the GPL the combined work may be
+// distributed under.
+type compatInfo struct {
+	kind licenseKind
+	gpl  []string
+	lgpl bool
+}
+
+// The curated compatibility matrix, keyed by lower-cased SPDX identifier. The
+// identifiers without "-only" or "-or-later" have the meaning of the SPDX
+// license list, which is "-only".
+var compatibility = map[string]compatInfo{
+	"mit":          {kind: kindPermissive},
+	"isc":          {kind: kindPermissive},
+	"0bsd":         {kind: kindPermissive},
+	"bsd-2-clause": {kind: kindPermissive},
+	"bsd-3-clause": {kind: kindPermissive},
+	"zlib":         {kind: kindPermissive},
+	"bsl-1.0":      {kind: kindPermissive},
+	"unlicense":    {kind: kindPermissive},
+	"cc0-1.0":      {kind: kindPermissive},
+	"wtfpl":        {kind: kindPermissive},
+	"blessing":     {kind: kindPermissive},
+	"artistic-2.0": {kind: kindPermissive},
+	"apache-2.0":   {kind: kindApache},
+	"bsd-4-clause": {kind: kindAdvertising},
+	"epl-1.0":      {kind: kindWeakCopyleft},
+	"epl-2.0":      {kind: kindWeakCopyleft},
+	"cddl-1.0":     {kind: kindWeakCopyleft},
+	"cddl-1.1":     {kind: kindWeakCopyleft},
+	"mpl-1.1":      {kind: kindWeakCopyleft},
+	"mpl-2.0":      {kind: kindMPL},
+
+	// Generic public-domain dedications
+	"licenseref-public-domain": {kind: kindPermissive},
+
+	"gpl-2.0":           {kind: kindGPL, gpl: []string{"2"}},
+	"gpl-2.0-only":      {kind: kindGPL, gpl: []string{"2"}},
+	"gpl-2.0-or-later":  {kind: kindGPL, gpl: []string{"2", "3"}},
+	"gpl-3.0":           {kind: kindGPL, gpl: []string{"3"}},
+	"gpl-3.0-only":      {kind: kindGPL, gpl: []string{"3"}},
+	"gpl-3.0-or-later":  {kind: kindGPL, gpl: []string{"3"}},
+	"agpl-3.0":          {kind: kindGPL, gpl: []string{"3"}},
+	"agpl-3.0-only":     {kind: kindGPL, gpl: []string{"3"}},
+	"agpl-3.0-or-later": {kind: kindGPL, gpl: []string{"3"}},
+
+	// The LGPL 2.1 may be converted to the GPL 2 or any later version
+	"lgpl-2.1":          {kind: kindGPL, gpl: []string{"2", "3"}, lgpl: true},
+	"lgpl-2.1-only":     {kind: kindGPL, gpl: []string{"2", "3"}, lgpl: true},
+	"lgpl-2.1-or-later": {kind: kindGPL, gpl: []string{"2", "3"}, lgpl: true},
+	"lgpl-3.0":          {kind: kindGPL, gpl: []string{"3"}, lgpl: true},
+	"lgpl-3.0-only":     {kind: kindGPL, gpl: []string{"3"}, lgpl: true},
+	"lgpl-3.0-or-later": {kind: kindGPL, gpl: []string{"3"}, lgpl: true},
+}
+
+// Compatible determines if works under licenses a and b may be combined into
+// a single work, according to a curated compatibility matrix of the known
+// licenses, and explains why. For example, the Apache-2.0 license is
+// compatible with the GPL-3.0, but not with the GPL-2.0-only, and the
+// LGPL permits linking from works under licenses which are otherwise
+// incompatible with it.
+//
+// Either license may be an SPDX license expression, in which case a license
+// chosen by OR is compatible if any of its alternatives is, and licenses
+// combined by AND are compatible if all of their parts are. Licenses missing
+// from the matrix are not compatible, since nothing is known of them.
+func Compatible(a, b string) (bool, Reason) {
+	ea, err := spdx.Parse(a)
+	if err != nil {
+		return false, Reason(fmt.Sprintf("%s is not a valid license expression", a))
+	}
+	eb, err := spdx.Parse(b)
+	if err != nil {
+		return false, Reason(fmt.Sprintf("%s is not a valid license expression", b))
+	}
+	return compatibleExpr(ea, eb)
+}
+
+// compatibleExpr walks both expressions down to pairs of licenses.
+func compatibleExpr(a, b spdx.Expr) (bool, Reason) {
+	for _, swap := range []bool{false, true} {
+		x, y := a, b
+		if swap {
+			x, y = b, a
+		}
+		switch x := x.(type) {
+		case *spdx.Or:
+			ok, reason := compatibleExpr(x.Left, y)
+			if ok {
+				return true, reason
+			}
+			if ok, reason := compatibleExpr(x.Right, y); ok {
+				return true, reason
+			}
+			return false, reason
+		case *spdx.And:
+			ok, reason := compatibleExpr(x.Left, y)
+			if !ok {
+				return false, reason
+			}
+			return compatibleExpr(x.Right, y)
+		case *spdx.With:
+			// Exceptions only grant additional permissions
+			return compatibleExpr(x.License, y)
+		}
+	}
+	return compatibleLicenses(a.String(), b.String())
+}
+
+// compatibleLicenses looks up a pair of licenses in the matrix.
+func compatibleLicenses(a, b string) (bool, Reason) {
+	ia, ok := compatibility[compatKey(a)]
+	if !ok {
+		return false, Reason(fmt.Sprintf("the compatibility of %s is unknown", a))
+	}
+	ib, ok := compatibility[compatKey(b)]
+	if !ok {
+		return false, Reason(fmt.Sprintf("the compatibility of %s is unknown", b))
+	}
+	if strings.EqualFold(a, b) {
+		return true, Reason(fmt.Sprintf("both works are under %s", a))
+	}
+	if ib.kind < ia.kind {
+		a, b, ia, ib = b, a, ib, ia
+	}
+
+	switch {
+	case ia.kind == kindPermissive:
+		return true, Reason(fmt.Sprintf("%s is permissive, and only requires attribution", a))
+
+	case ia.kind == kindApache && ib.kind == kindGPL:
+		if containsType(ib.gpl, "3") {
+			return true, Reason(fmt.Sprintf("%s is compatible with version 3 of the GPL, which %s allows", a, b))
+		}
+		if ib.lgpl {
+			return true, Reason(fmt.Sprintf("%s is incompatible with version 2 of the GPL, "+
+				"but %s permits linking from works under other licenses", a, b))
+		}
+		return false, Reason(fmt.Sprintf("the patent and indemnity terms of %s are "+
+			"incompatible with version 2 of the GPL, which %s requires", a, b))
+
+	case ia.kind == kindAdvertising && ib.kind == kindGPL:
+		if ib.lgpl {
+			return true, Reason(fmt.Sprintf("%s is incompatible with the GPL, "+
+				"but %s permits linking from works under other licenses", a, b))
+		}
+		return false, Reason(fmt.Sprintf("the advertising clause of %s is a further "+
+			"restriction, which %s forbids", a, b))
+
+	case ib.kind == kindGPL && ia.kind == kindWeakCopyleft:
+		if ib.lgpl {
+			return true, Reason(fmt.Sprintf("%s is incompatible with the GPL, "+
+				"but %s permits linking from works under other licenses", a, b))
+		}
+		return false, Reason(fmt.Sprintf("the copyleft terms of %s are incompatible with those of %s", a, b))
+
+	case ib.kind == kindGPL && ia.kind == kindMPL:
+		return true, Reason(fmt.Sprintf("%s allows distribution under %s as a secondary license, "+
+			"unless the work is marked as incompatible with secondary licenses", a, b))
+
+	case ia.kind == kindGPL && ib.kind == kindGPL:
+		for _, version := range ia.gpl {
+			if containsType(ib.gpl, version) {
+				return true, Reason(fmt.Sprintf("%s and %s allow distribution under version %s of the GPL", a, b, version))
+			}
+		}
+		if ia.lgpl || ib.lgpl {
+			lgpl, other := a, b
+			if !ia.lgpl {
+				lgpl, other = b, a
+			}
+			return false, Reason(fmt.Sprintf("%s and %s share no version of the GPL, "+
+				"so %s may not even be linked from %s", a, b, lgpl, other))
+		}
+		return false, Reason(fmt.Sprintf("%s and %s share no version of the GPL", a, b))
+	}
+
+	// Neither is of the GPL family, and their terms apply to their own files
+	return true, Reason(fmt.Sprintf("the terms of %s and %s apply to their own files", a, b))
+}
+
+// compatKey returns the key of a license in the matrix, with a trailing "+"
+// meaning "-or-later".
+func compatKey(id string) string {
+	key := strings.ToLower(id)
+	if strings.HasSuffix(key, "+") {
+		key = strings.TrimSuffix(key, "+") + "-or-later"
+	}
+	return key
+}
@@ -0,0 +1,56 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestCompatible(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"MIT", "MIT", true},
+		{"MIT", "GPL-3.0", true},
+		{"GPL-2.0", "BSD-3-Clause", true},
+		{"Apache-2.0", "GPL-3.0", true},
+		{"Apache-2.0", "GPL-2.0", false},
+		{"GPL-2.0-only", "Apache-2.0", false},
+		{"Apache-2.0", "GPL-2.0+", true},
+		{"Apache-2.0", "GPL-2.0-or-later", true},
+		{"GPL-2.0", "GPL-3.0", false},
+		{"GPL-2.0+", "GPL-3.0", true},
+		{"GPL-3.0", "AGPL-3.0", true},
+		{"LGPL-2.1", "GPL-2.0", true},
+		{"LGPL-3.0", "GPL-2.0", false},
+		{"LGPL-2.1", "EPL-1.0", true},
+		{"Apache-2.0", "LGPL-2.1", true},
+		{"EPL-1.0", "GPL-2.0", false},
+		{"CDDL-1.0", "GPL-3.0", false},
+		{"MPL-2.0", "GPL-2.0", true},
+		{"MPL-2.0", "EPL-1.0", true},
+		{"BSD-4-Clause", "GPL-2.0", false},
+		{"BSD-4-Clause", "LGPL-3.0", true},
+		{"BSD-4-Clause", "Apache-2.0", true},
+		{"MIT", "Proprietary", false},
+		{"MIT", "(", false},
+		{"MIT OR GPL-2.0", "Apache-2.0", true},
+		{"GPL-2.0", "MIT OR Apache-2.0", true},
+		{"MIT AND Apache-2.0", "GPL-2.0", false},
+		{"GPL-2.0 WITH Classpath-exception-2.0", "GPL-3.0", false},
+		{"GPL-2.0 WITH Classpath-exception-2.0", "LGPL-2.1", true},
+	}
+	for _, c := range cases {
+		ok, reason := license.Compatible(c.a, c.b)
+		if ok != c.expected {
+			t.Fatalf("%s, %s\nexpected: %v\ngot: %v (%s)", c.a, c.b, c.expected, ok, reason)
+		}
+		if reason == "" {
+			t.Fatalf("%s, %s: empty reason", c.a, c.b)
+		}
+		if swapped, _ := license.Compatible(c.b, c.a); swapped != ok {
+			t.Fatalf("%s, %s: not symmetric", c.a, c.b)
+		}
+	}
+}
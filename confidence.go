@@ -0,0 +1,138 @@
+package license
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// The minimum similarity of a text to the most similar canonical text for
+// GuessTypeWithConfidence to guess its type when the substring heuristics
+// cannot. Below it, the texts share little more than common phrases, which
+// the shortest canonical texts, such as that of the WTFPL, share with most.
+const minFallbackScore = 0.5
+
+var (
+	canonicalOnce    sync.Once
+	canonicalBigrams map[string]map[string]struct{}
+)
+
+// GuessTypeWithConfidence will guess the license type the same way GuessType
+// does, and additionally compute a similarity score between 0 and 1 against
+// the canonical text of the guessed license, as found on the SPDX license
+// list. The score is the Dice coefficient of the word bigrams found in both
+// texts, so a verbatim copy of a license scores close to 1 while a short
+// reference to it scores close to 0. It is then up to the caller to decide on
+// an acceptable threshold. Copies of a canonical text, which differ only in
+// their copyright notices, case or whitespace, score exactly 1. Licenses
+// without a canonical text always score 0, and registered licenses are scored
+// against their own text, if any.
+//
+// If the substring heuristics cannot guess the type, the known license whose
+// canonical text is most similar is returned instead, along with its score,
+// if the score is at least 0.5. Otherwise ErrUnrecognizedLicense is returned,
+// and the type of the license is left as is.
+func (l *License) GuessTypeWithConfidence() (string, float64, error) {
+	text := bigrams(prepareText(l.Text))
+
+	if err := l.GuessType(); err == nil {
+		if licenseType, ok := exactType(l.Text); ok && licenseType == l.Type {
+			return l.Type, 1, nil
+		}
+		return l.Type, dice(text, canonicalText(l.Type)), nil
+	}
+
+	var best string
+	var score float64
+	for _, texts := range []map[string]map[string]struct{}{canonicalTexts(), definedTexts()} {
+		for licenseType, canonical := range texts {
+			if s := dice(text, canonical); s > score || s == score && licenseType < best {
+				best, score = licenseType, s
+			}
+		}
+	}
+	if best == "" || score < minFallbackScore {
+		return "", 0, ErrUnrecognizedLicense
+	}
+
+	l.Type = best
+	return best, score, nil
+}
+
+// canonicalText returns the bigram set of the canonical text for the given
+// license type, or nil if there is none. The text of a registered license
+// takes precedence. Variants of the GNU licenses and licenses with exceptions
+// use the text of the license.
+func canonicalText(licenseType string) map[string]struct{} {
+	if d, ok := definedLicense(licenseType); ok && d.bigrams != nil {
+		return d.bigrams
+	}
+	if license, _, ok := withException(licenseType); ok {
+		licenseType = license
+	}
+	for _, id := range []string{licenseType, baseLicenseID(licenseType)} {
+		if l, ok := spdx.Get(id); ok {
+			if text, ok := canonicalTexts()[knownType(l.ID)]; ok {
+				return text
+			}
+		}
+	}
+	return nil
+}
+
+// canonicalTexts returns the bigram sets of all licenses on the SPDX license
+// list which have a canonical text, keyed by license type.
+func canonicalTexts() map[string]map[string]struct{} {
+	canonicalOnce.Do(func() {
+		canonicalBigrams = make(map[string]map[string]struct{})
+		for _, l := range spdx.List() {
+			if l.Text != "" {
+				canonicalBigrams[knownType(l.ID)] = bigrams(l.Text)
+			}
+		}
+	})
+	return canonicalBigrams
+}
+
+// knownType returns the spelling of an SPDX identifier used by the license
+// types known by default, since the SPDX identifiers are case-insensitive.
+func knownType(id string) string {
+	for _, license := range defaultRegistry.licenses {
+		if strings.EqualFold(license, id) {
+			return license
+		}
+	}
+	return id
+}
+
+// bigrams splits text into lower-cased words, ignoring punctuation and
+// whitespace, and returns the set of adjacent word pairs.
+func bigrams(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]struct{}, len(words))
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = struct{}{}
+	}
+	return set
+}
+
+// dice computes the Dice coefficient of two bigram sets.
+func dice(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for bigram := range a {
+		if _, ok := b[bigram]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
@@ -0,0 +1,103 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestGuessTypeWithConfidence(t *testing.T) {
+	for _, ltype := range license.KnownLicenses {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		l := license.New("", string(lbytes))
+		guess, score, err := l.GuessTypeWithConfidence()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if guess != ltype || l.Type != ltype {
+			t.Fatalf("\nexpected: %s\ngot: %s", ltype, guess)
+		}
+		if l, ok := spdx.Get(ltype); !ok || l.Text == "" {
+			continue
+		}
+		if score < 0.99 {
+			t.Fatalf("unexpected score for %s: %f", ltype, score)
+		}
+	}
+}
+
+func TestGuessTypeWithConfidence_Abbreviated(t *testing.T) {
+	// Abbreviated licenses are guessed, but with a low score
+	l := license.New("", "http://www.apache.org/licenses/LICENSE-2.0")
+	guess, score, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if guess != license.LicenseApache20 {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseApache20, guess)
+	}
+	if score > 0.1 {
+		t.Fatalf("unexpected score: %f", score)
+	}
+}
+
+func TestGuessTypeWithConfidence_Modified(t *testing.T) {
+	// Texts that defeat the substring heuristics fall back to similarity
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := strings.Replace(string(lbytes), "free of charge", "without charge", 1)
+
+	l := license.New("", text)
+	guess, score, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if guess != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, guess)
+	}
+	if score < 0.9 || score >= 1 {
+		t.Fatalf("unexpected score: %f", score)
+	}
+
+	// Fails properly if the text does not resemble any license
+	for _, text := range []string{
+		"No license data",
+		"Copyright (c) 2020 Foo. All rights reserved.",
+		"Licensed under the Apache License, Version 2.0",
+		"This project is maintained by Foo. Please report bugs on the issue tracker.",
+	} {
+		l = license.New("", text)
+		if _, _, err := l.GuessTypeWithConfidence(); err != license.ErrUnrecognizedLicense {
+			t.Fatalf("%q:\nexpected: %s\ngot: %v", text, license.ErrUnrecognizedLicense, err)
+		}
+		if l.Type != "" {
+			t.Fatalf("%q: unexpected license type: %s", text, l.Type)
+		}
+	}
+}
+
+func BenchmarkGuessTypeWithConfidence(b *testing.B) {
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseApache20))
+	if err != nil {
+		b.Fatalf("err: %s", err)
+	}
+	text := string(lbytes)
+
+	b.SetBytes(int64(len(text)))
+	for i := 0; i < b.N; i++ {
+		l := license.New("", text)
+		if _, _, err := l.GuessTypeWithConfidence(); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package license
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	copyrightStatementRegexp = regexp.MustCompile(
+		`(?i)(?:copyright\s*(?:\(c\)|©)?|\(c\)|©)[ \t]*(?:\(c\)|©)?[ \t]*(.*)`)
+	copyrightYearsRegexp = regexp.MustCompile(
+		`^(?:\d{4}(?:[ \t]*[-–][ \t]*(?:\d{4}|\d{2}|present))?[ \t]*,?[ \t]*)+`)
+	copyrightYearRegexp = regexp.MustCompile(
+		`(?i)(\d{4})(?:\s*[-–]\s*(\d{4}|\d{2}|present))?`)
+	allRightsReservedRegexp = regexp.MustCompile(`(?i)[,.;]?\s*all rights reserved\.?`)
+	placeholderHolderRegexp = regexp.MustCompile(`(?i)^(year|yyyy)\b|\bname of (the )?(author|copyright)`)
+)
+
+// Abbreviations which keep their trailing period at the end of a holder name
+var holderAbbreviations = map[string]bool{
+	"inc": true, "ltd": true, "co": true, "corp": true, "llc": true,
+	"jr": true, "sr": true,
+}
+
+// YearRange is a span of years in a copyright statement. A single year has
+// equal From and To, and a range ending in "present" has a To of 0.
+type YearRange struct {
+	From int `json:"from" yaml:"from"`
+	To   int `json:"to" yaml:"to"`
+}
+
+// Copyright is a copyright statement found in a license file or header.
+type Copyright struct {
+	Statement string      `json:"statement" yaml:"statement"`             // The statement as written
+	Years     []YearRange `json:"years,omitempty" yaml:"years,omitempty"` // The years of the statement, if any
+	Holder    string      `json:"holder" yaml:"holder"`                   // The copyright holder
+}
+
+// ExtractCopyrights will scan text for copyright statements, such as
+// "Copyright (c) 2014-2016, 2018 Jane Doe", and return the holder and years of
+// each one, in order of appearance. Statements from license templates with
+// placeholders instead of a holder, and mentions of copyright within
+// the license terms themselves, are ignored.
+func ExtractCopyrights(text string) []Copyright {
+	var copyrights []Copyright
+	for _, line := range strings.Split(text, "\n") {
+		if c, _, ok := parseCopyright(line); ok {
+			copyrights = append(copyrights, c)
+		}
+	}
+	return copyrights
+}
+
+// parseCopyright parses the copyright statement of a line, if any, and
+// returns it with the offset of its years in the line, or -1 if it has none.
+func parseCopyright(line string) (Copyright, int, bool) {
+	loc := copyrightStatementRegexp.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return Copyright{}, -1, false
+	}
+	statement := strings.TrimSpace(line[loc[0]:])
+	rest := strings.TrimSpace(line[loc[2]:loc[3]])
+
+	// Without a year, only the word followed by a copyright sign marks an
+	// actual statement.
+	marker := strings.ToLower(line[loc[0]:loc[2]])
+	marked := strings.HasPrefix(marker, "copyright") &&
+		(strings.Contains(marker, "©") || strings.Contains(marker, "(c)"))
+	years := copyrightYearsRegexp.FindString(rest)
+	if years == "" && !marked {
+		return Copyright{}, -1, false
+	}
+
+	holder := strings.TrimSpace(rest[len(years):])
+	holder = allRightsReservedRegexp.ReplaceAllLiteralString(holder, "")
+	holder = strings.TrimSpace(strings.TrimPrefix(holder, "by "))
+	holder = strings.Trim(holder, " \t,;*/")
+	holder = trimHolderPeriod(holder)
+	if holder == "" || placeholderHolderRegexp.MatchString(holder) ||
+		strings.ContainsAny(holder, "<[") && !strings.Contains(holder, "@") {
+		return Copyright{}, -1, false
+	}
+
+	offset := -1
+	if years != "" {
+		offset = loc[2] + strings.Index(line[loc[2]:], rest)
+	}
+	return Copyright{
+		Statement: statement,
+		Years:     parseYears(years),
+		Holder:    holder,
+	}, offset, true
+}
+
+// parseYears parses a list of years and year ranges.
+func parseYears(years string) []YearRange {
+	var ranges []YearRange
+	for _, m := range copyrightYearRegexp.FindAllStringSubmatch(years, -1) {
+		from, _ := strconv.Atoi(m[1])
+		r := YearRange{From: from, To: from}
+		switch {
+		case strings.EqualFold(m[2], "present"):
+			r.To = 0
+		case len(m[2]) == 2:
+			to, _ := strconv.Atoi(m[2])
+			if r.To = from/100*100 + to; r.To < from {
+				r.To += 100
+			}
+		case m[2] != "":
+			r.To, _ = strconv.Atoi(m[2])
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// trimHolderPeriod removes the period ending a sentence after the holder name,
+// unless it belongs to an abbreviation such as "Inc.".
+func trimHolderPeriod(holder string) string {
+	if !strings.HasSuffix(holder, ".") {
+		return holder
+	}
+	words := strings.Fields(holder)
+	last := strings.ToLower(strings.TrimSuffix(words[len(words)-1], "."))
+	if holderAbbreviations[last] {
+		return holder
+	}
+	return strings.TrimSuffix(holder, ".")
+}
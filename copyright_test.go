@@ -0,0 +1,77 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestExtractCopyrights(t *testing.T) {
+	text := `The MIT License (MIT)
+
+Copyright (c) 2014-2016, 2018 Jane Doe <jane@example.com>
+Copyright © 2009 The Go Authors. All rights reserved.
+// Copyright 2015-present Example, Inc.
+ * (C) 1999-02 by John Smith
+Portions copyright Acme Corp.
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+`
+	expected := []license.Copyright{
+		{
+			Statement: "Copyright (c) 2014-2016, 2018 Jane Doe <jane@example.com>",
+			Years:     []license.YearRange{{2014, 2016}, {2018, 2018}},
+			Holder:    "Jane Doe <jane@example.com>",
+		},
+		{
+			Statement: "Copyright © 2009 The Go Authors. All rights reserved.",
+			Years:     []license.YearRange{{2009, 2009}},
+			Holder:    "The Go Authors",
+		},
+		{
+			Statement: "Copyright 2015-present Example, Inc.",
+			Years:     []license.YearRange{{2015, 0}},
+			Holder:    "Example, Inc.",
+		},
+		{
+			Statement: "(C) 1999-02 by John Smith",
+			Years:     []license.YearRange{{1999, 2002}},
+			Holder:    "John Smith",
+		},
+	}
+
+	copyrights := license.ExtractCopyrights(text)
+	if !reflect.DeepEqual(copyrights, expected) {
+		t.Fatalf("\nexpected: %#v\ngot: %#v", expected, copyrights)
+	}
+}
+
+func TestExtractCopyrights_Templates(t *testing.T) {
+	// Canonical license texts only contain placeholders
+	for _, ltype := range []string{"MIT", "BSD-3-Clause", "ISC", "Apache-2.0", "0BSD"} {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if copyrights := license.ExtractCopyrights(string(lbytes)); len(copyrights) != 0 {
+			t.Fatalf("unexpected copyrights in %s: %#v", ltype, copyrights)
+		}
+	}
+
+	// Except for the license text's own copyright
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "GPL-2.0"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	copyrights := license.ExtractCopyrights(string(lbytes))
+	if len(copyrights) != 1 {
+		t.Fatalf("unexpected copyrights: %#v", copyrights)
+	}
+	if copyrights[0].Holder != "Free Software Foundation, Inc." {
+		t.Fatalf("unexpected holder: %s", copyrights[0].Holder)
+	}
+}
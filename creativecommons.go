@@ -0,0 +1,18 @@
+package license
+
+// Creative Commons licenses, which frequently license documentation and data
+// bundled with software. CC0-1.0 is among the recognized license types above.
+const (
+	LicenseCCBY30     = "CC-BY-3.0"
+	LicenseCCBY40     = "CC-BY-4.0"
+	LicenseCCBYSA30   = "CC-BY-SA-3.0"
+	LicenseCCBYSA40   = "CC-BY-SA-4.0"
+	LicenseCCBYNC30   = "CC-BY-NC-3.0"
+	LicenseCCBYNC40   = "CC-BY-NC-4.0"
+	LicenseCCBYNCSA30 = "CC-BY-NC-SA-3.0"
+	LicenseCCBYNCSA40 = "CC-BY-NC-SA-4.0"
+	LicenseCCBYND30   = "CC-BY-ND-3.0"
+	LicenseCCBYND40   = "CC-BY-ND-4.0"
+	LicenseCCBYNCND30 = "CC-BY-NC-ND-3.0"
+	LicenseCCBYNCND40 = "CC-BY-NC-ND-4.0"
+)
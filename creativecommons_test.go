@@ -0,0 +1,62 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_CreativeCommons(t *testing.T) {
+	v4 := func(title, name string) string {
+		return title + " 4.0 International\n\n" +
+			"Creative Commons Corporation (\"Creative Commons\") is not a law firm and\n" +
+			"does not provide legal services or legal advice.\n\n" +
+			"Creative Commons " + name + " 4.0 International Public License\n\n" +
+			"By exercising the Licensed Rights (defined below), You accept and agree\n" +
+			"to be bound by the terms and conditions of this Creative Commons\n" +
+			name + " 4.0 International Public License (\"Public License\").\n"
+	}
+	v3 := func(title string) string {
+		return "Creative Commons Legal Code\n\n" + title + " 3.0 Unported\n\n" +
+			"    CREATIVE COMMONS CORPORATION IS NOT A LAW FIRM AND DOES NOT PROVIDE\n" +
+			"    LEGAL SERVICES.\n\n" +
+			"THE WORK (AS DEFINED BELOW) IS PROVIDED UNDER THE TERMS OF THIS CREATIVE\n" +
+			"COMMONS PUBLIC LICENSE (\"CCPL\" OR \"LICENSE\").\n"
+	}
+
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{v4("Attribution", "Attribution"), license.LicenseCCBY40},
+		{v4("Attribution-ShareAlike", "Attribution-ShareAlike"), license.LicenseCCBYSA40},
+		{v4("Attribution-NonCommercial", "Attribution-NonCommercial"), license.LicenseCCBYNC40},
+		{v4("Attribution-NonCommercial-ShareAlike", "Attribution-NonCommercial-ShareAlike"), license.LicenseCCBYNCSA40},
+		{v4("Attribution-NoDerivatives", "Attribution-NoDerivatives"), license.LicenseCCBYND40},
+		{v4("Attribution-NonCommercial-NoDerivatives", "Attribution-NonCommercial-NoDerivatives"), license.LicenseCCBYNCND40},
+		{v3("Attribution"), license.LicenseCCBY30},
+		{v3("Attribution-ShareAlike"), license.LicenseCCBYSA30},
+		{v3("Attribution-NonCommercial"), license.LicenseCCBYNC30},
+		{v3("Attribution-NonCommercial-ShareAlike"), license.LicenseCCBYNCSA30},
+		{v3("Attribution-NoDerivs"), license.LicenseCCBYND30},
+		{v3("Attribution-NonCommercial-NoDerivs"), license.LicenseCCBYNCND30},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if !l.Recognized() {
+			t.Fatalf("%s was not recognized", l.Type)
+		}
+	}
+
+	// Mentions of Creative Commons alone are not enough
+	l := license.New("", "Creative Commons licenses are popular for documentation.")
+	if err := l.GuessType(); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected unrecognized license, got: %v (%s)", err, l.Type)
+	}
+}
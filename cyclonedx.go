@@ -0,0 +1,188 @@
+package license
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// ErrComponentNotFound is returned when merging licenses into a CycloneDX BOM
+// which does not contain the requested component.
+var ErrComponentNotFound = errors.New("license: component not found in BOM")
+
+var licenseRefRegexp = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// CycloneDXLicense is a license of a CycloneDX component, identified either by
+// its SPDX license identifier or by name.
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// CycloneDXLicenseChoice is an entry of the licenses of a CycloneDX component,
+// which is either a license or an SPDX license expression.
+type CycloneDXLicenseChoice struct {
+	License    *CycloneDXLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+// CycloneDXLicenses converts licenses to the licenses of a CycloneDX
+// component. A license type which is an SPDX license identifier becomes a
+// license with that id, and one which is a compound SPDX license expression
+// becomes an expression. Any other type becomes a license with that name.
+// Unrecognized licenses are omitted.
+//
+// Since CycloneDX only allows either a list of licenses or a single
+// expression, licenses are combined with AND into one expression if any of
+// them is an expression, with names turned into LicenseRef- identifiers.
+func CycloneDXLicenses(licenses []*License) []CycloneDXLicenseChoice {
+	var choices []CycloneDXLicenseChoice
+	for _, l := range licenses {
+		if l == nil || undetermined(l.Type) {
+			continue
+		}
+		choices = append(choices, cycloneDXChoice(l.Type))
+	}
+	return combineCycloneDX(choices)
+}
+
+// CycloneDXLicenses converts the licenses found by the report to the licenses
+// of a CycloneDX component, as described by CycloneDXLicenses.
+func (r *Report) CycloneDXLicenses() []CycloneDXLicenseChoice {
+	return CycloneDXLicenses(r.Licenses())
+}
+
+// cycloneDXChoice converts a single license type.
+func cycloneDXChoice(licenseType string) CycloneDXLicenseChoice {
+	e, err := spdx.Parse(licenseType)
+	if err != nil || spdx.Validate(e) != nil {
+		return CycloneDXLicenseChoice{License: &CycloneDXLicense{Name: licenseType}}
+	}
+	if id, ok := e.(*spdx.Identifier); ok && !id.OrLater {
+		if l, ok := spdx.Get(id.ID); ok {
+			return CycloneDXLicenseChoice{License: &CycloneDXLicense{ID: l.ID}}
+		}
+	}
+	return CycloneDXLicenseChoice{Expression: e.String()}
+}
+
+// combineCycloneDX removes duplicate choices, and combines them into a single
+// expression if any of them is an expression.
+func combineCycloneDX(choices []CycloneDXLicenseChoice) []CycloneDXLicenseChoice {
+	var unique []CycloneDXLicenseChoice
+	seen := make(map[string]bool)
+	expression := false
+	for _, c := range choices {
+		key := c.Expression
+		if c.License != nil {
+			key = "id:" + c.License.ID + "\x00name:" + c.License.Name
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, c)
+			expression = expression || c.License == nil
+		}
+	}
+	if !expression || len(unique) == 1 {
+		return unique
+	}
+
+	parts := make([]string, len(unique))
+	for i, c := range unique {
+		switch {
+		case c.License == nil:
+			parts[i] = c.Expression
+			if strings.Contains(parts[i], " ") {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		case c.License.ID != "":
+			parts[i] = c.License.ID
+		default:
+			parts[i] = "LicenseRef-" + strings.Trim(licenseRefRegexp.ReplaceAllString(c.License.Name, "-"), "-")
+		}
+	}
+	return []CycloneDXLicenseChoice{{Expression: strings.Join(parts, " AND ")}}
+}
+
+// MergeCycloneDX merges licenses into the licenses of a component of a
+// CycloneDX BOM in the JSON format, and returns the updated BOM. The component
+// is found by its bom-ref, or by its name, among the components of the BOM at
+// any depth. An empty component refers to the component described by the
+// metadata of the BOM.
+//
+// Licenses already listed by the component keep their id, name, url or
+// expression, and are combined with the new ones as described by
+// CycloneDXLicenses. Any other content of the BOM is preserved, although the
+// order of object keys is not.
+func MergeCycloneDX(bom []byte, component string, licenses []CycloneDXLicenseChoice) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bom))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("license: invalid BOM: %w", err)
+	}
+	if doc["bomFormat"] != "CycloneDX" {
+		return nil, fmt.Errorf("license: invalid BOM: bomFormat is %v, not CycloneDX", doc["bomFormat"])
+	}
+
+	var target map[string]interface{}
+	if component == "" {
+		if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+			target, _ = metadata["component"].(map[string]interface{})
+		}
+	} else {
+		target = findCycloneDXComponent(doc["components"], component)
+	}
+	if target == nil {
+		return nil, ErrComponentNotFound
+	}
+
+	var existing []CycloneDXLicenseChoice
+	if v, ok := target["licenses"]; ok {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return nil, fmt.Errorf("license: invalid BOM: %w", err)
+		}
+	}
+	merged := combineCycloneDX(append(existing, licenses...))
+	if len(merged) > 0 {
+		target["licenses"] = merged
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// findCycloneDXComponent searches components and their nested components for
+// the one with the given bom-ref or name.
+func findCycloneDXComponent(components interface{}, ref string) map[string]interface{} {
+	list, _ := components.([]interface{})
+	for _, c := range list {
+		c, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if c["bom-ref"] == ref || c["name"] == ref {
+			return c
+		}
+		if found := findCycloneDXComponent(c["components"], ref); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package license_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestCycloneDXLicenses(t *testing.T) {
+	cases := []struct {
+		types    []string
+		expected string
+	}{
+		{[]string{"MIT"}, `[{"license":{"id":"MIT"}}]`},
+		{[]string{"mit", "Apache-2.0", "MIT"}, `[{"license":{"id":"MIT"}},{"license":{"id":"Apache-2.0"}}]`},
+		{[]string{"MyLicense"}, `[{"license":{"name":"MyLicense"}}]`},
+		{[]string{"MIT OR Apache-2.0"}, `[{"expression":"MIT OR Apache-2.0"}]`},
+		{[]string{"GPL-2.0+"}, `[{"expression":"GPL-2.0+"}]`},
+		{[]string{"MIT OR Apache-2.0", "BSD-3-Clause", "My License"},
+			`[{"expression":"(MIT OR Apache-2.0) AND BSD-3-Clause AND LicenseRef-My-License"}]`},
+		{[]string{license.LicenseUnrecognized}, `null`},
+	}
+	for _, c := range cases {
+		var ls []*license.License
+		for _, ltype := range c.types {
+			ls = append(ls, license.New(ltype, ""))
+		}
+		data, err := json.Marshal(license.CycloneDXLicenses(ls))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(data) != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, data)
+		}
+	}
+}
+
+func TestMergeCycloneDX(t *testing.T) {
+	bom := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {"component": {"type": "application", "name": "app"}},
+  "components": [
+    {
+      "type": "library",
+      "name": "lib",
+      "components": [
+        {"type": "library", "bom-ref": "pkg:golang/example.com/nested@v1.0.0", "name": "nested",
+         "licenses": [{"license": {"id": "MIT", "url": "https://example.com/LICENSE"}}]}
+      ]
+    }
+  ]
+}`
+	mit := license.CycloneDXLicenses([]*license.License{license.New(license.LicenseMIT, "")})
+	apache := license.CycloneDXLicenses([]*license.License{license.New(license.LicenseApache20, "")})
+
+	merged, err := license.MergeCycloneDX([]byte(bom), "pkg:golang/example.com/nested@v1.0.0", apache)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	merged, err = license.MergeCycloneDX(merged, "", mit)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var doc struct {
+		SpecVersion string `json:"specVersion"`
+		Version     int    `json:"version"`
+		Metadata    struct {
+			Component struct {
+				Licenses []license.CycloneDXLicenseChoice `json:"licenses"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Components []struct {
+				Licenses []license.CycloneDXLicenseChoice `json:"licenses"`
+			} `json:"components"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(merged, &doc); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if doc.SpecVersion != "1.5" || doc.Version != 1 {
+		t.Fatalf("unexpected BOM: %s", merged)
+	}
+	if ls := doc.Metadata.Component.Licenses; len(ls) != 1 || ls[0].License.ID != "MIT" {
+		t.Fatalf("unexpected licenses: %s", merged)
+	}
+	ls := doc.Components[0].Components[0].Licenses
+	if len(ls) != 2 || ls[0].License.URL != "https://example.com/LICENSE" || ls[1].License.ID != "Apache-2.0" {
+		t.Fatalf("unexpected licenses: %s", merged)
+	}
+
+	// Fails properly for missing components and invalid BOMs
+	if _, err := license.MergeCycloneDX([]byte(bom), "missing", mit); err != license.ErrComponentNotFound {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrComponentNotFound, err)
+	}
+	if _, err := license.MergeCycloneDX([]byte(`{"bomFormat": "SPDX"}`), "", mit); err == nil {
+		t.Fatalf("expected error merging into a BOM which is not CycloneDX")
+	}
+}
@@ -0,0 +1,12 @@
+package license
+
+// Licenses of databases and other data, such as datasets bundled with
+// software. Info puts them in the data category, so that policies can decide
+// on them apart from the licenses of code.
+const (
+	LicenseODbL10           = "ODbL-1.0"
+	LicenseODCBy10          = "ODC-By-1.0"
+	LicenseCDLAPermissive10 = "CDLA-Permissive-1.0"
+	LicenseCDLAPermissive20 = "CDLA-Permissive-2.0"
+	LicenseCDLASharing10    = "CDLA-Sharing-1.0"
+)
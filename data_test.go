@@ -0,0 +1,49 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_Data(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`ODC Open Database License (ODbL)
+
+Preamble
+
+The Open Database License (ODbL) is a license agreement intended to allow
+users to freely share, modify, and use this Database while maintaining this
+same freedom for others.`, license.LicenseODbL10},
+		{`Open Data Commons Attribution License
+
+Preamble
+
+The Open Data Commons Attribution License is a license agreement intended to
+allow users to freely share, modify, and use this Database subject only to the
+attribution requirements set out in Section 4.`, license.LicenseODCBy10},
+		{`Community Data License Agreement – Permissive – Version 2.0
+
+This is the Community Data License Agreement – Permissive, Version 2.0 (the
+"agreement").`, license.LicenseCDLAPermissive20},
+		{`Community Data License Agreement – Sharing – Version 1.0
+
+This is the Community Data License Agreement – Sharing, Version 1.0 (the
+"agreement").`, license.LicenseCDLASharing10},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if info := license.Info(l.Type); info.Category != license.CategoryData {
+			t.Fatalf("%s\nexpected: %s\ngot: %s", l.Type, license.CategoryData, info.Category)
+		}
+	}
+}
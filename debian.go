@@ -0,0 +1,505 @@
+package license
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// ErrNotMachineReadable is returned when reading a debian/copyright file which
+// is not in the machine-readable format.
+var ErrNotMachineReadable = errors.New("license: not a machine-readable debian/copyright file")
+
+// DebianCopyrightFormat is the URI of version 1.0 of the machine-readable
+// debian/copyright format, also known as DEP-5.
+const DebianCopyrightFormat = "https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/"
+
+var (
+	debianGNURegexp     = regexp.MustCompile(`(?i)^(a?gpl|lgpl|gfdl)-(\d+(?:\.\d+)?)(\+)?$`)
+	debianSPDXGNURegexp = regexp.MustCompile(`^(A?GPL|LGPL|GFDL)-(\d+\.\d+)(-only|-or-later)?$`)
+)
+
+// Short names of the debian/copyright format which are not SPDX license
+// identifiers, by their lower case
+var debianLicenseNames = map[string]string{
+	"artistic":     "Artistic-1.0",
+	"cc0":          "CC0-1.0",
+	"expat":        "MIT",
+	"freebsd":      "BSD-2-Clause",
+	"gfdl-niv-1.1": "GFDL-1.1-no-invariants-only",
+	"gfdl-niv-1.2": "GFDL-1.2-no-invariants-only",
+	"gfdl-niv-1.3": "GFDL-1.3-no-invariants-only",
+	"perl":         "(Artistic-1.0-Perl OR GPL-1.0-or-later)",
+	"zope-2.0":     "ZPL-2.0",
+	"zope-2.1":     "ZPL-2.1",
+}
+
+// Short names of the debian/copyright format preferred to SPDX license
+// identifiers
+var debianShortNames = map[string]string{
+	"MIT":          "Expat",
+	"Artistic-1.0": "Artistic",
+}
+
+// DebianCopyright is a machine-readable debian/copyright file, which tells
+// the copyright and license of the files of a source package, as specified by
+// DEP-5.
+type DebianCopyright struct {
+	Format          string           `json:"format" yaml:"format"`                                       // The URI of the format
+	UpstreamName    string           `json:"upstreamName,omitempty" yaml:"upstreamName,omitempty"`       // The name upstream uses for the software
+	UpstreamContact string           `json:"upstreamContact,omitempty" yaml:"upstreamContact,omitempty"` // The contact of upstream
+	Source          string           `json:"source,omitempty" yaml:"source,omitempty"`                   // Where the upstream source comes from
+	Files           []*DebianFiles   `json:"files" yaml:"files"`                                         // The Files stanzas, in order
+	Licenses        []*DebianLicense `json:"licenses,omitempty" yaml:"licenses,omitempty"`               // The standalone License stanzas, in order
+}
+
+// DebianFiles is a Files stanza of a debian/copyright file: the copyright and
+// license of the files matching its patterns.
+type DebianFiles struct {
+	Patterns  []string `json:"patterns" yaml:"patterns"`                   // The patterns of the files, where "*" matches any characters, including "/"
+	Copyright string   `json:"copyright" yaml:"copyright"`                 // The copyright statements, one per line
+	License   string   `json:"license" yaml:"license"`                     // The short names of the license, as written, such as "GPL-2+ or Artistic"
+	Type      string   `json:"type" yaml:"type"`                           // The license as an SPDX license expression
+	Text      string   `json:"text,omitempty" yaml:"text,omitempty"`       // The text of the license, if given by the stanza
+	Comment   string   `json:"comment,omitempty" yaml:"comment,omitempty"` // The comment of the stanza, if any
+}
+
+// DebianLicense is a standalone License stanza of a debian/copyright file,
+// giving the text of a license the Files stanzas refer to by its short name.
+type DebianLicense struct {
+	Name    string `json:"name" yaml:"name"`                           // The short name of the license
+	Type    string `json:"type" yaml:"type"`                           // The license as an SPDX license expression
+	Text    string `json:"text" yaml:"text"`                           // The text of the license
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"` // The comment of the stanza, if any
+}
+
+// ReadDebianCopyright will read a machine-readable debian/copyright file.
+// Files in other formats fail with ErrNotMachineReadable.
+func ReadDebianCopyright(path string) (*DebianCopyright, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDebianCopyright(f)
+}
+
+// ParseDebianCopyright parses a machine-readable debian/copyright file, and
+// maps the short names of its licenses to SPDX license expressions. Short
+// names which are not SPDX license identifiers, nor among those of the
+// format, such as "GPL-2+" or "Expat", become LicenseRefs, and exceptions
+// not on the SPDX license exception list AdditionRefs.
+func ParseDebianCopyright(r io.Reader) (*DebianCopyright, error) {
+	paragraphs, err := readDeb822(r)
+	if len(paragraphs) == 0 || paragraphs[0]["format"] == "" {
+		return nil, ErrNotMachineReadable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header := paragraphs[0]
+	c := &DebianCopyright{
+		Format:          header["format"],
+		UpstreamName:    header["upstream-name"],
+		UpstreamContact: header["upstream-contact"],
+		Source:          header["source"],
+		Files:           []*DebianFiles{},
+	}
+	for _, p := range paragraphs[1:] {
+		name, text := p["license"], ""
+		if i := strings.Index(name, "\n"); i >= 0 {
+			name, text = name[:i], strings.TrimSpace(name[i+1:])
+		}
+		name = strings.TrimSpace(name)
+		files, ok := p["files"]
+		if !ok {
+			if name != "" {
+				c.Licenses = append(c.Licenses, &DebianLicense{Name: name, Type: debianExpression(name), Text: text, Comment: p["comment"]})
+			}
+			continue
+		}
+		c.Files = append(c.Files, &DebianFiles{
+			Patterns:  strings.Fields(files),
+			Copyright: p["copyright"],
+			License:   name,
+			Type:      debianExpression(name),
+			Text:      text,
+			Comment:   p["comment"],
+		})
+	}
+	return c, nil
+}
+
+// Stanza returns the Files stanza which applies to a file of the source
+// package, by its slash-separated path relative to the root of the package:
+// the last one with a pattern which matches it, or nil if none does.
+func (c *DebianCopyright) Stanza(path string) *DebianFiles {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	for i := len(c.Files) - 1; i >= 0; i-- {
+		for _, pattern := range c.Files[i].Patterns {
+			if matchDebianPattern(pattern, path) {
+				return c.Files[i]
+			}
+		}
+	}
+	return nil
+}
+
+// LicenseText returns the text of a license of the file by its short name, as
+// given by a standalone License stanza, or by a Files stanza, or "" if the
+// file has none.
+func (c *DebianCopyright) LicenseText(name string) string {
+	for _, l := range c.Licenses {
+		if strings.EqualFold(l.Name, name) {
+			return l.Text
+		}
+	}
+	for _, f := range c.Files {
+		if strings.EqualFold(f.License, name) && f.Text != "" {
+			return f.Text
+		}
+	}
+	return ""
+}
+
+// NewDebianCopyright creates a debian/copyright file from the results of a
+// report, with a Files stanza for the subtree of each directory with
+// recognized license files, as told by Scopes, relative to the common
+// ancestor of the directories of the report, a standalone License stanza with
+// the text of each license, and the copyright statements of the license files,
+// which are read again. The licenses of the license files of a directory are
+// a dual license, as for ResolvePrimary.
+func NewDebianCopyright(r *Report, name string) (*DebianCopyright, error) {
+	dirs := make([]string, 0, len(r.Results))
+	for _, result := range r.Results {
+		dirs = append(dirs, result.Dir)
+	}
+	root := commonDir(dirs)
+
+	c := &DebianCopyright{Format: DebianCopyrightFormat, UpstreamName: name, Files: []*DebianFiles{}}
+	for _, s := range r.Scopes() {
+		var types, copyrights []string
+		for _, result := range s.Results {
+			if undetermined(result.Type) {
+				continue
+			}
+			data, err := ioutil.ReadFile(result.File)
+			if err != nil {
+				return nil, err
+			}
+			for _, copyright := range ExtractCopyrights(string(data)) {
+				copyrights = appendUnique(copyrights, copyright.Statement)
+			}
+			if indexOf(types, result.Type) < 0 {
+				types = append(types, result.Type)
+			}
+			c.addLicense(result.Type, string(data))
+		}
+		if len(types) == 0 {
+			continue
+		}
+
+		pattern := "*"
+		if rel, err := filepath.Rel(root, filepath.Clean(s.Dir)); err == nil && rel != "." {
+			pattern = filepath.ToSlash(rel) + "/*"
+		}
+		licenseType := dualLicense(types)
+		if len(types) == 1 {
+			licenseType = types[0]
+		}
+		f := &DebianFiles{
+			Patterns:  []string{pattern},
+			Copyright: strings.Join(copyrights, "\n"),
+			License:   debianNames(licenseType),
+			Type:      debianExpression(debianNames(licenseType)),
+		}
+		if f.Copyright == "" {
+			f.Copyright = "Unknown"
+		}
+		c.Files = append(c.Files, f)
+	}
+	return c, nil
+}
+
+// addLicense adds a standalone License stanza for a license type, unless the
+// file has one already.
+func (c *DebianCopyright) addLicense(licenseType, text string) {
+	name := debianNames(licenseType)
+	for _, l := range c.Licenses {
+		if l.Name == name {
+			return
+		}
+	}
+	c.Licenses = append(c.Licenses, &DebianLicense{Name: name, Type: debianExpression(name), Text: strings.TrimSpace(text)})
+}
+
+// Write writes the file in the machine-readable debian/copyright format.
+func (c *DebianCopyright) Write(w io.Writer) error {
+	tw := &tagWriter{w: w}
+	debianField(tw, "Format", c.Format)
+	debianField(tw, "Upstream-Name", c.UpstreamName)
+	debianField(tw, "Upstream-Contact", c.UpstreamContact)
+	debianField(tw, "Source", c.Source)
+	for _, f := range c.Files {
+		tw.line("")
+		debianField(tw, "Files", strings.Join(f.Patterns, " "))
+		debianField(tw, "Copyright", f.Copyright)
+		debianField(tw, "License", strings.TrimRight(f.License+"\n"+f.Text, "\n"))
+		debianField(tw, "Comment", f.Comment)
+	}
+	for _, l := range c.Licenses {
+		tw.line("")
+		debianField(tw, "License", strings.TrimRight(l.Name+"\n"+l.Text, "\n"))
+		debianField(tw, "Comment", l.Comment)
+	}
+	return tw.err
+}
+
+// debianField writes a field, if its value is not empty, with continuation
+// lines indented by a space, and empty lines written as " .".
+func debianField(tw *tagWriter, name, value string) {
+	if value == "" {
+		return
+	}
+	lines := strings.Split(value, "\n")
+	tw.line(name + ": " + lines[0])
+	for _, line := range lines[1:] {
+		if line = strings.TrimRight(line, " \t"); line == "" {
+			line = "."
+		}
+		tw.line(" " + line)
+	}
+}
+
+// readDeb822 reads the paragraphs of a file in the deb822 format, made of
+// fields whose continuation lines start with whitespace, by their lower case
+// names. The lines of the License and Comment fields, which are text, keep
+// their indentation but for the first space, and lines of a single "." are
+// empty; those of other fields are trimmed. The paragraphs read before an
+// invalid line are returned with the error.
+func readDeb822(r io.Reader) ([]map[string]string, error) {
+	var paragraphs []map[string]string
+	var p map[string]string
+	field := ""
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			p, field = nil, ""
+			continue
+		case line[0] == '#':
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			if p == nil || field == "" {
+				return paragraphs, fmt.Errorf("license: invalid debian/copyright file: unexpected continuation line %q", line)
+			}
+			line = line[1:]
+			if strings.TrimSpace(line) == "." {
+				line = ""
+			}
+			p[field] += "\n" + line
+			continue
+		}
+
+		colon := strings.Index(line, ":")
+		if colon <= 0 {
+			return paragraphs, fmt.Errorf("license: invalid debian/copyright file: unexpected line %q", line)
+		}
+		if p == nil {
+			p = make(map[string]string)
+			paragraphs = append(paragraphs, p)
+		}
+		field = strings.ToLower(strings.TrimSpace(line[:colon]))
+		p[field] = strings.TrimSpace(line[colon+1:])
+	}
+	if err := s.Err(); err != nil {
+		return paragraphs, err
+	}
+	for _, p := range paragraphs {
+		for field, value := range p {
+			if field == "license" || field == "comment" {
+				continue
+			}
+			lines := strings.Split(strings.TrimSpace(value), "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimSpace(line)
+			}
+			p[field] = strings.Join(lines, "\n")
+		}
+	}
+	return paragraphs, nil
+}
+
+// matchDebianPattern determines if a path matches a pattern of a Files field,
+// where "*" matches any characters, including "/", and "?" any single one.
+func matchDebianPattern(pattern, path string) bool {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	ok, err := regexp.MatchString(re.String(), strings.TrimPrefix(path, "./"))
+	return err == nil && ok
+}
+
+// debianExpression returns the SPDX license expression of the short names of
+// the license of a stanza, where "and" and "or" combine licenses, "with X
+// exception" adds an exception, and a comma separates licenses combined
+// after the others, as in "GPL-2+ or Artistic, and BSD-3-clause".
+func debianExpression(names string) string {
+	var expr []string
+	for i, part := range strings.Split(names, ",") {
+		words := strings.Fields(part)
+		if i > 0 {
+			if len(words) == 0 {
+				continue
+			}
+			op := strings.ToUpper(words[0])
+			if op != "AND" && op != "OR" {
+				op = "AND"
+			} else {
+				words = words[1:]
+			}
+			expr = append([]string{"("}, expr...)
+			expr = append(expr, ")", op)
+		}
+
+		expr = append(expr, "(")
+		for j := 0; j < len(words); j++ {
+			switch word := strings.ToLower(words[j]); word {
+			case "and", "or":
+				expr = append(expr, strings.ToUpper(word))
+			case "with":
+				end := j + 1
+				for end < len(words) && !strings.EqualFold(words[end], "exception") {
+					end++
+				}
+				expr = append(expr, "WITH", debianException(strings.Join(words[j+1:end], "-")))
+				j = end
+			default:
+				expr = append(expr, debianLicense(words[j]))
+			}
+		}
+		expr = append(expr, ")")
+	}
+
+	e, err := spdx.Parse(strings.Join(expr, " "))
+	if err != nil {
+		return ""
+	}
+	return e.String()
+}
+
+// debianLicense returns the SPDX license identifier of a short name, with the
+// "+" operator of its later versions, if any, or a LicenseRef if it is not
+// known.
+func debianLicense(name string) string {
+	if id, ok := debianLicenseNames[strings.ToLower(name)]; ok {
+		return id
+	}
+	if m := debianGNURegexp.FindStringSubmatch(name); m != nil {
+		version := m[2]
+		if !strings.Contains(version, ".") {
+			version += ".0"
+		}
+		id := strings.ToUpper(m[1]) + "-" + version
+		if m[3] != "" {
+			id += "-or-later"
+		} else {
+			id += "-only"
+		}
+		if l, ok := spdx.Get(id); ok {
+			return l.ID
+		}
+	}
+	// Short names may leave out the minor version, as in "Apache-2"
+	base := strings.TrimSuffix(name, "+")
+	for _, id := range []string{base, base + ".0"} {
+		if l, ok := spdx.Get(id); ok {
+			return l.ID + strings.TrimPrefix(name, base)
+		}
+	}
+	return "LicenseRef-" + strings.Trim(licenseRefRegexp.ReplaceAllString(name, "-"), "-")
+}
+
+// debianException returns the SPDX license exception identifier of the name
+// of an exception, such as "Classpath", the first on the SPDX license
+// exception list, or an AdditionRef if it is not known.
+func debianException(name string) string {
+	prefix := strings.ToLower(name) + "-exception"
+	for _, e := range spdx.Exceptions() {
+		if id := strings.ToLower(e.ID); id == prefix || strings.HasPrefix(id, prefix+"-") {
+			return e.ID
+		}
+	}
+	return "AdditionRef-" + strings.Trim(licenseRefRegexp.ReplaceAllString(name, "-"), "-") + "-exception"
+}
+
+// debianNames returns the short names of the debian/copyright format of a
+// license type, the reverse of debianExpression.
+func debianNames(licenseType string) string {
+	e, err := spdx.Parse(licenseType)
+	if err != nil {
+		return strings.Trim(licenseRefRegexp.ReplaceAllString(licenseType, "-"), "-")
+	}
+	return debianExprNames(e)
+}
+
+func debianExprNames(e spdx.Expr) string {
+	switch e := e.(type) {
+	case *spdx.Or:
+		return debianExprNames(e.Left) + " or " + debianExprNames(e.Right)
+	case *spdx.And:
+		left, right := debianExprNames(e.Left), debianExprNames(e.Right)
+		if _, ok := e.Right.(*spdx.Or); ok {
+			left, right = right, left
+		}
+		if strings.Contains(left, " or ") {
+			return left + ", and " + right
+		}
+		return left + " and " + right
+	case *spdx.With:
+		name := strings.TrimPrefix(e.Exception, "AdditionRef-")
+		if i := strings.Index(strings.ToLower(name), "-exception"); i > 0 {
+			name = name[:i]
+		}
+		return debianExprNames(e.License) + " with " + name + " exception"
+	case *spdx.Identifier:
+		if name, ok := debianShortNames[e.ID]; ok && !e.OrLater {
+			return name
+		}
+		if m := debianSPDXGNURegexp.FindStringSubmatch(e.ID); m != nil {
+			name := m[1] + "-" + strings.TrimSuffix(m[2], ".0")
+			if m[3] == "-or-later" || e.OrLater {
+				name += "+"
+			}
+			return name
+		}
+		return strings.TrimPrefix(e.String(), "LicenseRef-")
+	}
+	return ""
+}
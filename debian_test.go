@@ -0,0 +1,165 @@
+package license_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+const debianCopyright = `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: example
+Source: https://example.com/example
+
+# The default
+Files: *
+Copyright: 2020 Jane Doe
+           2021 John Doe
+License: GPL-2+
+
+Files: lib/*
+  vendor/*.c
+Copyright: 2019 Example Inc.
+License: Expat or Apache-2.0
+Comment: Relicensed in 2019
+
+Files: vendor/zlib/*
+Copyright: 1995 Jean-loup Gailly and Mark Adler
+License: Zlib
+ This software is provided 'as-is', without any express or implied
+ warranty.
+ .
+ Permission is granted to anyone to use this software for any purpose.
+
+License: GPL-2+
+ This program is free software; you can redistribute it and/or modify
+ it under the terms of the GNU General Public License version 2, or any
+ later version.
+`
+
+func TestParseDebianCopyright(t *testing.T) {
+	c, err := license.ParseDebianCopyright(strings.NewReader(debianCopyright))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if c.UpstreamName != "example" || c.Source != "https://example.com/example" {
+		t.Fatalf("bad header: %#v", c)
+	}
+	if len(c.Files) != 3 || len(c.Licenses) != 1 {
+		t.Fatalf("expected 3 Files and 1 License stanzas, got %d and %d", len(c.Files), len(c.Licenses))
+	}
+	if expected := "2020 Jane Doe\n2021 John Doe"; c.Files[0].Copyright != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, c.Files[0].Copyright)
+	}
+	if expected := "This software is provided 'as-is', without any express or implied\nwarranty.\n\nPermission is granted to anyone to use this software for any purpose."; c.Files[2].Text != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, c.Files[2].Text)
+	}
+	if !strings.HasPrefix(c.LicenseText("gpl-2+"), "This program is free software") {
+		t.Fatalf("bad license text: %q", c.LicenseText("gpl-2+"))
+	}
+
+	for path, expected := range map[string]string{
+		"main.c":                "GPL-2.0-or-later",
+		"lib/sub/util.c":        "MIT OR Apache-2.0",
+		"./vendor/foo.c":        "MIT OR Apache-2.0",
+		"vendor/foo.h":          "GPL-2.0-or-later",
+		"vendor/zlib/inflate.c": "Zlib",
+	} {
+		f := c.Stanza(path)
+		if f == nil {
+			t.Fatalf("%s: no stanza", path)
+		}
+		if f.Type != expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %s", path, expected, f.Type)
+		}
+	}
+
+	for _, text := range []string{"This package was debianized by Jane Doe.\n", "Upstream-Name: example\n"} {
+		if _, err := license.ParseDebianCopyright(strings.NewReader(text)); err != license.ErrNotMachineReadable {
+			t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNotMachineReadable, err)
+		}
+	}
+}
+
+func TestDebianLicenseNames(t *testing.T) {
+	for names, expected := range map[string]string{
+		"GPL-2":                                "GPL-2.0-only",
+		"LGPL-2.1+":                            "LGPL-2.1-or-later",
+		"AGPL-3+":                              "AGPL-3.0-or-later",
+		"GFDL-NIV-1.3":                         "GFDL-1.3-no-invariants-only",
+		"Expat":                                "MIT",
+		"BSD-3-clause":                         "BSD-3-Clause",
+		"Apache-2":                             "Apache-2.0",
+		"Perl":                                 "Artistic-1.0-Perl OR GPL-1.0-or-later",
+		"GPL-2+ or Artistic, and BSD-3-clause": "(GPL-2.0-or-later OR Artistic-1.0) AND BSD-3-Clause",
+		"GPL-2+ with Classpath exception":      "GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"GPL-3+ with OpenSSL exception":        "GPL-3.0-or-later WITH AdditionRef-OpenSSL-exception",
+		"public-domain":                        "LicenseRef-public-domain",
+	} {
+		c, err := license.ParseDebianCopyright(strings.NewReader("Format: " + license.DebianCopyrightFormat + "\n\nFiles: *\nCopyright: Unknown\nLicense: " + names + "\n"))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if got := c.Files[0].Type; got != expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %s", names, expected, got)
+		}
+	}
+}
+
+func TestNewDebianCopyright(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := "Copyright (c) 2020 Jane Doe\n\n" + string(lbytes)
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), []byte(text), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	copyFixture(t, license.LicenseGPL30, filepath.Join(d, "sub", "COPYING"))
+
+	found, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c, err := license.NewDebianCopyright(license.NewReport(found), "example")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	parsed, err := license.ParseDebianCopyright(&buf)
+	if err != nil {
+		t.Fatalf("err: %s\n%s", err, buf.String())
+	}
+	if parsed.UpstreamName != "example" || len(parsed.Files) != 2 || len(parsed.Licenses) != 2 {
+		t.Fatalf("bad file:\n%s", buf.String())
+	}
+	for path, expected := range map[string]string{
+		"main.go":     "Expat",
+		"sub/main.go": "GPL-3",
+	} {
+		f := parsed.Stanza(path)
+		if f == nil || f.License != expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %#v", path, expected, f)
+		}
+	}
+	if f := parsed.Stanza("main.go"); f.Type != license.LicenseMIT || f.Copyright != "Copyright (c) 2020 Jane Doe" {
+		t.Fatalf("bad stanza: %#v", f)
+	}
+	if expected := strings.TrimSpace(string(lbytes)); !strings.HasSuffix(parsed.LicenseText("Expat"), expected) {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, parsed.LicenseText("Expat"))
+	}
+}
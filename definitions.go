@@ -0,0 +1,191 @@
+package license
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nfukasawa/go-license/internal/yaml"
+)
+
+// ErrInvalidDefinition is returned when registering a license definition
+// without an identifier, or without any way to match it.
+var ErrInvalidDefinition = errors.New("license: invalid license definition")
+
+// LicenseDefinition describes a license which is not built in, such as an
+// organization's internal license.
+type LicenseDefinition struct {
+	ID       string   `json:"id" yaml:"id"`                                 // The license type
+	Name     string   `json:"name,omitempty" yaml:"name,omitempty"`         // The full name of the license
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"` // Phrases which must all appear in the text
+	Text     string   `json:"text,omitempty" yaml:"text,omitempty"`         // The full license text, if any
+	Category Category `json:"category,omitempty" yaml:"category,omitempty"` // The category of the license, for Info
+
+	phrases []string            // Normalized patterns
+	bigrams map[string]struct{} // Word bigrams of the text
+}
+
+var (
+	definitionsMu sync.RWMutex
+	definitions   []*LicenseDefinition
+)
+
+// RegisterLicense adds a license type which GuessType reports when all of the
+// given phrases appear in the license text. Phrases are matched the same way
+// as the built-in licenses: case-insensitively, and regardless of line breaks
+// and repeated whitespace. Registered licenses are checked before the built-in
+// ones, and registering an identifier again replaces its definition.
+func RegisterLicense(id string, patterns []string) error {
+	return RegisterLicenseDefinition(&LicenseDefinition{ID: id, Patterns: patterns})
+}
+
+// RegisterLicenseDefinition adds a license type as described by RegisterLicense.
+// If the definition has a full text, it is also compared against by
+// GuessTypeWithConfidence. A definition needs patterns, a text, or both.
+func RegisterLicenseDefinition(def *LicenseDefinition) error {
+	d, err := compileDefinition(def)
+	if err != nil {
+		return err
+	}
+
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+	for i, existing := range definitions {
+		if existing.ID == d.ID {
+			definitions[i] = d
+			return nil
+		}
+	}
+	definitions = append(definitions, d)
+	return nil
+}
+
+// compileDefinition returns a copy of a license definition, with its patterns
+// normalized for matching.
+func compileDefinition(def *LicenseDefinition) (*LicenseDefinition, error) {
+	if def.ID == "" || len(def.Patterns) == 0 && def.Text == "" {
+		return nil, ErrInvalidDefinition
+	}
+
+	d := *def
+	d.phrases = make([]string, 0, len(d.Patterns))
+	for _, pattern := range d.Patterns {
+		if phrase := strings.TrimSpace(collapseSpace(strings.ToLower(pattern))); phrase != "" {
+			d.phrases = append(d.phrases, phrase)
+		}
+	}
+	if len(d.phrases) == 0 && d.Text == "" {
+		return nil, ErrInvalidDefinition
+	}
+	if d.Text != "" {
+		d.bigrams = bigrams(d.Text)
+	}
+	return &d, nil
+}
+
+// LoadLicenseDefinitions reads license definitions from a file, as done by
+// ReadLicenseDefinitions, and registers each of them.
+func LoadLicenseDefinitions(path string) error {
+	defs, err := ReadLicenseDefinitions(path)
+	if err != nil {
+		return err
+	}
+	for i, def := range defs {
+		if err := RegisterLicenseDefinition(def); err != nil {
+			return fmt.Errorf("license: invalid license definitions %s: entry %d: %w", path, i+1, err)
+		}
+	}
+	return nil
+}
+
+// ReadLicenseDefinitions reads license definitions from a JSON or YAML file,
+// such as:
+//
+//	licenses:
+//	  - id: LicenseRef-Acme-1.0
+//	    name: Acme Internal License 1.0
+//	    category: proprietary
+//	    patterns:
+//	      - acme internal license
+//	      - version 1.0
+//	    text: |
+//	      Acme Internal License ...
+//
+// The format is chosen by the file extension, and defaults to YAML.
+func ReadLicenseDefinitions(path string) ([]*LicenseDefinition, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Licenses []*LicenseDefinition `json:"licenses" yaml:"licenses"`
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid license definitions %s: %w", path, err)
+	}
+	return file.Licenses, nil
+}
+
+// registeredDefinitions returns the registered licenses.
+func registeredDefinitions() []*LicenseDefinition {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+	return append([]*LicenseDefinition(nil), definitions...)
+}
+
+// matchDefinitions returns the first of the license definitions whose phrases
+// all appear in the normalized text, and its phrases.
+func matchDefinitions(comp string, defs []*LicenseDefinition) (string, []string, bool) {
+	for _, d := range defs {
+		if len(d.phrases) == 0 {
+			continue
+		}
+		matched := true
+		for _, phrase := range d.phrases {
+			if !scan(comp, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return d.ID, d.phrases, true
+		}
+	}
+	return "", nil, false
+}
+
+// definedLicense returns the registered definition of a license type.
+func definedLicense(id string) (*LicenseDefinition, bool) {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+	for _, d := range definitions {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// definedTexts returns the bigram sets of the registered licenses which have
+// a full text, keyed by license type.
+func definedTexts() map[string]map[string]struct{} {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+	texts := make(map[string]map[string]struct{})
+	for _, d := range definitions {
+		if d.bigrams != nil {
+			texts[d.ID] = d.bigrams
+		}
+	}
+	return texts
+}
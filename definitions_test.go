@@ -0,0 +1,106 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestRegisterLicense(t *testing.T) {
+	if err := license.RegisterLicense("LicenseRef-Example-1.0", []string{
+		"example internal\nlicense", "version 1.0",
+	}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l := license.New("", "The Example Internal License,  Version 1.0\n\nAll use is restricted.")
+	if err := l.GuessType(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "LicenseRef-Example-1.0" || !l.Recognized() {
+		t.Fatalf("\nexpected: LicenseRef-Example-1.0\ngot: %s", l.Type)
+	}
+
+	// All phrases must match
+	l = license.New("", "The Example Internal License, Version 2.0")
+	if err := l.GuessType(); err == nil {
+		t.Fatalf("expected error guessing license with a missing phrase, got %s", l.Type)
+	}
+
+	// Invalid definitions fail properly
+	if err := license.RegisterLicense("", []string{"phrase"}); err != license.ErrInvalidDefinition {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidDefinition, err)
+	}
+	if err := license.RegisterLicense("LicenseRef-Empty", []string{" "}); err != license.ErrInvalidDefinition {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidDefinition, err)
+	}
+}
+
+func TestLoadLicenseDefinitions(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	definitions := map[string]string{
+		"licenses.yaml": `licenses:
+  - id: LicenseRef-Acme-1.0
+    name: Acme License 1.0
+    patterns:
+      - acme corporation license
+    text: |
+      Acme Corporation License
+
+      Permission to use this software is granted to employees of the Acme
+      Corporation only, and may be revoked at any time by the Acme
+      Corporation without notice.
+`,
+		"licenses.json": `{"licenses": [{"id": "LicenseRef-Initech-1.0", "patterns": ["initech license"]}]}`,
+	}
+	for name, content := range definitions {
+		path := filepath.Join(d, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := license.LoadLicenseDefinitions(path); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	for text, expected := range map[string]string{
+		"ACME Corporation License": "LicenseRef-Acme-1.0",
+		"The Initech License":      "LicenseRef-Initech-1.0",
+	} {
+		l := license.New("", text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", expected, l.Type)
+		}
+	}
+
+	// The full text is used for scoring, even without a matching phrase
+	l := license.New("", "Permission to use this software is granted to employees of the "+
+		"Acme Corporation only, and may be revoked at any time without notice.")
+	guess, score, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if guess != "LicenseRef-Acme-1.0" || score < 0.5 {
+		t.Fatalf("unexpected guess: %s %f", guess, score)
+	}
+
+	// Invalid files fail properly
+	path := filepath.Join(d, "invalid.yaml")
+	if err := ioutil.WriteFile(path, []byte("licenses:\n  - name: No ID\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := license.LoadLicenseDefinitions(path); err == nil {
+		t.Fatalf("expected error loading definition without an id")
+	}
+}
@@ -0,0 +1,302 @@
+package license
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// DependencyReport describes the licenses of the modules a Go module depends
+// on, along the graph of package imports, or of the packages installed for a
+// project of another ecosystem, such as those of node_modules.
+type DependencyReport struct {
+	Modules []*ModuleDependency `json:"modules" yaml:"modules"`
+}
+
+// ModuleDependency is a module depended on by the main module, or a package
+// installed for a project.
+type ModuleDependency struct {
+	Path       string     `json:"path" yaml:"path"`                               // The module path, or the package name
+	Version    string     `json:"version,omitempty" yaml:"version,omitempty"`     // The module version, if any
+	Ecosystem  string     `json:"ecosystem,omitempty" yaml:"ecosystem,omitempty"` // The package ecosystem, such as "npm", if not Go modules
+	Dir        string     `json:"dir,omitempty" yaml:"dir,omitempty"`             // The directory or archive the package is installed in, if not a module
+	Direct     bool       `json:"direct" yaml:"direct"`                           // Whether a package of the main module imports the module
+	TestOnly   bool       `json:"testOnly" yaml:"testOnly"`                       // Whether only tests depend on the module
+	Packages   []string   `json:"packages" yaml:"packages"`                       // The packages of the module depended on
+	ImportedBy []string   `json:"importedBy" yaml:"importedBy"`                   // The packages of other modules which import them
+	Licenses   []*License `json:"licenses,omitempty" yaml:"licenses,omitempty"`   // The licenses found in the module directory
+}
+
+// LicenseExposure summarizes how the main module depends on a license type.
+type LicenseExposure struct {
+	Type        string   `json:"type" yaml:"type"`               // The license type
+	Direct      bool     `json:"direct" yaml:"direct"`           // Whether a direct dependency has the license
+	TestOnly    bool     `json:"testOnly" yaml:"testOnly"`       // Whether only test dependencies have the license
+	Modules     []string `json:"modules" yaml:"modules"`         // The modules with the license, as "path@version"
+	ImportPaths []string `json:"importPaths" yaml:"importPaths"` // The packages which introduce the license, by importing those modules
+}
+
+// goListPackage is a package as printed by "go list -json".
+type goListPackage struct {
+	ImportPath string
+	ForTest    string
+	Standard   bool
+	Module     *goListModule
+	Imports    []string
+	Deps       []string
+}
+
+// goListModule is a module as printed by "go list -json".
+type goListModule struct {
+	Path    string
+	Version string
+	Dir     string
+	Main    bool
+	Replace *goListModule
+}
+
+func (m *goListModule) key() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// NewDependencyReportFromDir runs "go list -deps -test -json ./..." in the
+// directory of a Go module, which must have its dependencies downloaded, and
+// creates a dependency report from its output as done by NewDependencyReport.
+func NewDependencyReportFromDir(ctx context.Context, dir string, opts ...Option) (*DependencyReport, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "list", "-deps", "-test", "-json", "./...")
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("license: go list: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return NewDependencyReport(ctx, bytes.NewReader(out), opts...)
+}
+
+// NewDependencyReport reads the output of "go list -deps -test -json", and
+// searches the directory of each module the main module depends on for
+// license files. Modules are direct dependencies if a package of the main
+// module imports one of their packages, and test-only if only the tests of
+// the main module depend on them. Standard library packages are left out.
+func NewDependencyReport(ctx context.Context, r io.Reader, opts ...Option) (*DependencyReport, error) {
+	o := newOptions(opts)
+
+	var pkgs []*goListPackage
+	modules := make(map[string]*goListModule) // Keyed by import path
+	dec := json.NewDecoder(r)
+	for {
+		p := new(goListPackage)
+		if err := dec.Decode(p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("license: invalid go list output: %w", err)
+		}
+		if p.Standard {
+			continue
+		}
+		p.ImportPath = packagePath(p.ImportPath)
+		pkgs = append(pkgs, p)
+		if p.Module != nil {
+			modules[p.ImportPath] = p.Module
+		}
+	}
+
+	// The packages depended on by the main module, and by its tests
+	build := make(map[string]bool)
+	test := make(map[string]bool)
+	direct := make(map[string]bool)
+	for _, p := range pkgs {
+		if !isMainPackage(p) {
+			continue
+		}
+		deps := build
+		if p.ForTest != "" || strings.HasSuffix(p.ImportPath, ".test") {
+			deps = test
+		}
+		for _, dep := range p.Deps {
+			deps[packagePath(dep)] = true
+		}
+		for _, imp := range p.Imports {
+			direct[packagePath(imp)] = true
+		}
+	}
+
+	deps := make(map[string]*ModuleDependency)
+	for _, p := range pkgs {
+		m := modules[p.ImportPath]
+		if m == nil || m.Main || !build[p.ImportPath] && !test[p.ImportPath] {
+			continue
+		}
+		d, ok := deps[m.key()]
+		if !ok {
+			d = &ModuleDependency{Path: m.Path, Version: m.Version, TestOnly: true}
+			deps[m.key()] = d
+		}
+		d.Direct = d.Direct || direct[p.ImportPath]
+		d.TestOnly = d.TestOnly && !build[p.ImportPath]
+		d.Packages = appendUnique(d.Packages, p.ImportPath)
+	}
+	for _, p := range pkgs {
+		from := modules[p.ImportPath]
+		for _, imp := range p.Imports {
+			imp = packagePath(imp)
+			if m := modules[imp]; m != nil && deps[m.key()] != nil && (from == nil || from.key() != m.key()) {
+				deps[m.key()].ImportedBy = appendUnique(deps[m.key()].ImportedBy, p.ImportPath)
+			}
+		}
+	}
+
+	report := &DependencyReport{Modules: []*ModuleDependency{}}
+	for key, d := range deps {
+		sort.Strings(d.Packages)
+		sort.Strings(d.ImportedBy)
+		report.Modules = append(report.Modules, d)
+
+		m := modules[d.Packages[0]]
+		dir := m.Dir
+		if m.Replace != nil {
+			dir = m.Replace.Dir
+		}
+		if dir == "" {
+			continue
+		}
+		ls, err := guessFromDir(ctx, dir, o)
+		switch {
+		case err == nil:
+			d.Licenses = ls
+		case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, os.IsNotExist(err):
+		default:
+			return nil, fmt.Errorf("license: %s: %w", key, err)
+		}
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	return report, nil
+}
+
+// Exposure returns the license types the main module depends on, ordered by
+// type, along with the modules which have them and the packages which import
+// those modules. A license is only test-only if every module with the license
+// is.
+func (r *DependencyReport) Exposure() []*LicenseExposure {
+	exposures := make(map[string]*LicenseExposure)
+	for _, d := range r.Modules {
+		key := d.Path
+		if d.Version != "" {
+			key += "@" + d.Version
+		}
+		for _, l := range d.Licenses {
+			e, ok := exposures[l.Type]
+			if !ok {
+				e = &LicenseExposure{Type: l.Type, TestOnly: true}
+				exposures[l.Type] = e
+			}
+			e.Direct = e.Direct || d.Direct
+			e.TestOnly = e.TestOnly && d.TestOnly
+			e.Modules = appendUnique(e.Modules, key)
+			for _, p := range d.ImportedBy {
+				e.ImportPaths = appendUnique(e.ImportPaths, p)
+			}
+		}
+	}
+
+	result := make([]*LicenseExposure, 0, len(exposures))
+	for _, e := range exposures {
+		sort.Strings(e.ImportPaths)
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+// packageLicenses returns the license declared by the manifest of a package
+// installed in dir, if it declares one, or else the licenses of the license
+// files of the directory, if any are found.
+func packageLicenses(ctx context.Context, dir, manifest string, declared []string, o *options) ([]*License, error) {
+	if licenseType := declaredType(declared); licenseType != "" {
+		return []*License{{Type: licenseType, File: manifest}}, nil
+	}
+	ls, err := guessFromDir(ctx, dir, o)
+	switch {
+	case err == nil:
+		return ls, nil
+	case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, os.IsNotExist(err):
+		return nil, nil
+	}
+	return nil, err
+}
+
+// declaredType returns the license type declared by the licenses of a
+// manifest, as SPDX license expressions or license names, combined into a
+// dual license if there are several, or "" if they declare none, as when
+// referring to a license file instead.
+func declaredType(licenses []string) string {
+	var types []string
+	for _, license := range licenses {
+		license = strings.TrimSpace(license)
+		upper := strings.ToUpper(license)
+		switch {
+		case license == "" || strings.HasPrefix(upper, "SEE LICENSE IN "):
+			continue
+		case upper == "UNLICENSED":
+			license = LicenseProprietary
+		default:
+			if e, err := spdx.Parse(license); err == nil && spdx.Validate(e) == nil {
+				license = e.String()
+			} else {
+				license = licenseFromName(license)
+			}
+		}
+		if indexOf(types, license) < 0 {
+			types = append(types, license)
+		}
+	}
+	switch len(types) {
+	case 0:
+		return ""
+	case 1:
+		return types[0]
+	}
+	return dualLicense(types)
+}
+
+// isMainPackage determines if a package belongs to the main module, including
+// its test variants, which are listed without a module.
+func isMainPackage(p *goListPackage) bool {
+	if p.Module != nil {
+		return p.Module.Main
+	}
+	return p.ForTest != "" || strings.HasSuffix(p.ImportPath, ".test")
+}
+
+// packagePath strips the test variant suffix of an import path, as in
+// "example.com/pkg [example.com/pkg.test]".
+func packagePath(importPath string) string {
+	if i := strings.Index(importPath, " ["); i >= 0 {
+		return importPath[:i]
+	}
+	return importPath
+}
+
+// appendUnique appends s to list, unless list has it already.
+func appendUnique(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,117 @@
+package license_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewDependencyReport(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	licenses := map[string]string{"lib": "MIT", "util": "Apache-2.0", "assert": "MIT"}
+	for dir, ltype := range licenses {
+		text, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := os.MkdirAll(filepath.Join(d, dir), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(d, dir, "LICENSE"), text, 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	module := func(path, version, dir string, main bool) map[string]interface{} {
+		m := map[string]interface{}{"Path": path, "Version": version, "Main": main}
+		if dir != "" {
+			m["Dir"] = filepath.Join(d, dir)
+		}
+		return m
+	}
+	app := module("example.com/app", "", "", true)
+	lib := module("github.com/a/lib", "v1.0.0", "lib", false)
+	util := module("github.com/b/util", "v0.2.0", "util", false)
+	assert := module("github.com/t/assert", "v1.1.0", "assert", false)
+	pkgs := []map[string]interface{}{
+		{"ImportPath": "fmt", "Standard": true},
+		{"ImportPath": "github.com/b/util", "Module": util, "Imports": []string{"fmt"}},
+		{"ImportPath": "github.com/a/lib", "Module": lib, "Imports": []string{"fmt", "github.com/b/util"},
+			"Deps": []string{"fmt", "github.com/b/util"}},
+		{"ImportPath": "github.com/a/lib/extra", "Module": lib, "Imports": []string{"github.com/a/lib"},
+			"Deps": []string{"fmt", "github.com/a/lib", "github.com/b/util"}},
+		{"ImportPath": "example.com/app", "Module": app, "Imports": []string{"github.com/a/lib/extra"},
+			"Deps": []string{"fmt", "github.com/a/lib", "github.com/a/lib/extra", "github.com/b/util"}},
+		{"ImportPath": "github.com/t/assert", "Module": assert, "Imports": []string{"fmt"}},
+		{"ImportPath": "example.com/app [example.com/app.test]", "ForTest": "example.com/app", "Module": app,
+			"Imports": []string{"github.com/a/lib/extra", "github.com/t/assert"},
+			"Deps":    []string{"fmt", "github.com/a/lib", "github.com/a/lib/extra", "github.com/b/util", "github.com/t/assert"}},
+		{"ImportPath": "example.com/app.test", "Imports": []string{"example.com/app [example.com/app.test]"},
+			"Deps": []string{"example.com/app [example.com/app.test]", "fmt", "github.com/t/assert"}},
+	}
+	var out strings.Builder
+	enc := json.NewEncoder(&out)
+	for _, p := range pkgs {
+		if err := enc.Encode(p); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	r, err := license.NewDependencyReport(context.Background(), strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type summary struct {
+		path, version    string
+		direct, testOnly bool
+		packages         []string
+		importedBy       []string
+		ltype            string
+	}
+	expected := []summary{
+		{"github.com/a/lib", "v1.0.0", true, false,
+			[]string{"github.com/a/lib", "github.com/a/lib/extra"}, []string{"example.com/app"}, "MIT"},
+		{"github.com/b/util", "v0.2.0", false, false,
+			[]string{"github.com/b/util"}, []string{"github.com/a/lib"}, "Apache-2.0"},
+		{"github.com/t/assert", "v1.1.0", true, true,
+			[]string{"github.com/t/assert"}, []string{"example.com/app"}, "MIT"},
+	}
+	var got []summary
+	for _, m := range r.Modules {
+		if len(m.Licenses) != 1 {
+			t.Fatalf("%s: unexpected licenses: %v", m.Path, m.Licenses)
+		}
+		got = append(got, summary{m.Path, m.Version, m.Direct, m.TestOnly, m.Packages, m.ImportedBy, m.Licenses[0].Type})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, got)
+	}
+
+	exposure := r.Exposure()
+	expectedExposure := []*license.LicenseExposure{
+		{Type: "Apache-2.0", Modules: []string{"github.com/b/util@v0.2.0"}, ImportPaths: []string{"github.com/a/lib"}},
+		{Type: "MIT", Direct: true, Modules: []string{"github.com/a/lib@v1.0.0", "github.com/t/assert@v1.1.0"},
+			ImportPaths: []string{"example.com/app"}},
+	}
+	if !reflect.DeepEqual(exposure, expectedExposure) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expectedExposure, exposure)
+	}
+
+	// Fails properly on invalid input
+	if _, err := license.NewDependencyReport(context.Background(), strings.NewReader("{")); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,76 @@
+package license
+
+// Detector guesses the type of a license text, for licenses which neither the
+// built-in guessing nor a LicenseDefinition can describe, such as those
+// recognized by a service. Detect sets the Type of l, and optionally its
+// Language and Rider, or returns ErrUnrecognizedLicense to leave the text to
+// the next detector.
+type Detector interface {
+	Detect(l *License) error
+}
+
+// DetectorFunc is a function used as a Detector.
+type DetectorFunc func(l *License) error
+
+// Detect calls f(l).
+func (f DetectorFunc) Detect(l *License) error {
+	return f(l)
+}
+
+// WithDetectors tries the given detectors, in order, before the licenses of
+// WithLicenses and the registered and built-in ones. Guesses of detectors are
+// still as strict as set by WithStrictness.
+func WithDetectors(detectors ...Detector) Option {
+	return func(o *options) {
+		o.detectors = append(append([]Detector{}, o.detectors...), detectors...)
+	}
+}
+
+// guessType guesses the type of the license as done by License.GuessType,
+// trying the detectors and licenses of o first, in place of the registered
+// licenses if o is isolated, and rejecting guesses below its threshold, other
+// than translations.
+func (o *options) guessType(l *License) error {
+	for _, d := range o.detectors {
+		g := &License{Text: l.Text, File: l.File}
+		switch err := d.Detect(g); err {
+		case nil:
+			l.Type, l.Language, l.Rider, l.Reference = g.Type, g.Language, g.Rider, g.Reference
+			return nil
+		case ErrUnrecognizedLicense:
+		default:
+			return err
+		}
+	}
+
+	defs := o.licenses
+	if !o.isolated {
+		defs = append(defs[:len(defs):len(defs)], registeredDefinitions()...)
+	}
+	g := &License{Text: l.Text}
+	if _, err := g.guessDefinedType(defs); err != nil {
+		return err
+	}
+	licenseType, language, rider, reference := g.Type, g.Language, g.Rider, g.Reference
+
+	// Translations are not similar to the canonical text of their license
+	if o.threshold > 0 && language == "" {
+		if canonical := o.canonicalText(licenseType); canonical != nil &&
+			dice(bigrams(prepareText(l.Text)), canonical) < o.threshold {
+			return ErrUnrecognizedLicense
+		}
+	}
+	l.Type, l.Language, l.Rider, l.Reference = licenseType, language, rider, reference
+	return nil
+}
+
+// canonicalText returns the bigram set of the canonical text of a license
+// type, preferring the licenses of o.
+func (o *options) canonicalText(licenseType string) map[string]struct{} {
+	for _, d := range o.licenses {
+		if d.ID == licenseType {
+			return d.bigrams
+		}
+	}
+	return canonicalText(licenseType)
+}
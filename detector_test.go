@@ -0,0 +1,40 @@
+package license_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestWithDetectors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"LICENSE": {Data: []byte("Licensed under the terms of contract 42.")},
+		"COPYING": {Data: []byte("All rights reserved.")},
+	}
+	contract := license.DetectorFunc(func(l *license.License) error {
+		if strings.Contains(l.Text, "contract 42") {
+			l.Type = "LicenseRef-Contract-42"
+			return nil
+		}
+		return license.ErrUnrecognizedLicense
+	})
+
+	ls, err := license.NewLicensesFromFS(fsys, ".", license.WithDetectors(contract))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].Type != license.LicenseUnrecognized || ls[1].Type != "LicenseRef-Contract-42" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	errDown := errors.New("service unavailable")
+	failing := license.DetectorFunc(func(*license.License) error {
+		return errDown
+	})
+	if _, err := license.NewFromFile("LICENSE", license.WithFS(fsys), license.WithDetectors(failing)); err != errDown {
+		t.Fatalf("\nexpected: %s\ngot: %v", errDown, err)
+	}
+}
@@ -0,0 +1,211 @@
+package license
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The number of unchanged sentences shown around each change of a diff
+const diffContext = 2
+
+// The end of a sentence
+var sentenceEndRegexp = regexp.MustCompile(`[.!?]\s+`)
+
+// DiffReport describes how a license text diverges from the canonical text of
+// its license type.
+type DiffReport struct {
+	Type       string  `json:"type" yaml:"type"`                     // The license type compared against
+	Similarity float64 `json:"similarity" yaml:"similarity"`         // The similarity of the texts, as a percentage
+	Diff       string  `json:"diff,omitempty" yaml:"diff,omitempty"` // A unified diff of the divergent sentences, if any
+}
+
+// Diff compares the text of a license against the canonical text of its type,
+// as returned by CanonicalText, so that changes to a license, such as to its
+// warranty disclaimer or patent clauses, can be reviewed. The similarity is
+// scored as done by GuessTypeWithConfidence, and the texts are compared by
+// sentence, ignoring the differences ignored when matching them: whitespace,
+// line wrapping, case, punctuation and copyright notices.
+//
+// ErrUnrecognizedLicense is returned if the type of the license is not known,
+// and ErrNoCanonicalText if there is no canonical text to compare against.
+func Diff(l *License) (*DiffReport, error) {
+	if l == nil || undetermined(l.Type) {
+		return nil, ErrUnrecognizedLicense
+	}
+	licenseType := l.Type
+	if license, _, ok := withException(licenseType); ok {
+		licenseType = license
+	}
+	canonical, err := CanonicalText(licenseType)
+	if err != nil {
+		return nil, err
+	}
+
+	text := prepareText(l.Text)
+	r := &DiffReport{
+		Type:       l.Type,
+		Similarity: 100 * dice(bigrams(text), bigrams(canonical)),
+	}
+
+	name := l.File
+	if name == "" {
+		name = "text"
+	}
+	ops := diffLines(sentences(canonical), sentences(text))
+	r.Diff = unifiedDiff(licenseType, name, ops)
+	return r, nil
+}
+
+// diffLine is a sentence of a text, along with the words it is compared by.
+type diffLine struct {
+	text string
+	key  string
+}
+
+// sentences splits a text into its sentences, leaving out copyright notices.
+func sentences(text string) []diffLine {
+	var lines []diffLine
+	for _, paragraph := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n\n") {
+		var kept []string
+		for _, line := range strings.Split(paragraph, "\n") {
+			line = strings.TrimSpace(line)
+			if !copyrightRegexp.MatchString(strings.ToLower(line)) {
+				kept = append(kept, line)
+			}
+		}
+		paragraph = strings.Join(strings.Fields(strings.Join(kept, " ")), " ")
+
+		var split []string
+		start := 0
+		for _, loc := range sentenceEndRegexp.FindAllStringIndex(paragraph, -1) {
+			split = append(split, paragraph[start:loc[0]+1])
+			start = loc[1]
+		}
+		for _, sentence := range append(split, paragraph[start:]) {
+			if key := strings.Join(normalizeWords(sentence, false), " "); key != "" {
+				lines = append(lines, diffLine{sentence, key})
+			}
+		}
+	}
+	return lines
+}
+
+// diffOp is a sentence which is in both texts (' '), only in the first ('-')
+// or only in the second ('+').
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// diffLines returns the edits which turn a into b, by their longest common
+// subsequence.
+func diffLines(a, b []diffLine) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i].key == b[j].key:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].key == b[j].key:
+			ops = append(ops, diffOp{' ', b[j].text})
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i].text})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j].text})
+			j++
+		}
+	}
+	return ops
+}
+
+// unifiedDiff formats the edits in the unified diff format, with a hunk for
+// each run of changes and the sentences around them, numbered by sentence.
+// There are no hunks if the texts have the same sentences.
+func unifiedDiff(from, to string, ops []diffOp) string {
+	// The sentences of either text preceding each edit
+	before := make([][2]int, len(ops)+1)
+	for k, op := range ops {
+		before[k+1] = before[k]
+		if op.kind != '+' {
+			before[k+1][0]++
+		}
+		if op.kind != '-' {
+			before[k+1][1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Changes closer than twice the context are shown in one hunk
+		start, end := i-diffContext, i
+		if start < 0 {
+			start = 0
+		}
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				if end += diffContext; end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = run
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", from, to)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(before[start][0], before[end][0]-before[start][0]),
+			hunkRange(before[start][1], before[end][1]-before[start][1]))
+		for _, op := range ops[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.text)
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange formats the range of lines of a hunk, starting after the given
+// number of lines.
+func hunkRange(before, count int) string {
+	if count == 1 {
+		return fmt.Sprint(before + 1)
+	}
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
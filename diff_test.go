@@ -0,0 +1,63 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestDiff(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// An unmodified copy, however wrapped, does not diverge
+	var paragraphs []string
+	for _, paragraph := range strings.Split(string(data), "\n\n") {
+		paragraphs = append(paragraphs, strings.Join(strings.Fields(paragraph), " "))
+	}
+	l := &license.License{Type: license.LicenseMIT, Text: strings.Join(paragraphs, "\n\n")}
+	r, err := license.Diff(l)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.Diff != "" || r.Similarity < 99 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+
+	// A modified warranty disclaimer is shown in context
+	modified := strings.Replace(string(data), "IN NO EVENT SHALL THE\nAUTHORS", "IN NO EVENT, EXCEPT FOR GROSS NEGLIGENCE, SHALL THE\nAUTHORS", 1)
+	l = &license.License{Type: license.LicenseMIT, Text: modified, File: "LICENSE"}
+	r, err = license.Diff(l)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.Type != license.LicenseMIT || r.Similarity >= 100 || r.Similarity < 90 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	for _, expected := range []string{
+		"--- MIT\n+++ LICENSE\n@@ -",
+		"\n-IN NO EVENT SHALL THE AUTHORS",
+		"\n+IN NO EVENT, EXCEPT FOR GROSS NEGLIGENCE, SHALL THE AUTHORS",
+		"\n THE SOFTWARE IS PROVIDED",
+	} {
+		if !strings.Contains(r.Diff, expected) {
+			t.Fatalf("missing %q in diff:\n%s", expected, r.Diff)
+		}
+	}
+	if strings.Contains(r.Diff, "Permission is hereby granted") {
+		t.Fatalf("unexpected context in diff:\n%s", r.Diff)
+	}
+
+	// Fails properly without a canonical text to compare against
+	if _, err := license.Diff(license.New(license.LicensePublicDomain, "")); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+	if _, err := license.Diff(license.New(license.LicenseUnrecognized, "")); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected ErrUnrecognizedLicense, got: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package license
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+var (
+	fingerprintsOnce      sync.Once
+	canonicalFingerprints map[string]string // License types by the fingerprint of their canonical text
+)
+
+// fingerprint returns the hex SHA-256 hash of the text of a license, folded to
+// lower case, with typographic characters folded and whitespace collapsed, and
+// leaving out the copyright statements starting its lines, so that copies of a
+// license which differ only in their copyright holders, case or whitespace
+// share a fingerprint. Markup is not stripped, since doing so costs more than
+// guessing most texts.
+func fingerprint(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, line := range strings.Split(strings.ToLower(foldUnicode(text)), "\n") {
+		if line = stripCopyright(strings.TrimSpace(line)); line != "" {
+			b.WriteString(line)
+			b.WriteByte(' ')
+		}
+	}
+	sum := sha256.Sum256([]byte(collapseSpace(b.String())))
+	return hex.EncodeToString(sum[:])
+}
+
+// stripCopyright removes the copyright statement starting a line folded to
+// lower case, up to the end of its first sentence, along with any "all rights
+// reserved" following it, and returns the rest of the line, so that license
+// terms on the same line as a statement are kept. Holders whose names have a
+// period, such as "Foo Inc.", leave the rest of their name in the line, which
+// only makes their copies differ.
+func stripCopyright(line string) string {
+	if !copyrightRegexp.MatchString(line) {
+		return line
+	}
+	loc := sentenceEndRegexp.FindStringIndex(line)
+	if loc == nil {
+		return ""
+	}
+	rest := strings.TrimSpace(line[loc[1]:])
+	if strings.HasPrefix(rest, "all rights reserved") {
+		rest = strings.TrimPrefix(strings.TrimPrefix(rest, "all rights reserved"), ".")
+	}
+	return strings.TrimSpace(rest)
+}
+
+// exactType returns the license type whose canonical text, as found on the
+// SPDX license list, the text is a copy of, save for the copyright statements
+// starting its lines, case and whitespace. Terms following a statement on its
+// line are part of the text compared.
+func exactType(text string) (string, bool) {
+	fingerprintsOnce.Do(func() {
+		canonicalFingerprints = make(map[string]string)
+		for _, l := range spdx.List() {
+			if l.Text != "" {
+				canonicalFingerprints[fingerprint(l.Text)] = knownType(l.ID)
+			}
+		}
+	})
+	licenseType, ok := canonicalFingerprints[fingerprint(text)]
+	return licenseType, ok
+}
@@ -0,0 +1,96 @@
+package license_test
+
+import (
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestGuessType_ExactMatch(t *testing.T) {
+	for _, s := range spdx.List() {
+		if s.Text == "" {
+			continue
+		}
+		text, err := license.CanonicalText(s.ID)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		// Copies with a copyright notice of their own, reformatted by hand,
+		// still match
+		text = "Copyright (c) 2021 Some Body\n\n" + strings.ToUpper(strings.Replace(text, "\n", "\n\n  ", -1))
+
+		l := &license.License{Text: text}
+		licenseType, score, err := l.GuessTypeWithConfidence()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !strings.EqualFold(licenseType, s.ID) || score != 1 {
+			t.Fatalf("\nexpected: %s (1)\ngot: %s (%v)", s.ID, licenseType, score)
+		}
+
+		matches, err := l.GuessTypeWithMatches()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(matches) != 1 || matches[0].Template != licenseType || matches[0].Start != 0 {
+			t.Fatalf("unexpected matches for %s: %v", s.ID, matches)
+		}
+	}
+}
+
+func TestGuessType_ExactMatchCopyright(t *testing.T) {
+	text, err := license.CanonicalText(license.LicenseMIT, license.WithCopyright("Some Body", 2021))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l := &license.License{Text: text}
+	if _, score, err := l.GuessTypeWithConfidence(); err != nil || score != 1 {
+		t.Fatalf("unexpected score: %v (%v)", score, err)
+	}
+}
+
+func TestGuessType_ExactMatchModified(t *testing.T) {
+	text, err := license.CanonicalText(license.LicenseMIT)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l := &license.License{Text: strings.Replace(text, "free of charge", "for a small fee", 1)}
+	licenseType, score, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if licenseType != license.LicenseMIT || score >= 1 {
+		t.Fatalf("\nexpected: %s (<1)\ngot: %s (%v)", license.LicenseMIT, licenseType, score)
+	}
+}
+
+func TestGuessType_ExactMatchCopyrightLine(t *testing.T) {
+	text, err := license.CanonicalText(license.LicenseMIT)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	// Terms added on the line of a copyright statement are not left out
+	l := &license.License{Text: "Copyright (c) 2020 Foo. Use by Bar Corp. is not permitted.\n\n" + text}
+	licenseType, score, err := l.GuessTypeWithConfidence()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if licenseType != license.LicenseMIT || score >= 1 {
+		t.Fatalf("\nexpected: %s (<1)\ngot: %s (%v)", license.LicenseMIT, licenseType, score)
+	}
+}
+
+func BenchmarkGuessType_ExactMatch(b *testing.B) {
+	text, err := license.CanonicalText(license.LicenseMIT, license.WithCopyright("Some Body", 2021))
+	if err != nil {
+		b.Fatalf("err: %s", err)
+	}
+	for i := 0; i < b.N; i++ {
+		l := &license.License{Text: text}
+		if err := l.GuessType(); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package license
+
+import (
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// licenseExceptions are the SPDX license exceptions recognized in license
+// texts, with the phrases which identify them, and the license types they
+// apply to.
+var licenseExceptions = []struct {
+	id       string
+	phrases  []string
+	licenses []string
+}{
+	{
+		id: "LLVM-exception",
+		phrases: []string{
+			"llvm exceptions to the apache 2.0 license",
+			"portions of this software are embedded into an object form of such source code",
+		},
+		licenses: []string{LicenseApache20},
+	},
+	{
+		id: "Classpath-exception-2.0",
+		phrases: []string{
+			"give you permission to link this library with independent modules to produce an executable",
+			`subject to the "classpath" exception`,
+		},
+		licenses: []string{LicenseGPL20, LicenseGPL30},
+	},
+	{
+		id:       "GCC-exception-3.1",
+		phrases:  []string{"gcc runtime library exception version 3.1"},
+		licenses: []string{LicenseGPL30},
+	},
+	{
+		id:       "SHL-2.1",
+		phrases:  []string{"solderpad hardware license version 2.1"},
+		licenses: []string{LicenseApache20},
+	},
+	{
+		id:       "Linux-syscall-note",
+		phrases:  []string{"this copyright does not cover user programs that use kernel services by normal system calls"},
+		licenses: []string{LicenseGPL20},
+	},
+}
+
+// guessException returns the identifier of the license exception to the
+// license type found in the normalized text, if any, and the phrase which
+// identifies it.
+func guessException(comp, licenseType string) (string, string) {
+	base := baseLicenseID(licenseType)
+	for _, e := range licenseExceptions {
+		if !containsType(e.licenses, base) {
+			continue
+		}
+		for _, phrase := range e.phrases {
+			if scan(comp, phrase) {
+				return e.id, phrase
+			}
+		}
+	}
+	return "", ""
+}
+
+// withException splits a license type of the form "<license> WITH
+// <exception>" into the license and the exception.
+func withException(licenseType string) (string, string, bool) {
+	e, err := spdx.Parse(licenseType)
+	if err != nil {
+		return "", "", false
+	}
+	w, ok := e.(*spdx.With)
+	if !ok {
+		return "", "", false
+	}
+	return w.License.String(), w.Exception, true
+}
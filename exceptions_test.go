@@ -0,0 +1,103 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_Exceptions(t *testing.T) {
+	cases := []struct {
+		file     string
+		preamble string
+		appendix string
+		expected string
+	}{
+		{license.LicenseApache20, "", `
+---- LLVM Exceptions to the Apache 2.0 License ----
+
+As an exception, if, as a result of your compiling your source code, portions
+of this Software are embedded into an Object form of such source code, you
+may redistribute such embedded portions in such Object form without complying
+with the conditions of Sections 4(a), 4(b) and 4(d) of the License.
+`, "Apache-2.0 WITH LLVM-exception"},
+		{license.LicenseGPL20, "", `
+Linking this library statically or dynamically with other modules is making
+a combined work based on this library. Thus, the terms and conditions of the
+GNU General Public License cover the whole combination.
+
+As a special exception, the copyright holders of this library give you
+permission to link this library with independent modules to produce an
+executable, regardless of the license terms of these independent modules.
+`, "GPL-2.0 WITH Classpath-exception-2.0"},
+		{license.LicenseGPL20, `   NOTE! This copyright does *not* cover user programs that use kernel
+ services by normal system calls - this is merely considered normal use
+ of the kernel, and does *not* fall under the heading of "derived work".
+
+`, "", "GPL-2.0 WITH Linux-syscall-note"},
+		{license.LicenseGPL30, "", `
+GCC RUNTIME LIBRARY EXCEPTION
+
+Version 3.1, 31 March 2009
+`, "GPL-3.0 WITH GCC-exception-3.1"},
+
+		// Exceptions only apply to their own licenses
+		{license.LicenseMIT, "", `
+GCC RUNTIME LIBRARY EXCEPTION
+
+Version 3.1, 31 March 2009
+`, license.LicenseMIT},
+	}
+	for _, c := range cases {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", c.file))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l := license.New("", c.preamble+string(lbytes)+c.appendix)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if !l.Recognized() {
+			t.Fatalf("%s was not recognized", l.Type)
+		}
+	}
+
+	if l := license.New("Apache-2.0 WITH MyException", ""); l.Recognized() {
+		t.Fatalf("unknown exception was recognized")
+	}
+}
+
+func TestScanSourceFile_Exception(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	path := filepath.Join(d, "Example.java")
+	src := `/*
+ * This code is free software; you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License version 2 only, as
+ * published by the Free Software Foundation.  Oracle designates this
+ * particular file as subject to the "Classpath" exception as provided
+ * by Oracle in the LICENSE file that accompanied this code.
+ */
+package example;
+`
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l, err := license.ScanSourceFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "GPL-2.0-only WITH Classpath-exception-2.0"; l.Type != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, l.Type)
+	}
+}
@@ -0,0 +1,158 @@
+package license
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The number of competing candidates listed by Explain
+const explainCandidates = 5
+
+// Methods by which the type of a license text is decided, as explained by
+// Explain
+const (
+	MethodExact      = "exact"      // The text is a copy of a canonical text
+	MethodDefinition = "definition" // The text matches a registered license
+	MethodPhrases    = "phrases"    // The text contains the phrases identifying a license
+	MethodTemplate   = "template"   // The text matches the template of a license
+	MethodReference  = "reference"  // The text only links to the text of a license
+	MethodNone       = "none"       // The type of the text is not recognized
+)
+
+// GuessExplanation explains how the type of a license text was decided, so
+// that auditors can justify a classification.
+type GuessExplanation struct {
+	Type       string       `json:"type,omitempty" yaml:"type,omitempty"`         // The license type guessed, if any
+	Method     string       `json:"method" yaml:"method"`                         // How the type was decided
+	Language   string       `json:"language,omitempty" yaml:"language,omitempty"` // The language of the text, if it is a translation
+	Rider      string       `json:"rider,omitempty" yaml:"rider,omitempty"`       // The rider appended to the text, if any
+	Matches    []*Match     `json:"matches,omitempty" yaml:"matches,omitempty"`   // The regions of the text which identified the type
+	Candidates []*Candidate `json:"candidates" yaml:"candidates"`                 // The licenses most similar to the text, most similar first
+	Diff       *DiffReport  `json:"diff,omitempty" yaml:"diff,omitempty"`         // The differences from the canonical text of the type, or of the best candidate if none was guessed
+}
+
+// Candidate is a license considered for a text by Explain.
+type Candidate struct {
+	Type       string  `json:"type" yaml:"type"`             // The license type
+	Similarity float64 `json:"similarity" yaml:"similarity"` // The similarity of the text to the canonical text, as scored by GuessTypeWithConfidence
+	Template   bool    `json:"template" yaml:"template"`     // Whether the text matches the template of the license
+}
+
+// Explain guesses the license type the same way GuessType does, and explains
+// the decision: how the type was decided, the phrases matched, as returned by
+// GuessTypeWithMatches, the licenses whose canonical texts are most similar,
+// whether the text matches their templates, and how the text differs
+// from the canonical text of the license, as reported by Diff. A text whose
+// type is not recognized is explained by its nearest candidate instead. The
+// explanation is printed in a human-readable form by its String method.
+func (l *License) Explain() *GuessExplanation {
+	e := &GuessExplanation{Method: MethodNone}
+	matches, err := l.GuessTypeWithMatches()
+	if err == nil {
+		e.Type, e.Language, e.Rider, e.Matches = l.Type, l.Language, l.Rider, matches
+		switch _, exact := exactType(l.Text); {
+		case exact:
+			e.Method = MethodExact
+		case len(matches) == 1 && matches[0].Template != "":
+			e.Method = MethodTemplate
+		case l.Reference:
+			e.Method = MethodReference
+		default:
+			e.Method = MethodPhrases
+			if _, ok := definedLicense(l.Type); ok {
+				e.Method = MethodDefinition
+			}
+		}
+	}
+	e.Candidates = candidates(l.Text)
+
+	compared := e.Type
+	if compared == "" && len(e.Candidates) > 0 {
+		compared = e.Candidates[0].Type
+	}
+	if compared != "" {
+		if d, err := Diff(&License{Type: compared, Text: l.Text, File: l.File}); err == nil {
+			e.Diff = d
+		}
+	}
+	return e
+}
+
+// candidates returns the licenses whose canonical texts are most similar to a
+// text.
+func candidates(text string) []*Candidate {
+	prepared := prepareText(text)
+	set := bigrams(prepared)
+	var cs []*Candidate
+	for _, texts := range []map[string]map[string]struct{}{canonicalTexts(), definedTexts()} {
+		for licenseType, canonical := range texts {
+			if s := dice(set, canonical); s > 0 {
+				cs = append(cs, &Candidate{Type: licenseType, Similarity: s})
+			}
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Similarity != cs[j].Similarity {
+			return cs[i].Similarity > cs[j].Similarity
+		}
+		return cs[i].Type < cs[j].Type
+	})
+	if len(cs) > explainCandidates {
+		cs = cs[:explainCandidates]
+	}
+
+	words := normalizeWords(prepared, false)
+	for _, t := range loadTemplates() {
+		for _, c := range cs {
+			if c.Type == t.id {
+				c.Template = t.match(words)
+			}
+		}
+	}
+	return cs
+}
+
+// String explains the decision in a human-readable form.
+func (e *GuessExplanation) String() string {
+	var b strings.Builder
+	switch e.Method {
+	case MethodExact:
+		fmt.Fprintf(&b, "%s: the text is a copy of its canonical text\n", e.Type)
+	case MethodDefinition:
+		fmt.Fprintf(&b, "%s: the text matches the registered license\n", e.Type)
+	case MethodTemplate:
+		fmt.Fprintf(&b, "%s: the text matches its template\n", e.Type)
+	case MethodPhrases:
+		fmt.Fprintf(&b, "%s: the text contains the phrases identifying it\n", e.Type)
+	case MethodReference:
+		fmt.Fprintf(&b, "%s: the text only links to the license\n", e.Type)
+	default:
+		b.WriteString("unrecognized: the text does not identify any license\n")
+	}
+	if e.Language != "" {
+		fmt.Fprintf(&b, "language: %s\n", e.Language)
+	}
+	if e.Rider != "" {
+		fmt.Fprintf(&b, "rider: %s\n", e.Rider)
+	}
+	if e.Method == MethodPhrases || e.Method == MethodDefinition || e.Method == MethodReference {
+		b.WriteString("\nmatched:\n")
+		for _, m := range e.Matches {
+			fmt.Fprintf(&b, "  line %d: %q\n", m.StartLine, m.Phrase)
+		}
+	}
+
+	b.WriteString("\ncandidates:\n")
+	for _, c := range e.Candidates {
+		template := ""
+		if c.Template {
+			template = ", matches its template"
+		}
+		fmt.Fprintf(&b, "  %s: %.1f%% similar%s\n", c.Type, 100*c.Similarity, template)
+	}
+	if e.Diff != nil && e.Diff.Diff != "" {
+		fmt.Fprintf(&b, "\ndifferences from %s:\n%s", e.Diff.Type, e.Diff.Diff)
+	}
+	return b.String()
+}
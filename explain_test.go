@@ -0,0 +1,51 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestExplain(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	e := (&license.License{Text: string(mit)}).Explain()
+	if e.Type != license.LicenseMIT || e.Method != license.MethodExact || e.Diff == nil || e.Diff.Diff != "" {
+		t.Fatalf("unexpected explanation: %+v", e)
+	}
+	if len(e.Candidates) != 5 || e.Candidates[0].Type != license.LicenseMIT || !e.Candidates[0].Template ||
+		e.Candidates[1].Template || e.Candidates[1].Similarity >= e.Candidates[0].Similarity {
+		t.Fatalf("unexpected candidates: %v", e.Candidates)
+	}
+
+	l := &license.License{Text: string(mit) + "\nThe Software shall be used for Good, not Evil.\n"}
+	e = l.Explain()
+	if e.Method != license.MethodPhrases || len(e.Matches) != 1 || e.Matches[0].Phrase == "" || e.Rider == "" || l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected explanation: %+v", e)
+	}
+	if !strings.Contains(e.Diff.Diff, "+The Software shall be used for Good, not Evil.") {
+		t.Fatalf("unexpected differences: %s", e.Diff.Diff)
+	}
+	s := e.String()
+	for _, expected := range []string{"MIT: the text contains the phrases", "rider: The Software shall", "line 5:", "candidates:\n  MIT:", "differences from MIT:"} {
+		if !strings.Contains(s, expected) {
+			t.Fatalf("\nexpected: %s\ngot: %s", expected, s)
+		}
+	}
+
+	// Unrecognized texts are compared against the nearest candidate
+	e = (&license.License{Text: "Copyright 2020 Jane Doe. You may not sell this software for profit."}).Explain()
+	if e.Type != "" || e.Method != license.MethodNone || len(e.Candidates) == 0 || e.Diff == nil ||
+		e.Diff.Type != e.Candidates[0].Type {
+		t.Fatalf("unexpected explanation: %+v", e)
+	}
+	if s := e.String(); !strings.HasPrefix(s, "unrecognized:") {
+		t.Fatalf("unexpected explanation: %s", s)
+	}
+}
@@ -0,0 +1,24 @@
+// Package extract extracts the plain text of license files in the PDF and RTF
+// formats, as commercial components often ship their licenses in. Importing
+// the package registers its extractors with license.RegisterExtractor, for the
+// ".pdf" and ".rtf" extensions:
+//
+//	import _ "github.com/nfukasawa/go-license/extract"
+package extract
+
+import (
+	"errors"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+var (
+	// Various errors
+	ErrInvalidPDF = errors.New("extract: invalid PDF document")
+	ErrInvalidRTF = errors.New("extract: invalid RTF document")
+)
+
+func init() {
+	license.RegisterSizedExtractor(".pdf", pdfLimited)
+	license.RegisterExtractor(".rtf", RTF)
+}
@@ -0,0 +1,174 @@
+package extract_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/extract"
+)
+
+const rtfMIT = `{\rtf1\ansi\ansicpg1252\deff0{\fonttbl{\f0\fswiss Helvetica;}}{\colortbl;\red0\green0\blue0;}
+{\*\generator Riched20 10.0;}{\info{\title License}}\f0\fs24
+{\b The MIT License}\par
+\par
+Permission is hereby granted, free of charge, to any person obtaining a copy 
+of this software and associated documentation files (the \ldblquote Software\rdblquote ), 
+to deal in the Software without restriction \u8212? see \{below\}.\par
+Copyright \'a9 2024 Acme\~Inc.}`
+
+func TestRTF(t *testing.T) {
+	text, err := extract.RTF([]byte(rtfMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "The MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy " +
+		"of this software and associated documentation files (the “Software”), to deal in the Software without " +
+		"restriction — see {below}.\nCopyright © 2024 Acme Inc."
+	if text != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, text)
+	}
+
+	if _, err := extract.RTF([]byte("The MIT License")); err != extract.ErrInvalidRTF {
+		t.Fatalf("expected ErrInvalidRTF, got: %v", err)
+	}
+	if _, err := extract.RTF([]byte(`{\rtf1 text}}`)); err != extract.ErrInvalidRTF {
+		t.Fatalf("expected ErrInvalidRTF, got: %v", err)
+	}
+
+	// Binary data of a negative length, or past the end of the document
+	for _, doc := range []string{`{\rtf1 hello \bin-8 world}`, `{\rtf1 hello \bin99 world}`} {
+		if _, err := extract.RTF([]byte(doc)); err != nil && err != extract.ErrInvalidRTF {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
+
+// pdf returns a PDF document with a page of the given content, compressed if
+// requested.
+func pdf(content string, compress bool) []byte {
+	stream := []byte(content)
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(stream)
+		w.Close()
+		stream, filter = buf.Bytes(), " /Filter /FlateDecode"
+	}
+
+	var doc bytes.Buffer
+	doc.WriteString("%PDF-1.4\n")
+	doc.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	doc.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	doc.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n")
+	fmt.Fprintf(&doc, "4 0 obj\n<< /Length %d%s >>\nstream\n", len(stream), filter)
+	doc.Write(stream)
+	doc.WriteString("\nendstream\nendobj\n")
+	doc.WriteString("5 0 obj\n<< /Subtype /Image /Length 2 >>\nstream\nBT\nendstream\nendobj\n")
+	doc.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return doc.Bytes()
+}
+
+const pdfContent = `BT
+/F1 12 Tf
+72 720 Td
+(The MIT License) Tj
+0 -28 Td
+[(Permission is hereby granted, free of charge, to any person ) -40 (obtaining a copy)] TJ
+T*
+[(of)-250(this)-250(software)] TJ
+(\(the \223Software\224\)) '
+<FEFF00A9> Tj
+ET`
+
+func TestPDF(t *testing.T) {
+	expected := "The MIT License\nPermission is hereby granted, free of charge, to any person obtaining a copy\n" +
+		"of this software\n(the “Software”)©"
+	for _, compress := range []bool{false, true} {
+		text, err := extract.PDF(pdf(pdfContent, compress))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if text != expected {
+			t.Fatalf("\nexpected: %q\ngot: %q", expected, text)
+		}
+	}
+
+	if _, err := extract.PDF([]byte(rtfMIT)); err != extract.ErrInvalidPDF {
+		t.Fatalf("expected ErrInvalidPDF, got: %v", err)
+	}
+}
+
+func TestPDFSizeLimit(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	// The text follows a megabyte of spaces, which compress to a few bytes
+	path := filepath.Join(d, "LICENSE.pdf")
+	content := strings.Repeat(" ", 1<<20) + "BT\n(This is free and unencumbered software released into the public domain.) Tj\nET"
+	if err := ioutil.WriteFile(path, pdf(content, true), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l, err := license.NewFromFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseUnlicense {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseUnlicense, l.Type)
+	}
+	if l, err := license.NewFromFile(path, license.WithMaxFileSize(1<<16)); err == nil && l.Type == license.LicenseUnlicense {
+		t.Fatalf("expected the text past the size limit to be left out")
+	}
+}
+
+func TestRegisteredExtractors(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	mit, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var content strings.Builder
+	content.WriteString("BT\n")
+	for _, line := range strings.Split(string(mit), "\n") {
+		line = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(line)
+		fmt.Fprintf(&content, "(%s) Tj T*\n", line)
+	}
+	content.WriteString("ET\n")
+
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE.pdf"), pdf(content.String(), true), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l, err := license.NewFromDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, l.Type)
+	}
+
+	path := filepath.Join(d, "LICENSE.rtf")
+	if err := ioutil.WriteFile(path, []byte(rtfMIT), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l, err = license.NewFromFile(path); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || !strings.HasPrefix(l.Text, "The MIT License") {
+		t.Fatalf("unexpected license: %s (%q)", l.Type, l.Text)
+	}
+}
@@ -0,0 +1,351 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/internal/charset"
+)
+
+var (
+	// The start of the data of a stream, which is not the end of a stream
+	pdfStreamRegexp = regexp.MustCompile(`\bstream\r?\n`)
+
+	// The length of a stream, if it is given directly
+	pdfLengthRegexp = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+
+	// Streams which do not hold the content of a page
+	pdfSkippedRegexp = regexp.MustCompile(`/Subtype\s*/Image|/Length1\b|/Type\s*/(?:XRef|ObjStm|Metadata|EmbeddedFile)\b`)
+)
+
+// The kerning, in thousandths of a unit, beyond which a TJ operator is taken
+// to separate words.
+const pdfSpaceKerning = -200
+
+// PDF returns the text shown by the pages of a PDF document, as written by
+// the text operators of its content streams, which are either uncompressed or
+// compressed with the FlateDecode filter. Text is decoded as WinAnsiEncoding,
+// or as UTF-16 if it starts with a byte order mark, so the text of fonts with
+// other encodings is not extracted correctly. Text is ordered as in the
+// content streams, with a newline wherever the position of text is set. The
+// streams decompressed are bounded by license.DefaultMaxFileSize in total.
+func PDF(data []byte) (string, error) {
+	return pdfLimited(data, license.DefaultMaxFileSize)
+}
+
+// pdfLimited returns the text of a PDF document, as done by PDF, with the
+// data decompressed bounded by limit bytes in total, or not if it is 0.
+func pdfLimited(data []byte, limit int64) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", ErrInvalidPDF
+	}
+
+	t := &pdfText{}
+	for _, content := range pdfStreams(data, limit) {
+		t.content(content)
+		t.newline()
+	}
+	return strings.TrimSpace(t.out.String()), nil
+}
+
+// pdfStreams returns the decoded data of the streams of a PDF document which
+// may hold page content, up to limit bytes of decompressed data, if it is not
+// 0, past which the streams are left out.
+func pdfStreams(data []byte, limit int64) [][]byte {
+	var streams [][]byte
+	remaining := limit
+	for _, loc := range pdfStreamRegexp.FindAllIndex(data, -1) {
+		dict := data[:loc[0]]
+		if i := bytes.LastIndex(dict, []byte("obj")); i >= 0 {
+			dict = dict[i:]
+		}
+		start := loc[1]
+
+		end := -1
+		if m := pdfLengthRegexp.FindSubmatch(dict); m != nil && m[2] == nil {
+			if n, err := strconv.Atoi(string(m[1])); err == nil && start+n <= len(data) &&
+				bytes.HasPrefix(bytes.TrimLeft(data[start+n:], "\r\n"), []byte("endstream")) {
+				end = start + n
+			}
+		}
+		if end < 0 {
+			i := bytes.Index(data[start:], []byte("endstream"))
+			if i < 0 {
+				continue
+			}
+			end = start + len(bytes.TrimRight(data[start:start+i], "\r\n"))
+		}
+
+		if pdfSkippedRegexp.Match(dict) {
+			continue
+		}
+		stream := data[start:end]
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			r, err := zlib.NewReader(bytes.NewReader(stream))
+			if err != nil {
+				continue
+			}
+			var src io.Reader = r
+			if limit > 0 {
+				if remaining <= 0 {
+					return streams
+				}
+				src = io.LimitReader(r, remaining)
+			}
+			decoded, err := ioutil.ReadAll(src)
+			remaining -= int64(len(decoded))
+			if err != nil && len(decoded) == 0 {
+				continue
+			}
+			stream = decoded
+		case bytes.Contains(dict, []byte("/Filter")):
+			// Other filters are used for images and fonts
+			continue
+		}
+		streams = append(streams, stream)
+	}
+	return streams
+}
+
+// pdfToken is an operand of a PDF content stream operator.
+type pdfToken struct {
+	text   string     // The text of a string
+	isText bool       // Whether the operand is a string
+	num    float64    // The value of a number
+	array  []pdfToken // The elements of an array
+}
+
+// pdfText holds the text extracted from content streams.
+type pdfText struct {
+	out strings.Builder
+}
+
+// content extracts the text of a content stream.
+func (t *pdfText) content(data []byte) {
+	var operands []pdfToken
+	var arrays [][]pdfToken
+	push := func(tok pdfToken) {
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], tok)
+		} else {
+			operands = append(operands, tok)
+		}
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			var s string
+			s, i = pdfLiteral(data, i+1)
+			push(pdfToken{text: s, isText: true})
+		case c == '<' && i+1 < len(data) && data[i+1] == '<', c == '>' && i+1 < len(data) && data[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(data[i:], '>')
+			if end < 0 {
+				return
+			}
+			push(pdfToken{text: pdfHex(data[i+1 : i+end]), isText: true})
+			i += end + 1
+		case c == '[':
+			arrays = append(arrays, nil)
+			i++
+		case c == ']':
+			i++
+			if len(arrays) > 0 {
+				array := arrays[len(arrays)-1]
+				arrays = arrays[:len(arrays)-1]
+				push(pdfToken{array: array})
+			}
+		case c == '/':
+			i++
+			for i < len(data) && !isPDFSpace(data[i]) && !isPDFDelimiter(data[i]) {
+				i++
+			}
+		case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+			start := i
+			for i < len(data) && (data[i] == '+' || data[i] == '-' || data[i] == '.' || data[i] >= '0' && data[i] <= '9') {
+				i++
+			}
+			n, _ := strconv.ParseFloat(string(data[start:i]), 64)
+			push(pdfToken{num: n})
+		default:
+			start := i
+			for i < len(data) && !isPDFSpace(data[i]) && !isPDFDelimiter(data[i]) {
+				i++
+			}
+			if i == start {
+				i++ // A stray delimiter
+				continue
+			}
+			op := string(data[start:i])
+			if op == "ID" {
+				// The data of an inline image, up to its end
+				end := bytes.Index(data[i:], []byte("EI"))
+				if end < 0 {
+					return
+				}
+				i += end + 2
+			}
+			t.operator(op, operands)
+			operands, arrays = operands[:0], nil
+		}
+	}
+}
+
+// operator writes the text shown by a content stream operator.
+func (t *pdfText) operator(op string, operands []pdfToken) {
+	last := func() pdfToken {
+		if len(operands) == 0 {
+			return pdfToken{}
+		}
+		return operands[len(operands)-1]
+	}
+
+	switch op {
+	case "Tj":
+		t.out.WriteString(last().text)
+	case "'", `"`:
+		t.newline()
+		t.out.WriteString(last().text)
+	case "TJ":
+		for _, tok := range last().array {
+			if tok.isText {
+				t.out.WriteString(tok.text)
+			} else if tok.num < pdfSpaceKerning {
+				t.space()
+			}
+		}
+	case "Td", "TD":
+		if len(operands) == 2 && operands[1].num == 0 {
+			t.space()
+		} else {
+			t.newline()
+		}
+	case "T*", "Tm", "BT", "ET":
+		t.newline()
+	}
+}
+
+// newline ends the line of text written, if any.
+func (t *pdfText) newline() {
+	if s := t.out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		t.out.WriteByte('\n')
+	}
+}
+
+// space separates the text written from the text which follows.
+func (t *pdfText) space() {
+	if s := t.out.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		t.out.WriteByte(' ')
+	}
+}
+
+// pdfLiteral decodes the literal string starting at data[i], after its opening
+// parenthesis, and returns it along with the offset following it.
+func pdfLiteral(data []byte, i int) (string, int) {
+	var s []byte
+	depth := 1
+	for ; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return decodeBytes(s), i + 1
+			}
+		case '\\':
+			if i++; i >= len(data) {
+				break
+			}
+			c = data[i]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7'; j++ {
+						n = n*8 + int(data[i]-'0')
+						i++
+					}
+					i--
+					c = byte(n)
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return decodeBytes(s), i
+}
+
+// pdfHex decodes the digits of a hexadecimal string.
+func pdfHex(digits []byte) string {
+	var hex []byte
+	for _, c := range digits {
+		if !isPDFSpace(c) {
+			hex = append(hex, c)
+		}
+	}
+	if len(hex)%2 == 1 {
+		hex = append(hex, '0')
+	}
+	s := make([]byte, len(hex)/2)
+	for i := range s {
+		b, err := strconv.ParseUint(string(hex[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		s[i] = byte(b)
+	}
+	return decodeBytes(s)
+}
+
+// decodeBytes decodes the bytes of a PDF string, which are UTF-16 if they
+// start with a byte order mark, and Windows-1252 otherwise.
+func decodeBytes(s []byte) string {
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		return charset.DecodeUTF16(s[2:], true)
+	}
+	return charset.DecodeWindows1252(s)
+}
+
+// isPDFSpace determines if c is a PDF white-space character.
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter determines if c is a PDF delimiter character.
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,155 @@
+package extract
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/nfukasawa/go-license/internal/charset"
+)
+
+// Destinations of an RTF document which are not part of its text
+var rtfIgnoredDestinations = map[string]bool{
+	"author": true, "colortbl": true, "datastore": true, "fldinst": true,
+	"filetbl": true, "fonttbl": true, "footer": true, "footerf": true,
+	"footerl": true, "footerr": true, "generator": true, "header": true,
+	"headerf": true, "headerl": true, "headerr": true, "info": true,
+	"latentstyles": true, "listoverridetable": true, "listtable": true,
+	"nonshppict": true, "object": true, "operator": true, "pict": true,
+	"revtbl": true, "rsidtbl": true, "stylesheet": true, "themedata": true,
+	"colorschememapping": true, "title": true, "xmlnstbl": true,
+}
+
+// The characters of RTF control words which stand for one
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": "\t",
+	"emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"emspace": " ", "enspace": " ", "qmspace": " ",
+}
+
+// rtfGroup is the state of a group of an RTF document.
+type rtfGroup struct {
+	skip bool // Whether the text of the group is left out
+	uc   int  // The number of characters following a \u control word
+}
+
+// RTF returns the plain text of an RTF document, leaving out its formatting
+// and the destinations which are not part of its text, such as the font
+// table. Paragraphs and lines end with a newline.
+func RTF(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte(`{\rtf`)) {
+		return "", ErrInvalidRTF
+	}
+
+	var out strings.Builder
+	var stack []rtfGroup
+	group := rtfGroup{uc: 1}
+	fallback := 0 // The characters of a \u control word left to skip
+	emit := func(s string) {
+		if group.skip {
+			return
+		}
+		if fallback > 0 {
+			fallback--
+			return
+		}
+		out.WriteString(s)
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '{':
+			stack = append(stack, group)
+			continue
+		case '}':
+			if len(stack) == 0 {
+				return "", ErrInvalidRTF
+			}
+			group, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			fallback = 0
+			continue
+		case '\r', '\n':
+			continue
+		case '\\':
+		default:
+			emit(string(charset.Windows1252(c)))
+			continue
+		}
+
+		// A control symbol or a control word
+		if i++; i >= len(data) {
+			break
+		}
+		c = data[i]
+		switch {
+		case c == '\'':
+			if i+2 < len(data) {
+				if b, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8); err == nil {
+					emit(string(charset.Windows1252(byte(b))))
+				}
+				i += 2
+			}
+		case c == '*':
+			group.skip = true
+		case c == '\\' || c == '{' || c == '}':
+			emit(string(c))
+		case c == '~':
+			emit(" ")
+		case c == '_':
+			emit("-")
+		case c == '\r' || c == '\n':
+			emit("\n")
+		case isLetter(c):
+			start := i
+			for i < len(data) && isLetter(data[i]) {
+				i++
+			}
+			word := string(data[start:i])
+			paramStart := i
+			if i < len(data) && data[i] == '-' {
+				i++
+			}
+			for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+				i++
+			}
+			param, hasParam := 0, i > paramStart
+			if hasParam {
+				param, _ = strconv.Atoi(string(data[paramStart:i]))
+			}
+			if i >= len(data) || data[i] != ' ' {
+				i-- // The delimiter is part of the text
+			}
+
+			switch {
+			case rtfIgnoredDestinations[word]:
+				group.skip = true
+			case word == "u" && hasParam:
+				if param < 0 {
+					param += 65536
+				}
+				emit(string(rune(param)))
+				if !group.skip {
+					fallback = group.uc
+				}
+			case word == "uc" && hasParam:
+				group.uc = param
+			case word == "bin" && hasParam && param >= 0:
+				// Binary data, which may claim more bytes than are left
+				if i += param; i >= len(data) {
+					i = len(data)
+				}
+			case rtfSymbols[word] != "":
+				emit(rtfSymbols[word])
+			}
+		}
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// isLetter determines if c is an ASCII letter.
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,80 @@
+package license
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nfukasawa/go-license/internal/charset"
+)
+
+// ErrBinaryFile is returned for license files whose content is binary, such
+// as executables, rather than text, unless an extractor is registered for
+// their extension.
+var ErrBinaryFile = errors.New("license: license file is binary")
+
+// Extractor returns the plain text of a license file in a format other than
+// plain text, such as PDF.
+type Extractor func(data []byte) (string, error)
+
+// SizedExtractor is an Extractor which is given the size limit of license
+// files, as set by WithMaxFileSize, or 0 for no limit, to bound the data it
+// decompresses.
+type SizedExtractor func(data []byte, limit int64) (string, error)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]SizedExtractor{}
+)
+
+// RegisterExtractor sets the extractor of the license files with the given
+// file name extension, such as ".pdf", which is matched case-insensitively.
+// The text of such files is extracted before guessing their type, by the
+// functions which read license files. Extractors for PDF and RTF files are
+// registered by importing the extract package:
+//
+//	import _ "github.com/nfukasawa/go-license/extract"
+//
+// A nil extractor removes the extractor of the extension.
+func RegisterExtractor(ext string, fn Extractor) {
+	if fn == nil {
+		RegisterSizedExtractor(ext, nil)
+		return
+	}
+	RegisterSizedExtractor(ext, func(data []byte, limit int64) (string, error) {
+		return fn(data)
+	})
+}
+
+// RegisterSizedExtractor sets the extractor of the license files with the
+// given file name extension, as done by RegisterExtractor, for extractors
+// which bound the data they decompress by the size limit of license files.
+func RegisterSizedExtractor(ext string, fn SizedExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	ext = strings.ToLower(ext)
+	if fn == nil {
+		delete(extractors, ext)
+		return
+	}
+	extractors[ext] = fn
+}
+
+// extractText returns the text of a license file, as extracted by the
+// extractor registered for its extension, if any, or decoded from the text
+// encoding sniffed from its content: UTF-8, UTF-16 or Windows-1252. Binary
+// content fails with ErrBinaryFile. The extractor is given the size limit of
+// license files.
+func extractText(name string, data []byte, limit int64) (string, error) {
+	extractorsMu.RLock()
+	fn, ok := extractors[strings.ToLower(filepath.Ext(name))]
+	extractorsMu.RUnlock()
+	if !ok {
+		if charset.Binary(data) {
+			return "", ErrBinaryFile
+		}
+		return charset.Decode(data), nil
+	}
+	return fn(data, limit)
+}
@@ -0,0 +1,52 @@
+package license_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestRegisterExtractor(t *testing.T) {
+	errCorrupt := errors.New("corrupt")
+	license.RegisterExtractor(".B64", func(data []byte) (string, error) {
+		if !strings.HasPrefix(string(data), "encoded:") {
+			return "", errCorrupt
+		}
+		return strings.TrimPrefix(string(data), "encoded:"), nil
+	})
+	defer license.RegisterExtractor(".b64", nil)
+
+	fsys := fstest.MapFS{
+		"LICENSE.b64": {Data: []byte("encoded:This is free and unencumbered software released into the public domain.")},
+		"COPYING.b64": {Data: []byte("garbage")},
+	}
+	ls, err := license.NewLicensesFromFS(fsys, ".", license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].File != "COPYING.b64" || ls[0].Type != license.LicenseUnrecognized ||
+		ls[1].Type != license.LicenseUnlicense {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
+
+func TestBinaryLicenseFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"LICENSE":     {Data: []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00MIT License")},
+		"LICENSE.txt": {Data: []byte("M\x00I\x00T\x00 \x00L\x00i\x00c\x00e\x00n\x00s\x00e\x00")},
+	}
+	if _, err := license.NewFromFile("LICENSE", license.WithFS(fsys)); err != license.ErrBinaryFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrBinaryFile, err)
+	}
+
+	ls, err := license.NewLicensesFromFS(fsys, ".", license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].File != "LICENSE" || ls[0].Text != "" || ls[1].Text != "MIT License" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
@@ -0,0 +1,94 @@
+package license
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// NewFromReader will read license text from r until EOF, and guess the type of
+// license based on the bytes read, with the given options, as NewFromFile
+// does. At most one byte more than the size limit set by WithMaxFileSize is
+// read, and binary content fails with ErrBinaryFile.
+func NewFromReader(r io.Reader, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+	return guessFromSource(o.ctx, readerSource(r, o), "", o)
+}
+
+// NewFromFS will search a directory of the given file system for well-known
+// and accepted license file names, and if one is found, read in its content
+// and guess the license type. The directory is a slash-separated path as
+// accepted by fs.ReadDir, such as "." for the root of fsys.
+func NewFromFS(fsys fs.FS, dir string, opts ...Option) (*License, error) {
+	return NewFromFSCtx(newOptions(opts).ctx, fsys, dir, opts...)
+}
+
+// NewFromFSCtx is like NewFromFS, but stops with the error of ctx once it is
+// done.
+func NewFromFSCtx(ctx context.Context, fsys fs.FS, dir string, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+	return o.firstRecognized(guessFromFS(ctx, fsys, dir, o))
+}
+
+// NewLicensesFromFS will search a directory of the given file system for
+// well-known and accepted license file names, and if any are found, read in
+// their content and guess the license types.
+func NewLicensesFromFS(fsys fs.FS, dir string, opts ...Option) ([]*License, error) {
+	return NewLicensesFromFSCtx(newOptions(opts).ctx, fsys, dir, opts...)
+}
+
+// NewLicensesFromFSCtx is like NewLicensesFromFS, but stops with the error of
+// ctx once it is done.
+func NewLicensesFromFSCtx(ctx context.Context, fsys fs.FS, dir string, opts ...Option) ([]*License, error) {
+	return guessFromFS(ctx, fsys, dir, newOptions(opts))
+}
+
+// guessFromFS searches a directory of the given file system (non-recursively)
+// for files with well-established names that indicate license content.
+func guessFromFS(ctx context.Context, fsys fs.FS, dir string, o *options) ([]*License, error) {
+	files, err := readFSDirectory(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return guessFromFiles(ctx, files, o, fsSource(fsys, dir))
+}
+
+// fsSource reads the files of a directory of fsys. Symlinks are only known if
+// fsys has an Lstat method, as os.DirFS does.
+func fsSource(fsys fs.FS, dir string) *licenseSource {
+	src := &licenseSource{
+		join: func(name string) string {
+			return path.Join(dir, name)
+		},
+		read: func(name string) ([]byte, error) {
+			return fs.ReadFile(fsys, name)
+		},
+		open: fsys.Open,
+		stat: func(name string) (fs.FileInfo, error) {
+			return fs.Stat(fsys, name)
+		},
+	}
+	if lfs, ok := fsys.(interface {
+		Lstat(name string) (fs.FileInfo, error)
+	}); ok {
+		src.symlink = func(name string) bool {
+			info, err := lfs.Lstat(name)
+			return err == nil && info.Mode()&fs.ModeSymlink != 0
+		}
+	}
+	return src
+}
+
+// readFSDirectory returns the names of the files in a directory of fsys.
+func readFSDirectory(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, len(entries))
+	for pos, entry := range entries {
+		files[pos] = entry.Name()
+	}
+	return files, nil
+}
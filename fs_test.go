@@ -0,0 +1,137 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromReader(t *testing.T) {
+	licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l, err := license.NewFromReader(strings.NewReader(string(licenseText)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected license type: %s", l.Type)
+	}
+	if l.Text != string(licenseText) {
+		t.Fatalf("unexpected license text: %s", l.Text)
+	}
+	if l.File != "" {
+		t.Fatalf("unexpected file path: %s", l.File)
+	}
+
+	// Fails properly if license type is not guessable
+	if _, err := license.NewFromReader(strings.NewReader("No license data")); err == nil {
+		t.Fatalf("expected error guessing license type from non-license text")
+	}
+}
+
+func TestNewFromFS(t *testing.T) {
+	licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"README":            {Data: []byte("Read me")},
+		"sub/LICENSE":       {Data: licenseText},
+		"sub/COPYING":       {Data: []byte("No license data")},
+		"empty/placeholder": {Data: []byte("Nothing here")},
+	}
+
+	l, err := license.NewFromFS(fsys, "sub")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected license type: %s", l.Type)
+	}
+	if l.Text != string(licenseText) {
+		t.Fatalf("unexpected license text: %s", l.Text)
+	}
+	if l.File != "sub/LICENSE" {
+		t.Fatalf("unexpected file path: %s", l.File)
+	}
+
+	ls, err := license.NewLicensesFromFS(fsys, "sub")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].Type != license.LicenseUnrecognized || ls[0].File != "sub/COPYING" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	// Fails properly if the directory contains no license files
+	if _, err := license.NewFromFS(fsys, "empty"); err == nil {
+		t.Fatalf("expected error loading directory without license files")
+	}
+
+	// Fails properly if the directory does not exist
+	if _, err := license.NewFromFS(fsys, "nonexistent"); err == nil {
+		t.Fatalf("expected error loading non-existent directory")
+	}
+}
+
+func TestNewFromReader_Options(t *testing.T) {
+	opts := []license.Option{
+		license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Acme", Patterns: []string{"acme internal terms"}}),
+	}
+	l, err := license.NewFromReader(strings.NewReader("Use is subject to the Acme internal terms."), opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "LicenseRef-Acme" {
+		t.Fatalf("\nexpected: %s\ngot: %s", "LicenseRef-Acme", l.Type)
+	}
+
+	s, err := license.NewScanner(opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l, err = s.FromReader(strings.NewReader("Use is subject to the Acme internal terms.")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "LicenseRef-Acme" {
+		t.Fatalf("\nexpected: %s\ngot: %s", "LicenseRef-Acme", l.Type)
+	}
+}
+
+func TestNewFromReader_Limits(t *testing.T) {
+	licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Only one byte past the size limit is read
+	r := strings.NewReader(string(licenseText))
+	if _, err := license.NewFromReader(r, license.WithMaxFileSize(100)); err != license.ErrFileBudget {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrFileBudget, err)
+	}
+	if r.Len() != len(licenseText)-101 {
+		t.Fatalf("\nexpected: %d\ngot: %d", len(licenseText)-101, r.Len())
+	}
+
+	// Truncated texts are guessed from the bytes up to the limit
+	l, err := license.NewFromReader(strings.NewReader(string(licenseText)),
+		license.WithMaxFileSize(int64(len(licenseText)-10)), license.WithTruncatedFiles())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, l.Type)
+	}
+
+	// Binary content fails properly
+	if _, err := license.NewFromReader(strings.NewReader("\x7fELF\x02\x01\x01\x00\x00\x00")); err != license.ErrBinaryFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrBinaryFile, err)
+	}
+}
@@ -0,0 +1,97 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// pathologicalTexts are inputs which used to take time superlinear in their
+// length, or might: huge repeated tokens, deeply nested markup and invalid
+// UTF-8.
+var pathologicalTexts = []string{
+	strings.Repeat("license ", 20000),
+	strings.Repeat("a", 200000),
+	strings.Repeat("<div>", 20000) + "MIT" + strings.Repeat("</div>", 20000),
+	strings.Repeat("[", 20000) + strings.Repeat("](", 20000) + strings.Repeat(")", 20000),
+	strings.Repeat("**_`", 20000),
+	strings.Repeat("<!-- ", 20000),
+	strings.Repeat("> ", 20000) + "MIT",
+	strings.Repeat("\xff\xfe permission ", 20000),
+	strings.Repeat("some words here\n\n", 20000),
+	strings.Repeat("-----\n", 20000),
+	strings.Repeat("Copyright 2020 Jane Doe\n", 20000),
+	strings.Repeat("Permission is hereby granted, free of charge, to any person obtaining a copy of this software\n", 2000),
+}
+
+func TestGuessType_Pathological(t *testing.T) {
+	// The guesses complete, rather than the test timing out
+	for _, text := range pathologicalTexts {
+		l := license.New("", text)
+		if err := l.GuessType(); err == nil && l.Type == "" {
+			t.Fatalf("%.40q: no type guessed", text)
+		}
+		if _, err := l.GuessTypes(); err != nil && err != license.ErrUnrecognizedLicense {
+			t.Fatalf("err: %s", err)
+		}
+		if _, _, err := license.MatchTemplate(text); err != nil && err != license.ErrUnrecognizedLicense {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
+
+// addSeeds adds the fixtures and the pathological texts to the seed corpus of
+// a fuzz target.
+func addSeeds(f *testing.F) {
+	for _, ltype := range license.KnownLicenses {
+		data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			f.Fatalf("err: %s", err)
+		}
+		f.Add(string(data))
+	}
+	for _, text := range pathologicalTexts {
+		f.Add(text)
+	}
+}
+
+func FuzzGuessType(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, text string) {
+		l := license.New("", text)
+		if err := l.GuessType(); err == nil && l.Type == "" {
+			t.Fatalf("no type guessed")
+		}
+	})
+}
+
+func FuzzGuessTypes(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, text string) {
+		types, err := license.New("", text).GuessTypes()
+		if err != nil {
+			return
+		}
+		for _, licenseType := range types {
+			if licenseType == "" {
+				t.Fatalf("empty type guessed: %v", types)
+			}
+		}
+	})
+}
+
+func FuzzMatchTemplate(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, text string) {
+		id, score, err := license.MatchTemplate(text)
+		if err != nil {
+			return
+		}
+		if id == "" || score <= 0 || score > 1 {
+			t.Fatalf("\nexpected: a type scored in (0, 1]\ngot: %q %v", id, score)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+//go:build ignore
+
+// This program checks the rule table GuessType detects the built-in licenses
+// by, rules.json, against the corpus of license texts of the fixtures, of the
+// SPDX license list and of the LicenseRef licenses, reformats it, and generates
+// the corpus of samples the rules are tested against, fixtures/corpus.json: a
+// sample for each alternative of each rule and its variants, made up of the
+// phrases which identify it. It is invoked by go generate:
+//
+//	go run gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+type rule struct {
+	Type     string     `json:"type"`
+	Match    [][]string `json:"match"`
+	Grant    string     `json:"grant,omitempty"`
+	Version  string     `json:"version,omitempty"`
+	Variants []*rule    `json:"variants,omitempty"`
+	Comment  string     `json:"comment,omitempty"`
+}
+
+type table struct {
+	Rules []*rule `json:"rules"`
+}
+
+type sample struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func main() {
+	rules := flag.String("rules", "rules.json", "rule table")
+	out := flag.String("out", filepath.Join("fixtures", "corpus.json"), "output file of the samples")
+	corpus := flag.String("corpus", "fixtures/licenses,spdx/text,text", "comma-separated directories of license texts, named by their types")
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(*rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var t table
+	if err := json.Unmarshal(data, &t); err != nil {
+		log.Fatalf("%s: %s", *rules, err)
+	}
+	texts, err := readCorpus(strings.Split(*corpus, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var samples []sample
+	matched := map[string]bool{}
+	for i, r := range t.Rules {
+		if err := check(r, texts, matched); err != nil {
+			log.Fatalf("%s: rule %d: %s", *rules, i+1, err)
+		}
+		samples = append(samples, samplesOf(r, nil)...)
+	}
+	for id, ok := range matched {
+		if !ok {
+			log.Fatalf("%s: no rule of %s matches its corpus text", *rules, id)
+		}
+	}
+
+	if err := write(*rules, t); err != nil {
+		log.Fatal(err)
+	}
+	if err := write(*out, samples); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d rules, %d samples\n", len(t.Rules), len(samples))
+}
+
+// readCorpus reads the license texts of the directories, lower cased and with
+// whitespace collapsed, by their license types.
+func readCorpus(dirs []string) (map[string]string, error) {
+	texts := map[string]string{}
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+			if err != nil {
+				return nil, err
+			}
+			id := strings.ToLower(strings.TrimSuffix(f.Name(), ".txt"))
+			if _, ok := texts[id]; !ok {
+				texts[id] = strings.Join(strings.Fields(strings.ToLower(string(data))), " ")
+			}
+		}
+	}
+	return texts, nil
+}
+
+// check validates a rule, and records in matched whether the corpus text of
+// its type, if any, contains the phrases of one of its alternatives, since a
+// rule may detect a license by a text wrapping it.
+func check(r *rule, texts map[string]string, matched map[string]bool) error {
+	if r.Type == "" || len(r.Match) == 0 {
+		return fmt.Errorf("no type or phrases")
+	}
+	for _, phrases := range r.Match {
+		if len(phrases) == 0 {
+			return fmt.Errorf("%s: empty alternative", r.Type)
+		}
+	}
+	switch r.Grant {
+	case "":
+	case "gnu", "gfdl":
+		if r.Version == "" {
+			return fmt.Errorf("%s: no version for the %s grant", r.Type, r.Grant)
+		}
+	default:
+		return fmt.Errorf("%s: unknown grant %q", r.Type, r.Grant)
+	}
+	for _, v := range r.Variants {
+		if err := check(v, texts, matched); err != nil {
+			return err
+		}
+	}
+
+	id := strings.ToLower(r.Type)
+	text, ok := texts[id]
+	if !ok {
+		return nil
+	}
+	for _, phrases := range r.Match {
+		found := true
+		for _, phrase := range phrases {
+			found = found && strings.Contains(text, phrase)
+		}
+		matched[id] = matched[id] || found
+	}
+	return nil
+}
+
+// samplesOf returns a sample for each alternative of a rule and its variants,
+// following the phrases of the rules they are variants of.
+func samplesOf(r *rule, parent []string) []sample {
+	var samples []sample
+	for _, phrases := range r.Match {
+		text := append(append([]string(nil), parent...), phrases...)
+		samples = append(samples, sample{Type: r.Type, Text: strings.Join(text, "\n\n") + "\n"})
+	}
+	for _, v := range r.Variants {
+		samples = append(samples, samplesOf(v, append(append([]string(nil), parent...), r.Match[0]...))...)
+	}
+	return samples
+}
+
+// write writes v to a file as indented JSON.
+func write(name string, v interface{}) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, b.Bytes(), 0644)
+}
@@ -0,0 +1,83 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// versionSuffixRegexp matches the version and variant at the end of a license
+// identifier, such as the "-2.0" of "Apache-2.0".
+var versionSuffixRegexp = regexp.MustCompile(`-v?[0-9].*$`)
+
+// Generate returns the contents of a LICENSE file for the license, with the
+// copyright holder and year filled in where the text has placeholders for
+// them, as done by CanonicalText. Texts which carry their own copyright, such
+// as those of the Apache and GNU licenses, are left as is.
+func Generate(id, holder string, year int) (string, error) {
+	text, err := CanonicalText(id, WithCopyright(holder, year))
+	if err != nil {
+		return "", err
+	}
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	return strings.TrimRight(text, " \t\n") + "\n", nil
+}
+
+// GenerateFiles returns the LICENSE files for an SPDX license expression,
+// keyed by file name. A single license is written to LICENSE. Each license of
+// a dual or multi-licensed project is written to a file of its own, named
+// after the license, such as LICENSE-MIT and LICENSE-APACHE for
+// "MIT OR Apache-2.0".
+func GenerateFiles(expression, holder string, year int) (map[string]string, error) {
+	e, err := spdx.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, id := range spdx.Licenses(e) {
+		if !containsType(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	names := licenseFileNames(ids)
+
+	files := make(map[string]string, len(ids))
+	for i, id := range ids {
+		text, err := Generate(id, holder, year)
+		if err != nil {
+			return nil, err
+		}
+		files[names[i]] = text
+	}
+	return files, nil
+}
+
+// licenseFileNames names the LICENSE files of the licenses. The name of a
+// license file is its identifier without the version, unless two licenses
+// would share it. A license with an exception is named after the license.
+func licenseFileNames(ids []string) []string {
+	if len(ids) == 1 {
+		return []string{"LICENSE"}
+	}
+	ids = append([]string(nil), ids...)
+	short := make([]string, len(ids))
+	count := make(map[string]int)
+	for i, id := range ids {
+		if license, _, ok := withException(id); ok {
+			ids[i] = license
+		}
+		short[i] = strings.ToUpper(versionSuffixRegexp.ReplaceAllString(ids[i], ""))
+		count[short[i]]++
+	}
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		if count[short[i]] > 1 {
+			names[i] = "LICENSE-" + strings.ToUpper(id)
+		} else {
+			names[i] = "LICENSE-" + short[i]
+		}
+	}
+	return names
+}
@@ -0,0 +1,86 @@
+package license_test
+
+import (
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGenerate(t *testing.T) {
+	text, err := license.Generate("MIT", "Acme Inc.", 2024)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(text, "The MIT License (MIT)\n\nCopyright (c) 2024 Acme Inc.\n") || strings.HasSuffix(text, "\n\n") {
+		t.Fatalf("unexpected LICENSE file:\n%s", text)
+	}
+	l := license.New("", text)
+	if err := l.GuessType(); err != nil || l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected type of generated file: %s (%v)", l.Type, err)
+	}
+
+	// Licenses whose text the SPDX license list publishes apart from its
+	// original 26, and licenses with an exception, are generated as well
+	for _, c := range []struct {
+		id, contains string
+	}{
+		{license.LicenseEPL20, "Eclipse Public License - v 2.0"},
+		{"Apache-2.0 WITH LLVM-exception", "---- LLVM Exceptions to the Apache 2.0 License ----"},
+	} {
+		text, err := license.Generate(c.id, "Acme Inc.", 2024)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.id, err)
+		}
+		if !strings.Contains(text, c.contains) {
+			t.Fatalf("%s: unexpected LICENSE file:\n%s", c.id, text)
+		}
+		l := license.New("", text)
+		if err := l.GuessType(); err != nil || l.Type != c.id {
+			t.Fatalf("%s: unexpected type of generated file: %s (%v)", c.id, l.Type, err)
+		}
+	}
+
+	if _, err := license.Generate("MyLicense", "Acme Inc.", 2024); err != license.ErrNoCanonicalText {
+		t.Fatalf("expected ErrNoCanonicalText, got: %v", err)
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	for expr, expected := range map[string][]string{
+		"MIT":                           {"LICENSE"},
+		"MIT OR Apache-2.0":             {"LICENSE-MIT", "LICENSE-APACHE"},
+		"GPL-2.0-only OR GPL-3.0-only":  {"LICENSE-GPL-2.0-ONLY", "LICENSE-GPL-3.0-ONLY"},
+		"MIT AND (MIT OR BSD-3-Clause)": {"LICENSE-MIT", "LICENSE-BSD"},
+		"EPL-2.0":                       {"LICENSE"},
+		"EPL-2.0 OR GPL-2.0-or-later WITH Classpath-exception-2.0":  {"LICENSE-EPL", "LICENSE-GPL"},
+		"GPL-2.0-only WITH Classpath-exception-2.0 OR GPL-3.0-only": {"LICENSE-GPL-2.0-ONLY", "LICENSE-GPL-3.0-ONLY"},
+	} {
+		files, err := license.GenerateFiles(expr, "Acme Inc.", 2024)
+		if err != nil {
+			t.Fatalf("%s: err: %s", expr, err)
+		}
+		if len(files) != len(expected) {
+			t.Fatalf("%s: unexpected files: %d", expr, len(files))
+		}
+		for _, name := range expected {
+			if files[name] == "" {
+				t.Fatalf("%s: missing %s", expr, name)
+			}
+		}
+	}
+
+	// The file of a license with an exception ends with the exception
+	files, err := license.GenerateFiles("EPL-2.0 OR GPL-2.0-or-later WITH Classpath-exception-2.0", "Acme Inc.", 2024)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(files["LICENSE-GPL"], "GNU GENERAL PUBLIC LICENSE") ||
+		!strings.Contains(files["LICENSE-GPL"], "permission to link this library with independent modules") {
+		t.Fatalf("unexpected LICENSE-GPL:\n%s", files["LICENSE-GPL"])
+	}
+
+	if _, err := license.GenerateFiles("MIT OR", "Acme Inc.", 2024); err == nil {
+		t.Fatalf("expected an error for an invalid expression")
+	}
+}
@@ -0,0 +1,10 @@
+package license
+
+// Licenses of hardware designs. The Solderpad Hardware License wraps the
+// Apache-2.0, and is reported as its exception.
+const (
+	LicenseCERNOHLP20  = "CERN-OHL-P-2.0"
+	LicenseCERNOHLW20  = "CERN-OHL-W-2.0"
+	LicenseCERNOHLS20  = "CERN-OHL-S-2.0"
+	LicenseSolderpad21 = "Apache-2.0 WITH SHL-2.1"
+)
@@ -0,0 +1,43 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_Hardware(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`CERN Open Hardware Licence Version 2 - Permissive
+
+Preamble
+
+CERN has developed this licence to promote collaboration among hardware
+designers and to provide a legal tool which supports the freedom to use,
+study, modify, share and distribute hardware designs and products based on
+those designs.`, license.LicenseCERNOHLP20},
+		{`CERN Open Hardware Licence Version 2 - Weakly Reciprocal`, license.LicenseCERNOHLW20},
+		{`CERN Open Hardware Licence Version 2 - Strongly Reciprocal`, license.LicenseCERNOHLS20},
+		{`SOLDERPAD HARDWARE LICENSE version 2.1
+
+This license operates as a wraparound license to the Apache License Version
+2.0 (the "Apache License") and incorporates the terms and conditions of the
+Apache License (which can be found here: http://apache.org/licenses/LICENSE-2.0),
+with the following additions and modifications.`, license.LicenseSolderpad21},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if _, ok := license.Obligations(l.Type); !ok {
+			t.Fatalf("%s: no obligations", l.Type)
+		}
+	}
+}
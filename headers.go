@@ -0,0 +1,470 @@
+package license
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nfukasawa/go-license/internal/charset"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// commentStyle describes the comment syntax of a programming language.
+type commentStyle struct {
+	line       []string // Prefixes of line comments, if any
+	blockStart string   // Start of block comments, if any
+	blockEnd   string   // End of block comments, if any
+	prologs    []string // Prefixes of a first line which may precede the header, other than "#!"
+	docstring  bool     // Whether the header may continue in a docstring, as in Python
+}
+
+var (
+	cStyle          = &commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle       = &commentStyle{line: []string{"#"}}
+	pythonStyle     = &commentStyle{line: []string{"#"}, docstring: true}
+	dashStyle       = &commentStyle{line: []string{"--"}}
+	sqlStyle        = &commentStyle{line: []string{"--"}, blockStart: "/*", blockEnd: "*/"}
+	haskellStyle    = &commentStyle{line: []string{"--"}, blockStart: "{-", blockEnd: "-}"}
+	luaStyle        = &commentStyle{line: []string{"--"}, blockStart: "--[[", blockEnd: "]]"}
+	percentStyle    = &commentStyle{line: []string{"%"}}
+	lispStyle       = &commentStyle{line: []string{";"}}
+	fortranStyle    = &commentStyle{line: []string{"!"}}
+	cssStyle        = &commentStyle{blockStart: "/*", blockEnd: "*/"}
+	mlStyle         = &commentStyle{blockStart: "(*", blockEnd: "*)"}
+	fsharpStyle     = &commentStyle{line: []string{"//"}, blockStart: "(*", blockEnd: "*)"}
+	juliaStyle      = &commentStyle{line: []string{"#"}, blockStart: "#=", blockEnd: "=#"}
+	powershellStyle = &commentStyle{line: []string{"#"}, blockStart: "<#", blockEnd: "#>"}
+	terraformStyle  = &commentStyle{line: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/"}
+	phpStyle        = &commentStyle{line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/", prologs: []string{"<?php"}}
+	markupStyle     = &commentStyle{blockStart: "<!--", blockEnd: "-->", prologs: []string{"<?xml", "<!doctype"}}
+)
+
+// The comment styles of known source file extensions
+var commentStyles = map[string]*commentStyle{
+	".go":   cStyle,
+	".c":    cStyle,
+	".h":    cStyle,
+	".cc":   cStyle,
+	".cpp":  cStyle,
+	".hpp":  cStyle,
+	".js":   cStyle,
+	".mjs":  cStyle,
+	".ts":   cStyle,
+	".java": cStyle,
+	".py":   pythonStyle,
+	".sh":   hashStyle,
+	".rb":   hashStyle,
+	".pl":   hashStyle,
+	".pm":   hashStyle,
+
+	".cxx":    cStyle,
+	".hh":     cStyle,
+	".hxx":    cStyle,
+	".cs":     cStyle,
+	".cjs":    cStyle,
+	".jsx":    cStyle,
+	".tsx":    cStyle,
+	".kt":     cStyle,
+	".kts":    cStyle,
+	".swift":  cStyle,
+	".scala":  cStyle,
+	".groovy": cStyle,
+	".gradle": cStyle,
+	".rs":     cStyle,
+	".dart":   cStyle,
+	".m":      cStyle,
+	".mm":     cStyle,
+	".proto":  cStyle,
+	".sol":    cStyle,
+	".zig":    cStyle,
+	".v":      cStyle,
+	".sv":     cStyle,
+	".css":    cssStyle,
+	".scss":   cStyle,
+	".less":   cStyle,
+	".php":    phpStyle,
+
+	".bash":    hashStyle,
+	".zsh":     hashStyle,
+	".fish":    hashStyle,
+	".r":       hashStyle,
+	".ex":      hashStyle,
+	".exs":     hashStyle,
+	".nim":     hashStyle,
+	".tcl":     hashStyle,
+	".cmake":   hashStyle,
+	".mk":      hashStyle,
+	".yml":     hashStyle,
+	".yaml":    hashStyle,
+	".toml":    hashStyle,
+	".graphql": hashStyle,
+	".jl":      juliaStyle,
+	".ps1":     powershellStyle,
+	".psm1":    powershellStyle,
+	".tf":      terraformStyle,
+
+	".sql":  sqlStyle,
+	".hs":   haskellStyle,
+	".elm":  haskellStyle,
+	".lua":  luaStyle,
+	".adb":  dashStyle,
+	".ads":  dashStyle,
+	".vhd":  dashStyle,
+	".vhdl": dashStyle,
+	".erl":  percentStyle,
+	".hrl":  percentStyle,
+	".tex":  percentStyle,
+	".lisp": lispStyle,
+	".el":   lispStyle,
+	".clj":  lispStyle,
+	".cljs": lispStyle,
+	".scm":  lispStyle,
+	".f90":  fortranStyle,
+	".f95":  fortranStyle,
+	".ml":   mlStyle,
+	".mli":  mlStyle,
+	".fs":   fsharpStyle,
+	".html": markupStyle,
+	".htm":  markupStyle,
+	".xml":  markupStyle,
+	".svg":  markupStyle,
+	".vue":  markupStyle,
+}
+
+// The comment styles of source files known by their names, lower-cased,
+// rather than their extensions
+var commentStyleNames = map[string]*commentStyle{
+	"makefile":       hashStyle,
+	"gnumakefile":    hashStyle,
+	"dockerfile":     hashStyle,
+	"cmakelists.txt": hashStyle,
+	"rakefile":       hashStyle,
+	"gemfile":        hashStyle,
+	"jenkinsfile":    cStyle,
+}
+
+// sourceStyle returns the comment style of a source file, by its name or
+// else its extension, if it is known.
+func sourceStyle(name string) (*commentStyle, bool) {
+	base := strings.ToLower(filepath.Base(name))
+	if style, ok := commentStyleNames[base]; ok {
+		return style, true
+	}
+	style, ok := commentStyles[filepath.Ext(base)]
+	return style, ok
+}
+
+// prolog determines if the first line of a source file, trimmed, precedes its
+// header, such as a "#!" line or the "<?php" opening tag.
+func (s *commentStyle) prolog(line string) bool {
+	if strings.HasPrefix(line, "#!") {
+		return true
+	}
+	lower := strings.ToLower(line)
+	for _, p := range s.prologs {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanSourceFile will read the comments at the top of a source file, and guess
+// the license declared by them. Recognized headers are SPDX-License-Identifier
+// tags, the boilerplate notices recommended by common licenses such as
+// Apache-2.0 and the GPL family, one-line statements such as "Licensed under
+// the MIT license", and complete license texts. For SPDX tags, the type of the
+// license is the normalized license expression, and tags using identifiers not
+// on the SPDX license list return an error. GNU notices are told apart by
+// whether they grant any later version of the license.
+//
+// Headers which only refer to a license file, such as "Use of this source code
+// is governed by a BSD-style license that can be found in the LICENSE file",
+// are guessed as the license of that file, flagged as their Reference. The
+// file is looked for in the directory of the source file, and then in those
+// above it, up to the root of its repository, and ErrNoLicenseFile is returned
+// if there is none.
+//
+// The comment syntax is chosen by the file name, such as Makefile, or else its
+// extension, among those of more than 25 languages, and source files of an
+// unknown type return ErrUnknownSourceType. The module docstring of Python
+// files is read as part of their header.
+//
+// The options apply as for NewFromFile: the file is read from the file system
+// set by WithFS, if any, within the size limit set by WithMaxFileSize, and
+// headers which are complete license texts are guessed with the detectors and
+// licenses configured.
+func ScanSourceFile(path string, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	style, ok := sourceStyle(path)
+	if !ok {
+		return nil, ErrUnknownSourceType
+	}
+
+	src := o.fileSource(path)
+	if o.exceedsMaxSize(src, path) {
+		o.skipFile()
+		return nil, ErrFileBudget
+	}
+	data, err := o.readFile(src, path)
+	if err != nil {
+		return nil, err
+	}
+	if charset.Binary(data) {
+		return nil, ErrBinaryFile
+	}
+
+	header := extractHeader(charset.Decode(data), style)
+	if strings.TrimSpace(header) == "" {
+		return nil, ErrNoLicenseHeader
+	}
+
+	l := &License{
+		Text: header,
+		File: path,
+	}
+	guess := o.guesser()
+	err = o.budgetedGuess(func(l *License) error { return l.guessHeader(guess) }, l)
+	if err == ErrUnrecognizedLicense {
+		// Headers such as Go's "Use of this source code is governed by a
+		// BSD-style license that can be found in the LICENSE file" only refer
+		// to a license file
+		if m := licenseFileRegexp.FindStringSubmatch(strings.Join(strings.Fields(header), " ")); m != nil {
+			ref, err := referencedLicense(path, m[1], o)
+			if err != nil {
+				return nil, err
+			}
+			l.Type, l.Language, l.Rider, l.Reference = ref.Type, ref.Language, ref.Rider, true
+			return l, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// licenseFileRegexp matches the statements of license headers which refer to
+// a license file, such as "license that can be found in the LICENSE file".
+var licenseFileRegexp = regexp.MustCompile(`(?i)\b(?:found in|see) (?:the )?([\w.-]*(?:licen[cs]e|copying)[\w.-]*) file\b`)
+
+// referencedLicense guesses the license of the file named by the header of a
+// source file, in the directory of the source file or the closest one above
+// it, up to the root of its repository.
+func referencedLicense(source, name string, o *options) (*License, error) {
+	dir, parent, join := filepath.Dir(source), filepath.Dir, filepath.Join
+	if o.fsys != nil {
+		dir, parent, join = path.Dir(source), path.Dir, path.Join
+	}
+	for {
+		file := join(dir, name)
+		src := o.fileSource(file)
+		if info, err := src.stat(file); err == nil && !info.IsDir() {
+			return guessFromSource(o.ctx, src, file, o)
+		}
+		if parent(dir) == dir || isRepoRoot(src, join, dir) {
+			return nil, ErrNoLicenseFile
+		}
+		dir = parent(dir)
+	}
+}
+
+// isRepoRoot determines if dir holds the metadata of a version control system.
+func isRepoRoot(src *licenseSource, join func(...string) string, dir string) bool {
+	for _, name := range vcsDirs {
+		if _, err := src.stat(join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// extractHeader returns the text of the comments preceding the first line of
+// code, with the comment markers removed.
+func extractHeader(src string, style *commentStyle) string {
+	header, _ := splitHeader(src, style)
+	return header
+}
+
+// splitHeader returns the text of the comments preceding the first line of
+// code, as extractHeader does, and the number of lines before that line.
+func splitHeader(src string, style *commentStyle) (string, int) {
+	var header []string
+	inBlock := false
+	docstring := style.docstring
+	quotes := "" // The quotes closing the docstring, while in it
+
+	lines := strings.Split(src, "\n")
+	n := len(lines)
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if quotes != "" {
+			if end := strings.Index(line, quotes); end >= 0 {
+				line, quotes = line[:end], ""
+			}
+			header = append(header, strings.TrimSpace(line))
+			continue
+		}
+		if inBlock {
+			if end := strings.Index(line, style.blockEnd); end >= 0 {
+				line, inBlock = line[:end], false
+			}
+			header = append(header, strings.TrimSpace(strings.TrimPrefix(line, "*")))
+			continue
+		}
+
+		switch {
+		case line == "":
+			header = append(header, "")
+			continue
+		case i == 0 && style.prolog(line):
+			continue
+		case docstring && docstringQuotes(line) != "":
+			// Only the first docstring, that of the module, is read.
+			docstring, quotes = false, docstringQuotes(line)
+			line = line[strings.Index(line, quotes)+len(quotes):]
+			if end := strings.Index(line, quotes); end >= 0 {
+				line, quotes = line[:end], ""
+			}
+			header = append(header, strings.TrimSpace(line))
+			continue
+		case style.blockStart != "" && strings.HasPrefix(line, style.blockStart):
+			line = strings.TrimLeft(line[len(style.blockStart):], "*")
+			if end := strings.Index(line, style.blockEnd); end >= 0 {
+				line = line[:end]
+			} else {
+				inBlock = true
+			}
+			header = append(header, strings.TrimSpace(line))
+			continue
+		}
+
+		comment := false
+		for _, prefix := range style.line {
+			if strings.HasPrefix(line, prefix) {
+				line = strings.TrimLeft(line[len(prefix):], prefix[len(prefix)-1:])
+				header = append(header, strings.TrimSpace(line))
+				comment = true
+				break
+			}
+		}
+		if !comment {
+			n = i
+			break
+		}
+	}
+	return strings.Join(header, "\n"), n
+}
+
+// docstringQuotes returns the quotes opening a docstring on line, if any. The
+// docstring may be a raw or Unicode string literal.
+func docstringQuotes(line string) string {
+	if len(line) > 0 && strings.ContainsRune("rRuU", rune(line[0])) {
+		line = line[1:]
+	}
+	for _, quotes := range []string{`"""`, "'''"} {
+		if strings.HasPrefix(line, quotes) {
+			return quotes
+		}
+	}
+	return ""
+}
+
+// guessHeaderType guesses the license type declared by a license header,
+// falling back to GuessType for complete license texts.
+func (l *License) guessHeaderType() error {
+	return l.guessHeader((*License).GuessType)
+}
+
+// guessHeader guesses the license type declared by a license header, as
+// guessHeaderType does, but falls back to guess for complete license texts.
+func (l *License) guessHeader(guess func(*License) error) error {
+	if tags := spdx.FindTags(l.Text); len(tags) > 0 {
+		if tags[0].Err != nil {
+			return tags[0].Err
+		}
+		l.Type = tags[0].Expr.String()
+		return nil
+	}
+
+	comp := strings.Join(strings.Fields(strings.ToLower(l.Text)), " ")
+
+	switch {
+	case scan(comp, "licensed under the apache license, version 2.0"):
+		l.Type = LicenseApache20
+
+	case scan(comp, "gnu affero general public license as published by the "+
+		"free software foundation, either version 3"):
+		l.Type = gnuVariant(LicenseAGPL30, "3", comp)
+
+	case scan(comp, "gnu lesser general public license as published by the "+
+		"free software foundation; either version 2.1"):
+		l.Type = gnuVariant(LicenseLGPL21, "2.1", comp)
+
+	case scan(comp, "gnu lesser general public license as published by the "+
+		"free software foundation, either version 3"):
+		l.Type = gnuVariant(LicenseLGPL30, "3", comp)
+
+	case scan(comp, "gnu general public license as published by the "+
+		"free software foundation; either version 2"):
+		l.Type = gnuVariant(LicenseGPL20, "2", comp)
+
+	case scan(comp, "gnu general public license as published by the "+
+		"free software foundation, either version 3"):
+		l.Type = gnuVariant(LicenseGPL30, "3", comp)
+
+	case scan(comp, "gnu lesser general public license version 2.1 as "+
+		"published by the free software foundation"):
+		l.Type = LicenseLGPL21Only
+
+	case scan(comp, "gnu general public license version 2 as published by "+
+		"the free software foundation") ||
+		scan(comp, "gnu general public license version 2 only, as published "+
+			"by the free software foundation"):
+		l.Type = LicenseGPL20Only
+
+	case scan(comp, "subject to the terms of the mozilla public license, v. 2.0"):
+		l.Type = LicenseMPL20
+
+	case scan(comp, "subject to the mozilla public license version 1.1"):
+		l.Type = LicenseMPL11
+
+	case scan(comp, "terms of the eclipse public license v1.0") ||
+		scan(comp, "terms of the eclipse public license 1.0"):
+		l.Type = LicenseEPL10
+
+	case scan(comp, "terms of the eclipse public license v2.0") ||
+		scan(comp, "terms of the eclipse public license 2.0"):
+		l.Type = LicenseEPL20
+
+	case scan(comp, "licensed under the mit license") ||
+		scan(comp, "released under the mit license") ||
+		scan(comp, "governed by an mit-style license"):
+		l.Type = LicenseMIT
+
+	case scan(comp, "under the same terms as perl itself"):
+		l.Type = LicensePerl
+
+	case scan(comp, "licensed under the academic free license version 3.0"):
+		l.Type = LicenseAFL30
+
+	case scan(comp, "licensed under the microsoft public license"):
+		l.Type = LicenseMSPL
+
+	case scan(comp, "licensed under the microsoft reciprocal license"):
+		l.Type = LicenseMSRL
+
+	default:
+		return guess(l)
+	}
+
+	if exception, _ := guessException(comp, l.Type); exception != "" {
+		l.Type += " WITH " + exception
+	}
+	return nil
+}
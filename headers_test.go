@@ -0,0 +1,301 @@
+package license_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestScanSourceFile(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	mitText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			"apache.go",
+			`// Copyright 2016 The Example Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example
+`,
+			license.LicenseApache20,
+		},
+		{
+			"spdx.c",
+			`/* SPDX-License-Identifier: GPL-2.0+ or mit */
+#include <stdio.h>
+`,
+			"GPL-2.0+ OR mit",
+		},
+		{
+			"mit.js",
+			`/**
+ * example.js v1.0.0
+ * Released under the MIT License.
+ */
+'use strict';
+`,
+			license.LicenseMIT,
+		},
+		{
+			"gpl.py",
+			`#!/usr/bin/env python
+# -*- coding: utf-8 -*-
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+
+import os
+`,
+			license.LicenseGPL30OrLater,
+		},
+		{
+			"kernel.c",
+			`/*
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 2 as
+ * published by the Free Software Foundation.
+ */
+#include <stdio.h>
+`,
+			license.LicenseGPL20Only,
+		},
+		{
+			"mpl.js",
+			`/* The contents of this file are subject to the Mozilla Public License Version
+ * 1.1 (the "License"); you may not use this file except in compliance with
+ * the License. */
+`,
+			license.LicenseMPL11,
+		},
+		{
+			"epl.java",
+			`/*
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License 2.0 which is available at
+ * http://www.eclipse.org/legal/epl-2.0
+ */
+package example;
+`,
+			license.LicenseEPL20,
+		},
+		{
+			"Example.pm",
+			`# Copyright (C) 2010 Jane Doe
+#
+# This library is free software; you can redistribute it and/or modify
+# it under the same terms as Perl itself.
+
+package Example;
+`,
+			license.LicensePerl,
+		},
+		{
+			"full.go",
+			"/*\n" + string(mitText) + "*/\n\npackage example\n",
+			license.LicenseMIT,
+		},
+	}
+	for _, c := range cases {
+		path := filepath.Join(d, c.name)
+		if err := ioutil.WriteFile(path, []byte(c.src), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l, err := license.ScanSourceFile(path)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %s", c.name, c.expected, l.Type)
+		}
+		if l.File != path {
+			t.Fatalf("unexpected file path: %s", l.File)
+		}
+	}
+
+	// Fails properly if the file has no header
+	path := filepath.Join(d, "none.go")
+	if err := ioutil.WriteFile(path, []byte("package example\n// Licensed under the MIT License\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.ScanSourceFile(path); err != license.ErrNoLicenseHeader {
+		t.Fatalf("expected error scanning file without header, got: %v", err)
+	}
+
+	// Fails properly if the header is not a license header
+	path = filepath.Join(d, "doc.go")
+	if err := ioutil.WriteFile(path, []byte("// Package example is an example.\npackage example\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.ScanSourceFile(path); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected error scanning file without license header, got: %v", err)
+	}
+
+	// Fails properly if the SPDX tag uses unknown identifiers
+	path = filepath.Join(d, "unknown.go")
+	if err := ioutil.WriteFile(path, []byte("// SPDX-License-Identifier: MyLicense\npackage example\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.ScanSourceFile(path); !errors.Is(err, spdx.ErrUnknownLicense) {
+		t.Fatalf("expected error scanning file with unknown SPDX identifier, got: %v", err)
+	}
+
+	// Fails properly if the file type is unknown
+	if _, err := license.ScanSourceFile(filepath.Join(d, "README")); err != license.ErrUnknownSourceType {
+		t.Fatalf("expected error scanning unknown file type, got: %v", err)
+	}
+
+	// Fails properly if the file doesn't exist
+	if _, err := license.ScanSourceFile(filepath.Join(d, "nonexistent.go")); err == nil {
+		t.Fatalf("expected error scanning non-existent file")
+	}
+}
+
+func TestScanSourceFile_Languages(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	// The headers are extracted from line and block comments alike
+	const header = "Copyright 2024 Jane Doe\nLicensed under the MIT License."
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{"main.go", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\n\npackage main\n"},
+		{"main.c", "/*\n * Copyright 2024 Jane Doe\n * Licensed under the MIT License.\n */\n#include <stdio.h>\n"},
+		{"main.cpp", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\n#include <iostream>\n"},
+		{"Main.cs", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\nusing System;\n"},
+		{"Main.java", "/**\n * Copyright 2024 Jane Doe\n * Licensed under the MIT License.\n */\npackage example;\n"},
+		{"main.ts", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\nexport {};\n"},
+		{"Main.kt", "/* Copyright 2024 Jane Doe\n   Licensed under the MIT License. */\npackage example\n"},
+		{"main.swift", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\nimport Foundation\n"},
+		{"main.rs", "// Copyright 2024 Jane Doe\n// Licensed under the MIT License.\nfn main() {}\n"},
+		{"style.css", "/* Copyright 2024 Jane Doe */\n/* Licensed under the MIT License. */\nbody {}\n"},
+		{"index.php", "<?php\n// Copyright 2024 Jane Doe\n# Licensed under the MIT License.\necho 1;\n"},
+		{"main.py", "#!/usr/bin/env python\n# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nimport os\n"},
+		{"doc.py", "\"\"\"\nCopyright 2024 Jane Doe\nLicensed under the MIT License.\n\"\"\"\nimport os\n\"\"\"Not the header.\"\"\"\n"},
+		{"raw.py", "#!/usr/bin/env python\n# Copyright 2024 Jane Doe\nr'''Licensed under the MIT License.'''\nimport os\n"},
+		{"main.rb", "# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nputs 1\n"},
+		{"build.sh", "#!/bin/sh\n# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nset -e\n"},
+		{"config.yml", "# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nkey: value\n"},
+		{"Dockerfile", "# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nFROM scratch\n"},
+		{"Makefile", "# Copyright 2024 Jane Doe\n# Licensed under the MIT License.\nall:\n"},
+		{"main.ps1", "<#\nCopyright 2024 Jane Doe\nLicensed under the MIT License.\n#>\nWrite-Host 1\n"},
+		{"main.jl", "#= Copyright 2024 Jane Doe\nLicensed under the MIT License. =#\nprintln(1)\n"},
+		{"schema.sql", "-- Copyright 2024 Jane Doe\n-- Licensed under the MIT License.\nSELECT 1;\n"},
+		{"Main.hs", "{-\nCopyright 2024 Jane Doe\nLicensed under the MIT License.\n-}\nmodule Main where\n"},
+		{"main.lua", "--[[\nCopyright 2024 Jane Doe\nLicensed under the MIT License.\n]]\nprint(1)\n"},
+		{"main.erl", "%% Copyright 2024 Jane Doe\n%% Licensed under the MIT License.\n-module(main).\n"},
+		{"paper.tex", "% Copyright 2024 Jane Doe\n% Licensed under the MIT License.\n\\documentclass{article}\n"},
+		{"init.el", ";;; Copyright 2024 Jane Doe\n;;; Licensed under the MIT License.\n(provide 'init)\n"},
+		{"main.f90", "! Copyright 2024 Jane Doe\n! Licensed under the MIT License.\nprogram main\n"},
+		{"main.ml", "(* Copyright 2024 Jane Doe\n   Licensed under the MIT License. *)\nlet () = ()\n"},
+		{"index.html", "<!DOCTYPE html>\n<!--\n  Copyright 2024 Jane Doe\n  Licensed under the MIT License.\n-->\n<html></html>\n"},
+		{"pom.xml", "<?xml version=\"1.0\"?>\n<!-- Copyright 2024 Jane Doe -->\n<!-- Licensed under the MIT License. -->\n<project/>\n"},
+		{"main.tf", "# Copyright 2024 Jane Doe\n// Licensed under the MIT License.\nterraform {}\n"},
+	}
+	for _, c := range cases {
+		path := filepath.Join(d, c.name)
+		if err := ioutil.WriteFile(path, []byte(c.src), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l, err := license.ScanSourceFile(path)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if l.Type != license.LicenseMIT {
+			t.Fatalf("%s:\nexpected: %s\ngot: %s", c.name, license.LicenseMIT, l.Type)
+		}
+		if text := strings.TrimSpace(l.Text); text != header {
+			t.Fatalf("%s:\nexpected: %q\ngot: %q", c.name, header, text)
+		}
+	}
+}
+
+func TestScanSourceFile_Options(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go": {Data: []byte("// Use is subject to the Acme internal terms.\npackage main\n")},
+	}
+	opts := []license.Option{
+		license.WithFS(fsys),
+		license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Acme", Patterns: []string{"acme internal terms"}}),
+	}
+	l, err := license.ScanSourceFile("src/main.go", opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "LicenseRef-Acme" {
+		t.Fatalf("\nexpected: %s\ngot: %s", "LicenseRef-Acme", l.Type)
+	}
+
+	if _, err := license.ScanSourceFile("src/main.go", license.WithFS(fsys), license.WithMaxFileSize(10)); err != license.ErrFileBudget {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrFileBudget, err)
+	}
+}
+
+func TestScanSourceFile_LicenseFileReference(t *testing.T) {
+	bsd, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseBSD3Clause))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	const header = "// Copyright 2009 The Go Authors. All rights reserved.\n" +
+		"// Use of this source code is governed by a BSD-style\n" +
+		"// license that can be found in the LICENSE file.\n\npackage example\n"
+	fsys := fstest.MapFS{
+		"repo/.git/HEAD":     {Data: []byte("ref: refs/heads/main\n")},
+		"repo/LICENSE":       {Data: bsd},
+		"repo/src/pkg/x.go":  {Data: []byte(header)},
+		"LICENSE":            {Data: []byte("Licensed under the MIT License.")},
+		"other/src/x.go":     {Data: []byte(header)},
+		"other/.hg/requires": {Data: []byte("store\n")},
+	}
+
+	// The closest license file is found above the source file
+	l, err := license.ScanSourceFile("repo/src/pkg/x.go", license.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseBSD3Clause || !l.Reference || l.File != "repo/src/pkg/x.go" {
+		t.Fatalf("unexpected license: %s %t %s", l.Type, l.Reference, l.File)
+	}
+
+	// License files outside of the repository are not referred to
+	if _, err := license.ScanSourceFile("other/src/x.go", license.WithFS(fsys)); err != license.ErrNoLicenseFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoLicenseFile, err)
+	}
+}
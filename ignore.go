@@ -0,0 +1,246 @@
+package license
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile is the name of the files listing the paths skipped when scanning
+// a directory recursively, relative to the directory of the file, in the
+// syntax of .gitignore files: one pattern per line, where "*" matches any
+// characters but "/", "**" matches any directories, a leading "/" anchors a
+// pattern to the directory of the file, a trailing "/" only matches
+// directories and a leading "!" includes a path again. The last pattern
+// matching a path decides, and the files of deeper directories override those
+// above them.
+const IgnoreFile = ".licenseignore"
+
+// The name of the ignore files of git, read by WithGitignore.
+const gitignoreFile = ".gitignore"
+
+// Directory names of the metadata of version control systems, which are never
+// descended into, unless WithVCSDirs is given.
+var vcsDirs = []string{".git", ".hg", ".svn"}
+
+// ignoreRule is a pattern of an ignore file.
+type ignoreRule struct {
+	base    string         // The slash-separated directory the pattern is relative to, or "" for the root
+	re      *regexp.Regexp // The pattern, matching paths relative to base
+	negate  bool           // Whether the pattern includes paths again
+	dirOnly bool           // Whether the pattern only matches directories
+}
+
+// ignoreRules are the patterns of ignore files, in the order they apply.
+type ignoreRules []ignoreRule
+
+// WithIgnorePatterns skips the directories and license files matching
+// patterns in the syntax of an IgnoreFile, relative to the directory scanned,
+// such as "testdata/" or "/third_party/**", when scanning recursively or
+// reading archives. The patterns apply before those of the ignore files of
+// the directories scanned, which may override them.
+func WithIgnorePatterns(patterns ...string) Option {
+	return func(o *options) {
+		o.ignore = append(append([]string{}, o.ignore...), patterns...)
+	}
+}
+
+// WithGitignore additionally skips the paths ignored by the .gitignore files
+// of the directories scanned recursively, such as build outputs and
+// downloaded dependencies. The IgnoreFile of a directory overrides its
+// .gitignore file.
+func WithGitignore() Option {
+	return func(o *options) {
+		o.gitignore = true
+	}
+}
+
+// WithVCSDirs descends into the metadata directories of version control
+// systems, .git, .hg and .svn, when scanning recursively, which are otherwise
+// skipped, since their object databases are slow to scan and do not hold the
+// licenses of the project.
+func WithVCSDirs() Option {
+	return func(o *options) {
+		o.vcs = true
+	}
+}
+
+// ignoreFiles returns the names of the ignore files read from each directory
+// scanned recursively, in the order their patterns apply.
+func (o *options) ignoreFiles() []string {
+	if o.gitignore {
+		return []string{gitignoreFile, IgnoreFile}
+	}
+	return []string{IgnoreFile}
+}
+
+// ignoreRules compiles the ignore patterns of o.
+func (o *options) ignoreRules() (ignoreRules, error) {
+	var rules ignoreRules
+	for _, pattern := range o.ignore {
+		rule, ok, err := parseIgnoreRule("", pattern)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// parseIgnore parses the patterns of an ignore file in the directory base,
+// leaving out the invalid ones, as git does.
+func parseIgnore(base string, data []byte) ignoreRules {
+	var rules ignoreRules
+	for _, line := range strings.Split(string(data), "\n") {
+		if rule, ok, err := parseIgnoreRule(base, line); ok && err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreRule parses a line of an ignore file, which is not a pattern if
+// it is blank or a comment.
+func parseIgnoreRule(base, line string) (ignoreRule, bool, error) {
+	rule := ignoreRule{base: base}
+	pattern := strings.TrimRight(line, "\r")
+	if !strings.HasSuffix(pattern, "\\ ") {
+		pattern = strings.TrimRight(pattern, " ")
+	}
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return rule, false, nil
+	}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return rule, false, nil
+	}
+
+	re, err := ignoreRegexp(pattern)
+	if err != nil {
+		return rule, false, fmt.Errorf("license: invalid ignore pattern %q: %w", line, err)
+	}
+	rule.re = re
+	return rule, true, nil
+}
+
+// ignoreRegexp compiles an ignore pattern into a regular expression matching
+// slash-separated paths. Patterns without a "/" but at their end match the
+// names of files and directories at any depth.
+func ignoreRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(pattern, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		atStart := i == 0 || pattern[i-1] == '/'
+		switch {
+		case atStart && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case atStart && pattern[i:] == "**":
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, path.ErrBadPattern
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, path.ErrBadPattern
+	}
+	return re, nil
+}
+
+// ignored determines if a slash-separated path, relative to the root of the
+// rules, is ignored by them, assuming its directories are not.
+func (r ignoreRules) ignored(name string, dir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		rel := name
+		if rule.base != "" {
+			if !strings.HasPrefix(name, rule.base+"/") {
+				continue
+			}
+			rel = name[len(rule.base)+1:]
+		}
+		if rule.dirOnly && !dir {
+			continue
+		}
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// excludes determines if a slash-separated file path, relative to the root of
+// the rules, or any of its directories is ignored by them.
+func (r ignoreRules) excludes(name string) bool {
+	if len(r) == 0 {
+		return false
+	}
+	parts := strings.Split(name, "/")
+	for i := 1; i < len(parts); i++ {
+		if r.ignored(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return r.ignored(name, false)
+}
+
+// unignored returns the licenses whose files, relative to root, the rules do
+// not ignore.
+func (r ignoreRules) unignored(root string, ls []*License) []*License {
+	if len(r) == 0 {
+		return ls
+	}
+	var kept []*License
+	for _, l := range ls {
+		if !r.ignored(relSlash(root, l.File), false) {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// relSlash returns the slash-separated path of name relative to root.
+func relSlash(root, name string) string {
+	rel, err := filepath.Rel(root, name)
+	if err != nil {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(rel)
+}
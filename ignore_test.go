@@ -0,0 +1,146 @@
+package license_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestWithIgnorePatterns(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"LICENSE":                        {Data: mit},
+		".licenseignore":                 {Data: []byte("# Test data\ntestdata/\n/gen/*\n!/gen/keep\n")},
+		"testdata/LICENSE":               {Data: mit},
+		"pkg/testdata/LICENSE":           {Data: mit},
+		"gen/out/LICENSE":                {Data: mit},
+		"gen/keep/LICENSE":               {Data: mit},
+		"docs/LICENSE":                   {Data: mit},
+		"docs/LICENSE.old":               {Data: mit},
+		"docs/.licenseignore":            {Data: []byte("*.old\n")},
+		"third_party/a/LICENSE":          {Data: mit},
+		"third_party/b/LICENSE":          {Data: mit},
+		"third_party/b/.licenseignore":   {Data: []byte("# Overrides the patterns of the options\n!LICENSE\n")},
+		"examples/third_party/c/LICENSE": {Data: mit},
+	}
+	s, err := license.NewScanner(license.WithFS(fsys), license.WithIgnorePatterns("/third_party/*/LICENSE"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	results, err := s.FromDirRecursive(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var got []string
+	for dir, ls := range results {
+		for _, l := range ls {
+			got = append(got, l.File)
+		}
+		if dir == "docs" && len(ls) != 1 {
+			t.Fatalf("unexpected licenses in docs: %v", ls)
+		}
+	}
+	sort.Strings(got)
+	expected := "LICENSE docs/LICENSE examples/third_party/c/LICENSE gen/keep/LICENSE third_party/b/LICENSE"
+	if strings.Join(got, " ") != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, strings.Join(got, " "))
+	}
+
+	// Invalid patterns fail the scan
+	s, err = license.NewScanner(license.WithFS(fsys), license.WithIgnorePatterns("[a-"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := s.FromDirRecursive(context.Background(), "."); err == nil || !strings.Contains(err.Error(), "invalid ignore pattern") {
+		t.Fatalf("expected an invalid pattern error, got: %v", err)
+	}
+}
+
+func TestWithIgnorePatterns_Tar(t *testing.T) {
+	archive := buildTar(t, map[string]string{
+		"LICENSE":                  "MIT",
+		"testdata/fixture/LICENSE": "ISC",
+		"pkg/LICENSE":              "Apache-2.0",
+	}, false)
+	results, err := license.NewFromTar(bytes.NewReader(archive), license.WithIgnorePatterns("testdata"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results["testdata/fixture"] != nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+}
+
+func TestNewFromDirRecursive_IgnoreFile(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+	copyFixture(t, "ISC", filepath.Join(d, "fixtures", "LICENSE"))
+	copyFixture(t, "Apache-2.0", filepath.Join(d, "sub", "LICENSE"))
+	if err := ioutil.WriteFile(filepath.Join(d, license.IgnoreFile), []byte("fixtures/\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	results, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results[filepath.Join(d, "fixtures")] != nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+}
+
+func TestWithGitignore(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"LICENSE":                {Data: mit},
+		".gitignore":             {Data: []byte("build/\n/deps\n")},
+		".licenseignore":         {Data: []byte("!/deps\n")},
+		"build/LICENSE":          {Data: mit},
+		"deps/LICENSE":           {Data: mit},
+		".git/objects/LICENSE":   {Data: mit},
+		".hg/store/LICENSE":      {Data: mit},
+		"sub/.svn/LICENSE":       {Data: mit},
+		"sub/LICENSE":            {Data: mit},
+		"sub/.gitignore":         {Data: []byte("*\n")},
+		"other/build.sh/LICENSE": {Data: mit},
+	}
+	for _, tc := range []struct {
+		opts     []license.Option
+		expected string
+	}{
+		{nil, ". build deps other/build.sh sub"},
+		{[]license.Option{license.WithGitignore()}, ". deps other/build.sh"},
+		{[]license.Option{license.WithSkipDirs(), license.WithVCSDirs()}, ". .git/objects .hg/store build deps other/build.sh sub sub/.svn"},
+	} {
+		s, err := license.NewScanner(append(tc.opts, license.WithFS(fsys))...)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		results, err := s.FromDirRecursive(context.Background(), ".")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var dirs []string
+		for dir := range results {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+		if got := strings.Join(dirs, " "); got != tc.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", tc.expected, got)
+		}
+	}
+}
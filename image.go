@@ -0,0 +1,195 @@
+package license
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ErrInvalidImage is returned for tarballs which are not container images.
+var ErrInvalidImage = errors.New("license: not a container image tarball")
+
+// The largest manifest read from an image tarball
+const maxManifestSize = 1 << 20
+
+// The most image indexes followed to reach an image manifest
+const maxIndexDepth = 8
+
+// ImageLayer is the licenses found in a layer of a container image.
+type ImageLayer struct {
+	Digest   string                `json:"digest" yaml:"digest"`     // The digest of the layer, or its path within the tarball if unknown
+	Licenses map[string][]*License `json:"licenses" yaml:"licenses"` // The licenses found in the layer, by directory
+}
+
+// dockerManifest is an image listed by the manifest.json of "docker save".
+type dockerManifest struct {
+	Layers []string
+}
+
+// ociManifest is an OCI image index or image manifest.
+type ociManifest struct {
+	Manifests []ociDescriptor `json:"manifests"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	Digest string `json:"digest"`
+}
+
+// NewFromImage will walk the layers of a container image tarball, as written
+// by "docker save" or holding an OCI image layout, and guess the licenses of
+// the license files found anywhere in the file system of each layer. The
+// result holds the layers from the base layer up, each with the licenses found
+// in it by directory, so that a file replaced by a later layer is found in
+// both. Images with several platforms or tags are read by their first
+// manifest. Layers compressed by gzip are read, but other compression formats,
+// such as zstd, are not supported.
+func NewFromImage(ctx context.Context, path string, opts ...Option) ([]*ImageLayer, error) {
+	o := newOptions(opts)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	blobs, err := readImageManifests(f)
+	if err != nil {
+		return nil, err
+	}
+	names, err := imageLayers(blobs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+
+	layers := make([]*ImageLayer, len(names))
+	positions := make(map[string][]int) // The positions of the layers by tarball entry
+	for pos, name := range names {
+		positions[name] = append(positions[name], pos)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	tr, err := tarReader(f)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		name := tarPath(hdr.Name)
+		if len(positions[name]) == 0 || layers[positions[name][0]] != nil {
+			continue
+		}
+		results, err := guessFromTar(ctx, tr, o)
+		if err != nil {
+			return nil, fmt.Errorf("license: layer %s: %w", name, err)
+		}
+		for _, pos := range positions[name] {
+			layers[pos] = &ImageLayer{Digest: layerDigest(name), Licenses: results}
+		}
+	}
+
+	for pos, layer := range layers {
+		if layer == nil {
+			return nil, fmt.Errorf("%w: %s: missing layer %s", ErrInvalidImage, path, names[pos])
+		}
+	}
+	return layers, nil
+}
+
+// readImageManifests reads the files of an image tarball which are small
+// enough to be manifests, by their path within the tarball.
+func readImageManifests(r io.Reader) (map[string][]byte, error) {
+	tr, err := tarReader(r)
+	if err != nil {
+		return nil, err
+	}
+	blobs := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return blobs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA || hdr.Size > maxManifestSize {
+			continue
+		}
+		name := tarPath(hdr.Name)
+		if name != "manifest.json" && name != "index.json" && !strings.HasPrefix(name, "blobs/") {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		blobs[name] = data
+	}
+}
+
+// imageLayers returns the tarball entries of the layers of an image, from the
+// base layer up, using the manifest of "docker save" if there is one, or else
+// the index of the OCI image layout.
+func imageLayers(blobs map[string][]byte) ([]string, error) {
+	if data, ok := blobs["manifest.json"]; ok {
+		var manifests []dockerManifest
+		if err := json.Unmarshal(data, &manifests); err != nil {
+			return nil, ErrInvalidImage
+		}
+		if len(manifests) == 0 {
+			return nil, ErrInvalidImage
+		}
+		names := make([]string, len(manifests[0].Layers))
+		for pos, layer := range manifests[0].Layers {
+			names[pos] = tarPath(layer)
+		}
+		return names, nil
+	}
+
+	data, ok := blobs["index.json"]
+	for depth := 0; ok && depth < maxIndexDepth; depth++ {
+		var m ociManifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, ErrInvalidImage
+		}
+		if len(m.Manifests) == 0 {
+			names := make([]string, len(m.Layers))
+			for pos, layer := range m.Layers {
+				names[pos] = blobPath(layer.Digest)
+			}
+			return names, nil
+		}
+		data, ok = blobs[blobPath(m.Manifests[0].Digest)]
+	}
+	return nil, ErrInvalidImage
+}
+
+// blobPath returns the path of a blob of an OCI image layout by its digest,
+// such as "blobs/sha256/<hex>" for "sha256:<hex>".
+func blobPath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+// layerDigest returns the digest of a layer by its path within an image
+// tarball, which is the path itself if it is not a blob.
+func layerDigest(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] != "blobs" {
+		return name
+	}
+	return parts[1] + ":" + parts[2]
+}
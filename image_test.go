@@ -0,0 +1,217 @@
+package license_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// buildTar creates an in-memory tar archive of the given files, compressed by
+// gzip if requested, where each value names the fixture to use as the file
+// content.
+func buildTar(t testing.TB, files map[string]string, compress bool) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		content, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", files[name]))
+		if err != nil {
+			content = []byte(files[name])
+		}
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !compress {
+		return buf.Bytes()
+	}
+
+	zbuf := new(bytes.Buffer)
+	zw := gzip.NewWriter(zbuf)
+	if _, err := zw.Write(buf.Bytes()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return zbuf.Bytes()
+}
+
+// imageLayers returns two image layers, the second of which is compressed.
+func imageLayers(t *testing.T) [][]byte {
+	return [][]byte{
+		buildTar(t, map[string]string{
+			"usr/share/licenses/foo/LICENSE": "MIT",
+			"usr/bin/foo":                    "binary",
+			"node_modules/bar/LICENSE":       "ISC",
+		}, false),
+		buildTar(t, map[string]string{
+			"./app/LICENSE": "Apache-2.0",
+			"./app/COPYING": "GPL-2.0",
+			"./app/main.go": "package main",
+			"./etc/README":  "readme",
+		}, true),
+	}
+}
+
+// writeImage writes the files of an image tarball to a temporary file.
+func writeImage(t *testing.T, files map[string][]byte) string {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.tar")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return path
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func checkImageLayers(t *testing.T, layers []*license.ImageLayer, digests []string) {
+	if len(layers) != len(digests) {
+		t.Fatalf("unexpected layers: %v", layers)
+	}
+	for pos, layer := range layers {
+		if layer.Digest != digests[pos] {
+			t.Fatalf("\nexpected: %s\ngot: %s", digests[pos], layer.Digest)
+		}
+	}
+
+	found := make(map[string]string)
+	for pos, layer := range layers {
+		for dir, ls := range layer.Licenses {
+			for _, l := range ls {
+				if filepath.Dir(l.File) != filepath.FromSlash(dir) {
+					t.Fatalf("license file %s outside of %s", l.File, dir)
+				}
+				found[fmt.Sprintf("%d:%s", pos, l.File)] = l.Type
+			}
+		}
+	}
+	expected := map[string]string{
+		"0:usr/share/licenses/foo/LICENSE": license.LicenseMIT,
+		"1:app/LICENSE":                    license.LicenseApache20,
+		"1:app/COPYING":                    license.LicenseGPL20,
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("unexpected licenses: %v", found)
+	}
+	for file, ltype := range expected {
+		if found[file] != ltype {
+			t.Fatalf("\nexpected: %s\ngot: %s", ltype, found[file])
+		}
+	}
+}
+
+func TestNewFromImage_DockerSave(t *testing.T) {
+	layers := imageLayers(t)
+	path := writeImage(t, map[string][]byte{
+		"manifest.json":  []byte(`[{"Config":"config.json","RepoTags":["foo:latest"],"Layers":["base/layer.tar","app/layer.tar"]}]`),
+		"config.json":    []byte(`{}`),
+		"base/layer.tar": layers[0],
+		"app/layer.tar":  layers[1],
+	})
+
+	result, err := license.NewFromImage(context.Background(), path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkImageLayers(t, result, []string{"base/layer.tar", "app/layer.tar"})
+}
+
+func TestNewFromImage_OCILayout(t *testing.T) {
+	layers := imageLayers(t)
+	manifest := []byte(`{"schemaVersion":2,"layers":[{"digest":"` + digest(layers[0]) + `"},{"digest":"` + digest(layers[1]) + `"}]}`)
+	index := []byte(`{"schemaVersion":2,"manifests":[{"digest":"` + digest(manifest) + `"}]}`)
+	files := map[string][]byte{
+		"oci-layout": []byte(`{"imageLayoutVersion":"1.0.0"}`),
+		"index.json": index,
+	}
+	for _, blob := range append(layers, manifest) {
+		files["blobs/sha256/"+digest(blob)[len("sha256:"):]] = blob
+	}
+	path := writeImage(t, files)
+
+	result, err := license.NewFromImage(context.Background(), path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkImageLayers(t, result, []string{digest(layers[0]), digest(layers[1])})
+}
+
+func TestNewFromImage_Options(t *testing.T) {
+	layers := imageLayers(t)
+	path := writeImage(t, map[string][]byte{
+		"manifest.json":  []byte(`[{"Layers":["base/layer.tar"]}]`),
+		"base/layer.tar": layers[0],
+	})
+
+	result, err := license.NewFromImage(context.Background(), path, license.WithSkipDirs())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ls := result[0].Licenses["node_modules/bar"]; len(ls) != 1 || ls[0].Type != license.LicenseISC {
+		t.Fatalf("unexpected licenses: %v", result[0].Licenses)
+	}
+
+	result, err = license.NewFromImage(context.Background(), path, license.WithMaxDepth(2))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(result[0].Licenses) != 0 {
+		t.Fatalf("unexpected licenses: %v", result[0].Licenses)
+	}
+}
+
+func TestNewFromImage_Invalid(t *testing.T) {
+	path := writeImage(t, map[string][]byte{"LICENSE": []byte("text")})
+	if _, err := license.NewFromImage(context.Background(), path); !errors.Is(err, license.ErrInvalidImage) {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidImage, err)
+	}
+
+	path = writeImage(t, map[string][]byte{
+		"manifest.json": []byte(`[{"Layers":["base/layer.tar"]}]`),
+	})
+	if _, err := license.NewFromImage(context.Background(), path); !errors.Is(err, license.ErrInvalidImage) {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidImage, err)
+	}
+}
@@ -0,0 +1,237 @@
+package license
+
+import (
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Category classifies a license by the obligations it places on works which
+// use it.
+type Category string
+
+// License categories
+const (
+	CategoryUnknown         Category = ""
+	CategoryPermissive      Category = "permissive"
+	CategoryWeakCopyleft    Category = "weak-copyleft"
+	CategoryStrongCopyleft  Category = "strong-copyleft"
+	CategoryPublicDomain    Category = "public-domain"
+	CategoryProprietary     Category = "proprietary"
+	CategorySourceAvailable Category = "source-available"
+	CategoryData            Category = "data"
+)
+
+// LicenseInfo holds the metadata of a license type. Public-domain dedications,
+// such as the Unlicense, CC0-1.0 and the SQLite blessing, are of the
+// public-domain category. Source-available licenses, such as BUSL-1.1, publish
+// the source code of a work but restrict its use, and are of the
+// source-available category. Licenses of databases and other data, such as
+// ODbL-1.0 and CDLA-Permissive-2.0, are of the data category.
+type LicenseInfo struct {
+	ID          string   `json:"id" yaml:"id"`                                     // The license type
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty"`             // The full name of the license
+	Category    Category `json:"category,omitempty" yaml:"category,omitempty"`     // The category of the license
+	OSIApproved bool     `json:"osiApproved" yaml:"osiApproved"`                   // Whether the OSI approved the license
+	FSFLibre    bool     `json:"fsfLibre" yaml:"fsfLibre"`                         // Whether the FSF considers the license free
+	Deprecated  bool     `json:"deprecated" yaml:"deprecated"`                     // Whether the SPDX identifier is deprecated
+	ReplacedBy  string   `json:"replacedBy,omitempty" yaml:"replacedBy,omitempty"` // The SPDX identifier or expression to use instead, if deprecated
+}
+
+// licenseInfos is the curated metadata of the recognized license types.
+var licenseInfos = map[string]LicenseInfo{
+	LicenseMIT:        {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseISC:        {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseBSD3Clause: {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseBSD2Clause: {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseBSD4Clause: {Category: CategoryPermissive, FSFLibre: true},
+	LicenseApache20:   {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseMPL20:      {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseGPL20:      {Category: CategoryStrongCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseGPL30:      {Category: CategoryStrongCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseLGPL21:     {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseLGPL30:     {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseAGPL30:     {Category: CategoryStrongCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseCDDL10:     {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseEPL10:      {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseZlib:       {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseUnlicense:  {Category: CategoryPublicDomain, OSIApproved: true, FSFLibre: true},
+	License0BSD:       {Category: CategoryPermissive, OSIApproved: true},
+	LicenseBSL10:      {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseCC010:      {Category: CategoryPublicDomain, FSFLibre: true},
+	LicenseArtistic20: {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseWTFPL:      {Category: CategoryPermissive, FSFLibre: true}, // A license granting every permission, not a dedication
+	LicenseBlessing:   {Category: CategoryPublicDomain},
+
+	LicensePublicDomain: {Name: "Public Domain", Category: CategoryPublicDomain},
+	LicenseMPL11:        {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseEPL20:        {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseCDDL11:       {Category: CategoryWeakCopyleft},
+	LicenseEUPL11:       {Category: CategoryStrongCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseEUPL12:       {Category: CategoryStrongCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseCCBY30:       {Category: CategoryPermissive},
+	LicenseCCBY40:       {Category: CategoryPermissive, FSFLibre: true},
+	LicenseCCBYSA30:     {Category: CategoryWeakCopyleft},
+	LicenseCCBYSA40:     {Category: CategoryWeakCopyleft, FSFLibre: true},
+
+	LicenseBUSL11:                   {Category: CategorySourceAvailable},
+	LicenseElastic20:                {Category: CategorySourceAvailable},
+	LicenseSSPL10:                   {Category: CategorySourceAvailable},
+	LicenseCommonsClause:            {Name: "Commons Clause License Condition v1.0", Category: CategorySourceAvailable},
+	LicensePolyFormNoncommercial100: {Category: CategorySourceAvailable},
+	LicensePolyFormSmallBusiness100: {Category: CategorySourceAvailable},
+	LicensePolyFormFreeTrial100:     {Name: "PolyForm Free Trial License 1.0.0", Category: CategorySourceAvailable},
+	LicensePolyFormInternalUse100:   {Name: "PolyForm Internal Use License 1.0.0", Category: CategorySourceAvailable},
+	LicensePolyFormPerimeter100:     {Name: "PolyForm Perimeter License 1.0.0", Category: CategorySourceAvailable},
+	LicensePolyFormShield100:        {Name: "PolyForm Shield License 1.0.0", Category: CategorySourceAvailable},
+	LicensePolyFormStrict100:        {Name: "PolyForm Strict License 1.0.0", Category: CategorySourceAvailable},
+
+	LicenseAFL30:          {Category: CategoryPermissive, OSIApproved: true, FSFLibre: true},
+	LicenseArtistic10:     {Category: CategoryPermissive, OSIApproved: true},
+	LicenseArtistic10Perl: {Category: CategoryPermissive, OSIApproved: true},
+	LicenseGPL10:          {Category: CategoryStrongCopyleft},
+	LicenseMSPL:           {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseMSRL:           {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseOpenSSL:        {Category: CategoryPermissive, FSFLibre: true},
+	LicenseOFL11:          {Category: CategoryWeakCopyleft, OSIApproved: true, FSFLibre: true},
+	LicenseOFL11RFN:       {Category: CategoryWeakCopyleft, OSIApproved: true},
+	LicenseUbuntuFont10:   {Category: CategoryWeakCopyleft},
+	LicenseGFDL11:         {Category: CategoryStrongCopyleft, FSFLibre: true},
+	LicenseGFDL12:         {Category: CategoryStrongCopyleft, FSFLibre: true},
+	LicenseGFDL13:         {Category: CategoryStrongCopyleft, FSFLibre: true},
+
+	LicenseODbL10:           {Category: CategoryData},
+	LicenseODCBy10:          {Category: CategoryData},
+	LicenseCDLAPermissive10: {Category: CategoryData},
+	LicenseCDLAPermissive20: {Category: CategoryData},
+	LicenseCDLASharing10:    {Category: CategoryData},
+
+	LicenseCERNOHLP20: {Category: CategoryPermissive, OSIApproved: true},
+	LicenseCERNOHLW20: {Category: CategoryWeakCopyleft, OSIApproved: true},
+	LicenseCERNOHLS20: {Category: CategoryStrongCopyleft, OSIApproved: true},
+
+	LicenseJSON:     {Category: CategorySourceAvailable},
+	LicenseBeerware: {Category: CategoryPermissive},
+
+	LicenseProprietary: {Name: "Proprietary", Category: CategoryProprietary},
+}
+
+// Deprecated SPDX license identifiers which are not replaced by their "-only"
+// or "-or-later" variant
+var deprecatedReplacements = map[string]string{
+	"BSD-2-Clause-FreeBSD":             "BSD-2-Clause",
+	"BSD-2-Clause-NetBSD":              "BSD-2-Clause",
+	"bzip2-1.0.5":                      "bzip2-1.0.6",
+	"eCos-2.0":                         "GPL-2.0-or-later WITH eCos-exception-2.0",
+	"GPL-2.0-with-autoconf-exception":  "GPL-2.0-only WITH Autoconf-exception-2.0",
+	"GPL-2.0-with-bison-exception":     "GPL-2.0-only WITH Bison-exception-2.2",
+	"GPL-2.0-with-classpath-exception": "GPL-2.0-only WITH Classpath-exception-2.0",
+	"GPL-2.0-with-font-exception":      "GPL-2.0-only WITH Font-exception-2.0",
+	"GPL-2.0-with-GCC-exception":       "GPL-2.0-only WITH GCC-exception-2.0",
+	"GPL-3.0-with-autoconf-exception":  "GPL-3.0-only WITH Autoconf-exception-3.0",
+	"GPL-3.0-with-GCC-exception":       "GPL-3.0-only WITH GCC-exception-3.1",
+	"Nunit":                            "zlib-acknowledgement",
+	"StandardML-NJ":                    "SMLNJ",
+	"wxWindows":                        "LGPL-2.0-or-later WITH WxWindows-exception-3.1",
+}
+
+// IsOSIApproved determines if the OSI approved a license type, as reported by
+// Info.
+func IsOSIApproved(id string) bool {
+	return Info(id).OSIApproved
+}
+
+// IsFSFLibre determines if the FSF considers a license type free, as reported
+// by Info.
+func IsFSFLibre(id string) bool {
+	return Info(id).FSFLibre
+}
+
+// IsDeprecatedID determines if a license identifier is deprecated on the SPDX
+// license list, and returns the identifier or expression to use instead, if
+// the list has one.
+func IsDeprecatedID(id string) (bool, string) {
+	info := Info(id)
+	return info.Deprecated, info.ReplacedBy
+}
+
+// Info returns the metadata of a license type. The name, deprecation,
+// approvals and canonical case of SPDX license identifiers come from the SPDX
+// license list, and the category, and the approvals of other types, from a
+// curated table of the recognized license types, which also covers their
+// "-only" and "-or-later" variants.
+// Registered licenses take their name and category from their definition,
+// and licenses with an exception the metadata of the license.
+//
+// Types which are neither known nor registered only have their ID set.
+func Info(id string) LicenseInfo {
+	if license, _, ok := withException(id); ok {
+		info := Info(license)
+		info.ID = id
+		return info
+	}
+
+	info, ok := knownInfo(id)
+	if !ok {
+		info, _ = knownInfo(baseLicenseID(id))
+	}
+	info.ID = id
+
+	if l, ok := spdx.Get(id); ok {
+		info.ID = l.ID
+		info.Name = l.Name
+		info.Deprecated = l.Deprecated
+		info.OSIApproved, info.FSFLibre = l.OSIApproved, l.FSFLibre
+		if l.Deprecated {
+			info.ReplacedBy = replacementID(l.ID)
+		}
+	} else if d, ok := definedLicense(id); ok {
+		info.Name = d.Name
+		info.Category = d.Category
+	}
+	return info
+}
+
+// baseLicenseID strips the "-only", "-or-later" or "+" suffix of a license
+// identifier, and the "-invariants" or "-no-invariants" of the GFDL.
+func baseLicenseID(id string) string {
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		if strings.HasSuffix(id, suffix) {
+			id = strings.TrimSuffix(id, suffix)
+			break
+		}
+	}
+	for _, suffix := range []string{"-no-invariants", "-invariants"} {
+		if strings.HasSuffix(id, suffix) {
+			return strings.TrimSuffix(id, suffix)
+		}
+	}
+	return id
+}
+
+// replacementID returns the identifier or expression which replaces a
+// deprecated identifier, if the SPDX license list has it. The deprecated GNU
+// identifiers mean "-only".
+func replacementID(id string) string {
+	if replacement, ok := deprecatedReplacements[id]; ok {
+		return replacement
+	}
+	replacement := id + "-only"
+	if strings.HasSuffix(id, "+") {
+		replacement = strings.TrimSuffix(id, "+") + "-or-later"
+	}
+	if l, ok := spdx.Get(replacement); ok && !l.Deprecated {
+		return l.ID
+	}
+	return ""
+}
+
+// knownInfo looks up the curated metadata of a license type, ignoring case.
+func knownInfo(id string) (LicenseInfo, bool) {
+	for known, info := range licenseInfos {
+		if strings.EqualFold(known, id) {
+			return info, true
+		}
+	}
+	return LicenseInfo{}, false
+}
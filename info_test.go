@@ -0,0 +1,134 @@
+package license_test
+
+import (
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestInfo(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected license.LicenseInfo
+	}{
+		{"MIT", license.LicenseInfo{
+			ID: "MIT", Name: "MIT License", Category: license.CategoryPermissive,
+			OSIApproved: true, FSFLibre: true,
+		}},
+		{"zlib", license.LicenseInfo{
+			ID: "Zlib", Name: "zlib License", Category: license.CategoryPermissive,
+			OSIApproved: true, FSFLibre: true,
+		}},
+		{"GPL-2.0", license.LicenseInfo{
+			ID: "GPL-2.0", Name: "GNU General Public License v2.0 only", Category: license.CategoryStrongCopyleft,
+			OSIApproved: true, FSFLibre: true, Deprecated: true, ReplacedBy: "GPL-2.0-only",
+		}},
+		{"GPL-2.0+", license.LicenseInfo{
+			ID: "GPL-2.0+", Name: "GNU General Public License v2.0 or later", Category: license.CategoryStrongCopyleft,
+			OSIApproved: true, FSFLibre: true, Deprecated: true, ReplacedBy: "GPL-2.0-or-later",
+		}},
+		{"LGPL-2.1-only", license.LicenseInfo{
+			ID: "LGPL-2.1-only", Name: "GNU Lesser General Public License v2.1 only", Category: license.CategoryWeakCopyleft,
+			OSIApproved: true, FSFLibre: true,
+		}},
+		{"EUPL-1.2", license.LicenseInfo{
+			ID: "EUPL-1.2", Name: "European Union Public License 1.2", Category: license.CategoryStrongCopyleft,
+			OSIApproved: true, FSFLibre: true,
+		}},
+		{"CC0-1.0", license.LicenseInfo{
+			ID: "CC0-1.0", Name: "Creative Commons Zero v1.0 Universal", Category: license.CategoryPublicDomain,
+			FSFLibre: true,
+		}},
+		{"WTFPL", license.LicenseInfo{
+			ID: "WTFPL", Name: "Do What The F*ck You Want To Public License", Category: license.CategoryPermissive,
+			FSFLibre: true,
+		}},
+		{license.LicensePublicDomain, license.LicenseInfo{
+			ID: license.LicensePublicDomain, Name: "Public Domain", Category: license.CategoryPublicDomain,
+		}},
+		{"BUSL-1.1", license.LicenseInfo{
+			ID: "BUSL-1.1", Name: "Business Source License 1.1", Category: license.CategorySourceAvailable,
+		}},
+		{license.LicensePolyFormShield100, license.LicenseInfo{
+			ID: license.LicensePolyFormShield100, Name: "PolyForm Shield License 1.0.0", Category: license.CategorySourceAvailable,
+		}},
+		{"Proprietary", license.LicenseInfo{ID: "Proprietary"}},
+	}
+	for _, c := range cases {
+		if info := license.Info(c.id); !reflect.DeepEqual(info, c.expected) {
+			t.Fatalf("%s\nexpected: %+v\ngot: %+v", c.id, c.expected, info)
+		}
+	}
+}
+
+func TestInfo_Registered(t *testing.T) {
+	def := &license.LicenseDefinition{
+		ID:       "LicenseRef-Acme-Info",
+		Name:     "Acme Internal License",
+		Patterns: []string{"acme internal license"},
+		Category: license.CategoryProprietary,
+	}
+	if err := license.RegisterLicenseDefinition(def); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := license.LicenseInfo{ID: def.ID, Name: def.Name, Category: license.CategoryProprietary}
+	if info := license.Info(def.ID); !reflect.DeepEqual(info, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, info)
+	}
+}
+
+func TestIsOSIApproved(t *testing.T) {
+	cases := map[string]bool{
+		"MIT":           true,
+		"afl-3.0":       true,
+		"GPL-3.0+":      true,
+		"CC0-1.0":       false,
+		"BUSL-1.1":      false,
+		"LicenseRef-X":  false,
+		"Public-Domain": false,
+	}
+	for id, expected := range cases {
+		if approved := license.IsOSIApproved(id); approved != expected {
+			t.Fatalf("%s\nexpected: %t\ngot: %t", id, expected, approved)
+		}
+	}
+}
+
+func TestIsFSFLibre(t *testing.T) {
+	cases := map[string]bool{
+		"MIT":      true,
+		"Ruby":     true,
+		"WTFPL":    true,
+		"0BSD":     false,
+		"SSPL-1.0": false,
+	}
+	for id, expected := range cases {
+		if libre := license.IsFSFLibre(id); libre != expected {
+			t.Fatalf("%s\nexpected: %t\ngot: %t", id, expected, libre)
+		}
+	}
+}
+
+func TestIsDeprecatedID(t *testing.T) {
+	cases := []struct {
+		id          string
+		deprecated  bool
+		replacement string
+	}{
+		{"MIT", false, ""},
+		{"GPL-3.0", true, "GPL-3.0-only"},
+		{"LGPL-2.1+", true, "LGPL-2.1-or-later"},
+		{"StandardML-NJ", true, "SMLNJ"},
+		{"GPL-2.0-with-classpath-exception", true, "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"Net-SNMP", true, ""},
+		{"Proprietary", false, ""},
+	}
+	for _, c := range cases {
+		deprecated, replacement := license.IsDeprecatedID(c.id)
+		if deprecated != c.deprecated || replacement != c.replacement {
+			t.Fatalf("%s\nexpected: %t %q\ngot: %t %q", c.id, c.deprecated, c.replacement, deprecated, replacement)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package charset decodes the text encodings license files are found in:
+// UTF-8, UTF-16 with or without a byte order mark, and Windows-1252, which is
+// a superset of the printable characters of Latin-1.
+package charset
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The characters of the bytes 0x80 to 0x9f in Windows-1252, which are the
+// bytes of Latin-1 otherwise. Bytes undefined in Windows-1252 are kept.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// The share of NUL bytes in every other byte beyond which text without a byte
+// order mark is taken to be UTF-16.
+const utf16NULs = 0.4
+
+// The number of bytes Binary sniffs.
+const sniffLen = 8000
+
+// Decode returns the text of data, in the encoding sniffed from its byte order
+// mark, if any, or from its content. Text which is not valid UTF-8 is decoded
+// as Windows-1252.
+func Decode(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xef, 0xbb, 0xbf}):
+		return string(data[3:])
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		return DecodeUTF16(data[2:], false)
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		return DecodeUTF16(data[2:], true)
+	}
+	if bigEndian, ok := sniffUTF16(data); ok {
+		return DecodeUTF16(data, bigEndian)
+	}
+
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return DecodeWindows1252(data)
+}
+
+// Binary determines if data is not text in any of the encodings decoded by
+// Decode, since its first sniffLen bytes contain a NUL byte but are not
+// UTF-16, as git decides.
+func Binary(data []byte) bool {
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+	if bytes.HasPrefix(data, []byte{0xff, 0xfe}) || bytes.HasPrefix(data, []byte{0xfe, 0xff}) {
+		return false
+	}
+	if _, ok := sniffUTF16(data); ok {
+		return false
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// sniffUTF16 determines if data without a byte order mark is UTF-16, from the
+// share of NUL bytes in every other byte, and in which byte order.
+func sniffUTF16(data []byte) (bigEndian, ok bool) {
+	if len(data) < 2 {
+		return false, false
+	}
+	var even, odd int
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 {
+			even++
+		}
+		if data[i+1] == 0 {
+			odd++
+		}
+	}
+	units := float64(len(data) / 2)
+	switch {
+	case float64(odd) > utf16NULs*units && even*8 < odd:
+		return false, true
+	case float64(even) > utf16NULs*units && odd*8 < even:
+		return true, true
+	}
+	return false, false
+}
+
+// DecodeUTF16 decodes UTF-16 text, in big-endian byte order if bigEndian is
+// set, and in little-endian byte order otherwise. A trailing odd byte is
+// left out.
+func DecodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// DecodeWindows1252 decodes Windows-1252 text.
+func DecodeWindows1252(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = Windows1252(b)
+	}
+	return string(runes)
+}
+
+// Windows1252 returns the character of a byte of Windows-1252 text.
+func Windows1252(b byte) rune {
+	if b >= 0x80 && b <= 0x9f {
+		return windows1252[b-0x80]
+	}
+	return rune(b)
+}
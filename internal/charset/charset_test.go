@@ -0,0 +1,45 @@
+package charset_test
+
+import (
+	"testing"
+
+	"github.com/nfukasawa/go-license/internal/charset"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected string
+	}{
+		{[]byte("plain “text”"), "plain “text”"},
+		{[]byte("\xef\xbb\xbfwith a BOM"), "with a BOM"},
+		{[]byte("\xff\xfeM\x00I\x00T\x00 \x00\xa9\x00"), "MIT ©"},
+		{[]byte("\xfe\xff\x00M\x00I\x00T"), "MIT"},
+		{[]byte("M\x00I\x00T\x00 \x00L\x00i\x00c\x00e\x00n\x00s\x00e\x00"), "MIT License"},
+		{[]byte("\x00M\x00I\x00T"), "MIT"},
+		{[]byte("Copyright \xa9 J\xfcrgen \x93quoted\x94"), "Copyright © Jürgen “quoted”"},
+	}
+	for _, test := range tests {
+		if text := charset.Decode(test.data); text != test.expected {
+			t.Fatalf("\nexpected: %q\ngot: %q", test.expected, text)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected bool
+	}{
+		{[]byte("MIT License"), false},
+		{[]byte("\xff\xfeM\x00I\x00T\x00"), false},
+		{[]byte("M\x00I\x00T\x00 \x00L\x00i\x00c\x00e\x00n\x00s\x00e\x00"), false},
+		{[]byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02\x00>\x00"), true},
+		{[]byte("MIT License\x00\x01\x02\x03"), true},
+	}
+	for _, test := range tests {
+		if binary := charset.Binary(test.data); binary != test.expected {
+			t.Fatalf("%q\nexpected: %t\ngot: %t", test.data, test.expected, binary)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// mapping is a decoded JSON object, with its keys in order.
+type mapping []entry
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// Marshal encodes v as a YAML document, following the rules of encoding/json
+// for the mapping of v onto values, in the subset of YAML decoded by Unmarshal.
+// Fields are written in the order encoding/json writes them.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if isBlock(value) {
+		writeBlock(&b, value, 0)
+	} else {
+		b.WriteString(scalar(value) + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+// decodeJSON decodes the next JSON value of dec, keeping the order of the keys
+// of objects.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := mapping{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, entry{key.(string), value})
+		}
+		_, err := dec.Token()
+		return m, err
+	case json.Delim('['):
+		seq := []interface{}{}
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+		}
+		_, err := dec.Token()
+		return seq, err
+	}
+	return tok, nil
+}
+
+// isBlock determines if v is written as a block, rather than on a single line.
+func isBlock(v interface{}) bool {
+	switch v := v.(type) {
+	case mapping:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// writeBlock writes the mapping or sequence v, indented by indent spaces.
+func writeBlock(b *bytes.Buffer, v interface{}, indent int) {
+	prefix := strings.Repeat(" ", indent)
+	if m, ok := v.(mapping); ok {
+		for _, e := range m {
+			b.WriteString(prefix + quote(e.key) + ":")
+			if isBlock(e.value) {
+				b.WriteString("\n")
+				writeBlock(b, e.value, indent+2)
+			} else {
+				b.WriteString(" " + scalar(e.value) + "\n")
+			}
+		}
+		return
+	}
+
+	for _, item := range v.([]interface{}) {
+		if !isBlock(item) {
+			b.WriteString(prefix + "- " + scalar(item) + "\n")
+			continue
+		}
+		// The item starts on the line of its marker.
+		var nested bytes.Buffer
+		writeBlock(&nested, item, indent+2)
+		b.WriteString(prefix + "- " + nested.String()[indent+2:])
+	}
+}
+
+// scalar formats a value written on a single line.
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return quote(v)
+	case mapping:
+		return "{}"
+	}
+	return "[]"
+}
+
+// quote formats s as a plain scalar, or a double-quoted one if it would be
+// read otherwise as a plain scalar. Quotes are escaped as \x22, since comments
+// are told apart from quoted strings without regard to escapes.
+func quote(s string) string {
+	if isPlain(s) {
+		return s
+	}
+	return strings.Replace(strconv.Quote(s), `\"`, `\x22`, -1)
+}
+
+// isPlain determines if s reads back as itself as a plain scalar.
+func isPlain(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s || !utf8.ValidString(s) ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if !strconv.IsPrint(r) {
+			return false
+		}
+	}
+	v, err := parseScalar(s)
+	return err == nil && v == s
+}
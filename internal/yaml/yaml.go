@@ -0,0 +1,397 @@
+// Package yaml decodes the subset of YAML used by the configuration files of
+// go-license: block mappings and sequences, flow sequences, quoted and plain
+// scalars, literal and folded block scalars, and comments. Anchors, tags,
+// flow mappings and multiple documents are not supported. Values are encoded
+// in the same subset, for the output of reports.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// line is a significant line of a YAML document.
+type line struct {
+	num    int    // The line number, starting at 1
+	indent int    // The number of leading spaces
+	text   string // The content, without indentation or comments
+	raw    string // The content, without indentation, with comments
+}
+
+// Unmarshal decodes a YAML document into v, following the rules of
+// encoding/json for the mapping of values onto v.
+func Unmarshal(data []byte, v interface{}) error {
+	value, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Decode decodes a YAML document into its generic representation, made of
+// map[string]interface{}, []interface{}, string, bool, int64, float64 and nil
+// values.
+func Decode(data []byte) (interface{}, error) {
+	var lines []line
+	for i, raw := range strings.Split(strings.Replace(string(data), "\r\n", "\n", -1), "\n") {
+		if strings.HasPrefix(strings.TrimLeft(raw, " "), "\t") {
+			return nil, fmt.Errorf("yaml: line %d: tabs are not allowed for indentation", i+1)
+		}
+		content := strings.TrimLeft(raw, " ")
+		text := strings.TrimSpace(stripComment(content))
+		if i == 0 && text == "---" {
+			continue
+		}
+		lines = append(lines, line{
+			num:    i + 1,
+			indent: len(raw) - len(content),
+			text:   text,
+			raw:    strings.TrimRight(content, " "),
+		})
+	}
+
+	p := &parser{lines: lines}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	value, err := p.parseBlock(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content")
+	}
+	return value, nil
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("yaml: line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+// skipBlank advances past empty and comment-only lines.
+func (p *parser) skipBlank() {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+}
+
+// parseBlock parses the mapping or sequence starting at the current line.
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *parser) parseSequence(indent int) (interface{}, error) {
+	seq := []interface{}{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		l := p.lines[p.pos]
+		if l.indent < indent || l.indent == indent && !isSequenceItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, p.errorf("expected sequence item")
+		}
+
+		item := strings.TrimSpace(strings.TrimPrefix(l.text, "-"))
+		switch {
+		case item == "":
+			p.pos++
+			value, err := p.parseNested(indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+		case isSequenceItem(item) || isMappingEntry(item):
+			// The item is itself a block, starting on the same line.
+			offset := len(l.text) - len(item)
+			p.lines[p.pos].indent += offset
+			p.lines[p.pos].text = item
+			p.lines[p.pos].raw = strings.TrimSpace(strings.TrimPrefix(l.raw, "-"))
+			value, err := p.parseBlock(indent + offset)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+		default:
+			value, err := p.parseValue(item, indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+		}
+	}
+	return seq, nil
+}
+
+func (p *parser) parseMapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent || !isMappingEntry(l.text) {
+			return nil, p.errorf("expected mapping entry")
+		}
+
+		key, value := splitMappingEntry(l.text)
+		key, err := parseKey(key)
+		if err != nil {
+			return nil, p.errorf("%s", err)
+		}
+		if _, ok := m[key]; ok {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+
+		if value == "" {
+			p.pos++
+			p.skipBlank()
+			// A sequence may be indented at the same level as its key.
+			if p.pos < len(p.lines) && p.lines[p.pos].indent == indent &&
+				isSequenceItem(p.lines[p.pos].text) {
+				m[key], err = p.parseSequence(indent)
+			} else {
+				m[key], err = p.parseNested(indent)
+			}
+		} else {
+			m[key], err = p.parseValue(value, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// parseNested parses the block indented below the current level, if any.
+func (p *parser) parseNested(indent int) (interface{}, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+		return nil, nil
+	}
+	return p.parseBlock(p.lines[p.pos].indent)
+}
+
+// parseValue parses the value following a key or sequence item marker on the
+// current line, consuming any following lines of a block scalar.
+func (p *parser) parseValue(value string, indent int) (interface{}, error) {
+	p.pos++
+	switch {
+	case value == "|" || value == "|-" || value == ">" || value == ">-":
+		return p.parseBlockScalar(value, indent), nil
+	case strings.HasPrefix(value, "["):
+		v, err := parseFlowSequence(value)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%s", err)
+		}
+		return v, nil
+	case value == "{}":
+		return map[string]interface{}{}, nil
+	case strings.HasPrefix(value, "{"):
+		p.pos--
+		return nil, p.errorf("flow mappings are not supported")
+	}
+	v, err := parseScalar(value)
+	if err != nil {
+		p.pos--
+		return nil, p.errorf("%s", err)
+	}
+	return v, nil
+}
+
+// parseBlockScalar collects the lines indented below the current level into a
+// literal (|) or folded (>) string.
+func (p *parser) parseBlockScalar(style string, indent int) string {
+	var lines []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		l := p.lines[p.pos]
+		if l.raw == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if l.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+		lines = append(lines, strings.Repeat(" ", l.indent-blockIndent)+l.raw)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var text string
+	if strings.HasPrefix(style, "|") {
+		text = strings.Join(lines, "\n")
+	} else {
+		for i, l := range lines {
+			switch {
+			case i == 0:
+				text = l
+			case l == "" || lines[i-1] == "":
+				text += "\n" + l
+			default:
+				text += " " + l
+			}
+		}
+	}
+	if !strings.HasSuffix(style, "-") && text != "" {
+		text += "\n"
+	}
+	return text
+}
+
+// isMappingEntry determines if text is a "key: value" pair.
+func isMappingEntry(text string) bool {
+	key, _ := splitMappingEntry(text)
+	return key != ""
+}
+
+// splitMappingEntry splits a "key: value" pair at the first colon which is
+// followed by a space or the end of the line and is outside of quotes.
+func splitMappingEntry(text string) (string, string) {
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+		}
+	}
+	return "", ""
+}
+
+func parseKey(key string) (string, error) {
+	v, err := parseScalar(key)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return key, nil
+}
+
+// parseFlowSequence parses a single-line sequence such as [a, "b", 3].
+func parseFlowSequence(value string) (interface{}, error) {
+	if !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("unterminated flow sequence %s", value)
+	}
+	seq := []interface{}{}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return seq, nil
+	}
+	var items []string
+	quote, start := byte(0), 0
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, inner[start:])
+	for _, item := range items {
+		v, err := parseScalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+	}
+	return seq, nil
+}
+
+// parseScalar parses a quoted or plain scalar.
+func parseScalar(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string %s", value)
+		}
+		return s, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("invalid quoted string %s", value)
+		}
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+	}
+
+	switch value {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+	return value, nil
+}
+
+// stripComment removes a trailing comment outside of quotes.
+func stripComment(content string) string {
+	quote := byte(0)
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || content[i-1] == ' ' || content[i-1] == '[' || content[i-1] == ',' {
+				quote = c
+			}
+		case c == '#' && (i == 0 || content[i-1] == ' '):
+			return content[:i]
+		}
+	}
+	return content
+}
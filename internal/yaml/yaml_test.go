@@ -0,0 +1,149 @@
+package yaml_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nfukasawa/go-license/internal/yaml"
+)
+
+func TestDecode(t *testing.T) {
+	doc := `---
+# A comment
+name: example   # trailing comment
+quoted: "a: b # c"
+single: 'it''s'
+count: 3
+ratio: 0.5
+enabled: true
+missing:
+empty: []
+flow: [MIT, "Apache-2.0", 'BSD-3-Clause']
+list:
+  - one
+  - two
+same-level:
+- three
+nested:
+  key: value
+  deeper:
+    - name: first
+      id: 1
+    - name: second
+literal: |
+  line one
+    indented
+  line three
+folded: >-
+  folded
+  text
+
+  new paragraph
+`
+	expected := map[string]interface{}{
+		"name":       "example",
+		"quoted":     "a: b # c",
+		"single":     "it's",
+		"count":      int64(3),
+		"ratio":      0.5,
+		"enabled":    true,
+		"missing":    nil,
+		"empty":      []interface{}{},
+		"flow":       []interface{}{"MIT", "Apache-2.0", "BSD-3-Clause"},
+		"list":       []interface{}{"one", "two"},
+		"same-level": []interface{}{"three"},
+		"nested": map[string]interface{}{
+			"key": "value",
+			"deeper": []interface{}{
+				map[string]interface{}{"name": "first", "id": int64(1)},
+				map[string]interface{}{"name": "second"},
+			},
+		},
+		"literal": "line one\n  indented\nline three\n",
+		"folded":  "folded text\n\nnew paragraph",
+	}
+
+	v, err := yaml.Decode([]byte(doc))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("\nexpected: %#v\ngot: %#v", expected, v)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, doc := range []string{
+		"key: value\n  bad: indent\n",
+		"key: value\nkey: again\n",
+		"- item\nkey: value\n",
+		"key: {a: b}\n",
+		"key: [unterminated\n",
+		"key: \"unterminated\n",
+		"\tkey: value\n",
+	} {
+		if _, err := yaml.Decode([]byte(doc)); err == nil || !strings.HasPrefix(err.Error(), "yaml: line") {
+			t.Fatalf("expected error decoding %q, got: %v", doc, err)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var v struct {
+		Allow []string `json:"allow"`
+		Deny  []string `json:"deny"`
+	}
+	if err := yaml.Unmarshal([]byte("allow: [MIT, ISC]\ndeny:\n  - GPL-3.0\n"), &v); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(v.Allow, []string{"MIT", "ISC"}) || !reflect.DeepEqual(v.Deny, []string{"GPL-3.0"}) {
+		t.Fatalf("unexpected value: %#v", v)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type item struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags,omitempty"`
+		Score float64  `json:"score"`
+	}
+	v := struct {
+		Title   string            `json:"title"`
+		Strings []string          `json:"strings"`
+		Items   []item            `json:"items"`
+		Nested  [][]int           `json:"nested"`
+		Empty   []string          `json:"empty"`
+		Map     map[string]string `json:"map"`
+		None    *item             `json:"none"`
+		Enabled bool              `json:"enabled"`
+	}{
+		Title: "MIT",
+		Strings: []string{
+			"", "true", "3", "null", "- dash", "a: b", "a # b", `say "hi" # twice`,
+			"it's", "two\nlines", " padded ", "#comment", "[flow]", "tab\there", "ünïcödé",
+		},
+		Items:  []item{{Name: "first", Tags: []string{"a", "b"}, Score: 0.5}, {Name: "second", Score: 1}},
+		Nested: [][]int{{1, 2}, {3}},
+		Empty:  []string{},
+		Map:    map[string]string{},
+	}
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(string(data), "title: MIT\nstrings:\n") {
+		t.Fatalf("unexpected document:\n%s", data)
+	}
+
+	got := v
+	got.Strings, got.Items, got.Nested, got.Empty, got.Map = nil, nil, nil, nil, nil
+	got.Title, got.Enabled = "", true
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("err: %s\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Fatalf("\nexpected: %#v\ngot: %#v\n%s", v, got, data)
+	}
+}
@@ -0,0 +1,250 @@
+package license
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Extensions of Java archives
+var jarExtensions = []string{".jar", ".war", ".ear"}
+
+// The deepest nesting of Java archives read, such as a JAR in a JAR in a WAR
+const maxJARNesting = 4
+
+// NewFromJAR opens a Java archive on disk, such as a JAR, WAR or EAR file, and
+// returns the artifacts packaged in it, as done by NewFromJARReader.
+func NewFromJAR(path string, opts ...Option) ([]*ModuleDependency, error) {
+	rc, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return NewFromJARReader(&rc.Reader, path, opts...)
+}
+
+// NewFromJARReader returns the artifacts packaged in a Java archive of the
+// given name: one for each Maven pom.xml file found in its META-INF/maven
+// directory, named "groupId:artifactId" with the coordinates of the
+// pom.properties file next to it, or else of the pom.xml itself, and with the
+// license declared by its <licenses> entries, by name or by the URL of their
+// text. If the archive embeds a single pom.xml which declares no license, or
+// none, the artifact has the licenses of the META-INF/LICENSE* files of the
+// archive instead, since those of shaded archives cannot be told apart. An
+// archive without any pom.xml is an artifact named after it. The artifacts of
+// the archives nested in it, as in WAR files or Spring Boot JARs, follow its
+// own, their Dir being named "archive!/path" after them. Nested archives are
+// read up to four levels deep, and those larger than the limit set by
+// WithMaxFileSize are skipped.
+func NewFromJARReader(r *zip.Reader, name string, opts ...Option) ([]*ModuleDependency, error) {
+	o := newOptions(opts)
+	return jarArtifacts(o.ctx, r, name, 0, o)
+}
+
+// NewJARReport searches a directory and its subdirectories, such as a lib
+// directory or a local Maven repository, for Java archives, and reports the
+// artifacts packaged in them in the same form as NewDependencyReport, as done
+// by NewFromJAR. Artifacts found in several archives are reported once.
+func NewJARReport(ctx context.Context, dir string, opts ...Option) (*DependencyReport, error) {
+	o := newOptions(opts)
+	report := &DependencyReport{Modules: []*ModuleDependency{}}
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && o.skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || indexOf(jarExtensions, strings.ToLower(filepath.Ext(p))) < 0 {
+			return nil
+		}
+
+		rc, err := zip.OpenReader(p)
+		if err == zip.ErrFormat {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		defer rc.Close()
+		artifacts, err := jarArtifacts(ctx, &rc.Reader, p, 0, o)
+		if err != nil {
+			return err
+		}
+		for _, a := range artifacts {
+			if key := a.Path + "@" + a.Version; !seen[key] {
+				seen[key] = true
+				report.Modules = append(report.Modules, a)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	return report, nil
+}
+
+// jarArtifacts returns the artifacts packaged in a Java archive, nested in
+// depth others, as described by NewFromJARReader.
+func jarArtifacts(ctx context.Context, r *zip.Reader, name string, depth int, o *options) ([]*ModuleDependency, error) {
+	var poms []string
+	var nested []*zip.File
+	for _, f := range r.File {
+		switch {
+		case strings.HasPrefix(f.Name, "META-INF/maven/") && path.Base(f.Name) == "pom.xml":
+			poms = append(poms, f.Name)
+		case depth < maxJARNesting && indexOf(jarExtensions, strings.ToLower(path.Ext(f.Name))) >= 0:
+			nested = append(nested, f)
+		}
+	}
+	sort.Strings(poms)
+
+	var artifacts []*ModuleDependency
+	for _, file := range poms {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		a, err := pomArtifact(r, file)
+		if err != nil {
+			return nil, fmt.Errorf("license: invalid manifest %s!/%s: %w", name, file, err)
+		}
+		a.Dir = name
+		artifacts = append(artifacts, a)
+	}
+	if len(artifacts) == 0 {
+		artifact := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		artifacts = append(artifacts, &ModuleDependency{
+			Path:      artifact,
+			Ecosystem: "maven",
+			Dir:       name,
+			Packages:  []string{artifact},
+		})
+	}
+	if len(artifacts) == 1 && artifacts[0].Licenses == nil {
+		ls, err := guessFromFS(ctx, r, "META-INF", o)
+		switch {
+		case err == nil:
+			artifacts[0].Licenses = ls
+		case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, os.IsNotExist(err):
+		default:
+			return nil, err
+		}
+	}
+
+	for _, f := range nested {
+		if o.maxFileSize > 0 && f.UncompressedSize64 > uint64(o.maxFileSize) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		var data []byte
+		if o.maxFileSize > 0 {
+			data, err = ioutil.ReadAll(io.LimitReader(rc, o.maxFileSize+1))
+		} else {
+			data, err = ioutil.ReadAll(rc)
+		}
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if o.maxFileSize > 0 && int64(len(data)) > o.maxFileSize {
+			continue
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err == zip.ErrFormat {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		more, err := jarArtifacts(ctx, zr, name+"!/"+f.Name, depth+1, o)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, more...)
+	}
+	return artifacts, nil
+}
+
+// pomArtifact reads the artifact described by a pom.xml file of a Java
+// archive, and the pom.properties file next to it, if any.
+func pomArtifact(r *zip.Reader, file string) (*ModuleDependency, error) {
+	data, err := fs.ReadFile(r, file)
+	if err != nil {
+		return nil, err
+	}
+	var pom mavenPOM
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+	groupID, artifactID, version := pom.GroupID, pom.ArtifactID, pom.Version
+	if groupID == "" {
+		groupID = pom.Parent.GroupID
+	}
+	if version == "" {
+		version = pom.Parent.Version
+	}
+
+	if props, err := fs.ReadFile(r, path.Join(path.Dir(file), "pom.properties")); err == nil {
+		s := bufio.NewScanner(bytes.NewReader(props))
+		for s.Scan() {
+			line := strings.TrimSpace(s.Text())
+			eq := strings.IndexAny(line, "=:")
+			if line == "" || line[0] == '#' || line[0] == '!' || eq < 0 {
+				continue
+			}
+			switch value := strings.TrimSpace(line[eq+1:]); strings.TrimSpace(line[:eq]) {
+			case "groupId":
+				groupID = value
+			case "artifactId":
+				artifactID = value
+			case "version":
+				version = value
+			}
+		}
+	}
+
+	var declared []string
+	for _, l := range pom.Licenses {
+		name := strings.TrimSpace(l.Name)
+		if id, err := NormalizeID(name); err == nil {
+			name = id
+		} else if id, err := LicenseFromURL(l.URL); err == nil {
+			name = id
+		}
+		declared = append(declared, name)
+	}
+	a := &ModuleDependency{
+		Path:      groupID + ":" + artifactID,
+		Version:   version,
+		Ecosystem: "maven",
+		Packages:  []string{groupID + ":" + artifactID},
+	}
+	if licenseType := declaredType(declared); licenseType != "" {
+		a.Licenses = []*License{{Type: licenseType, File: file}}
+	}
+	return a, nil
+}
@@ -0,0 +1,119 @@
+package license_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromJARReader(t *testing.T) {
+	inner := buildZip(t, map[string]string{
+		"META-INF/maven/org.example/inner/pom.xml": `<project><parent><groupId>org.example</groupId><version>2.0</version></parent>` +
+			`<artifactId>inner</artifactId><licenses><license><name>Eclipse Public License - v 2.0</name></license></licenses></project>`,
+	})
+	data := buildZip(t, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n",
+		"META-INF/LICENSE":     "MIT",
+		"META-INF/maven/org.example/lib/pom.xml": `<project><groupId>org.example</groupId><artifactId>lib</artifactId><version>${revision}</version>` +
+			`<licenses><license><name>ASL</name><url>https://www.apache.org/licenses/LICENSE-2.0.txt</url></license></licenses></project>`,
+		"META-INF/maven/org.example/lib/pom.properties": "#Generated by Maven\ngroupId=org.example\nartifactId=lib\nversion=1.2.3\n",
+		"BOOT-INF/lib/inner-2.0.jar":                    string(inner),
+	})
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	artifacts, err := license.NewFromJARReader(r, "app.jar")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	type summary struct {
+		Path, Version, Dir, Type, File string
+	}
+	expected := []summary{
+		{"org.example:lib", "1.2.3", "app.jar", "Apache-2.0", "META-INF/maven/org.example/lib/pom.xml"},
+		{"org.example:inner", "2.0", "app.jar!/BOOT-INF/lib/inner-2.0.jar", "EPL-2.0", "META-INF/maven/org.example/inner/pom.xml"},
+	}
+	var got []summary
+	for _, a := range artifacts {
+		if len(a.Licenses) != 1 || a.Ecosystem != "maven" {
+			t.Fatalf("%s: unexpected licenses: %v", a.Path, a.Licenses)
+		}
+		got = append(got, summary{a.Path, a.Version, a.Dir, a.Licenses[0].Type, a.Licenses[0].File})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, got)
+	}
+}
+
+func TestNewFromJARReader_Nesting(t *testing.T) {
+	data := buildZip(t, map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n"})
+	for i := 0; i < 6; i++ {
+		data = buildZip(t, map[string]string{"lib/nested.jar": string(data)})
+	}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	artifacts, err := license.NewFromJARReader(r, "app.jar")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(artifacts) != 5 {
+		t.Fatalf("\nexpected: %d artifacts\ngot: %d", 5, len(artifacts))
+	}
+
+	artifacts, err = license.NewFromJARReader(r, "app.jar", license.WithMaxFileSize(128))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Dir != "app.jar" {
+		t.Fatalf("unexpected artifacts: %v", artifacts)
+	}
+}
+
+func TestNewJARReport(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	// Archives without a pom.xml are named after their file, and those
+	// without declared licenses have those of their license files
+	writeFiles(t, d, map[string]string{
+		"lib/plain-1.0.jar": string(buildZip(t, map[string]string{"META-INF/LICENSE.txt": "BSD-3-Clause"})),
+		"lib/undeclared.jar": string(buildZip(t, map[string]string{
+			"META-INF/maven/org.example/undeclared/pom.xml": "<project><groupId>org.example</groupId><artifactId>undeclared</artifactId><version>0.1</version></project>",
+			"META-INF/NOTICE":  "Copyright 2020 Example",
+			"META-INF/LICENSE": "ISC",
+		})),
+		"lib/copy/undeclared.jar": string(buildZip(t, map[string]string{
+			"META-INF/maven/org.example/undeclared/pom.xml": "<project><groupId>org.example</groupId><artifactId>undeclared</artifactId><version>0.1</version></project>",
+		})),
+		"lib/README.txt": "Not an archive",
+	})
+
+	r, err := license.NewJARReport(context.Background(), d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(r.Modules) != 2 {
+		t.Fatalf("unexpected artifacts: %v", r.Modules)
+	}
+	if m := r.Modules[0]; m.Path != "org.example:undeclared" || m.Dir != filepath.Join(d, "lib", "copy", "undeclared.jar") || m.Licenses != nil {
+		t.Fatalf("unexpected artifact: %+v", m)
+	}
+	if m := r.Modules[1]; m.Path != "plain-1.0" || len(m.Licenses) != 1 || m.Licenses[0].Type != license.LicenseBSD3Clause {
+		t.Fatalf("unexpected artifact: %+v", m)
+	}
+}
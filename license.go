@@ -1,20 +1,32 @@
 package license
 
 import (
+	"context"
 	"errors"
+	"io"
+	"io/fs"
 	"io/ioutil"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 const (
 	LicenseUnrecognized = "Unrecognized"
+	// The SPDX values reported by WithSPDXResults for license files whose type
+	// cannot be determined, and for directories without license files
+	LicenseNoAssertion = "NOASSERTION"
+	LicenseNone        = "NONE"
 	// Recognized license types
 	LicenseMIT        = "MIT"
 	LicenseISC        = "ISC"
 	LicenseBSD3Clause = "BSD-3-Clause"
 	LicenseBSD2Clause = "BSD-2-Clause"
+	LicenseBSD4Clause = "BSD-4-Clause"
 	LicenseApache20   = "Apache-2.0"
 	LicenseMPL20      = "MPL-2.0"
 	LicenseGPL20      = "GPL-2.0"
@@ -26,6 +38,99 @@ const (
 	LicenseEPL10      = "EPL-1.0"
 	LicenseZlib       = "zlib"
 	LicenseUnlicense  = "Unlicense"
+	License0BSD       = "0BSD"
+	LicenseBSL10      = "BSL-1.0"
+	LicenseCC010      = "CC0-1.0"
+	LicenseArtistic20 = "Artistic-2.0"
+	LicenseWTFPL      = "WTFPL"
+)
+
+// Variants of the GNU licenses, which are reported instead of the deprecated
+// identifiers above when the text grants either the named version only, or
+// any later version as well.
+const (
+	LicenseGPL20Only     = "GPL-2.0-only"
+	LicenseGPL20OrLater  = "GPL-2.0-or-later"
+	LicenseGPL30Only     = "GPL-3.0-only"
+	LicenseGPL30OrLater  = "GPL-3.0-or-later"
+	LicenseLGPL21Only    = "LGPL-2.1-only"
+	LicenseLGPL21OrLater = "LGPL-2.1-or-later"
+	LicenseLGPL30Only    = "LGPL-3.0-only"
+	LicenseLGPL30OrLater = "LGPL-3.0-or-later"
+	LicenseAGPL30Only    = "AGPL-3.0-only"
+	LicenseAGPL30OrLater = "AGPL-3.0-or-later"
+)
+
+// Public-domain dedications which are not licenses in their own right. The
+// generic dedication is not on the SPDX license list, so it is reported as a
+// LicenseRef.
+const (
+	LicenseBlessing     = "blessing"
+	LicensePublicDomain = "LicenseRef-Public-Domain"
+)
+
+// Other versions of the recognized licenses, which are told apart by the
+// version in the title of their text.
+const (
+	LicenseMPL11  = "MPL-1.1"
+	LicenseEPL20  = "EPL-2.0"
+	LicenseCDDL11 = "CDDL-1.1"
+)
+
+// Licenses recognized by their title, and by their translations, as their
+// texts are published in many languages.
+const (
+	LicenseEUPL11 = "EUPL-1.1"
+	LicenseEUPL12 = "EUPL-1.2"
+)
+
+// Licenses of the Perl ecosystem, and the Academic Free License. Perl modules
+// are commonly licensed under the same terms as Perl itself, a choice of the
+// Artistic License 1.0, as distributed with Perl, and the GPL.
+const (
+	LicenseAFL30          = "AFL-3.0"
+	LicenseArtistic10     = "Artistic-1.0"
+	LicenseArtistic10Perl = "Artistic-1.0-Perl"
+	LicenseGPL10          = "GPL-1.0"
+	LicensePerl           = "Artistic-1.0-Perl OR GPL-1.0-or-later"
+)
+
+// Licenses of Microsoft, common in the .NET ecosystem
+const (
+	LicenseMSPL = "MS-PL"
+	LicenseMSRL = "MS-RL"
+)
+
+// The dual license of OpenSSL before version 3.0, combining the OpenSSL
+// License and the original SSLeay license. Later versions are under the
+// Apache-2.0.
+const LicenseOpenSSL = "OpenSSL"
+
+// Font licenses. The OFL is reported as OFL-1.1-RFN if the copyright
+// statement of the font reserves its names.
+const (
+	LicenseOFL11        = "OFL-1.1"
+	LicenseOFL11RFN     = "OFL-1.1-RFN"
+	LicenseUbuntuFont10 = "Ubuntu-font-1.0"
+)
+
+// Licenses written in jest, which are neither OSI approved nor FSF free. The
+// JSON license is the MIT license with a condition that the software be used
+// for good, not evil, so Info puts it in the source-available category.
+const (
+	LicenseJSON     = "JSON"
+	LicenseBeerware = "Beerware"
+)
+
+// Versions of the GNU Free Documentation License, which are reported with
+// their "-only" or "-or-later" variants as the GNU licenses above are, and
+// with "-invariants" or "-no-invariants" if the text granting the license
+// tells whether the document has invariant sections, as in
+// "GFDL-1.3-no-invariants-or-later".
+const (
+	LicenseGFDL11 = "GFDL-1.1"
+	LicenseGFDL12 = "GFDL-1.2"
+	LicenseGFDL13 = "GFDL-1.3"
 )
 
 var (
@@ -33,20 +138,33 @@ var (
 	ErrNoLicenseFile       = errors.New("license: unable to find any license file")
 	ErrUnrecognizedLicense = errors.New("license: could not guess license type")
 	ErrMultipleLicenses    = errors.New("license: multiple license files found")
+	ErrNoLicenseHeader     = errors.New("license: unable to find a license header")
+	ErrUnknownSourceType   = errors.New("license: unknown source file type")
 )
 
+// Compiled license file name patterns, keyed by pattern
+var licensePatterns sync.Map
+
 // A set of reasonable license file names to use when guessing where the
 // license may be. Case does not matter.
+//
+// Deprecated: DefaultLicenseFiles is a read-only default, copied when the
+// package is initialized, so changing it has no effect. Use WithFilePatterns,
+// a Registry or a Scanner instead.
 var DefaultLicenseFiles = []string{
-	"license*", "licence*", "copying*", "unlicense",
+	"license*", "licence*", "copying*", "unlicense*", "ofl.txt", "ufl.txt",
 }
 
 // A slice of standardized license abbreviations
+//
+// Deprecated: KnownLicenses is a read-only default, copied when the package is
+// initialized, so changing it has no effect. Use a Registry instead.
 var KnownLicenses = []string{
 	LicenseMIT,
 	LicenseISC,
 	LicenseBSD3Clause,
 	LicenseBSD2Clause,
+	LicenseBSD4Clause,
 	LicenseApache20,
 	LicenseMPL20,
 	LicenseGPL20,
@@ -58,13 +176,23 @@ var KnownLicenses = []string{
 	LicenseEPL10,
 	LicenseZlib,
 	LicenseUnlicense,
+	License0BSD,
+	LicenseBSL10,
+	LicenseCC010,
+	LicenseArtistic20,
+	LicenseWTFPL,
 }
 
 // License describes a software license
 type License struct {
-	Type string // The type of license in use
-	Text string // License text data
-	File string // The path to the source file, if any
+	Type string `json:"type" yaml:"type"`                     // The type of license in use
+	Text string `json:"text,omitempty" yaml:"text,omitempty"` // License text data
+	File string `json:"file,omitempty" yaml:"file,omitempty"` // The path to the source file, if any
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`   // The URL the license was fetched from, if any
+
+	Language  string `json:"language,omitempty" yaml:"language,omitempty"`   // The language of the text, as a BCP 47 tag, if it is a translation
+	Rider     string `json:"rider,omitempty" yaml:"rider,omitempty"`         // Restrictive terms appended to the license text, if any
+	Reference bool   `json:"reference,omitempty" yaml:"reference,omitempty"` // Whether the text only links to the license, rather than stating its terms
 }
 
 // New creates a new License from explicitly passed license type and data
@@ -77,60 +205,65 @@ func New(licenseType, licenseText string) *License {
 }
 
 // NewFromFile will attempt to load a license from a file on disk, and guess the
-// type of license based on the bytes read.
-func NewFromFile(path string) (*License, error) {
-	licenseText, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	l := &License{
-		Text: string(licenseText),
-		File: path,
-	}
-
-	if err := l.GuessType(); err != nil {
-		return nil, err
-	}
-
-	return l, nil
+// type of license based on the bytes read, or on the text extracted from them
+// by the extractor registered for its extension, if any. Files exceeding the
+// limits set by WithMaxFileSize and WithMaxFileTime fail with ErrFileBudget.
+func NewFromFile(path string, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+	return guessFromFile(o.ctx, path, o)
 }
 
 // NewFromDir will search a directory for well-known and accepted license file
 // names, and if one is found, read in its content and guess the license type.
-func NewFromDir(dir string) (*License, error) {
-	ls, err := guessFromDir(dir)
-	if err != nil {
-		return nil, err
-	}
+// If several are found, the first recognized one is picked, by the priority set
+// by WithFilePriority, if any, and else by the names of the files.
+func NewFromDir(dir string, opts ...Option) (*License, error) {
+	return NewFromDirCtx(newOptions(opts).ctx, dir, opts...)
+}
 
-	for _, l := range ls {
-		if l.Type != LicenseUnrecognized {
-			return l, nil
-		}
-	}
-	return nil, ErrUnrecognizedLicense
+// NewFromDirCtx is like NewFromDir, but stops with the error of ctx once it is
+// done.
+func NewFromDirCtx(ctx context.Context, dir string, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+	return o.firstRecognized(scanDir(ctx, dir, o))
 }
 
 // NewLicensesFromDir will search a directory for well-known and accepted license files
 // names, and if one is found, read in its content and guess the license type.
-func NewLicensesFromDir(dir string) ([]*License, error) {
-	return guessFromDir(dir)
+// The licenses are ordered by the names of their files.
+func NewLicensesFromDir(dir string, opts ...Option) ([]*License, error) {
+	return NewLicensesFromDirCtx(newOptions(opts).ctx, dir, opts...)
+}
+
+// NewLicensesFromDirCtx is like NewLicensesFromDir, but stops with the error of
+// ctx once it is done.
+func NewLicensesFromDirCtx(ctx context.Context, dir string, opts ...Option) ([]*License, error) {
+	return scanDir(ctx, dir, newOptions(opts))
 }
 
-// Recognized determines if the license is known to go-license.
+// Recognized determines if the license is known to go-license, either as one
+// of the KnownLicenses, as an identifier from the SPDX license list, as a
+// public-domain dedication, or as a registered license, optionally with an
+// exception from the SPDX license exception list.
 func (l *License) Recognized() bool {
-	for _, license := range KnownLicenses {
-		if license == l.Type {
-			return true
-		}
-	}
-	return false
+	return defaultRegistry.Recognized(l.Type)
 }
 
 // GuessType will scan license text and attempt to guess what license type it
 // describes. It will return the license type on success, or an error if it
-// cannot accurately guess the license type.
+// cannot accurately guess the license type. A license exception found in the
+// text, such as the LLVM exception to Apache-2.0, is represented by an SPDX
+// expression like "Apache-2.0 WITH LLVM-exception". The Commons Clause is
+// represented by an expression like "MIT AND LicenseRef-Commons-Clause", and
+// other restrictive terms appended to the text of a license, known as riders,
+// are set as the Rider of the license. Texts which only link to the text of a
+// license, such as "Licensed under https://opensource.org/licenses/MIT", are
+// guessed as that license, flagged as its Reference, as done by
+// LicenseFromURL.
+//
+// Plain text copies of the canonical texts of the SPDX license list, which
+// differ only in their copyright notices, case or whitespace, are recognized
+// by their hash, without any scanning.
 //
 // This method is a hack. It might be more accurate to also scan the entire body
 // of license text and compare it using an algorithm like Jaro-Winkler or
@@ -140,81 +273,196 @@ func (l *License) Recognized() bool {
 // completely deterministic on which license is in play. For now, we will just
 // scan until we find differentiating strings and call that good-enuf.gov.
 func (l *License) GuessType() error {
-	newlineRegexp := regexp.MustCompile("(\r\n|\n)")
-	spaceRegexp := regexp.MustCompile("\\s{2,}")
+	_, err := l.guessType()
+	return err
+}
+
+// guessType guesses the type of the license as described by GuessType, and
+// returns the phrases of the normalized text which identify it.
+func (l *License) guessType() ([]string, error) {
+	return l.guessDefinedType(registeredDefinitions())
+}
 
-	// Lower case everything to make comparison more adaptable
-	comp := strings.ToLower(l.Text)
+// guessDefinedType guesses the type of the license as described by GuessType,
+// with the given licenses in place of the registered ones.
+func (l *License) guessDefinedType(defs []*LicenseDefinition) ([]string, error) {
+	// Copies of canonical texts are recognized before any scanning, unless a
+	// defined license may take precedence
+	l.Language, l.Rider, l.Reference = "", "", false
+	exact, isExact := exactType(l.Text)
+	if isExact && len(defs) == 0 {
+		l.Type = exact
+		return nil, nil
+	}
+
+	// Lower case everything to make comparison more adaptable, after removing
+	// any formatting and folding typographic characters
+	comp := strings.ToLower(prepareText(l.Text))
 
 	// Kill the newlines, since it is not clear if the provided license will
 	// contain them or not, and either way it does not change the terms of the
 	// license, so one is not "more correct" than the other. This just replaces
 	// them with spaces. Also replace multiple spaces with a single space to
 	// make comparison more simple.
-	comp = newlineRegexp.ReplaceAllLiteralString(comp, " ")
-	comp = spaceRegexp.ReplaceAllLiteralString(comp, " ")
-
-	switch {
-	case scan(comp, "permission is hereby granted, free of charge, to any "+
-		"person obtaining a copy of this software"):
-		l.Type = LicenseMIT
-
-	case scan(comp, "permission to use, copy, modify, and/or distribute this "+
-		"software for any"):
-		l.Type = LicenseISC
-
-	case scan(comp, "apache license version 2.0, january 2004") ||
-		scan(comp, "http://www.apache.org/licenses/license-2.0"):
-		l.Type = LicenseApache20
+	comp = collapseSpace(comp)
 
-	case scan(comp, "gnu general public license version 2, june 1991"):
-		l.Type = LicenseGPL20
+	// Defined licenses take precedence over the built-in ones
+	if licenseType, phrases, ok := matchDefinitions(comp, defs); ok {
+		l.Type = licenseType
+		return phrases, nil
+	}
+	if isExact {
+		l.Type = exact
+		return nil, nil
+	}
 
-	case scan(comp, "gnu general public license version 3, 29 june 2007"):
-		l.Type = LicenseGPL30
+	// found records the phrases which identify the license, if all are found
+	var phrases []string
+	found := func(match ...string) bool {
+		for _, m := range match {
+			if !scan(comp, m) {
+				return false
+			}
+		}
+		phrases = append(phrases, match...)
+		return true
+	}
 
-	case scan(comp, "gnu lesser general public license version 2.1, "+
-		"february 1999"):
-		l.Type = LicenseLGPL21
+	// The Commons Clause is a rider, which restricts the license it is
+	// attached to
+	commonsClause := found("license condition v1.0", "the right to sell the software")
+
+	// The built-in licenses are detected by the first rule of the table
+	// which matches
+	guessed := false
+	for _, r := range loadRules() {
+		if licenseType, matched, ok := r.guess(comp); ok {
+			l.Type, guessed = licenseType, true
+			phrases = append(phrases, matched...)
+			break
+		}
+	}
+	if !guessed {
+		if t, ok := guessTranslation(comp); ok {
+			l.Type, l.Language = t.licenseType, t.language
+			return t.phrases, nil
+		}
 
-	case scan(comp, "gnu lesser general public license version 3, "+
-		"29 june 2007"):
-		l.Type = LicenseLGPL30
+		// Texts which only link to a license, such as "Licensed under
+		// https://opensource.org/licenses/MIT", are flagged as references,
+		// since they do not state its terms
+		licenseType, link, ok := referencedType(l.Text)
+		switch {
+		case ok:
+			l.Type, l.Reference = licenseType, true
+			phrases = append(phrases, strings.ToLower(link))
+		case commonsClause:
+			l.Type = LicenseCommonsClause
+			return phrases, nil
+		default:
+			// Proprietary texts have no canonical text, so they are
+			// guessed with no confidence, for audits to triage
+			if marker, ok := proprietaryMarker(comp); ok {
+				l.Type = LicenseProprietary
+				return []string{marker}, nil
+			}
+			return nil, ErrUnrecognizedLicense
+		}
+	}
 
-	case scan(comp, "gnu affero general public license "+
-		"version 3, 19 november 2007"):
-		l.Type = LicenseAGPL30
+	// Exceptions only grant additional permissions, so they are reported in
+	// addition to the license
+	if exception, phrase := guessException(comp, l.Type); exception != "" {
+		l.Type += " WITH " + exception
+		if indexOf(phrases, phrase) < 0 {
+			phrases = append(phrases, phrase)
+		}
+	}
 
-	case scan(comp, "mozilla public license") && scan(comp, "version 2.0"):
-		l.Type = LicenseMPL20
+	// Riders are reported along with the license they restrict
+	if commonsClause {
+		l.Type += " AND " + LicenseCommonsClause
+	} else {
+		l.Rider = findRider(prepareText(l.Text), l.Type)
+	}
+	return phrases, nil
+}
 
-	case scan(comp, "redistribution and use in source and binary forms"):
+// collapseSpace replaces each newline with a space, and then each run of two
+// or more whitespace characters with a single space.
+func collapseSpace(text string) string {
+	out := make([]byte, 0, len(text))
+	run := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
 		switch {
-		case scan(comp, "neither the name of"):
-			l.Type = LicenseBSD3Clause
-		default:
-			l.Type = LicenseBSD2Clause
+		case c == '\r' && i+1 < len(text) && text[i+1] == '\n':
+			i++
+			c = ' '
+		case c == '\n':
+			c = ' '
 		}
+		if c != ' ' && c != '\t' && c != '\f' && c != '\r' {
+			run = 0
+			out = append(out, c)
+			continue
+		}
+		if run++; run == 1 {
+			out = append(out, c)
+		} else {
+			out[len(out)-1] = ' '
+		}
+	}
+	return string(out)
+}
 
-	case scan(comp, "common development and distribution license (cddl) "+
-		"version 1.0"):
-		l.Type = LicenseCDDL10
-
-	case scan(comp, "eclipse public license - v 1.0"):
-		l.Type = LicenseEPL10
+// preamble returns the text preceding the title of a license, where a file
+// containing the license usually states which versions of it are granted.
+func preamble(comp, title string) string {
+	if i := strings.Index(comp, title); i >= 0 {
+		return comp[:i]
+	}
+	return ""
+}
 
-	case scan(comp, "permission is granted to anyone to use this software for any purpose"):
-		l.Type = LicenseZlib
+// gnuVariant returns the "-only" or "-or-later" variant of a GNU license type
+// of the given version, as granted by the normalized text. The license texts
+// themselves include the "or (at your option) any later version" grant in
+// their instructions, so only the text granting the license is to be checked.
+// Without either grant, the deprecated, ambiguous type is returned.
+func gnuVariant(licenseType, version, grant string) string {
+	switch {
+	case scan(grant, "any later version") || scan(grant, " or later") ||
+		scan(grant, "-or-later"):
+		return licenseType + "-or-later"
 
-	case scan(comp, "this is free and unencumbered software released into "+
-		"the public domain"):
-		l.Type = LicenseUnlicense
+	case scan(grant, " "+version+" only") || scan(grant, "v"+version+" only") ||
+		scan(grant, "-only"):
+		return licenseType + "-only"
+	}
+	return licenseType
+}
 
+// gfdlVariant returns the variant of a GFDL type of the given version, as
+// granted by the normalized text: "-only" or "-or-later" as for gnuVariant,
+// preceded by "-invariants" or "-no-invariants" if the grant tells whether
+// the document has invariant sections. The variants with invariants have no
+// ambiguous type, so they default to "-only", as the deprecated type means.
+func gfdlVariant(licenseType, version, grant string) string {
+	variant := gnuVariant(licenseType, version, grant)
+	invariants := ""
+	switch {
+	case scan(grant, "with no invariant sections"):
+		invariants = "-no-invariants"
+	case scan(grant, "with the invariant sections being"):
+		invariants = "-invariants"
 	default:
-		return ErrUnrecognizedLicense
+		return variant
 	}
-
-	return nil
+	if variant == licenseType {
+		variant += "-only"
+	}
+	return licenseType + invariants + strings.TrimPrefix(variant, licenseType)
 }
 
 // scan is a shortcut function to check for a literal match within a string
@@ -224,6 +472,42 @@ func scan(text, match string) bool {
 	return strings.Contains(text, match)
 }
 
+// firstRecognized returns the first of the licenses found whose type was
+// guessed, in the order of the file priority of o, if any, and else in the
+// order of the files.
+func (o *options) firstRecognized(ls []*License, err error) (*License, error) {
+	if err != nil {
+		return nil, err
+	}
+	var first *License
+	rank := -1
+	for _, l := range ls {
+		if undetermined(l.Type) {
+			continue
+		}
+		if r := o.fileRank(filepath.Base(l.File)); first == nil || r < rank {
+			first, rank = l, r
+		}
+	}
+	if first == nil {
+		if o.spdxResults && len(ls) > 0 {
+			return ls[0], nil
+		}
+		return nil, ErrUnrecognizedLicense
+	}
+	return first, nil
+}
+
+// undetermined determines if a license type is that of a license whose type
+// was not determined.
+func undetermined(licenseType string) bool {
+	switch licenseType {
+	case "", LicenseUnrecognized, LicenseNoAssertion, LicenseNone:
+		return true
+	}
+	return false
+}
+
 // returns a []string of files in a directory, or error
 func readDirectory(dir string) ([]string, error) {
 	fileinfos, err := ioutil.ReadDir(dir)
@@ -237,33 +521,186 @@ func readDirectory(dir string) ([]string, error) {
 	return files, nil
 }
 
-// guessFromDir searches a given directory (non-recursively) for files with well-
-// established names that indicate license content.
-func guessFromDir(dir string) (licenses []*License, err error) {
+// guessFromFile reads a license file, of the file system set by WithFS, if
+// any, and guesses its type, as described by NewFromFile.
+func guessFromFile(ctx context.Context, name string, o *options) (*License, error) {
+	return guessFromSource(ctx, o.fileSource(name), name, o)
+}
 
-	files, err := readDirectory(dir)
+// guessFromSource reads the named license file from src, and guesses its type,
+// within the limits set by o, as guessFromFile does.
+func guessFromSource(ctx context.Context, src *licenseSource, name string, o *options) (*License, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if o.exceedsMaxSize(src, name) {
+		o.skipFile()
+		return nil, ErrFileBudget
+	}
+	text, err := o.readLicenseFile(src, name)
 	if err != nil {
 		return nil, err
 	}
-	patterns, err := complileLicensePatters(DefaultLicenseFiles)
+
+	l := &License{Text: text, File: name}
+	if err := o.budgetedGuess(o.guesser(), l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// scanDir searches a directory of the file system set by WithFS, if any,
+// (non-recursively) for license files, as configured by o.
+func scanDir(ctx context.Context, dir string, o *options) ([]*License, error) {
+	if o.fsys != nil {
+		return guessFromFS(ctx, o.fsys, dir, o)
+	}
+	return guessFromDir(ctx, dir, o)
+}
+
+// guessFromDir searches a given directory (non-recursively) for files with well-
+// established names that indicate license content, as configured by o.
+func guessFromDir(ctx context.Context, dir string, o *options) (licenses []*License, err error) {
+	files, err := readDirectory(dir)
 	if err != nil {
 		return nil, err
 	}
-	matchs, err := getLicenseFile(patterns, files)
+	return guessFromFiles(ctx, files, o, osSource(dir))
+}
+
+// licenseSource reads the files of a directory listing for guessFromFiles.
+type licenseSource struct {
+	join    func(name string) string               // Returns the path of a file of the listing
+	read    func(path string) ([]byte, error)      // Reads a file
+	open    func(path string) (fs.File, error)     // Opens a file, to read part of it, if possible
+	symlink func(path string) bool                 // Determines if a file is a symlink, if known
+	stat    func(path string) (fs.FileInfo, error) // Describes a file, following symlinks, if known
+}
+
+// fileSource returns the source of the directory of the named file, of the
+// file system set by WithFS, if any.
+func (o *options) fileSource(name string) *licenseSource {
+	if o.fsys != nil {
+		return fsSource(o.fsys, path.Dir(name))
+	}
+	return osSource(filepath.Dir(name))
+}
+
+// readerSource reads the text of r as the content of any file. Past the size
+// limit of o, only one more byte is read, for the size to be found exceeded.
+func readerSource(r io.Reader, o *options) *licenseSource {
+	return &licenseSource{
+		join: func(name string) string { return name },
+		read: func(string) ([]byte, error) {
+			if o.maxFileSize <= 0 {
+				return ioutil.ReadAll(r)
+			}
+			return ioutil.ReadAll(io.LimitReader(r, o.maxFileSize+1))
+		},
+	}
+}
+
+// osSource reads the files of a directory of the operating system.
+func osSource(dir string) *licenseSource {
+	return &licenseSource{
+		join: func(name string) string {
+			return filepath.Join(dir, name)
+		},
+		read: ioutil.ReadFile,
+		open: func(path string) (fs.File, error) {
+			return os.Open(path)
+		},
+		symlink: func(path string) bool {
+			info, err := os.Lstat(path)
+			return err == nil && info.Mode()&fs.ModeSymlink != 0
+		},
+		stat: os.Stat,
+	}
+}
+
+// guessFromFiles picks the files matching the license file name patterns of o
+// out of the given directory listing, reads each of them from src in the
+// order of their names, and guesses its type as configured by o, until ctx is done. Files which cannot be
+// guessed are reported as unrecognized, and files which cannot be read or
+// exceed their budget are left out, unless partial results are requested. Symlinks and duplicates are
+// handled as configured by o.
+func guessFromFiles(ctx context.Context, files []string, o *options, src *licenseSource) (licenses []*License, err error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	compiled, err := complileLicensePatters(o.licenseFiles())
 	if err != nil {
 		return nil, err
 	}
+	matchs, err := getLicenseFile(compiled, files)
+	if err == ErrNoLicenseFile && o.spdxResults {
+		return []*License{{Type: LicenseNone}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sort.Strings(matchs)
 
+	guess := o.guesser()
+	var seen []fs.FileInfo
+	folded := make(map[string]string)
 	for _, match := range matchs {
-		file := filepath.Join(dir, match)
-		l, err := NewFromFile(file)
-		if err == ErrUnrecognizedLicense {
-			licenses = append(licenses, &License{
-				Type: LicenseUnrecognized,
-				File: file,
-			})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		file := src.join(match)
+		if o.symlinks == SymlinkNone && src.symlink != nil && src.symlink(file) {
+			continue
+		}
+		if o.exceedsMaxSize(src, file) {
+			o.skipFile()
+			if o.partial {
+				licenses = append(licenses, &License{Type: o.unrecognized(), File: file})
+			}
+			continue
+		}
+		text, err := o.readLicenseFile(src, file)
+		if err != nil {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if o.partial {
+				licenses = append(licenses, &License{Type: o.unrecognized(), File: file})
+			}
+			continue
 		}
-		if err == nil && l.GuessType() == nil {
+
+		if o.duplicates != DuplicatesKeep && src.stat != nil {
+			if info, err := src.stat(file); err == nil {
+				if sameFile(seen, info) {
+					continue
+				}
+				seen = append(seen, info)
+			}
+		}
+		if o.duplicates == DuplicatesFoldCase {
+			key := strings.ToLower(match)
+			if prev, ok := folded[key]; ok && prev == text {
+				continue
+			}
+			folded[key] = text
+		}
+
+		l := &License{Text: text, File: file}
+		switch err := o.budgetedGuess(guess, l); {
+		case err == nil:
+			licenses = append(licenses, l)
+		case err == ErrFileBudget:
+			if o.partial {
+				licenses = append(licenses, &License{Type: o.unrecognized(), File: file})
+			}
+		case err == ErrUnrecognizedLicense:
+			l.Type = o.unrecognized()
+			if !o.partial && !o.spdxResults {
+				l.Text = ""
+			}
 			licenses = append(licenses, l)
 		}
 	}
@@ -275,6 +712,54 @@ func guessFromDir(dir string) (licenses []*License, err error) {
 	return licenses, nil
 }
 
+// readLicenseFile reads a license file from src, and extracts its text. Only
+// the bytes up to the size limit are read if files are truncated.
+func (o *options) readLicenseFile(src *licenseSource, file string) (string, error) {
+	data, err := o.readFile(src, file)
+	if err != nil {
+		return "", err
+	}
+	return extractText(file, data, o.maxFileSize)
+}
+
+// readFile reads a file from src, as readLicenseFile does, without extracting
+// its text.
+func (o *options) readFile(src *licenseSource, file string) ([]byte, error) {
+	if !o.truncate || o.maxFileSize <= 0 {
+		return src.read(file)
+	}
+
+	var data []byte
+	var err error
+	if src.open != nil {
+		var f fs.File
+		if f, err = src.open(file); err != nil {
+			return nil, err
+		}
+		data, err = ioutil.ReadAll(io.LimitReader(f, o.maxFileSize))
+		f.Close()
+	} else {
+		data, err = src.read(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > o.maxFileSize {
+		data = data[:o.maxFileSize]
+	}
+	return data, nil
+}
+
+// sameFile determines if the file described by info is one of the files seen.
+func sameFile(seen []fs.FileInfo, info fs.FileInfo) bool {
+	for _, s := range seen {
+		if os.SameFile(s, info) {
+			return true
+		}
+	}
+	return false
+}
+
 // returns files that case-insensitive matches any of the license
 // files.  This is generic functionality so pulled out into separate
 // function for testing
@@ -284,6 +769,7 @@ func matchLicenseFile(patterns []*regexp.Regexp, files []string) []string {
 		for _, pattern := range patterns {
 			if pattern.MatchString(file) {
 				out = append(out, file)
+				break
 			}
 		}
 	}
@@ -299,10 +785,31 @@ func getLicenseFile(patterns []*regexp.Regexp, files []string) ([]string, error)
 	return matches, nil
 }
 
+// complileLicensePatters compiles license file name patterns, each only once
+// since the patterns may change between calls.
 func complileLicensePatters(licenses []string) (patterns []*regexp.Regexp, err error) {
 	for _, license := range licenses {
-		pattern := regexp.MustCompile("(?i)^" + strings.Replace(license, "*", ".*", -1))
+		if pattern, ok := licensePatterns.Load(license); ok {
+			patterns = append(patterns, pattern.(*regexp.Regexp))
+			continue
+		}
+		pattern, err := compileFilePattern(license)
+		if err != nil {
+			return nil, err
+		}
+		licensePatterns.Store(license, pattern)
 		patterns = append(patterns, pattern)
 	}
 	return patterns, nil
 }
+
+// compileFilePattern compiles a file name pattern, in which "*" matches any
+// characters and every other character matches itself, case-insensitively.
+// The pattern matches whole file names.
+func compileFilePattern(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
@@ -1,10 +1,13 @@
 package license_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	license "github.com/nfukasawa/go-license"
 )
@@ -130,6 +133,222 @@ func TestNewFromDir(t *testing.T) {
 	}
 }
 
+func TestNewFromDir_FilePatterns(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	for name, ltype := range map[string]string{
+		"LEGAL.txt": license.LicenseMIT,
+		"LICENSE":   license.LicenseApache20,
+	} {
+		licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(d, name), licenseText, 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Replaced patterns only match the given file names
+	l, err := license.NewFromDir(d, license.WithFilePatterns("legal*"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected license type: %s", l.Type)
+	}
+
+	// Additional patterns extend the default ones, without duplicates
+	ls, err := license.NewLicensesFromDir(d, license.WithAdditionalFilePatterns("legal*", "license"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	if _, err := license.NewFromDir(d, license.WithFilePatterns("notice*")); err != license.ErrNoLicenseFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoLicenseFile, err)
+	}
+
+	// Patterns match whole names, with characters other than "*" as is
+	for _, pattern := range []string{"legal.", "legalxtxt", "legal[", "(legal"} {
+		if _, err := license.NewFromDir(d, license.WithFilePatterns(pattern)); err != license.ErrNoLicenseFile {
+			t.Fatalf("%s:\nexpected: %s\ngot: %v", pattern, license.ErrNoLicenseFile, err)
+		}
+	}
+	if l, err := license.NewFromDir(d, license.WithFilePatterns("legal.txt")); err != nil || l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected license: %v (%v)", l, err)
+	}
+}
+
+func TestNewLicensesFromDir_PartialResults(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for name, text := range map[string]string{
+		"LICENSE":   string(licenseText),
+		"COPYING":   "All rights reserved by the authors.",
+		"LICENSES/": "",
+	} {
+		if text == "" {
+			err = os.Mkdir(filepath.Join(d, name), 0755)
+		} else {
+			err = ioutil.WriteFile(filepath.Join(d, name), []byte(text), 0644)
+		}
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Unrecognized files are reported without their text by default
+	ls, err := license.NewLicensesFromDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].Type != license.LicenseUnrecognized || ls[0].Text != "" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	ls, err = license.NewLicensesFromDir(d, license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]string{
+		"COPYING":  "All rights reserved by the authors.",
+		"LICENSE":  string(licenseText),
+		"LICENSES": "",
+	}
+	if len(ls) != len(expected) {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+	for _, l := range ls {
+		name := filepath.Base(l.File)
+		if l.Text != expected[name] {
+			t.Fatalf("%s: unexpected text: %s", name, l.Text)
+		}
+		if (name == "LICENSE") != (l.Type == license.LicenseMIT) {
+			t.Fatalf("%s: unexpected type: %s", name, l.Type)
+		}
+	}
+
+	// Found licenses are still preferred
+	l, err := license.NewFromDir(d, license.WithPartialResults())
+	if err != nil || l.Type != license.LicenseMIT {
+		t.Fatalf("unexpected license: %v (%v)", l, err)
+	}
+}
+
+func TestNewFromDir_SPDXResults(t *testing.T) {
+	d := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(d, "COPYING"), []byte("All rights reserved by the authors."), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	copyFixture(t, "MIT", filepath.Join(d, "sub", "LICENSE"))
+	if err := os.Mkdir(filepath.Join(d, "empty"), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Undeterminable license files are reported with their text
+	l, err := license.NewFromDir(d, license.WithSPDXResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseNoAssertion || l.File != filepath.Join(d, "COPYING") || l.Text == "" {
+		t.Fatalf("unexpected license: %v", l)
+	}
+
+	// Directories without license files are reported as such
+	l, err = license.NewFromDir(filepath.Join(d, "empty"), license.WithSPDXResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseNone || l.File != "" {
+		t.Fatalf("unexpected license: %v", l)
+	}
+
+	// Only the directory scanned recursively reports no license
+	results, err := license.NewFromDirRecursive(filepath.Join(d, "empty"), license.WithSPDXResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ls := results[filepath.Join(d, "empty")]; len(results) != 1 || len(ls) != 1 || ls[0].Type != license.LicenseNone {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	results, err = license.NewFromDirRecursive(d, license.WithSPDXResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results[filepath.Join(d, "sub")][0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected results: %v", results)
+	}
+}
+
+func TestNewLicensesFromDir_Order(t *testing.T) {
+	// Listings in archive order are reported in the order of their names
+	fsys := fstest.MapFS{}
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, name := range []string{"LICENSE.md", "COPYING", "LICENSE"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 3, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, err := tw.Write([]byte("MIT")); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	results, err := license.NewFromTar(bytes.NewReader(buf.Bytes()), license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ls := results["."]
+	if len(ls) != 3 || ls[0].File != "COPYING" || ls[1].File != "LICENSE" || ls[2].File != "LICENSE.md" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	for name, ltype := range map[string]string{"LICENSE.md": "MIT", "COPYING": "GPL-2.0", "LICENSE": "Apache-2.0"} {
+		text, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		fsys[name] = &fstest.MapFile{Data: text}
+	}
+
+	// The first file is picked, unless the priority of the files is set
+	l, err := license.NewFromFS(fsys, ".")
+	if err != nil || l.Type != license.LicenseGPL20 {
+		t.Fatalf("unexpected license: %v (%v)", l, err)
+	}
+	for _, c := range []struct {
+		priority []string
+		expected string
+	}{
+		{[]string{"license", "copying", "license.md"}, license.LicenseApache20},
+		{[]string{"*.md"}, license.LicenseMIT},
+		{[]string{"LICENSE.txt"}, license.LicenseGPL20},
+	} {
+		l, err := license.NewFromFS(fsys, ".", license.WithFilePriority(c.priority...))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("%v\nexpected: %s\ngot: %s", c.priority, c.expected, l.Type)
+		}
+	}
+}
+
 func TestLicenseRecognized(t *testing.T) {
 	// Known licenses are recognized
 	l := license.New("MIT", "The MIT License (MIT)")
@@ -137,6 +356,12 @@ func TestLicenseRecognized(t *testing.T) {
 		t.Fatalf("license was not recognized")
 	}
 
+	// Licenses from the SPDX license list are recognized
+	l = license.New("Beerware", "THE BEER-WARE LICENSE")
+	if !l.Recognized() {
+		t.Fatalf("SPDX license was not recognized")
+	}
+
 	// Unknown licenses are not recognized
 	l = license.New("None", "No license text")
 	if l.Recognized() {
@@ -177,3 +402,405 @@ func TestLicenseTypes_Abbreviated(t *testing.T) {
 		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseApache20, l.Type)
 	}
 }
+
+func TestLicenseTypes_0BSD(t *testing.T) {
+	// The older wording without "and/or" is recognized as well
+	l := license.New("", `Permission to use, copy, modify, and distribute this software for any
+purpose with or without fee is hereby granted.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES.`)
+	if err := l.GuessType(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.License0BSD {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.License0BSD, l.Type)
+	}
+}
+
+func TestLicenseTypes_Versions(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`MOZILLA PUBLIC LICENSE
+Version 1.1
+
+1. Definitions.
+
+1.0.1. "Commercial Use" means distribution or otherwise making the
+Covered Code available to a third party.`, license.LicenseMPL11},
+		{`Eclipse Public License - v 2.0
+
+THE ACCOMPANYING PROGRAM IS PROVIDED UNDER THE TERMS OF THIS ECLIPSE
+PUBLIC LICENSE ("AGREEMENT").`, license.LicenseEPL20},
+		{`COMMON DEVELOPMENT AND DISTRIBUTION LICENSE (CDDL)
+Version 1.1
+
+1. Definitions.`, license.LicenseCDDL11},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_Perl(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`This library is free software; you can redistribute it and/or modify it
+under the same terms as Perl itself.`, license.LicensePerl},
+		{`The "Artistic License"
+
+Preamble
+
+The intent of this document is to state the conditions under which a
+Package may be copied, such that the Copyright Holder maintains some
+semblance of artistic control over the development of the package.`, license.LicenseArtistic10},
+		{`The "Artistic License"
+
+Preamble
+
+The intent of this document is to state the conditions under which a
+Package may be copied, such that the Copyright Holder maintains some
+semblance of artistic control over the development of the package.
+
+7. C subroutines (or comparably compiled subroutines in other
+languages) supplied by you and linked into this Package in order to
+emulate subroutines and variables of the language defined by this
+Package shall not be considered part of this Package.`, license.LicenseArtistic10Perl},
+		{`Academic Free License ("AFL") v. 3.0
+
+This Academic Free License (the "License") applies to any original work of
+authorship (the "Original Work") whose owner (the "Licensor") has placed the
+following licensing notice adjacent to the copyright notice for the Original
+Work:`, license.LicenseAFL30},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_Microsoft(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`Microsoft Public License (Ms-PL)
+
+This license governs use of the accompanying software. If you use the
+software, you accept this license. If you do not accept the license, do not
+use the software.
+
+3. Conditions and Limitations
+(A) No Trademark License- This license does not grant you rights to use any
+contributors' name, logo, or trademarks.`, license.LicenseMSPL},
+		{`Microsoft Reciprocal License (Ms-RL)
+
+This license governs use of the accompanying software. If you use the
+software, you accept this license. If you do not accept the license, do not
+use the software.
+
+3. Conditions and Limitations
+(A) Reciprocal Grants- For any file you distribute that contains code from the
+software (in source code or binary format), you must provide recipients the
+source code to that file along with a copy of this license.`, license.LicenseMSRL},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_OpenSSL(t *testing.T) {
+	cases := []string{
+		`  LICENSE ISSUES
+  ==============
+
+  The OpenSSL toolkit stays under a double license, i.e. both the conditions of
+  the OpenSSL License and the original SSLeay license apply to the toolkit.`,
+		`Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions
+are met:
+
+3. All advertising materials mentioning features or use of this
+   software must display the following acknowledgment:
+   "This product includes software developed by the OpenSSL Project
+   for use in the OpenSSL Toolkit. (http://www.openssl.org/)"
+
+3. All advertising materials mentioning features or use of this software
+   must display the following acknowledgement:
+   "This product includes cryptographic software written by
+    Eric Young (eay@cryptsoft.com)"`,
+	}
+	for _, text := range cases {
+		l := license.New("", text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != license.LicenseOpenSSL {
+			t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseOpenSSL, l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_Fonts(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"OFL.txt", `Copyright 2010 The Example Project Authors
+
+This Font Software is licensed under the SIL Open Font License, Version 1.1.
+This license is copied below, and is also available with a FAQ at:
+https://openfontlicense.org
+
+SIL OPEN FONT LICENSE Version 1.1 - 26 February 2007`, license.LicenseOFL11},
+		{"OFL.txt", `Copyright (c) 2010, Jane Doe (jane@example.com),
+with Reserved Font Name Example.
+
+SIL OPEN FONT LICENSE Version 1.1 - 26 February 2007`, license.LicenseOFL11RFN},
+		{"LICENCE.txt", `UBUNTU FONT LICENCE Version 1.0
+
+PREAMBLE
+This licence allows the licensed fonts to be used, studied, modified and
+redistributed freely.`, license.LicenseUbuntuFont10},
+	}
+	for _, c := range cases {
+		d, err := ioutil.TempDir("", "go-license")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer os.RemoveAll(d)
+		if err := ioutil.WriteFile(filepath.Join(d, c.name), []byte(c.text), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		l, err := license.NewFromDir(d)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_GFDL(t *testing.T) {
+	const title = `
+
+                GNU Free Documentation License
+                 Version 1.3, 3 November 2008
+
+ Copyright (C) 2000, 2001, 2002, 2007, 2008 Free Software Foundation, Inc.`
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{title, license.LicenseGFDL13},
+		{`Permission is granted to copy, distribute and/or modify this document
+under the terms of the GNU Free Documentation License, Version 1.3 or any
+later version published by the Free Software Foundation.` + title, license.LicenseGFDL13 + "-or-later"},
+		{`Permission is granted to copy, distribute and/or modify this document
+under the terms of the GNU Free Documentation License, Version 1.3 or any
+later version published by the Free Software Foundation; with no Invariant
+Sections, no Front-Cover Texts, and no Back-Cover Texts.` + title, "GFDL-1.3-no-invariants-or-later"},
+		{`Permission is granted to copy, distribute and/or modify this document
+under the terms of the GNU Free Documentation License, Version 1.3; with the
+Invariant Sections being "History", no Front-Cover Texts, and no Back-Cover
+Texts.` + title, "GFDL-1.3-invariants-only"},
+		{`GNU Free Documentation License
+Version 1.2, November 2002`, license.LicenseGFDL12},
+		{`GNU Free Documentation License
+Version 1.1, March 2000`, license.LicenseGFDL11},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if o, ok := license.Obligations(l.Type); !ok || !o.SameLicense {
+			t.Fatalf("%s: unexpected obligations: %+v", l.Type, o)
+		}
+	}
+}
+
+func TestLicenseTypes_Jest(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`Copyright (c) 2002 JSON.org
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+The Software shall be used for Good, not Evil.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND.`, license.LicenseJSON},
+		{`"THE BEER-WARE LICENSE" (Revision 42):
+<phk@FreeBSD.ORG> wrote this file. As long as you retain this notice you
+can do whatever you want with this stuff. If we meet some day, and you think
+this stuff is worth it, you can buy me a beer in return Poul-Henning Kamp`, license.LicenseBeerware},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if license.IsOSIApproved(l.Type) {
+			t.Fatalf("%s: unexpectedly OSI approved", l.Type)
+		}
+	}
+}
+
+func TestLicenseTypes_PublicDomain(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`The author disclaims copyright to this source code.  In place of
+a legal notice, here is a blessing:
+
+    May you do good and not evil.
+    May you find forgiveness for yourself and forgive others.
+    May you share freely, never taking more than you give.`, license.LicenseBlessing},
+		{"This code has been released into the public domain by its author.", license.LicensePublicDomain},
+		{"All files in this directory are dedicated to the public domain.", license.LicensePublicDomain},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if !l.Recognized() {
+			t.Fatalf("%s was not recognized", l.Type)
+		}
+		if info := license.Info(l.Type); info.Category != license.CategoryPublicDomain {
+			t.Fatalf("unexpected category of %s: %s", l.Type, info.Category)
+		}
+	}
+}
+
+func TestLicenseTypes_GNUVariants(t *testing.T) {
+	cases := []struct {
+		file     string
+		preamble string
+		expected string
+	}{
+		{license.LicenseGPL20, "", license.LicenseGPL20},
+		{license.LicenseGPL20, "This program is licensed under the GPL, version 2 or (at your option) any later version.\n\n",
+			license.LicenseGPL20OrLater},
+		{license.LicenseGPL20, "Licensed under GPLv2 only.\n\n", license.LicenseGPL20Only},
+		{license.LicenseGPL30, "SPDX: GPL-3.0-or-later\n\n", license.LicenseGPL30OrLater},
+		{license.LicenseLGPL21, "This library may be used under version 2.1 only.\n\n", license.LicenseLGPL21Only},
+		{license.LicenseAGPL30, "AGPL version 3 or later\n\n", license.LicenseAGPL30OrLater},
+	}
+	for _, c := range cases {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", c.file))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l := license.New("", c.preamble+string(lbytes))
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("%q\nexpected: %s\ngot: %s", c.preamble, c.expected, l.Type)
+		}
+	}
+}
+
+func BenchmarkGuessType(b *testing.B) {
+	var texts []string
+	for _, ltype := range license.KnownLicenses {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			b.Fatalf("err: %s", err)
+		}
+		texts = append(texts, string(lbytes))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := license.New("", texts[i%len(texts)])
+		if err := l.GuessType(); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
+
+func BenchmarkGuessType_ByLicense(b *testing.B) {
+	for _, ltype := range license.KnownLicenses {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			b.Fatalf("err: %s", err)
+		}
+		text := string(lbytes)
+		b.Run(ltype, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				l := license.New("", text)
+				if err := l.GuessType(); err != nil {
+					b.Fatalf("err: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNewFromDir(b *testing.B) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		b.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		b.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), lbytes, 0644); err != nil {
+		b.Fatalf("err: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := license.NewFromDir(d); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package licensetest provides a corpus of real-world variants of license
+// files, and helpers to test that license texts are detected as expected, so
+// that users adding detectors or license definitions can validate them
+// against known-good data:
+//
+//	func TestDetector(t *testing.T) {
+//		s, err := license.NewScanner(license.WithDetectors(detector))
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		licensetest.Run(t, s.GuessType, licensetest.Corpus())
+//	}
+package licensetest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// IndexFile is the name of the file listing the license files of a corpus and
+// the license types they are detected as, read by ReadCorpus, as a JSON array
+// of objects with "file" and "type" fields.
+const IndexFile = "corpus.json"
+
+//go:embed corpus
+var corpus embed.FS
+
+// Case is a license file of a corpus, and the license type it is detected as.
+type Case struct {
+	File string `json:"file"` // The path of the license file, relative to the corpus
+	Type string `json:"type"` // The license type expected
+	Text string `json:"-"`    // The text of the license file
+}
+
+// GuessFunc guesses the type of a license, such as Scanner.GuessType, or
+// License.GuessType as a method expression.
+type GuessFunc func(l *license.License) error
+
+// Corpus returns the cases of the corpus of this package, license files in
+// the styles found in real projects: with the names of their copyright holders,
+// in Markdown, with CRLF line endings, as short notices, and granting versions
+// of the GNU licenses or exceptions. The cases may be changed by the caller.
+func Corpus() []*Case {
+	fsys, err := fs.Sub(corpus, "corpus")
+	if err != nil {
+		panic(err)
+	}
+	cases, err := ReadCorpus(fsys)
+	if err != nil {
+		panic("licensetest: malformed corpus: " + err.Error())
+	}
+	return cases
+}
+
+// ReadCorpus reads the cases of a corpus of license files from the IndexFile
+// of a file system, such as os.DirFS("testdata/licenses").
+func ReadCorpus(fsys fs.FS) ([]*Case, error) {
+	data, err := fs.ReadFile(fsys, IndexFile)
+	if err != nil {
+		return nil, err
+	}
+	var cases []*Case
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("licensetest: %s: %w", IndexFile, err)
+	}
+	for _, c := range cases {
+		text, err := fs.ReadFile(fsys, path.Clean(c.File))
+		if err != nil {
+			return nil, err
+		}
+		c.Text = string(text)
+	}
+	return cases, nil
+}
+
+// Check guesses the type of the license file of the case, and returns an
+// error if it is not the type expected.
+func (c *Case) Check(guess GuessFunc) error {
+	l := license.New("", c.Text)
+	l.File = c.File
+	if err := guess(l); err != nil {
+		return fmt.Errorf("licensetest: %s: expected %s: %w", c.File, c.Type, err)
+	}
+	if l.Type != c.Type {
+		return fmt.Errorf("licensetest: %s: expected %s, got %s", c.File, c.Type, l.Type)
+	}
+	return nil
+}
+
+// Run checks each of the cases in a subtest named by its file.
+func Run(t *testing.T, guess GuessFunc, cases []*Case) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.File, func(t *testing.T) {
+			if err := c.Check(guess); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// AssertType reports an error to t unless the type of a license text is
+// guessed as expected.
+func AssertType(t testing.TB, guess GuessFunc, text, expected string) {
+	t.Helper()
+	if err := (&Case{File: "text", Type: expected, Text: text}).Check(guess); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,51 @@
+package licensetest_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/licensetest"
+)
+
+func TestCorpus(t *testing.T) {
+	cases := licensetest.Corpus()
+	if len(cases) == 0 {
+		t.Fatalf("no cases")
+	}
+	for _, c := range cases {
+		if c.Text == "" {
+			t.Fatalf("%s: no text", c.File)
+		}
+	}
+	licensetest.Run(t, (*license.License).GuessType, cases)
+}
+
+func TestReadCorpus(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		licensetest.IndexFile: {Data: []byte(`[{"file": "licenses/MIT", "type": "MIT"}]`)},
+		"licenses/MIT":        {Data: mit},
+	}
+	cases, err := licensetest.ReadCorpus(fsys)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(cases) != 1 || cases[0].Text != string(mit) {
+		t.Fatalf("\nexpected: %s\ngot: %v", "licenses/MIT", cases)
+	}
+	if err := cases[0].Check((*license.License).GuessType); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Mismatching types are reported
+	cases[0].Type = license.LicenseISC
+	if err := cases[0].Check((*license.License).GuessType); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
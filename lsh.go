@@ -0,0 +1,213 @@
+package license
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+const (
+	shingleSize = 3   // The number of words in a shingle
+	minHashes   = 128 // The number of hashes in a MinHash signature
+	lshBands    = 32  // The number of bands of a signature indexed, of minHashes/lshBands rows each
+)
+
+var (
+	defaultIndexOnce sync.Once
+	defaultIndex     *LicenseIndex
+)
+
+// NearLicense is a license found near a text by a LicenseIndex.
+type NearLicense struct {
+	Type       string  `json:"type" yaml:"type"`             // The license type
+	Similarity float64 `json:"similarity" yaml:"similarity"` // The estimated Jaccard similarity of the texts, between 0 and 1
+}
+
+// LicenseIndex finds the licenses whose texts are nearest to a text, such as
+// a heavily modified copy of a license which GuessType cannot recognize. Texts
+// are compared by the sets of their three-word shingles, whose similarity is
+// estimated by MinHash signatures, and the licenses compared are narrowed down
+// by locality-sensitive hashing of the signatures, so that lookups take time
+// independent of the number of licenses indexed. A LicenseIndex is safe for
+// concurrent use.
+type LicenseIndex struct {
+	mu         sync.RWMutex
+	signatures map[string][]uint64 // By license type
+	buckets    []map[uint64][]string
+}
+
+// NewLicenseIndex creates an empty LicenseIndex.
+func NewLicenseIndex() *LicenseIndex {
+	ix := &LicenseIndex{
+		signatures: make(map[string][]uint64),
+		buckets:    make([]map[uint64][]string, lshBands),
+	}
+	for band := range ix.buckets {
+		ix.buckets[band] = make(map[uint64][]string)
+	}
+	return ix
+}
+
+// Add indexes the text of a license type, replacing any text indexed for the
+// type before.
+func (ix *LicenseIndex) Add(licenseType, text string) {
+	sig := minHash(text)
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if old, ok := ix.signatures[licenseType]; ok {
+		for band, key := range bandKeys(old) {
+			ix.buckets[band][key] = removeString(ix.buckets[band][key], licenseType)
+		}
+	}
+	ix.signatures[licenseType] = sig
+	for band, key := range bandKeys(sig) {
+		ix.buckets[band][key] = append(ix.buckets[band][key], licenseType)
+	}
+}
+
+// Nearest returns up to k of the licenses indexed whose texts are most similar
+// to the text, most similar first, and by type if equally similar. Licenses
+// which share a band of their signature with the text are compared first, and
+// the others only if fewer than k of those are similar to it at all.
+func (ix *LicenseIndex) Nearest(text string, k int) []*NearLicense {
+	if k <= 0 {
+		return nil
+	}
+	sig := minHash(text)
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var near []*NearLicense
+	compare := func(licenseType string) {
+		if seen[licenseType] {
+			return
+		}
+		seen[licenseType] = true
+		if s := signatureSimilarity(sig, ix.signatures[licenseType]); s > 0 {
+			near = append(near, &NearLicense{Type: licenseType, Similarity: s})
+		}
+	}
+	for band, key := range bandKeys(sig) {
+		for _, licenseType := range ix.buckets[band][key] {
+			compare(licenseType)
+		}
+	}
+	if len(near) < k {
+		for licenseType := range ix.signatures {
+			compare(licenseType)
+		}
+	}
+
+	sort.Slice(near, func(i, j int) bool {
+		if near[i].Similarity != near[j].Similarity {
+			return near[i].Similarity > near[j].Similarity
+		}
+		return near[i].Type < near[j].Type
+	})
+	if len(near) > k {
+		near = near[:k]
+	}
+	return near
+}
+
+// NearestLicenses returns up to k of the licenses whose canonical texts, as
+// found on the SPDX license list, are nearest to the text, as done by
+// LicenseIndex.Nearest. Registered licenses can be compared by an index of
+// their own.
+func NearestLicenses(text string, k int) []*NearLicense {
+	defaultIndexOnce.Do(func() {
+		defaultIndex = NewLicenseIndex()
+		for _, l := range spdx.List() {
+			if l.Text != "" {
+				defaultIndex.Add(knownType(l.ID), l.Text)
+			}
+		}
+	})
+	return defaultIndex.Nearest(text, k)
+}
+
+// minHash computes the MinHash signature of the shingles of a text.
+func minHash(text string) []uint64 {
+	words := strings.FieldsFunc(strings.ToLower(prepareText(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sig := make([]uint64, minHashes)
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	if len(words) == 0 {
+		return sig
+	}
+
+	size := shingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		shingle := h.Sum64()
+		for j := range sig {
+			if v := mix64(shingle ^ uint64(j)*0x9e3779b97f4a7c15); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+	return sig
+}
+
+// mix64 is the finalizer of SplitMix64, which derives the hash functions of a
+// MinHash signature from a single hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// bandKeys hashes each band of a signature.
+func bandKeys(sig []uint64) []uint64 {
+	rows := len(sig) / lshBands
+	keys := make([]uint64, lshBands)
+	for band := range keys {
+		key := uint64(band)
+		for _, v := range sig[band*rows : (band+1)*rows] {
+			key = mix64(key ^ v)
+		}
+		keys[band] = key
+	}
+	return keys
+}
+
+// signatureSimilarity estimates the Jaccard similarity of the shingle sets of
+// two signatures, as the fraction of their hashes which are equal.
+func signatureSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) || a[0] == ^uint64(0) || b[0] == ^uint64(0) {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// removeString removes s from list.
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, e := range list {
+		if e != s {
+			out = append(out, e)
+		}
+	}
+	return out
+}
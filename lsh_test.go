@@ -0,0 +1,70 @@
+package license_test
+
+import (
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNearestLicenses(t *testing.T) {
+	text, err := license.CanonicalText(license.LicenseApache20)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	near := license.NearestLicenses(text, 3)
+	if len(near) == 0 || near[0].Type != license.LicenseApache20 || near[0].Similarity != 1 {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+	if len(near) > 1 && near[1].Similarity >= near[0].Similarity {
+		t.Fatalf("unexpected order: %v", near)
+	}
+
+	// A heavily modified copy is still nearest to its license
+	sentences := strings.Split(text, ". ")
+	for i := range sentences {
+		if i%3 == 0 {
+			sentences[i] = "The Licensor may change these terms at any time"
+		}
+	}
+	near = license.NearestLicenses(strings.Join(sentences, ". "), 1)
+	if len(near) != 1 || near[0].Type != license.LicenseApache20 || near[0].Similarity >= 1 {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+
+	if near := license.NearestLicenses("", 3); len(near) != 0 {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+}
+
+func TestLicenseIndex(t *testing.T) {
+	ix := license.NewLicenseIndex()
+	ix.Add("LicenseRef-A", "You may use this software for any purpose except to harm others.")
+	ix.Add("LicenseRef-B", "This software may only be used by nonprofit organizations for research.")
+	ix.Add("LicenseRef-B", "This software may only be used by nonprofit organizations for teaching.")
+
+	near := ix.Nearest("This software may only be used by nonprofit organizations for teaching.", 5)
+	if len(near) != 1 || near[0].Type != "LicenseRef-B" || near[0].Similarity != 1 {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+	if near := ix.Nearest("Unrelated words entirely here.", 5); len(near) != 0 {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+	if near := ix.Nearest("anything", 0); near != nil {
+		t.Fatalf("unexpected licenses: %v", near)
+	}
+}
+
+func BenchmarkNearestLicenses(b *testing.B) {
+	text, err := license.CanonicalText(license.LicenseMIT)
+	if err != nil {
+		b.Fatalf("err: %s", err)
+	}
+	text = strings.Replace(text, "free of charge", "for a small fee", 1)
+	license.NearestLicenses(text, 3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		license.NearestLicenses(text, 3)
+	}
+}
@@ -0,0 +1,500 @@
+package license
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+var (
+	// ErrNoManifest is returned when a directory contains no package manifest.
+	ErrNoManifest = errors.New("license: unable to find any package manifest")
+
+	// ErrUnknownManifest is returned when reading a file which is not a
+	// supported package manifest.
+	ErrUnknownManifest = errors.New("license: unknown package manifest type")
+)
+
+var (
+	gemspecLicenseRegexp = regexp.MustCompile(`\.licenses?\s*=\s*(.+)`)
+	gemspecStringRegexp  = regexp.MustCompile(`"([^"]*)"|'([^']*)'|%w[\[({]([^\])}]*)[\])}]`)
+	licenseNameRegexp    = regexp.MustCompile(`[^a-z0-9.+-]+`)
+	tomlTextRegexp       = regexp.MustCompile(`\btext\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// Common license names used by manifests which are not SPDX license names
+var licenseNameAliases = map[string]string{
+	"apache 2":                          "Apache-2.0",
+	"apache 2.0":                        "Apache-2.0",
+	"apache license 2":                  "Apache-2.0",
+	"apache licence 2.0":                "Apache-2.0",
+	"apache software license":           "Apache-2.0",
+	"apache software license 2.0":       "Apache-2.0",
+	"apache2":                           "Apache-2.0",
+	"bsd":                               "BSD-3-Clause",
+	"bsd license":                       "BSD-3-Clause",
+	"bsd 2-clause":                      "BSD-2-Clause",
+	"bsd 2-clause license":              "BSD-2-Clause",
+	"bsd 3-clause":                      "BSD-3-Clause",
+	"bsd 3-clause license":              "BSD-3-Clause",
+	"new bsd license":                   "BSD-3-Clause",
+	"simplified bsd license":            "BSD-2-Clause",
+	"mit license":                       "MIT",
+	"expat":                             "MIT",
+	"mozilla public license 2.0":        "MPL-2.0",
+	"mpl 2":                             "MPL-2.0",
+	"mpl 2.0":                           "MPL-2.0",
+	"mpl2":                              "MPL-2.0",
+	"eclipse public license 1.0":        "EPL-1.0",
+	"gnu lesser general public license": "LGPL-2.1-or-later", // Named so from version 2.1, without a version
+	"gnu general public license 2.0":    "GPL-2.0-only",
+	"gnu general public license 3.0":    "GPL-3.0-only",
+	"gplv2":                             "GPL-2.0-only",
+	"gplv3":                             "GPL-3.0-only",
+	"gpl 2":                             "GPL-2.0-only",
+	"gpl 3":                             "GPL-3.0-only",
+	"lgplv2.1":                          "LGPL-2.1-only",
+	"lgplv3":                            "LGPL-3.0-only",
+	"lgpl 2.1":                          "LGPL-2.1-only",
+	"lgpl 3":                            "LGPL-3.0-only",
+	"agplv3":                            "AGPL-3.0-only",
+	"agpl 3":                            "AGPL-3.0-only",
+
+	// License classifiers of the Python Package Index, without the
+	// abbreviations in parentheses which end some of them
+	"gnu affero general public license 3":          "AGPL-3.0-only",
+	"gnu affero general public license 3 or later": "AGPL-3.0-or-later",
+	"gnu general public license":                   "GPL-1.0-or-later", // Without a version
+	"gnu general public license 2":                 "GPL-2.0-only",
+	"gnu general public license 2 or later":        "GPL-2.0-or-later",
+	"gnu general public license 3":                 "GPL-3.0-only",
+	"gnu general public license 3 or later":        "GPL-3.0-or-later",
+	"gnu lesser general public license 2":          "LGPL-2.0-only",
+	"gnu lesser general public license 2 or later": "LGPL-2.0-or-later",
+	"gnu lesser general public license 3":          "LGPL-3.0-only",
+	"gnu lesser general public license 3 or later": "LGPL-3.0-or-later",
+	"gnu library or lesser general public license": "LGPL-2.0-or-later", // Named so from version 2.0, without a version
+	"other proprietary license":                    LicenseProprietary,
+	"python software foundation license":           "PSF-2.0",
+}
+
+// Manifest is the license information declared by a package manifest.
+type Manifest struct {
+	File     string   `json:"file" yaml:"file"`                             // The path to the manifest
+	Licenses []string `json:"licenses,omitempty" yaml:"licenses,omitempty"` // The licenses declared, as written
+}
+
+// Mismatch is a difference between the licenses declared by a manifest and
+// those detected from license files.
+type Mismatch struct {
+	File       string   `json:"file" yaml:"file"`                                 // The path to the manifest
+	Missing    []string `json:"missing,omitempty" yaml:"missing,omitempty"`       // Declared, but not detected
+	Undeclared []string `json:"undeclared,omitempty" yaml:"undeclared,omitempty"` // Detected, but not declared
+}
+
+// manifestReaders read the declared licenses of each supported manifest,
+// keyed by file name.
+var manifestReaders = map[string]func(data []byte) ([]string, error){
+	"package.json":   readJSONManifest,
+	"composer.json":  readJSONManifest,
+	"Cargo.toml":     readTOMLManifest("package"),
+	"pyproject.toml": readTOMLManifest("project", "tool.poetry"),
+	"setup.cfg":      readSetupCfg,
+	"pom.xml":        readPOM,
+}
+
+// ReadManifest will read the licenses declared by a package manifest: a
+// package.json, Cargo.toml, pyproject.toml, setup.cfg, composer.json,
+// *.gemspec or pom.xml file. Manifests are recognized by their file name.
+func ReadManifest(path string) (*Manifest, error) {
+	read, ok := manifestReaders[filepath.Base(path)]
+	if !ok && strings.HasSuffix(path, ".gemspec") {
+		read, ok = readGemspec, true
+	}
+	if !ok {
+		return nil, ErrUnknownManifest
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	licenses, err := read(data)
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid manifest %s: %w", path, err)
+	}
+	return &Manifest{File: path, Licenses: licenses}, nil
+}
+
+// NewManifestsFromDir will read the licenses declared by each package
+// manifest found in a directory (non-recursively), in order of file name.
+func NewManifestsFromDir(dir string) ([]*Manifest, error) {
+	files, err := readDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var manifests []*Manifest
+	for _, file := range files {
+		m, err := ReadManifest(filepath.Join(dir, file))
+		switch err {
+		case nil:
+			manifests = append(manifests, m)
+		case ErrUnknownManifest:
+		default:
+			return nil, err
+		}
+	}
+	if len(manifests) == 0 {
+		return nil, ErrNoManifest
+	}
+	return manifests, nil
+}
+
+// Reconcile compares the licenses declared by the manifest with the licenses
+// detected from license files, and returns their differences, or nil if they
+// agree. Declared licenses may be SPDX license expressions or license names,
+// and each license of an expression is expected to be detected. Versions are
+// compared regardless of "-only", "-or-later" and "+" suffixes, since license
+// files do not tell them apart.
+func (m *Manifest) Reconcile(detected []*License) *Mismatch {
+	declared := make(map[string]string)
+	for _, license := range m.Licenses {
+		for _, id := range declaredLicenses(license) {
+			declared[compareKey(id)] = id
+		}
+	}
+	found := make(map[string]string)
+	for _, l := range detected {
+		if l == nil || undetermined(l.Type) {
+			continue
+		}
+		for _, id := range declaredLicenses(l.Type) {
+			found[compareKey(id)] = id
+		}
+	}
+
+	mismatch := &Mismatch{File: m.File}
+	for key, id := range declared {
+		if _, ok := found[key]; !ok {
+			mismatch.Missing = append(mismatch.Missing, id)
+		}
+	}
+	for key, id := range found {
+		if _, ok := declared[key]; !ok {
+			mismatch.Undeclared = append(mismatch.Undeclared, id)
+		}
+	}
+	if len(mismatch.Missing) == 0 && len(mismatch.Undeclared) == 0 {
+		return nil
+	}
+	sort.Strings(mismatch.Missing)
+	sort.Strings(mismatch.Undeclared)
+	return mismatch
+}
+
+// declaredLicenses returns the license identifiers of a declared license,
+// which is an SPDX license expression or a license name. References to a
+// license file and declarations of proprietary licenses, as used by npm, are
+// not licenses.
+func declaredLicenses(license string) []string {
+	license = strings.TrimSpace(license)
+	upper := strings.ToUpper(license)
+	if license == "" || upper == "UNLICENSED" || strings.HasPrefix(upper, "SEE LICENSE IN ") {
+		return nil
+	}
+	if e, err := spdx.Parse(license); err == nil && spdx.Validate(e) == nil {
+		return expressionIDs(e)
+	}
+	return []string{licenseFromName(license)}
+}
+
+// expressionIDs returns the license identifiers of an expression, without
+// exceptions.
+func expressionIDs(e spdx.Expr) []string {
+	switch e := e.(type) {
+	case *spdx.And:
+		return append(expressionIDs(e.Left), expressionIDs(e.Right)...)
+	case *spdx.Or:
+		return append(expressionIDs(e.Left), expressionIDs(e.Right)...)
+	case *spdx.With:
+		return []string{e.License.ID}
+	case *spdx.Identifier:
+		return []string{e.ID}
+	}
+	return nil
+}
+
+// NormalizeID returns the SPDX license identifier of a license identifier or
+// name as commonly written in package metadata, such as "Apache 2", "GPLv3",
+// "MIT License" or "Apache License, Version 2.0", ignoring case, punctuation
+// and the word "version". Names are mapped to the current SPDX identifiers,
+// never to deprecated ones such as GPL-3.0, and versioned GNU names such as
+// "GPLv3" to the "-only" ones. A bare "BSD" is taken to be BSD-3-Clause, its
+// most common meaning. GNU license names without a version are taken to be
+// the "-or-later" identifier of the first version of that name, since a
+// program which does not specify a version of a GNU license may be used under
+// any version: a bare "GNU General Public License" is GPL-1.0-or-later, and a
+// bare "GNU Lesser General Public License" LGPL-2.1-or-later, as the license
+// was named so from version 2.1 on. The license classifiers of the Python
+// Package Index are known too. Names which are not known, or whose identifier is not on the SPDX
+// license list, return an error wrapping spdx.ErrUnknownLicense.
+func NormalizeID(s string) (string, error) {
+	if l, ok := spdx.Get(strings.TrimSpace(s)); ok {
+		return l.ID, nil
+	}
+	if l, ok := spdx.Get(licenseFromName(s)); ok {
+		return l.ID, nil
+	}
+	return "", fmt.Errorf("%w: %s", spdx.ErrUnknownLicense, s)
+}
+
+// licenseFromName returns the SPDX license identifier of a license name, or
+// the name itself if it is not known. Deprecated identifiers sharing the name
+// of a current one, such as GPL-3.0 and GPL-3.0-only, are only returned for
+// names which no current identifier has.
+func licenseFromName(name string) string {
+	normalized := normalizeLicenseName(name)
+	if id, ok := licenseNameAliases[normalized]; ok {
+		return id
+	}
+	deprecated := ""
+	for _, l := range spdx.List() {
+		if l.Name == "" || normalizeLicenseName(l.Name) != normalized {
+			continue
+		}
+		if !l.Deprecated {
+			return l.ID
+		}
+		if deprecated == "" {
+			deprecated = l.ID
+		}
+	}
+	if deprecated != "" {
+		return deprecated
+	}
+	return name
+}
+
+// normalizeLicenseName ignores case, punctuation, including dashes between
+// words, a leading "the", and the words "version" and "v" preceding a version
+// number.
+func normalizeLicenseName(name string) string {
+	words := strings.Fields(licenseNameRegexp.ReplaceAllString(strings.ToLower(name), " "))
+	var out []string
+	for i, word := range words {
+		switch {
+		case i == 0 && word == "the", word == "version", word == "v", strings.Trim(word, "-") == "":
+			continue
+		case len(word) > 1 && word[0] == 'v' && word[1] >= '0' && word[1] <= '9':
+			word = word[1:]
+		}
+		out = append(out, word)
+	}
+	return strings.Join(out, " ")
+}
+
+// compareKey returns the key by which license identifiers are compared.
+func compareKey(id string) string {
+	key := strings.ToLower(id)
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	return key
+}
+
+// readJSONManifest reads the license of a package.json or composer.json file,
+// which is a string, an array of strings, or an object with a type, as well as
+// the deprecated licenses array of package.json.
+func readJSONManifest(data []byte) ([]string, error) {
+	var manifest struct {
+		License  json.RawMessage   `json:"license"`
+		Licenses []json.RawMessage `json:"licenses"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var licenses []string
+	for _, raw := range append([]json.RawMessage{manifest.License}, manifest.Licenses...) {
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		var s string
+		var list []string
+		var obj struct {
+			Type string `json:"type"`
+		}
+		switch {
+		case json.Unmarshal(raw, &s) == nil:
+			licenses = append(licenses, s)
+		case json.Unmarshal(raw, &list) == nil:
+			licenses = append(licenses, list...)
+		case json.Unmarshal(raw, &obj) == nil:
+			licenses = append(licenses, obj.Type)
+		default:
+			return nil, fmt.Errorf("unexpected license value %s", raw)
+		}
+	}
+	return licenses, nil
+}
+
+// readTOMLManifest returns a reader of the license key of the first of the
+// given TOML tables which has one. The license is a string, or an inline
+// table with a text, as allowed by pyproject.toml.
+func readTOMLManifest(tables ...string) func(data []byte) ([]string, error) {
+	return func(data []byte) ([]string, error) {
+		values, err := tomlValues(data, "license")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, table := range tables {
+			value, ok := values[table]
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(value, "{") {
+				text := tomlTextRegexp.FindStringSubmatch(value)
+				if text == nil {
+					return nil, nil
+				}
+				value = text[1]
+			}
+			license, err := tomlString(value)
+			if err != nil {
+				return nil, err
+			}
+			return []string{license}, nil
+		}
+		return nil, nil
+	}
+}
+
+// tomlValues returns the raw values of a key in the TOML tables of a file
+// defining it, by table name, as written on their first line.
+func tomlValues(data []byte, key string) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || line[0] == '#':
+		case line[0] == '[':
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+		default:
+			eq := strings.Index(line, "=")
+			if eq > 0 && strings.TrimSpace(line[:eq]) == key {
+				if _, ok := values[table]; !ok {
+					values[table] = strings.TrimSpace(line[eq+1:])
+				}
+			}
+		}
+	}
+	return values, s.Err()
+}
+
+// tomlString decodes a basic or literal TOML string, followed by an optional
+// comment.
+func tomlString(value string) (string, error) {
+	if strings.HasPrefix(value, "'") {
+		if end := strings.Index(value[1:], "'"); end >= 0 {
+			return value[1 : end+1], nil
+		}
+	}
+	if strings.HasPrefix(value, `"`) {
+		for end := 1; end < len(value); end++ {
+			if value[end] == '\\' {
+				end++
+				continue
+			}
+			if value[end] == '"' {
+				return strconv.Unquote(value[:end+1])
+			}
+		}
+	}
+	return "", fmt.Errorf("unexpected license value %s", value)
+}
+
+// readSetupCfg reads the license of the metadata section of a setup.cfg file.
+func readSetupCfg(data []byte) ([]string, error) {
+	section := ""
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[':
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+		case section == "metadata":
+			sep := strings.IndexAny(line, "=:")
+			if sep > 0 && strings.TrimSpace(line[:sep]) == "license" {
+				if license := strings.TrimSpace(line[sep+1:]); license != "" {
+					return []string{license}, nil
+				}
+			}
+		}
+	}
+	return nil, s.Err()
+}
+
+// readGemspec reads the license or licenses assigned in a *.gemspec file.
+func readGemspec(data []byte) ([]string, error) {
+	var licenses []string
+	for _, m := range gemspecLicenseRegexp.FindAllSubmatch(data, -1) {
+		for _, s := range gemspecStringRegexp.FindAllSubmatch(m[1], -1) {
+			switch {
+			case s[1] != nil || s[2] != nil:
+				licenses = append(licenses, string(s[1])+string(s[2]))
+			default:
+				for _, word := range strings.Fields(string(s[3])) {
+					licenses = append(licenses, word)
+				}
+			}
+		}
+	}
+	return licenses, nil
+}
+
+// mavenPOM is the artifact and the licenses described by a Maven pom.xml file.
+type mavenPOM struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Licenses []struct {
+		Name string `xml:"name"`
+		URL  string `xml:"url"`
+	} `xml:"licenses>license"`
+}
+
+// readPOM reads the names of the licenses of a Maven pom.xml file.
+func readPOM(data []byte) ([]string, error) {
+	var pom mavenPOM
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+	var licenses []string
+	for _, l := range pom.Licenses {
+		if name := strings.TrimSpace(l.Name); name != "" {
+			licenses = append(licenses, name)
+		}
+	}
+	return licenses, nil
+}
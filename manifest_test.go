@@ -0,0 +1,164 @@
+package license_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestReadManifest(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	cases := []struct {
+		file, content string
+		expected      []string
+	}{
+		{"package.json", `{"name": "pkg", "license": "(MIT OR Apache-2.0)"}`, []string{"(MIT OR Apache-2.0)"}},
+		{"package.json", `{"license": {"type": "ISC"}, "licenses": [{"type": "MIT"}]}`, []string{"ISC", "MIT"}},
+		{"composer.json", `{"license": ["LGPL-2.1-only", "GPL-3.0-or-later"]}`, []string{"LGPL-2.1-only", "GPL-3.0-or-later"}},
+		{"Cargo.toml", "[package]\nname = \"crate\"\nlicense = \"MIT OR Apache-2.0\" # dual\n\n[dependencies]\nlicense = \"x\"\n", []string{"MIT OR Apache-2.0"}},
+		{"pyproject.toml", "[project]\nname = \"pkg\"\nlicense = {text = 'BSD-3-Clause'}\n", []string{"BSD-3-Clause"}},
+		{"pyproject.toml", "[tool.poetry]\nlicense = \"MIT\"\n", []string{"MIT"}},
+		{"setup.cfg", "[metadata]\nname = pkg\nlicense = Apache-2.0\n", []string{"Apache-2.0"}},
+		{"pkg.gemspec", "Gem::Specification.new do |spec|\n  spec.licenses = ['MIT', \"Ruby\"]\nend\n", []string{"MIT", "Ruby"}},
+		{"pkg.gemspec", "Gem::Specification.new do |s|\n  s.license = %w[BSD-2-Clause]\nend\n", []string{"BSD-2-Clause"}},
+		{"pom.xml", `<project><licenses><license><name>The Apache Software License, Version 2.0</name></license></licenses></project>`,
+			[]string{"The Apache Software License, Version 2.0"}},
+		{"package.json", `{"name": "pkg"}`, nil},
+	}
+	for _, c := range cases {
+		path := filepath.Join(d, c.file)
+		if err := ioutil.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		m, err := license.ReadManifest(path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(m.Licenses, c.expected) || m.File != path {
+			t.Fatalf("%s: \nexpected: %v\ngot: %v", c.content, c.expected, m.Licenses)
+		}
+	}
+
+	// Fails properly for unknown and invalid manifests
+	if _, err := license.ReadManifest(filepath.Join(d, "README.md")); err != license.ErrUnknownManifest {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnknownManifest, err)
+	}
+	path := filepath.Join(d, "package.json")
+	if err := ioutil.WriteFile(path, []byte(`{"license": 1}`), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.ReadManifest(path); err == nil {
+		t.Fatalf("expected error reading invalid manifest")
+	}
+}
+
+func TestManifestReconcile(t *testing.T) {
+	detected := []*license.License{
+		license.New(license.LicenseMIT, ""),
+		license.New(license.LicenseGPL30, ""),
+		license.New(license.LicenseUnrecognized, ""),
+	}
+
+	cases := []struct {
+		declared []string
+		expected *license.Mismatch
+	}{
+		{[]string{"mit AND GPL-3.0-or-later"}, nil},
+		{[]string{"MIT License", "GNU General Public License v3.0 only"}, nil},
+		{[]string{"MIT OR Apache-2.0"}, &license.Mismatch{File: "package.json", Missing: []string{"Apache-2.0"}, Undeclared: []string{"GPL-3.0"}}},
+		{[]string{"SEE LICENSE IN LICENSE"}, &license.Mismatch{File: "package.json", Undeclared: []string{"GPL-3.0", "MIT"}}},
+	}
+	for _, c := range cases {
+		m := &license.Manifest{File: "package.json", Licenses: c.declared}
+		if mismatch := m.Reconcile(detected); !reflect.DeepEqual(mismatch, c.expected) {
+			t.Fatalf("%v: \nexpected: %+v\ngot: %+v", c.declared, c.expected, mismatch)
+		}
+	}
+
+	m := &license.Manifest{File: "pom.xml", Licenses: []string{"The Apache Software License, Version 2.0"}}
+	if mismatch := m.Reconcile([]*license.License{license.New(license.LicenseApache20, "")}); mismatch != nil {
+		t.Fatalf("unexpected mismatch: %+v", mismatch)
+	}
+}
+
+func TestNewManifestsFromDir(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	if _, err := license.NewManifestsFromDir(d); err != license.ErrNoManifest {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoManifest, err)
+	}
+
+	for name, content := range map[string]string{
+		"package.json": `{"license": "MIT"}`,
+		"Cargo.toml":   "[package]\nlicense = \"Apache-2.0\"\n",
+		"README.md":    "# Readme",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(d, name), []byte(content), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	manifests, err := license.NewManifestsFromDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(manifests) != 2 || manifests[0].Licenses[0] != "Apache-2.0" || manifests[1].Licenses[0] != "MIT" {
+		t.Fatalf("unexpected manifests: %v", manifests)
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	cases := map[string]string{
+		"MIT":                         "MIT",
+		"apache-2.0":                  "Apache-2.0",
+		"Apache 2":                    "Apache-2.0",
+		"Apache License, Version 2.0": "Apache-2.0",
+		"MIT License":                 "MIT",
+		"BSD":                         "BSD-3-Clause",
+		"GPLv3":                       "GPL-3.0-only",
+		"GPL 2":                       "GPL-2.0-only",
+		"LGPLv2.1":                    "LGPL-2.1-only",
+		"LGPL 2.1":                    "LGPL-2.1-only",
+		" MPL 2.0 ":                   "MPL-2.0",
+
+		"GNU Lesser General Public License":          "LGPL-2.1-or-later",
+		"GNU General Public License":                 "GPL-1.0-or-later",
+		"GNU General Public License v2 or later":     "GPL-2.0-or-later",
+		"GNU General Public License v3.0 only":       "GPL-3.0-only",
+		"GNU Library General Public License v2 only": "LGPL-2.0-only",
+
+		"Eclipse Public License - v 2.0": "EPL-2.0",
+	}
+	for s, expected := range cases {
+		id, err := license.NormalizeID(s)
+		if err != nil {
+			t.Fatalf("%s: err: %s", s, err)
+		}
+		if id != expected {
+			t.Fatalf("%s: \nexpected: %s\ngot: %s", s, expected, id)
+		}
+		if deprecated, _ := license.IsDeprecatedID(id); deprecated {
+			t.Fatalf("%s: unexpected deprecated identifier %s", s, id)
+		}
+	}
+
+	for _, s := range []string{"", "Proprietary", "SEE LICENSE IN LICENSE"} {
+		if _, err := license.NormalizeID(s); !errors.Is(err, spdx.ErrUnknownLicense) {
+			t.Fatalf("%q: expected ErrUnknownLicense, got: %v", s, err)
+		}
+	}
+}
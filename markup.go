@@ -0,0 +1,50 @@
+package license
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// HTML elements which end a line of text, and those which do not
+	htmlBlockRegexp  = regexp.MustCompile(`(?i)</?(?:html|head|body|title|meta|link|p|br|hr|div|section|article|header|footer|nav|main|aside|h[1-6]|ul|ol|li|dl|dt|dd|table|thead|tbody|tr|td|th|blockquote|pre|center)\b[^>]*>`)
+	htmlInlineRegexp = regexp.MustCompile(`(?i)</?(?:a|abbr|b|big|cite|code|em|font|i|img|kbd|q|s|samp|small|span|strike|strong|sub|sup|tt|u|var)\b[^>]*>`)
+	htmlOmitRegexp   = regexp.MustCompile(`(?is)<!--.*?-->|<!doctype[^>]*>|<(script|style)\b[^>]*>.*?</(?:script|style)>`)
+
+	// Markdown formatting, the text of which is kept
+	markdownRules = []struct {
+		re   *regexp.Regexp
+		repl string
+	}{
+		{regexp.MustCompile("(?m)^[ \t]*(?:```|~~~).*$"), ""},
+		{regexp.MustCompile(`(?m)^[ \t]*\[[^\]\n]+\]:[ \t]*\S+.*$`), ""},
+		{regexp.MustCompile(`!?\[([^\]\n]*)\](?:\([^)\n]*\)|\[[^\]\n]*\])`), "$1"},
+		{regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+(.*?)[ \t#]*$`), "$1"},
+		{regexp.MustCompile(`(?m)^[ \t]{0,3}(?:>[ \t]?)+`), ""},
+		{regexp.MustCompile(`\*\*([^*\n]+)\*\*`), "$1"},
+		{regexp.MustCompile(`__([^_\n]+)__`), "$1"},
+		{regexp.MustCompile(`\*([^*\s][^*\n]*)\*`), "$1"},
+		{regexp.MustCompile(`\b_([^_\s][^_\n]*)_\b`), "$1"},
+		{regexp.MustCompile("`([^`\n]*)`"), "$1"},
+		{regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!<>])`), "$1"},
+	}
+)
+
+// stripMarkup removes the HTML and Markdown formatting of license text copied
+// from a web page or written as LICENSE.md, which would otherwise break up the
+// phrases looked for by GuessType. HTML is only stripped of the elements it
+// is made of, so that references such as <http://fsf.org/> and placeholders
+// such as <year> are kept, and its entities are only decoded if it has any.
+func stripMarkup(text string) string {
+	if htmlBlockRegexp.MatchString(text) || htmlInlineRegexp.MatchString(text) {
+		text = htmlOmitRegexp.ReplaceAllString(text, "")
+		text = htmlBlockRegexp.ReplaceAllString(text, "\n")
+		text = htmlInlineRegexp.ReplaceAllString(text, "")
+		text = strings.Replace(html.UnescapeString(text), "\u00a0", " ", -1)
+	}
+	for _, rule := range markdownRules {
+		text = rule.re.ReplaceAllString(text, rule.repl)
+	}
+	return text
+}
@@ -0,0 +1,65 @@
+package license_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+const markdownMIT = `# The MIT License (MIT)
+
+Copyright (c) 2024 **Acme Inc.**
+
+*Permission* is hereby granted, **free of charge**, to any person obtaining a
+copy of this [software](https://example.com/) and associated documentation
+files (the "Software"), to deal in the Software without restriction.
+`
+
+const htmlBSD = `<!DOCTYPE html>
+<html><head><title>License</title><style>p { margin: 0 }</style></head>
+<body>
+<h1>BSD 2-Clause License</h1>
+<p>Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:</p>
+<ol>
+<li>Redistributions of source code must retain the above copyright notice.</li>
+</ol>
+<p>THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS &quot;AS IS&quot;&nbsp;AND
+ANY EXPRESS OR IMPLIED WARRANTIES <!-- sic -->ARE DISCLAIMED.</p>
+</body></html>
+`
+
+func TestGuessType_Markup(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{markdownMIT, license.LicenseMIT},
+		{htmlBSD, license.LicenseBSD2Clause},
+		{"<p>Permission is hereby granted, <b>free</b>\n<i>of charge</i>, to any\nperson obtaining a copy of this software</p>", license.LicenseMIT},
+		{"> This is free and unencumbered software released into\n> the `public domain`.", license.LicenseUnlicense},
+		{"_Apache License_\n__Version 2.0, January 2004__", license.LicenseApache20},
+	}
+	for _, test := range tests {
+		l := license.New("", test.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s (%q)", err, test.text)
+		}
+		if l.Type != test.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", test.expected, l.Type)
+		}
+	}
+
+	fsys := fstest.MapFS{
+		"LICENSE.md":   {Data: []byte(markdownMIT)},
+		"LICENSE.html": {Data: []byte(htmlBSD)},
+	}
+	ls, err := license.NewLicensesFromFS(fsys, ".")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 || ls[0].Type != license.LicenseBSD2Clause || ls[1].Type != license.LicenseMIT {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
@@ -0,0 +1,106 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The number of bytes of text around a match included in its snippet.
+const snippetContext = 40
+
+// Match is a region of a license text which identified its license type.
+type Match struct {
+	Phrase    string `json:"phrase,omitempty" yaml:"phrase,omitempty"`     // The phrase matched, as normalized by GuessType
+	Template  string `json:"template,omitempty" yaml:"template,omitempty"` // The license whose template matched the whole text, if no phrase did
+	Start     int    `json:"start" yaml:"start"`                           // The byte offset of the start of the match
+	End       int    `json:"end" yaml:"end"`                               // The byte offset of the end of the match
+	StartLine int    `json:"start_line" yaml:"start_line"`                 // The line of the start of the match, from 1
+	EndLine   int    `json:"end_line" yaml:"end_line"`                     // The line of the end of the match, from 1
+	Snippet   string `json:"snippet" yaml:"snippet"`                       // The matched text and its context, on a single line
+}
+
+// GuessTypeWithMatches will guess the license type the same way GuessType
+// does, and additionally return the regions of the text which identified it:
+// each phrase GuessType matched, at its first occurrence, in order of
+// matching. If no phrase identifies the license, but the text matches the SPDX
+// template of a license, as done by MatchTemplate, that license is guessed,
+// and the whole text is returned as the match, as it is for copies of the
+// canonical text of a license.
+func (l *License) GuessTypeWithMatches() ([]*Match, error) {
+	phrases, err := l.guessType()
+	if err != nil {
+		id, score, tErr := MatchTemplate(l.Text)
+		if tErr != nil || score < 1 {
+			return nil, err
+		}
+		l.Type = id
+		m := newMatch(l.Text, 0, len(strings.TrimRight(l.Text, " \t\r\n")))
+		m.Template = id
+		return []*Match{m}, nil
+	}
+
+	// Copies of canonical texts are matched as a whole
+	if phrases == nil {
+		m := newMatch(l.Text, 0, len(strings.TrimRight(l.Text, " \t\r\n")))
+		m.Template = l.Type
+		return []*Match{m}, nil
+	}
+
+	var matches []*Match
+	for _, phrase := range phrases {
+		loc := phraseRegexp(phrase).FindStringIndex(l.Text)
+		if loc == nil {
+			continue
+		}
+		m := newMatch(l.Text, loc[0], loc[1])
+		m.Phrase = phrase
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// phraseRegexp compiles a normalized phrase to match the original text, in
+// any case and with any whitespace between its words, as well as any Markdown
+// emphasis removed by GuessType.
+func phraseRegexp(phrase string) *regexp.Regexp {
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(words, "[*_`]*\\s+[*_`]*"))
+}
+
+// newMatch creates the match of the region of text from start to end.
+func newMatch(text string, start, end int) *Match {
+	m := &Match{
+		Start:     start,
+		End:       end,
+		StartLine: strings.Count(text[:start], "\n") + 1,
+		EndLine:   strings.Count(text[:end], "\n") + 1,
+	}
+
+	from, to := start-snippetContext, end+snippetContext
+	if from <= 0 {
+		from = 0
+	} else {
+		for from < start && !utf8.RuneStart(text[from]) {
+			from++
+		}
+	}
+	if to >= len(text) {
+		to = len(text)
+	} else {
+		for to > end && !utf8.RuneStart(text[to]) {
+			to--
+		}
+	}
+	m.Snippet = strings.Join(strings.Fields(text[from:to]), " ")
+	if from > 0 {
+		m.Snippet = "..." + m.Snippet
+	}
+	if to < len(text) {
+		m.Snippet += "..."
+	}
+	return m
+}
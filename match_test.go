@@ -0,0 +1,83 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGuessTypeWithMatches(t *testing.T) {
+	text := "Copyright (c) 2024 Acme Inc.\n\n" +
+		"Redistribution and use in source and binary forms, with or without\n" +
+		"modification, are permitted provided that the following conditions are met:\n\n" +
+		"3. Neither the name of the copyright holder nor the names of its\n" +
+		"   contributors may be used to endorse or promote products derived from\n" +
+		"   this software without specific prior written permission.\n"
+
+	l := license.New("", text)
+	matches, err := l.GuessTypeWithMatches()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseBSD3Clause {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseBSD3Clause, l.Type)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("unexpected matches: %d", len(matches))
+	}
+
+	m := matches[0]
+	if m.Phrase != "redistribution and use in source and binary forms" {
+		t.Fatalf("unexpected phrase: %s", m.Phrase)
+	}
+	if text[m.Start:m.End] != "Redistribution and use in source and binary forms" {
+		t.Fatalf("unexpected region: %q", text[m.Start:m.End])
+	}
+	if m.StartLine != 3 || m.EndLine != 3 {
+		t.Fatalf("unexpected lines: %d-%d", m.StartLine, m.EndLine)
+	}
+	expected := "Copyright (c) 2024 Acme Inc. Redistribution and use in source and binary forms, with or without modification, are perm..."
+	if m.Snippet != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, m.Snippet)
+	}
+
+	// The phrase may span lines
+	m = matches[1]
+	if m.Phrase != "neither the name of" || m.StartLine != 6 || m.EndLine != 6 {
+		t.Fatalf("unexpected match: %+v", m)
+	}
+
+	// Exceptions are matched too
+	l = license.New("", "Apache License Version 2.0, January 2004\n\n"+
+		"---- LLVM Exceptions to the Apache 2.0 License ----\n")
+	matches, err = l.GuessTypeWithMatches()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(matches) != 2 || matches[1].Phrase != "llvm exceptions to the apache 2.0 license" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+
+	// Falls back to the templates, which ignore punctuation
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	mit := strings.Replace(string(data), "free of charge,", "free of charge", 1)
+	l = license.New("", mit)
+	matches, err = l.GuessTypeWithMatches()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || len(matches) != 1 || matches[0].Template != license.LicenseMIT || matches[0].Start != 0 {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+
+	l = license.New("", "No license")
+	if _, err := l.GuessTypeWithMatches(); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected ErrUnrecognizedLicense, got: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	separatorRegexp = regexp.MustCompile(`(?m)^[ \t]*[-=*_#~]{3,}[ \t]*\r?$`)
+	paragraphRegexp = regexp.MustCompile(`\r?\n[ \t]*\r?\n`)
+)
+
+// GuessTypes will scan license text which may contain several licenses, such
+// as a LICENSE file shipping both the MIT and the Apache 2.0 license texts for
+// a dual-licensed project, and guess the type of each license it describes.
+// The types are returned in order of appearance, without duplicates. Unlike
+// GuessType, the type of the license is left unchanged.
+func (l *License) GuessTypes() ([]string, error) {
+	var types []string
+	for _, segment := range segmentLicenses(l.Text) {
+		s := &License{Text: segment}
+		if err := s.GuessType(); err != nil {
+			continue
+		}
+		if !containsType(types, s.Type) {
+			types = append(types, s.Type)
+		}
+	}
+
+	if len(types) == 0 {
+		return nil, ErrUnrecognizedLicense
+	}
+	return types, nil
+}
+
+// segmentLicenses splits text into segments which each contain at most one
+// license. Separator lines, such as a row of dashes, always end a segment.
+// Otherwise a new segment is started by any paragraph which on its own is
+// guessed to be a different license than the one the current segment started
+// with. Paragraphs which cannot be guessed on their own, such as titles and
+// trailing clauses, stay with the current segment.
+func segmentLicenses(text string) []string {
+	var segments []string
+	for _, block := range separatorRegexp.Split(text, -1) {
+		// The segment is built up in a builder, since concatenating the
+		// paragraphs of a text with many takes time quadratic in its length
+		var current strings.Builder
+		var currentType string
+		for _, paragraph := range paragraphRegexp.Split(block, -1) {
+			p := &License{Text: paragraph}
+			if p.GuessType() == nil {
+				if currentType != "" && p.Type != currentType {
+					segments = append(segments, current.String())
+					current.Reset()
+				}
+				if current.Len() == 0 || currentType == "" {
+					currentType = p.Type
+				}
+			}
+			current.WriteString(paragraph)
+			current.WriteString("\n\n")
+		}
+		segments = append(segments, current.String())
+	}
+	return segments
+}
+
+// containsType determines if the license type is in types.
+func containsType(types []string, licenseType string) bool {
+	for _, t := range types {
+		if t == licenseType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGuessTypes(t *testing.T) {
+	// Single licenses are guessed once
+	for _, ltype := range license.KnownLicenses {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l := license.New("", string(lbytes))
+		types, err := l.GuessTypes()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(types, []string{ltype}) {
+			t.Fatalf("\nexpected: %s\ngot: %v", ltype, types)
+		}
+	}
+}
+
+func TestGuessTypes_Multiple(t *testing.T) {
+	read := func(ltype string) string {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return string(lbytes)
+	}
+
+	// Concatenated license texts
+	l := license.New("", read("MIT")+"\n"+read("Apache-2.0"))
+	types, err := l.GuessTypes()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []string{license.LicenseMIT, license.LicenseApache20}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, types)
+	}
+	if l.Type != "" {
+		t.Fatalf("unexpected license type: %s", l.Type)
+	}
+
+	// License texts split by separators
+	l = license.New("", "This project is dual licensed.\n\n"+read("BSD-3-Clause")+
+		"\n---------\n"+read("BSD-2-Clause")+"\n=====\n"+read("ISC"))
+	types, err = l.GuessTypes()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected = []string{license.LicenseBSD3Clause, license.LicenseBSD2Clause, license.LicenseISC}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, types)
+	}
+
+	// Fails properly if no license type is guessable
+	l = license.New("", "No license data\n\nStill nothing")
+	if _, err := l.GuessTypes(); err == nil {
+		t.Fatalf("expected error guessing license types from non-license text")
+	}
+}
@@ -0,0 +1,54 @@
+package license
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/nfukasawa/go-license/internal/norm"
+)
+
+// Characters folded by foldUnicode into the ASCII characters license phrases
+// are written in, from the typographic quotes and dashes NFKC keeps
+var unicodeFolds = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`, "«", `"`, "»", `"`,
+	"‐", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-",
+)
+
+// prepareText returns license text as it is matched against the phrases of
+// the known licenses: without formatting, as done by stripMarkup, and with its
+// characters folded by foldUnicode.
+func prepareText(text string) string {
+	return foldUnicode(stripMarkup(text))
+}
+
+// foldUnicode folds the characters which differ between copies of a license
+// text but not in meaning. The text is normalized to NFKC, which composes
+// accented letters written with combining marks and replaces compatibility
+// characters, such as fullwidth forms and ligatures, by those they stand for.
+// Typographic quotes and dashes then become their ASCII counterparts, any
+// space becomes a space, and invisible characters such as zero-width spaces
+// and soft hyphens are removed.
+func foldUnicode(text string) string {
+	ascii := true
+	for i := 0; i < len(text); i++ {
+		if text[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return text
+	}
+
+	text = unicodeFolds.Replace(norm.NFKC.String(text))
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u00ad' || r == '\ufeff' || r >= '\u200b' && r <= '\u200f' || r == '\u2060':
+			return -1
+		case r >= 0x80 && unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,73 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGuessType_Unicode(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Permission is hereby granted,\u00a0free of charge, to any\u2009person obtaining a copy of this software", license.LicenseMIT},
+		{"Eclipse Public License — v 1.0", license.LicenseEPL10},
+		{"Boost Software License – Version 1.0", license.LicenseBSL10},
+		{"This is free and unen\u00adcumbered software released into the public\u200b domain", license.LicenseUnlicense},
+		{"Ｔｈｅ Artistic License 2.0", license.LicenseArtistic20},
+		{"Redistribution and use in source and binary forms, with or without modiﬁcation", license.LicenseBSD2Clause},
+		{"Ｐｅｒｍｉｓｓｉｏｎ is hereby granted, free of charge, to any person obtaining a copy of this software", license.LicenseMIT},
+		// Decomposed accents, as written by some editors and file systems
+		{"LICENCE PUBLIQUE GE\u0301NE\u0301RALE GNU\nVersion 3, du 29 juin 2007.", license.LicenseGPL30},
+		{"LICENCIA PU\u0301BLICA GENERAL DE GNU\nVersio\u0301n 3, 29 de junio de 2007", license.LicenseGPL30},
+	}
+	for _, test := range tests {
+		l := license.New("", test.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s (%q)", err, test.text)
+		}
+		if l.Type != test.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", test.expected, l.Type)
+		}
+	}
+}
+
+func TestNewFromFile_Encodings(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := strings.Replace(string(mit), "<copyright holders>", "Jürgen Müller", 1)
+
+	utf16le := []byte{0xff, 0xfe}
+	for _, u := range utf16.Encode([]rune(text)) {
+		utf16le = append(utf16le, byte(u), byte(u>>8))
+	}
+	latin1 := []byte(strings.Replace(text, "ü", "\xfc", -1))
+
+	for name, data := range map[string][]byte{"UTF-16": utf16le, "Latin-1": latin1} {
+		path := filepath.Join(d, name)
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		l, err := license.NewFromFile(path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != license.LicenseMIT || !strings.Contains(l.Text, "Jürgen Müller") {
+			t.Fatalf("unexpected license from %s: %s (%q)", name, l.Type, l.Text[:80])
+		}
+	}
+}
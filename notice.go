@@ -0,0 +1,67 @@
+package license
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoNoticeFile is returned when a directory does not contain a notice file.
+var ErrNoNoticeFile = errors.New("license: unable to find any notice file")
+
+// A set of reasonable notice file names, such as the NOTICE file of projects
+// under the Apache License. Case does not matter.
+var DefaultNoticeFiles = []string{"notice*"}
+
+// Notice is an Apache-style NOTICE file, which lists the attributions the
+// license requires to be preserved when redistributing the work.
+type Notice struct {
+	File         string      `json:"file" yaml:"file"`                                     // The path of the notice file
+	Text         string      `json:"text" yaml:"text"`                                     // The text of the notice file
+	Copyrights   []Copyright `json:"copyrights,omitempty" yaml:"copyrights,omitempty"`     // The copyright statements of the notice
+	Attributions []string    `json:"attributions,omitempty" yaml:"attributions,omitempty"` // Statements like "This product includes software developed by ..."
+}
+
+// NewNoticeFromFile reads and parses a notice file.
+func NewNoticeFromFile(path string) (*Notice, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	n := &Notice{File: path, Text: string(data)}
+	n.Copyrights = ExtractCopyrights(n.Text)
+	n.Attributions = extractAttributions(n.Text)
+	return n, nil
+}
+
+// NewNoticeFromDir will search a directory for well-known notice file names,
+// and read and parse the first one found.
+func NewNoticeFromDir(dir string) (*Notice, error) {
+	files, err := readDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := complileLicensePatters(DefaultNoticeFiles)
+	if err != nil {
+		return nil, err
+	}
+	found := matchLicenseFile(patterns, files)
+	if len(found) == 0 {
+		return nil, ErrNoNoticeFile
+	}
+	return NewNoticeFromFile(filepath.Join(dir, found[0]))
+}
+
+// extractAttributions returns the paragraphs of a notice which start with
+// "This product includes", with their lines joined.
+func extractAttributions(text string) []string {
+	var attributions []string
+	for _, paragraph := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n\n") {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if strings.HasPrefix(strings.ToLower(paragraph), "this product includes") {
+			attributions = append(attributions, paragraph)
+		}
+	}
+	return attributions
+}
@@ -0,0 +1,49 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+const apacheNotice = `Apache Commons Lang
+Copyright 2001-2020 The Apache Software Foundation
+
+This product includes software developed at
+The Apache Software Foundation (https://www.apache.org/).
+`
+
+func TestNewNoticeFromDir(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	if _, err := license.NewNoticeFromDir(d); err != license.ErrNoNoticeFile {
+		t.Fatalf("expected ErrNoNoticeFile, got: %v", err)
+	}
+
+	path := filepath.Join(d, "NOTICE.txt")
+	if err := ioutil.WriteFile(path, []byte(apacheNotice), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	n, err := license.NewNoticeFromDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if n.File != path || n.Text != apacheNotice {
+		t.Fatalf("unexpected notice: %#v", n)
+	}
+	if len(n.Copyrights) != 1 || n.Copyrights[0].Holder != "The Apache Software Foundation" {
+		t.Fatalf("unexpected copyrights: %#v", n.Copyrights)
+	}
+	expected := []string{"This product includes software developed at The Apache Software Foundation (https://www.apache.org/)."}
+	if !reflect.DeepEqual(n.Attributions, expected) {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, n.Attributions)
+	}
+}
@@ -0,0 +1,241 @@
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// npmPackage is the metadata of a package.json file.
+type npmPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+
+	dir      string   // The directory of the package
+	licenses []string // The licenses declared, as read by readJSONManifest
+}
+
+// runtime returns the names of the packages the package depends on when
+// installed as a dependency.
+func (p *npmPackage) runtime() []string {
+	return dependencyNames(p.Dependencies, p.OptionalDependencies, p.PeerDependencies)
+}
+
+// NewNPMReport searches the node_modules directory of a JavaScript project,
+// including scoped and nested packages, and reports the licenses of each
+// package installed: the license declared by the license or licenses fields
+// of its package.json, or else those of its license files. Packages are
+// direct dependencies if the package.json of the project depends on them, and
+// test-only if only its devDependencies do. Dependencies are resolved to the
+// installed versions as Node.js resolves them, so that each version is
+// imported by the packages which load it. Packages installed several times
+// with the same version are reported once, in the first directory found.
+func NewNPMReport(ctx context.Context, dir string, opts ...Option) (*DependencyReport, error) {
+	o := newOptions(opts)
+
+	project, err := readNPMPackage(filepath.Join(dir, "package.json"))
+	switch {
+	case os.IsNotExist(err):
+		project = new(npmPackage)
+	case err != nil:
+		return nil, err
+	}
+
+	var pkgs []*npmPackage
+	if err := walkNodeModules(ctx, filepath.Join(dir, "node_modules"), make(map[string]bool), &pkgs); err != nil {
+		return nil, err
+	}
+
+	// The packages each dependency resolves to, in the node_modules
+	// directories of the package depending on it and of its ancestors up to
+	// the project, as Node.js resolves them, or else all the packages of that
+	// name, such as those installed by pnpm outside of those directories
+	root := filepath.Clean(dir)
+	byDir := make(map[string]*npmPackage, len(pkgs))
+	byName := make(map[string][]*npmPackage)
+	for _, p := range pkgs {
+		byDir[p.dir] = p
+		byName[p.Name] = append(byName[p.Name], p)
+	}
+	resolve := func(from string, names []string) []*npmPackage {
+		var resolved []*npmPackage
+		for _, name := range names {
+			found := byName[name]
+			for d := from; ; d = filepath.Dir(d) {
+				if p := byDir[filepath.Join(d, "node_modules", name)]; p != nil {
+					found = []*npmPackage{p}
+					break
+				}
+				if d == root || filepath.Dir(d) == d {
+					break
+				}
+			}
+			resolved = append(resolved, found...)
+		}
+		return resolved
+	}
+	deps := make(map[*npmPackage][]*npmPackage, len(pkgs))
+	for _, p := range pkgs {
+		deps[p] = resolve(p.dir, p.runtime())
+	}
+	runtime := resolve(root, project.runtime())
+	devDeps := resolve(root, dependencyNames(project.DevDependencies))
+
+	// The packages the project depends on at run time and in development,
+	// and those importing each package, keyed by name and version
+	key := func(p *npmPackage) string {
+		return p.Name + "@" + p.Version
+	}
+	reachable := func(pkgs []*npmPackage) map[string]bool {
+		seen := make(map[*npmPackage]bool)
+		keys := make(map[string]bool)
+		for len(pkgs) > 0 {
+			p := pkgs[0]
+			pkgs = pkgs[1:]
+			if seen[p] {
+				continue
+			}
+			seen[p], keys[key(p)] = true, true
+			pkgs = append(pkgs, deps[p]...)
+		}
+		return keys
+	}
+	build := reachable(runtime)
+	dev := reachable(devDeps)
+	projectName := project.Name
+	if projectName == "" {
+		projectName = "."
+	}
+	direct := make(map[string]bool)
+	importers := make(map[string][]string)
+	for _, p := range append(runtime, devDeps...) {
+		direct[key(p)] = true
+		importers[key(p)] = appendUnique(importers[key(p)], projectName)
+	}
+	for _, p := range pkgs {
+		for _, dep := range deps[p] {
+			importers[key(dep)] = appendUnique(importers[key(dep)], p.Name)
+		}
+	}
+
+	report := &DependencyReport{Modules: []*ModuleDependency{}}
+	modules := make(map[string]*ModuleDependency)
+	for _, p := range pkgs {
+		k := key(p)
+		if modules[k] != nil {
+			continue
+		}
+		d := &ModuleDependency{
+			Path:       p.Name,
+			Version:    p.Version,
+			Ecosystem:  "npm",
+			Dir:        p.dir,
+			Direct:     direct[k],
+			TestOnly:   dev[k] && !build[k],
+			ImportedBy: importers[k],
+			Packages:   []string{p.Name},
+		}
+		sort.Strings(d.ImportedBy)
+		modules[k] = d
+		report.Modules = append(report.Modules, d)
+
+		if d.Licenses, err = packageLicenses(ctx, p.dir, filepath.Join(p.dir, "package.json"), p.licenses, o); err != nil {
+			return nil, fmt.Errorf("license: %s: %w", k, err)
+		}
+	}
+	sort.SliceStable(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	return report, nil
+}
+
+// walkNodeModules appends the packages installed in a node_modules directory
+// and in those nested in them to pkgs, in order of their names, following
+// symlinked packages, such as those of workspaces or of pnpm, once.
+func walkNodeModules(ctx context.Context, dir string, seen map[string]bool, pkgs *[]*npmPackage) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if strings.HasPrefix(name, "@") {
+			if err := walkNodeModules(ctx, path, seen, pkgs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil || seen[real] {
+			continue
+		}
+		seen[real] = true
+		p, err := readNPMPackage(filepath.Join(path, "package.json"))
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			return err
+		}
+		if p.Name == "" {
+			p.Name = name
+			if scope := filepath.Base(dir); strings.HasPrefix(scope, "@") {
+				p.Name = scope + "/" + name
+			}
+		}
+		p.dir = path
+		*pkgs = append(*pkgs, p)
+		if err := walkNodeModules(ctx, filepath.Join(path, "node_modules"), seen, pkgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readNPMPackage reads a package.json file.
+func readNPMPackage(path string) (*npmPackage, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(npmPackage)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("license: invalid manifest %s: %w", path, err)
+	}
+	if p.licenses, err = readJSONManifest(data); err != nil {
+		return nil, fmt.Errorf("license: invalid manifest %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// dependencyNames returns the names of the packages of dependency maps, in
+// order.
+func dependencyNames(deps ...map[string]string) []string {
+	var names []string
+	for _, m := range deps {
+		for name := range m {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,81 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// writeFiles writes files of the given contents under dir, creating any
+// missing parent directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
+
+func TestNewNPMReport(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	writeFiles(t, d, map[string]string{
+		"package.json":                               `{"name": "app", "dependencies": {"a": "^1.0.0"}, "devDependencies": {"jest": "*"}}`,
+		"node_modules/.bin/a":                        "#!/usr/bin/env node\n",
+		"node_modules/a/package.json":                `{"name": "a", "version": "1.0.0", "license": "MIT", "dependencies": {"b": "*"}}`,
+		"node_modules/a/node_modules/b/package.json": `{"name": "b", "version": "1.0.0", "license": "SEE LICENSE IN LICENSE.txt"}`,
+		"node_modules/b/package.json":                `{"name": "b", "version": "2.0.0"}`,
+		"node_modules/jest/package.json":             `{"name": "jest", "version": "29.0.0", "license": "MIT", "dependencies": {"@scope/c": "*", "b": "*"}}`,
+		"node_modules/@scope/c/package.json":         `{"name": "@scope/c", "version": "0.1.0", "licenses": [{"type": "MIT"}, {"type": "Apache 2.0"}]}`,
+	})
+	copyFixture(t, "ISC", filepath.Join(d, "node_modules", "b", "LICENSE"))
+	copyFixture(t, "BSD-2-Clause", filepath.Join(d, "node_modules", "a", "node_modules", "b", "LICENSE.txt"))
+
+	r, err := license.NewNPMReport(context.Background(), d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type summary struct {
+		Path, Version    string
+		Direct, TestOnly bool
+		ImportedBy       []string
+		Type, File       string
+	}
+	expected := []summary{
+		{"@scope/c", "0.1.0", false, true, []string{"jest"}, "Apache-2.0 OR MIT", "node_modules/@scope/c/package.json"},
+		{"a", "1.0.0", true, false, []string{"app"}, "MIT", "node_modules/a/package.json"},
+		{"b", "1.0.0", false, false, []string{"a"}, "BSD-2-Clause", "node_modules/a/node_modules/b/LICENSE.txt"},
+		{"b", "2.0.0", false, true, []string{"jest"}, "ISC", "node_modules/b/LICENSE"},
+		{"jest", "29.0.0", true, true, []string{"app"}, "MIT", "node_modules/jest/package.json"},
+	}
+	var got []summary
+	for _, m := range r.Modules {
+		if len(m.Licenses) != 1 || m.Ecosystem != "npm" {
+			t.Fatalf("%s: unexpected licenses: %v", m.Path, m.Licenses)
+		}
+		file, _ := filepath.Rel(d, m.Licenses[0].File)
+		got = append(got, summary{m.Path, m.Version, m.Direct, m.TestOnly, m.ImportedBy, m.Licenses[0].Type, filepath.ToSlash(file)})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, got)
+	}
+
+	// Projects without installed packages have none
+	if r, err := license.NewNPMReport(context.Background(), t.TempDir()); err != nil || len(r.Modules) != 0 {
+		t.Fatalf("unexpected report: %v, %v", r, err)
+	}
+}
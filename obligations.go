@@ -0,0 +1,111 @@
+package license
+
+import "strings"
+
+// LicenseObligations holds the conditions a license places on those who
+// distribute works under it, for use in compliance checklists.
+type LicenseObligations struct {
+	ID               string `json:"id" yaml:"id"`                             // The license type
+	IncludeCopyright bool   `json:"includeCopyright" yaml:"includeCopyright"` // The copyright and license notices must be included with copies
+	DiscloseSource   bool   `json:"discloseSource" yaml:"discloseSource"`     // The source code must be made available when distributing
+	StateChanges     bool   `json:"stateChanges" yaml:"stateChanges"`         // Changes made to the work must be documented
+	NetworkUse       bool   `json:"networkUse" yaml:"networkUse"`             // Users interacting with the work over a network are owed its source code
+	SameLicense      bool   `json:"sameLicense" yaml:"sameLicense"`           // Modified works must be distributed under the same license
+}
+
+// licenseObligations is the curated obligations of the recognized license
+// types, following the conditions summarized by choosealicense.com.
+var licenseObligations = map[string]LicenseObligations{
+	LicenseMIT:        {IncludeCopyright: true},
+	LicenseISC:        {IncludeCopyright: true},
+	LicenseBSD3Clause: {IncludeCopyright: true},
+	LicenseBSD2Clause: {IncludeCopyright: true},
+	LicenseBSD4Clause: {IncludeCopyright: true},
+	LicenseApache20:   {IncludeCopyright: true, StateChanges: true},
+	LicenseMPL20:      {IncludeCopyright: true, DiscloseSource: true, SameLicense: true},
+	LicenseGPL20:      {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseGPL30:      {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseLGPL21:     {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseLGPL30:     {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseAGPL30:     {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, NetworkUse: true, SameLicense: true},
+	LicenseCDDL10:     {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseEPL10:      {IncludeCopyright: true, DiscloseSource: true, SameLicense: true},
+	LicenseZlib:       {IncludeCopyright: true, StateChanges: true},
+	LicenseUnlicense:  {},
+	License0BSD:       {},
+	LicenseBSL10:      {IncludeCopyright: true},
+	LicenseCC010:      {},
+	LicenseArtistic20: {IncludeCopyright: true, StateChanges: true},
+	LicenseWTFPL:      {},
+	LicenseBlessing:   {},
+
+	LicensePublicDomain: {},
+	LicenseMPL11:        {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseEPL20:        {IncludeCopyright: true, DiscloseSource: true, SameLicense: true},
+	LicenseCDDL11:       {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseEUPL11:       {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, NetworkUse: true, SameLicense: true},
+	LicenseEUPL12:       {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, NetworkUse: true, SameLicense: true},
+	LicenseCCBY30:       {IncludeCopyright: true, StateChanges: true},
+	LicenseCCBY40:       {IncludeCopyright: true, StateChanges: true},
+	LicenseCCBYSA30:     {IncludeCopyright: true, StateChanges: true, SameLicense: true},
+	LicenseCCBYSA40:     {IncludeCopyright: true, StateChanges: true, SameLicense: true},
+	LicenseSSPL10:       {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, NetworkUse: true, SameLicense: true},
+
+	LicenseAFL30:          {IncludeCopyright: true, StateChanges: true},
+	LicenseArtistic10:     {IncludeCopyright: true, StateChanges: true},
+	LicenseArtistic10Perl: {IncludeCopyright: true, StateChanges: true},
+	LicenseGPL10:          {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseMSPL:           {IncludeCopyright: true},
+	LicenseMSRL:           {IncludeCopyright: true, DiscloseSource: true, SameLicense: true},
+	LicenseOpenSSL:        {IncludeCopyright: true},
+	LicenseOFL11:          {IncludeCopyright: true, SameLicense: true},
+	LicenseOFL11RFN:       {IncludeCopyright: true, SameLicense: true},
+	LicenseUbuntuFont10:   {IncludeCopyright: true, SameLicense: true},
+	LicenseGFDL11:         {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseGFDL12:         {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseGFDL13:         {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+
+	LicenseODbL10:           {IncludeCopyright: true, SameLicense: true},
+	LicenseODCBy10:          {IncludeCopyright: true},
+	LicenseCDLAPermissive10: {IncludeCopyright: true},
+	LicenseCDLAPermissive20: {IncludeCopyright: true},
+	LicenseCDLASharing10:    {IncludeCopyright: true, SameLicense: true},
+
+	LicenseCERNOHLP20: {IncludeCopyright: true},
+	LicenseCERNOHLW20: {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+	LicenseCERNOHLS20: {IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true},
+
+	LicenseJSON:     {IncludeCopyright: true},
+	LicenseBeerware: {IncludeCopyright: true},
+}
+
+// Obligations returns the obligations of a license type, as curated for the
+// recognized license types, which also covers their "-only" and "-or-later"
+// variants. Licenses with an exception have the obligations of the license,
+// which the exception may relax, such as for linking. The boolean reports
+// whether the obligations of the license are known.
+func Obligations(id string) (LicenseObligations, bool) {
+	if license, _, ok := withException(id); ok {
+		o, ok := Obligations(license)
+		o.ID = id
+		return o, ok
+	}
+
+	o, ok := knownObligations(id)
+	if !ok {
+		o, ok = knownObligations(baseLicenseID(id))
+	}
+	o.ID = id
+	return o, ok
+}
+
+// knownObligations looks up the curated obligations of a license type,
+// ignoring case.
+func knownObligations(id string) (LicenseObligations, bool) {
+	for known, o := range licenseObligations {
+		if strings.EqualFold(known, id) {
+			return o, true
+		}
+	}
+	return LicenseObligations{}, false
+}
@@ -0,0 +1,35 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestObligations(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected license.LicenseObligations
+		ok       bool
+	}{
+		{"MIT", license.LicenseObligations{ID: "MIT", IncludeCopyright: true}, true},
+		{"apache-2.0", license.LicenseObligations{ID: "apache-2.0", IncludeCopyright: true, StateChanges: true}, true},
+		{"GPL-3.0-or-later", license.LicenseObligations{
+			ID: "GPL-3.0-or-later", IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true,
+		}, true},
+		{"AGPL-3.0-only", license.LicenseObligations{
+			ID: "AGPL-3.0-only", IncludeCopyright: true, DiscloseSource: true, StateChanges: true, NetworkUse: true, SameLicense: true,
+		}, true},
+		{"GPL-2.0 WITH Classpath-exception-2.0", license.LicenseObligations{
+			ID: "GPL-2.0 WITH Classpath-exception-2.0", IncludeCopyright: true, DiscloseSource: true, StateChanges: true, SameLicense: true,
+		}, true},
+		{"Unlicense", license.LicenseObligations{ID: "Unlicense"}, true},
+		{"Proprietary", license.LicenseObligations{ID: "Proprietary"}, false},
+	}
+	for _, c := range cases {
+		o, ok := license.Obligations(c.id)
+		if o != c.expected || ok != c.ok {
+			t.Fatalf("%s\nexpected: %+v (%t)\ngot: %+v (%t)", c.id, c.expected, c.ok, o, ok)
+		}
+	}
+}
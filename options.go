@@ -0,0 +1,411 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Directory names which are not descended into when scanning recursively.
+//
+// Deprecated: Changing DefaultSkipDirs affects every caller in the process. Use
+// WithSkipDirs, or a Scanner, instead.
+var DefaultSkipDirs = []string{
+	".git", "node_modules", "vendor",
+}
+
+// SymlinkMode is how symlinks are handled when searching for license files.
+type SymlinkMode int
+
+const (
+	// SymlinkFiles reads symlinked license files, but does not descend into
+	// symlinked directories when scanning recursively. This is the default.
+	SymlinkFiles SymlinkMode = iota
+	// SymlinkNone skips symlinked license files and directories.
+	SymlinkNone
+	// SymlinkFollow reads symlinked license files, and descends into
+	// symlinked directories when scanning recursively. Each directory is
+	// scanned once, however many links lead to it, so that symlink loops end.
+	SymlinkFollow
+)
+
+// DuplicateMode is how license files found more than once are handled.
+type DuplicateMode int
+
+const (
+	// DuplicatesSameFile reports a license file reached by several names
+	// once, such as through a symlink or a hard link, or under names which
+	// differ only in case on a case-insensitive file system. The first name,
+	// in directory order, is kept. This is the default.
+	DuplicatesSameFile DuplicateMode = iota
+	// DuplicatesKeep reports every name of a license file.
+	DuplicatesKeep
+	// DuplicatesFoldCase additionally reports license files once whose names
+	// differ only in case and whose texts are identical, such as in archives
+	// created on case-sensitive file systems.
+	DuplicatesFoldCase
+)
+
+// Option configures how license files are searched for and guessed, how
+// license texts are filled in, and how streams are scanned.
+type Option func(*options)
+
+type options struct {
+	maxDepth int          // Maximum depth to descend, or -1 for no limit
+	skipDirs []string     // Directory names to skip
+	client   *http.Client // Client for network-backed lookups
+	maxClone int64        // Maximum size of a repository cloned, in bytes, or 0 for no limit
+	cacheDir *string      // Directory caching network-backed lookups, if set
+	files    []string     // License file name patterns, or nil for those of the registry
+	paths    []string     // Path patterns of the license files read from archives, or nil for all
+	ignore   []string     // Patterns of the paths skipped, in the syntax of an IgnoreFile
+	priority []string     // File name patterns, in order of preference of the license picked
+	holder   string       // Copyright holder to fill into license texts, if set
+	year     int          // Copyright year to fill into license texts, if set
+	chunk    int          // Maximum size of the text held when scanning streams
+	partial  bool         // Whether to report every license file found, for review
+
+	guessCache  GuessCache     // Cache of the guessed license types, if set
+	stats       *DetectorStats // Counters of the work done, if set
+	maxFileSize int64          // Maximum size of a license file, or 0 for no limit
+	truncate    bool           // Whether to guess license files above the size limit from their first bytes
+	maxFileTime time.Duration  // Maximum time spent guessing a license file, or 0 for no limit
+	spdxResults bool           // Whether to report undetermined results with the special values of SPDX
+
+	gitignore  bool       // Whether to skip the paths ignored by .gitignore files
+	vcs        bool       // Whether to descend into the metadata of version control systems
+	strictness Strictness // How closely license texts must match a license
+
+	registry *RegistrySnapshot // License types and file patterns, or nil for the defaults
+
+	symlinks   SymlinkMode   // How symlinks are handled
+	duplicates DuplicateMode // How license files found more than once are handled
+
+	// Configuration of a Scanner
+	fsys      fs.FS                // File system to scan, or nil for the operating system's
+	licenses  []*LicenseDefinition // Licenses guessed before the registered and built-in ones
+	isolated  bool                 // Whether the licenses replace the registered ones, as for a Scanner
+	threshold float64              // Minimum confidence of a guess, or 0 for none
+
+	detectors   []Detector      // Detectors tried before the licenses
+	ctx         context.Context // Context of the functions which take none
+	concurrency int             // Number of directories guessed at a time when scanning recursively
+	err         error           // First error of the options, returned by the functions given them
+	dryRun      bool            // Whether to only report the changes to files
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxDepth: -1,
+		skipDirs: DefaultSkipDirs,
+		client:   http.DefaultClient,
+		chunk:    DefaultChunkSize,
+		ctx:      context.Background(),
+
+		maxFileSize: DefaultMaxFileSize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithMaxDepth limits how many levels of subdirectories are descended into
+// when scanning recursively. A depth of 0 scans only the given directory, and
+// a negative depth removes the limit.
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// WithSkipDirs replaces the list of directory names which are not descended
+// into when scanning recursively. The metadata directories of version control
+// systems are skipped regardless, unless WithVCSDirs is given.
+func WithSkipDirs(names ...string) Option {
+	return func(o *options) {
+		o.skipDirs = names
+	}
+}
+
+// WithFilePatterns replaces the license file name patterns searched for,
+// which default to DefaultLicenseFiles. Patterns are matched case-insensitively
+// against whole file names, with "*" matching any characters and any other
+// character matching itself.
+func WithFilePatterns(patterns ...string) Option {
+	return func(o *options) {
+		o.files = append([]string{}, patterns...)
+	}
+}
+
+// WithAdditionalFilePatterns extends the license file name patterns searched
+// for, such as with "copyright*", "notice*" or "legal*".
+func WithAdditionalFilePatterns(patterns ...string) Option {
+	return func(o *options) {
+		o.files = append(append([]string{}, o.licenseFiles()...), patterns...)
+	}
+}
+
+// WithFilePriority sets which license file is picked when a single license is
+// wanted from a directory with several, as by NewFromDir: the license of the
+// file whose name matches the earliest of the given patterns, such as
+// "LICENSE", "COPYING", "LICENSE.md". Patterns are matched case-insensitively
+// against whole file names, with "*" matching any characters. Files which
+// match none of them come last, and files which are equally preferred are
+// picked in the order of their names, which is also the order in which
+// directories with several license files are reported.
+func WithFilePriority(patterns ...string) Option {
+	return func(o *options) {
+		o.priority = append([]string{}, patterns...)
+	}
+}
+
+// WithPathPatterns limits the license files read from tar archives and
+// container images to those whose slash-separated path, or the path of one of
+// their parent directories, matches one of the given patterns, in the syntax
+// of path.Match. For example, "*/LICENSE" matches the license file at the top
+// of a source tarball, and "usr/share/licenses/*" the license files of every
+// package below that directory.
+func WithPathPatterns(patterns ...string) Option {
+	return func(o *options) {
+		o.paths = append([]string{}, patterns...)
+	}
+}
+
+// WithGuessCache caches the guessed types of the license files read, such as
+// in a cache created by NewLRUCache, so that identical license texts found in
+// many directories are only guessed once.
+func WithGuessCache(c GuessCache) Option {
+	return func(o *options) {
+		o.guessCache = c
+	}
+}
+
+// WithHTTPClient sets the client used by network-backed lookups, such as
+// NewFromRepo. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithMaxCloneSize limits the size on disk of the repositories cloned by
+// NewFromRepo to size bytes, past which the clone is aborted with
+// ErrCloneTooLarge. A size of 0, the default, removes the limit.
+func WithMaxCloneSize(size int64) Option {
+	return func(o *options) {
+		o.maxClone = size
+	}
+}
+
+// WithCacheDir sets the directory where the results of network-backed
+// lookups, such as NewFromModule, are cached. An empty directory disables the
+// cache. The default is a go-license directory in os.UserCacheDir.
+func WithCacheDir(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = &dir
+	}
+}
+
+// WithCopyright fills the placeholders of the copyright statement of a license
+// text with the copyright holder and year. An empty holder or a zero year
+// leaves its placeholders as they are.
+func WithCopyright(holder string, year int) Option {
+	return func(o *options) {
+		o.holder = holder
+		o.year = year
+	}
+}
+
+// WithChunkSize limits the amount of text DetectStream holds in memory at a
+// time. Licenses longer than the chunk size are guessed in parts.
+func WithChunkSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.chunk = size
+		}
+	}
+}
+
+// WithPartialResults makes the functions which return every license file
+// found, such as NewLicensesFromDir and NewFromDirRecursive, report each
+// license file located, so that it can be reviewed manually instead of going
+// unnoticed. Files whose type cannot be guessed keep their text, and files
+// which cannot be read, such as a LICENSES directory, are reported too, both
+// with the type LicenseUnrecognized.
+func WithPartialResults() Option {
+	return func(o *options) {
+		o.partial = true
+	}
+}
+
+// WithSPDXResults reports the results which cannot be determined with the
+// special values of SPDX, for SBOM exporters to encode them as such, rather
+// than as errors or LicenseUnrecognized. License files whose type cannot be
+// guessed are reported as LicenseNoAssertion, with their file and text, as are
+// those reported by WithPartialResults. Directories without license files are
+// reported as a single license of the type LicenseNone, instead of
+// ErrNoLicenseFile, and NewFromDir returns the first license found, of either
+// type, if none is recognized. Scanning recursively, only the directory
+// scanned reports LicenseNone, since its subdirectories are covered by it.
+func WithSPDXResults() Option {
+	return func(o *options) {
+		o.spdxResults = true
+	}
+}
+
+// unrecognized returns the type of the license files whose type cannot be
+// guessed.
+func (o *options) unrecognized() string {
+	if o.spdxResults {
+		return LicenseNoAssertion
+	}
+	return LicenseUnrecognized
+}
+
+// WithSymlinks sets how symlinks are handled when searching for license files.
+// The default is SymlinkFiles.
+func WithSymlinks(mode SymlinkMode) Option {
+	return func(o *options) {
+		o.symlinks = mode
+	}
+}
+
+// WithDuplicates sets how license files found more than once in a directory
+// are handled. The default is DuplicatesSameFile.
+func WithDuplicates(mode DuplicateMode) Option {
+	return func(o *options) {
+		o.duplicates = mode
+	}
+}
+
+// WithFS makes a Scanner, NewFromFile, NewFromDir, NewLicensesFromDir and
+// NewFromDirRecursive search the given file system, with slash-separated
+// paths as accepted by fs.ReadDir, instead of the operating system's.
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+// WithLicenses recognizes the given licenses, before the registered and
+// built-in ones, without registering them for every caller as
+// RegisterLicenseDefinition does. The licenses must be valid, as for
+// RegisterLicenseDefinition, or else the functions given the option fail.
+func WithLicenses(defs ...*LicenseDefinition) Option {
+	return func(o *options) {
+		for _, def := range defs {
+			d, err := compileDefinition(def)
+			if err != nil {
+				o.fail(err)
+				continue
+			}
+			o.licenses = append(o.licenses, d)
+		}
+	}
+}
+
+// WithThreshold rejects guesses whose confidence, as computed by
+// GuessTypeWithConfidence, is below the given score. Licenses without a
+// canonical text cannot be scored, and are always accepted.
+func WithThreshold(score float64) Option {
+	return func(o *options) {
+		o.threshold = score
+	}
+}
+
+// WithContext sets the context of the functions which take none, such as
+// NewFromFile, NewFromDir and NewFromDirRecursive, which stop with its error
+// once it is done, as their variants taking a context do. Those variants use
+// their own context instead.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithConcurrency guesses the license files of up to n directories at a time
+// when scanning recursively, which speeds up scans of large trees. The
+// results are the same as with the default of 1, other than the error
+// returned if several directories fail.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// fail records the first error of the options.
+func (o *options) fail(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// cache returns the cache directory, or "" if there is none.
+func (o *options) cache() string {
+	if o.cacheDir != nil {
+		return *o.cacheDir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-license")
+}
+
+// licenseFiles returns the license file name patterns to search for.
+func (o *options) licenseFiles() []string {
+	if o.files == nil {
+		return o.snapshot().files
+	}
+	return o.files
+}
+
+// matchPath determines if the license file at the given slash-separated path
+// matches the path patterns, as described by WithPathPatterns.
+func (o *options) matchPath(name string) (bool, error) {
+	if o.paths == nil {
+		return true, nil
+	}
+	for ; name != "." && name != "/"; name = path.Dir(name) {
+		for _, pattern := range o.paths {
+			ok, err := path.Match(pattern, name)
+			if err != nil {
+				return false, fmt.Errorf("license: invalid path pattern %q: %w", pattern, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// fileRank returns the position of the first file priority pattern matching
+// a file name, or the number of patterns if none does.
+func (o *options) fileRank(name string) int {
+	for rank, pattern := range o.priority {
+		if re, err := compileFilePattern(pattern); err == nil && re.MatchString(name) {
+			return rank
+		}
+	}
+	return len(o.priority)
+}
+
+// skipDir determines if a directory with the given name should be skipped.
+func (o *options) skipDir(name string) bool {
+	if !o.vcs && indexOf(vcsDirs, name) >= 0 {
+		return true
+	}
+	for _, skip := range o.skipDirs {
+		if skip == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestOptions_Uniform(t *testing.T) {
+	fsys := fstest.MapFS{
+		"LICENSE":   {Data: []byte("Acme internal terms")},
+		"lib/LEGAL": {Data: []byte("Acme internal terms")},
+	}
+	opts := []license.Option{
+		license.WithFS(fsys),
+		license.WithFilePatterns("license*", "legal*"),
+		license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Acme", Patterns: []string{"acme internal terms"}}),
+	}
+
+	l, err := license.NewFromFile("lib/LEGAL", opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != "LicenseRef-Acme" {
+		t.Fatalf("\nexpected: %s\ngot: %s", "LicenseRef-Acme", l.Type)
+	}
+	if l, err = license.NewFromDir(".", opts...); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.File != "LICENSE" {
+		t.Fatalf("\nexpected: %s\ngot: %s", "LICENSE", l.File)
+	}
+	found, err := license.NewFromDirRecursive(".", opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(found) != 2 || found["lib"][0].Type != "LicenseRef-Acme" {
+		t.Fatalf("unexpected results: %v", found)
+	}
+
+	invalid := license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Empty"})
+	if _, err := license.NewFromDir(".", license.WithFS(fsys), invalid); err != license.ErrInvalidDefinition {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidDefinition, err)
+	}
+}
+
+func TestNewFromFile_MaxFileSize(t *testing.T) {
+	path := filepath.Join("fixtures", "licenses", "MIT")
+	if _, err := license.NewFromFile(path, license.WithMaxFileSize(100)); err != license.ErrFileBudget {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrFileBudget, err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := license.NewFromDir(".", license.WithContext(ctx)); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+	if _, err := license.NewFromFile("LICENSE", license.WithContext(ctx)); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+	if _, err := license.NewFromDirRecursive(".", license.WithContext(ctx)); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+}
+
+func TestWithConcurrency(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+	for i, ltype := range []string{"Apache-2.0", "GPL-3.0", "ISC", "BSD-3-Clause", "MPL-2.0"} {
+		copyFixture(t, ltype, filepath.Join(d, "pkg", string(rune('a'+i)), "LICENSE"))
+	}
+	copyFixture(t, "ISC", filepath.Join(d, "pkg", "a", "testdata", "LICENSE"))
+	if err := ioutil.WriteFile(filepath.Join(d, "pkg", "a", license.IgnoreFile), []byte("testdata/\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	got, err := license.NewFromDirRecursive(d, license.WithConcurrency(4))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(got) != 6 || !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, got)
+	}
+}
@@ -0,0 +1,227 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/nfukasawa/go-license/internal/yaml"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Verdict is the outcome of evaluating a license against a policy.
+type Verdict int
+
+const (
+	VerdictReview Verdict = iota // The license needs to be reviewed
+	VerdictAllow                 // The license is allowed
+	VerdictDeny                  // The license is denied
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictAllow:
+		return "allow"
+	case VerdictDeny:
+		return "deny"
+	default:
+		return "review"
+	}
+}
+
+// MarshalText encodes the verdict as "allow", "deny" or "review".
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText decodes a verdict from "allow", "deny" or "review".
+func (v *Verdict) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "allow":
+		*v = VerdictAllow
+	case "deny":
+		*v = VerdictDeny
+	case "review", "":
+		*v = VerdictReview
+	default:
+		return fmt.Errorf("license: unknown verdict %q", text)
+	}
+	return nil
+}
+
+// rank orders verdicts from worst to best.
+func (v Verdict) rank() int {
+	switch v {
+	case VerdictDeny:
+		return 0
+	case VerdictReview:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Decision is the result of evaluating a license against a policy.
+type Decision struct {
+	Verdict Verdict `json:"verdict" yaml:"verdict"` // The outcome
+	Reason  string  `json:"reason" yaml:"reason"`   // A human readable explanation of the outcome
+}
+
+// Policy declares which licenses are allowed, denied, or need to be reviewed.
+// Entries are license identifiers, optionally with an exception such as
+// "GPL-2.0 WITH Classpath-exception-2.0", and are matched case-insensitively.
+// An entry which is not matched exactly also matches the licenses which only
+// differ from it by a "+", "-only" or "-or-later" suffix, so that "GPL-2.0"
+// covers "GPL-2.0-only" and "GPL-2.0-or-later".
+// Licenses which are not listed may be decided by their category, as returned
+// by Info, such as to deny all source-available licenses.
+type Policy struct {
+	Allow      []string             `json:"allow" yaml:"allow"`                               // Licenses which are allowed
+	Deny       []string             `json:"deny" yaml:"deny"`                                 // Licenses which are denied
+	Review     []string             `json:"review" yaml:"review"`                             // Licenses which need to be reviewed
+	Categories map[Category]Verdict `json:"categories,omitempty" yaml:"categories,omitempty"` // The verdicts for licenses not listed, by category
+	Default    Verdict              `json:"default" yaml:"default"`                           // The verdict for licenses not listed
+}
+
+// LoadPolicy reads a policy from a JSON or YAML file, such as:
+//
+//	allow: [MIT, Apache-2.0, BSD-3-Clause]
+//	deny:
+//	  - GPL-3.0
+//	  - AGPL-3.0
+//	review: [MPL-2.0]
+//	categories:
+//	  source-available: deny
+//	default: review
+//
+// The format is chosen by the file extension, and defaults to YAML.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(Policy)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, p)
+	} else {
+		err = yaml.Unmarshal(data, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Evaluate decides whether the license is acceptable under the policy. The
+// license type may be an SPDX license expression, in which case a license
+// chosen by OR takes the best verdict of its alternatives, and licenses
+// combined by AND take the worst verdict of their parts. An allowed license
+// with a rider needs review, as the rider restricts it.
+func (p *Policy) Evaluate(l *License) Decision {
+	if l == nil || l.Type == "" {
+		return Decision{p.Default, "no license"}
+	}
+	d := p.EvaluateExpression(l.Type)
+	if l.Rider != "" && d.Verdict == VerdictAllow {
+		return Decision{VerdictReview, fmt.Sprintf("%s has a rider", l.Type)}
+	}
+	return d
+}
+
+// EvaluateExpression decides whether a license identifier or SPDX license
+// expression is acceptable under the policy, as described by Evaluate.
+func (p *Policy) EvaluateExpression(expression string) Decision {
+	e, err := spdx.Parse(expression)
+	if err != nil {
+		return p.evaluateLicense(expression)
+	}
+	return p.evaluate(e)
+}
+
+func (p *Policy) evaluate(e spdx.Expr) Decision {
+	switch e := e.(type) {
+	case *spdx.And:
+		left, right := p.evaluate(e.Left), p.evaluate(e.Right)
+		if right.Verdict.rank() < left.Verdict.rank() {
+			return right
+		}
+		return left
+	case *spdx.Or:
+		left, right := p.evaluate(e.Left), p.evaluate(e.Right)
+		if right.Verdict.rank() > left.Verdict.rank() {
+			return right
+		}
+		return left
+	case *spdx.With:
+		// An exception only grants additional permissions, so unless it is
+		// listed explicitly, the license it applies to decides.
+		if p.listed(e.String()) {
+			return p.evaluateLicense(e.String())
+		}
+		return p.evaluateLicense(e.License.String())
+	default:
+		return p.evaluateLicense(e.String())
+	}
+}
+
+// evaluateLicense decides on a single license. Entries matching it exactly
+// take precedence over those matching its base license.
+func (p *Policy) evaluateLicense(license string) Decision {
+	for _, key := range []func(string) string{policyKey, policyBaseKey} {
+		switch {
+		case listContains(p.Deny, license, key):
+			return Decision{VerdictDeny, fmt.Sprintf("%s is denied", license)}
+		case listContains(p.Allow, license, key):
+			return Decision{VerdictAllow, fmt.Sprintf("%s is allowed", license)}
+		case listContains(p.Review, license, key):
+			return Decision{VerdictReview, fmt.Sprintf("%s needs review", license)}
+		}
+	}
+	category := Info(license).Category
+	if v, ok := p.Categories[category]; ok && category != CategoryUnknown {
+		return Decision{v, fmt.Sprintf("%s is %s", license, category)}
+	}
+	return Decision{p.Default, fmt.Sprintf("%s is not covered by the policy", license)}
+}
+
+// listed determines if the license appears on any list of the policy.
+func (p *Policy) listed(license string) bool {
+	return listContains(p.Deny, license, policyBaseKey) || listContains(p.Allow, license, policyBaseKey) ||
+		listContains(p.Review, license, policyBaseKey)
+}
+
+func listContains(list []string, license string, key func(string) string) bool {
+	k := key(license)
+	for _, entry := range list {
+		if key(entry) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// policyKey returns the form in which a license is matched with the entries
+// of a policy.
+func policyKey(license string) string {
+	return strings.ToLower(strings.Join(strings.Fields(license), " "))
+}
+
+// policyBaseKey returns the key of the base license, without the suffixes
+// which choose the versions of the license that apply.
+func policyBaseKey(license string) string {
+	key := policyKey(license)
+	id, exception := key, ""
+	if i := strings.Index(key, " with "); i >= 0 {
+		id, exception = key[:i], key[i:]
+	}
+	for _, suffix := range []string{"+", "-only", "-or-later"} {
+		if strings.HasSuffix(id, suffix) {
+			id = strings.TrimSuffix(id, suffix)
+			break
+		}
+	}
+	return id + exception
+}
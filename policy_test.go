@@ -0,0 +1,171 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	p := &license.Policy{
+		Allow:  []string{"MIT", "Apache-2.0", "BSD-3-Clause", "GPL-2.0 WITH Classpath-exception-2.0"},
+		Deny:   []string{"GPL-3.0", "AGPL-3.0", "GPL-2.0"},
+		Review: []string{"MPL-2.0"},
+	}
+
+	cases := []struct {
+		ltype    string
+		expected license.Verdict
+	}{
+		{"MIT", license.VerdictAllow},
+		{"mit", license.VerdictAllow},
+		{"GPL-3.0", license.VerdictDeny},
+		{"MPL-2.0", license.VerdictReview},
+		{"ISC", license.VerdictReview},
+		{license.LicenseUnrecognized, license.VerdictReview},
+		{"", license.VerdictReview},
+		{"MIT OR GPL-3.0", license.VerdictAllow},
+		{"MPL-2.0 OR GPL-3.0", license.VerdictReview},
+		{"MIT AND GPL-3.0", license.VerdictDeny},
+		{"MIT AND MPL-2.0", license.VerdictReview},
+		{"MIT AND (Apache-2.0 OR GPL-3.0)", license.VerdictAllow},
+		{"GPL-2.0 WITH Classpath-exception-2.0", license.VerdictAllow},
+		{"GPL-2.0 WITH GCC-exception-2.0", license.VerdictDeny},
+		{"Apache-2.0 WITH LLVM-exception", license.VerdictAllow},
+		{"GPL-2.0+", license.VerdictDeny},
+		{"GPL-2.0-only", license.VerdictDeny},
+		{"GPL-2.0-or-later", license.VerdictDeny},
+		{"GPL-3.0-or-later OR MIT", license.VerdictAllow},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", license.VerdictAllow},
+		{"GPL-2.0-or-later WITH GCC-exception-2.0", license.VerdictDeny},
+	}
+	for _, c := range cases {
+		d := p.Evaluate(license.New(c.ltype, ""))
+		if d.Verdict != c.expected {
+			t.Fatalf("%q:\nexpected: %s\ngot: %s (%s)", c.ltype, c.expected, d.Verdict, d.Reason)
+		}
+		if d.Reason == "" {
+			t.Fatalf("%q: missing reason", c.ltype)
+		}
+	}
+
+	// The reason names the deciding license
+	d := p.EvaluateExpression("ISC AND GPL-3.0")
+	if d.Reason != "GPL-3.0 is denied" {
+		t.Fatalf("unexpected reason: %s", d.Reason)
+	}
+
+	// Exact entries take precedence over those of the base license
+	p.Allow = append(p.Allow, "GPL-2.0-or-later")
+	if d := p.EvaluateExpression("GPL-2.0-or-later"); d.Verdict != license.VerdictAllow {
+		t.Fatalf("unexpected verdict: %s (%s)", d.Verdict, d.Reason)
+	}
+	if d := p.EvaluateExpression("GPL-2.0-only"); d.Verdict != license.VerdictDeny {
+		t.Fatalf("unexpected verdict: %s (%s)", d.Verdict, d.Reason)
+	}
+
+	// Unlisted licenses take the default verdict
+	p.Default = license.VerdictDeny
+	if d := p.Evaluate(license.New("ISC", "")); d.Verdict != license.VerdictDeny {
+		t.Fatalf("unexpected verdict: %s", d.Verdict)
+	}
+	if d := p.Evaluate(nil); d.Verdict != license.VerdictDeny {
+		t.Fatalf("unexpected verdict: %s", d.Verdict)
+	}
+}
+
+func TestPolicyEvaluate_Categories(t *testing.T) {
+	p := &license.Policy{
+		Allow: []string{"MIT", "Elastic-2.0"},
+		Categories: map[license.Category]license.Verdict{
+			license.CategorySourceAvailable: license.VerdictDeny,
+			license.CategoryPermissive:      license.VerdictAllow,
+		},
+	}
+
+	cases := []struct {
+		ltype    string
+		expected license.Verdict
+	}{
+		{"BUSL-1.1", license.VerdictDeny},
+		{"SSPL-1.0", license.VerdictDeny},
+		{"MIT AND " + license.LicenseCommonsClause, license.VerdictDeny},
+		{"Elastic-2.0", license.VerdictAllow},
+		{"ISC", license.VerdictAllow},
+		{"GPL-3.0", license.VerdictReview},
+		{license.LicenseUnrecognized, license.VerdictReview},
+	}
+	for _, c := range cases {
+		d := p.EvaluateExpression(c.ltype)
+		if d.Verdict != c.expected {
+			t.Fatalf("%q:\nexpected: %s\ngot: %s (%s)", c.ltype, c.expected, d.Verdict, d.Reason)
+		}
+	}
+	if d := p.EvaluateExpression("BUSL-1.1"); d.Reason != "BUSL-1.1 is source-available" {
+		t.Fatalf("unexpected reason: %s", d.Reason)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	expected := &license.Policy{
+		Allow:  []string{"MIT", "Apache-2.0"},
+		Deny:   []string{"GPL-3.0", "AGPL-3.0"},
+		Review: []string{"MPL-2.0"},
+		Categories: map[license.Category]license.Verdict{
+			license.CategorySourceAvailable: license.VerdictDeny,
+		},
+		Default: license.VerdictDeny,
+	}
+	files := map[string]string{
+		"policy.yaml": `# Dependency license policy
+allow: [MIT, Apache-2.0]
+deny:
+  - GPL-3.0
+  - AGPL-3.0
+review:
+  - MPL-2.0
+categories:
+  source-available: deny
+default: deny
+`,
+		"policy.json": `{"allow": ["MIT", "Apache-2.0"], "deny": ["GPL-3.0", "AGPL-3.0"],
+"review": ["MPL-2.0"], "categories": {"source-available": "deny"}, "default": "deny"}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(d, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		p, err := license.LoadPolicy(path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(p, expected) {
+			t.Fatalf("\nexpected: %#v\ngot: %#v", expected, p)
+		}
+	}
+
+	// Fails properly if the verdict is unknown
+	path := filepath.Join(d, "invalid.yml")
+	if err := ioutil.WriteFile(path, []byte("default: maybe\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.LoadPolicy(path); err == nil {
+		t.Fatalf("expected error loading invalid policy")
+	}
+
+	// Fails properly if the file doesn't exist
+	if _, err := license.LoadPolicy(filepath.Join(d, "nonexistent.yaml")); err == nil {
+		t.Fatalf("expected error loading non-existent policy")
+	}
+}
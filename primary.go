@@ -0,0 +1,204 @@
+package license
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Directory names of vendored trees, whose licenses are those of other
+// projects
+var vendoredDirs = []string{
+	"vendor", "node_modules", "third_party", "third-party", "thirdparty", "external",
+}
+
+// Explanation tells how ResolvePrimary picked the primary license of a
+// project.
+type Explanation struct {
+	Dir     string   `json:"dir,omitempty" yaml:"dir,omitempty"`         // The directory of the primary license, if any
+	Files   []string `json:"files,omitempty" yaml:"files,omitempty"`     // The license files which make up the primary license
+	Ignored []string `json:"ignored,omitempty" yaml:"ignored,omitempty"` // The directories left out as vendored trees
+	Reasons []string `json:"reasons" yaml:"reasons"`                     // The heuristics which picked the license, in the order applied
+}
+
+// ResolvePrimary picks the primary license of a project from the licenses
+// found in its directories, such as those returned by NewFromDirRecursive,
+// using the following heuristics, in order:
+//
+//   - Directories within vendored trees, such as vendor, node_modules and
+//     third_party, are ignored, since their licenses are those of other
+//     projects.
+//   - The root directory of the project, the common ancestor of those scanned
+//     outside of vendored trees, wins over its subdirectories. Without a license there, the top-most
+//     directories with a license win, the first by path if there are several.
+//   - Several licenses of different types in that directory, such as
+//     LICENSE-MIT and LICENSE-APACHE, are a dual license, combined into an
+//     SPDX expression like "Apache-2.0 OR MIT". Several files with the same
+//     type are the same license, the first by name of which is picked.
+//
+// Unrecognized licenses are never picked. The license returned is nil if no
+// license was recognized, and the explanation tells which heuristics applied.
+func ResolvePrimary(results map[string][]*License) (*License, Explanation) {
+	var e Explanation
+	dirs := make([]string, 0, len(results))
+	for dir := range results {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	root := projectRoot(commonDir(dirs))
+
+	best := -1
+	var candidates []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			rel = dir
+		}
+		if isVendored(rel) {
+			e.Ignored = append(e.Ignored, dir)
+			continue
+		}
+		if len(recognizedLicenses(results[dir])) == 0 {
+			continue
+		}
+		d := depth(root, dir)
+		switch {
+		case best < 0 || d < best:
+			best, candidates = d, []string{dir}
+		case d == best:
+			candidates = append(candidates, dir)
+		}
+	}
+	if len(e.Ignored) > 0 {
+		e.Reasons = append(e.Reasons, fmt.Sprintf("ignored %d vendored directories", len(e.Ignored)))
+	}
+	if len(candidates) == 0 {
+		e.Reasons = append(e.Reasons, "no license was recognized")
+		return nil, e
+	}
+
+	e.Dir = candidates[0]
+	switch {
+	case best == 0:
+		e.Reasons = append(e.Reasons, fmt.Sprintf("the root directory %s has a license", e.Dir))
+	case len(candidates) > 1:
+		e.Reasons = append(e.Reasons, fmt.Sprintf("the root directory has no license; "+
+			"%s is the first of %d top-most directories with a license", e.Dir, len(candidates)))
+	default:
+		e.Reasons = append(e.Reasons, fmt.Sprintf("the root directory has no license; "+
+			"%s is the top-most directory with a license", e.Dir))
+	}
+
+	ls := recognizedLicenses(results[e.Dir])
+	var types []string
+	var picked []*License
+	for _, l := range ls {
+		if indexOf(types, l.Type) < 0 {
+			types = append(types, l.Type)
+			picked = append(picked, l)
+		}
+	}
+	for _, l := range picked {
+		e.Files = append(e.Files, l.File)
+	}
+	if len(picked) == 1 {
+		if len(ls) > 1 {
+			e.Reasons = append(e.Reasons, fmt.Sprintf("%d license files of %s are the same license, %s",
+				len(ls), e.Dir, picked[0].Type))
+		}
+		return picked[0], e
+	}
+
+	l := &License{Type: dualLicense(types)}
+	e.Reasons = append(e.Reasons, fmt.Sprintf("%d licenses of %s are a dual license, %s", len(types), e.Dir, l.Type))
+	return l, e
+}
+
+// recognizedLicenses returns the licenses whose type was guessed.
+func recognizedLicenses(ls []*License) []*License {
+	var recognized []*License
+	for _, l := range ls {
+		if !undetermined(l.Type) {
+			recognized = append(recognized, l)
+		}
+	}
+	return recognized
+}
+
+// dualLicense combines license types into an SPDX expression offering a
+// choice of them, ordered by type.
+func dualLicense(types []string) string {
+	sorted := append([]string(nil), types...)
+	sort.Strings(sorted)
+
+	var expr spdx.Expr
+	for _, licenseType := range sorted {
+		e, err := spdx.Parse(licenseType)
+		if err != nil {
+			e = &spdx.Identifier{ID: licenseType}
+		}
+		if expr == nil {
+			expr = e
+		} else {
+			expr = &spdx.Or{Left: expr, Right: e}
+		}
+	}
+	return expr.String()
+}
+
+// commonDir returns the deepest directory which all of the given directories
+// are in, or are.
+func commonDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return "."
+	}
+	common := filepath.Clean(dirs[0])
+	for _, dir := range dirs[1:] {
+		dir = filepath.Clean(dir)
+		for common != dir && !strings.HasPrefix(dir, common+string(filepath.Separator)) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				if common == "." {
+					return common
+				}
+				break
+			}
+			common = parent
+		}
+	}
+	return common
+}
+
+// projectRoot returns the directory of the project a directory is in: the
+// directory itself, or the parent of its top-most vendored tree, so that the
+// directories of a vendored tree are never mistaken for the project they are
+// bundled with.
+func projectRoot(dir string) string {
+	root := filepath.Clean(dir)
+	for d := root; ; {
+		parent := filepath.Dir(d)
+		if isVendored(filepath.Base(d)) {
+			root = parent
+		}
+		if parent == d {
+			return root
+		}
+		d = parent
+	}
+}
+
+// isVendored determines if a directory, relative to the root of a project, is
+// within a vendored tree.
+func isVendored(rel string) bool {
+	for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+		for _, vendored := range vendoredDirs {
+			if strings.EqualFold(name, vendored) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package license_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestResolvePrimary(t *testing.T) {
+	lic := func(dir, file, licenseType string) *license.License {
+		return &license.License{Type: licenseType, File: filepath.Join(dir, file)}
+	}
+	for _, tc := range []struct {
+		name     string
+		results  map[string][]*license.License
+		expected string
+		dir      string
+		files    []string
+	}{
+		{
+			name: "root wins",
+			results: map[string][]*license.License{
+				"repo":      {lic("repo", "LICENSE", license.LicenseMIT)},
+				"repo/docs": {lic("repo/docs", "LICENSE", license.LicenseCCBY40)},
+			},
+			expected: license.LicenseMIT,
+			dir:      "repo",
+			files:    []string{"repo/LICENSE"},
+		},
+		{
+			name: "dual license",
+			results: map[string][]*license.License{
+				".": {
+					lic(".", "LICENSE-MIT", license.LicenseMIT),
+					lic(".", "LICENSE-APACHE", license.LicenseApache20),
+					lic(".", "COPYING", license.LicenseMIT),
+				},
+			},
+			expected: "Apache-2.0 OR MIT",
+			dir:      ".",
+			files:    []string{"LICENSE-MIT", "LICENSE-APACHE"},
+		},
+		{
+			name: "vendored trees ignored",
+			results: map[string][]*license.License{
+				".":                   {lic(".", "LICENSE", license.LicenseUnrecognized)},
+				"vendor/example.com":  {lic("vendor/example.com", "LICENSE", license.LicenseGPL30)},
+				"pkg/third_party/foo": {lic("pkg/third_party/foo", "LICENSE", license.LicenseGPL20)},
+				"pkg/b":               {lic("pkg/b", "LICENSE", license.LicenseISC)},
+				"pkg/a":               {lic("pkg/a", "LICENSE", license.LicenseBSD3Clause)},
+			},
+			expected: license.LicenseBSD3Clause,
+			dir:      "pkg/a",
+			files:    []string{"pkg/a/LICENSE"},
+		},
+		{
+			name: "only vendored trees",
+			results: map[string][]*license.License{
+				"proj/vendor/github.com/x/y": {lic("proj/vendor/github.com/x/y", "LICENSE", license.LicenseGPL30)},
+			},
+		},
+		{
+			name: "vendored trees ignored below the common ancestor",
+			results: map[string][]*license.License{
+				"proj/vendor/github.com/x/y": {lic("proj/vendor/github.com/x/y", "LICENSE", license.LicenseGPL30)},
+				"proj/vendor/github.com/x/z": {lic("proj/vendor/github.com/x/z", "LICENSE", license.LicenseGPL20)},
+				"proj/cmd":                   {lic("proj/cmd", "LICENSE", license.LicenseMIT)},
+			},
+			expected: license.LicenseMIT,
+			dir:      "proj/cmd",
+			files:    []string{"proj/cmd/LICENSE"},
+		},
+		{
+			name: "unrecognized",
+			results: map[string][]*license.License{
+				".": {lic(".", "LICENSE", license.LicenseUnrecognized)},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l, e := license.ResolvePrimary(tc.results)
+			if tc.expected == "" {
+				if l != nil {
+					t.Fatalf("unexpected license: %v", l)
+				}
+				return
+			}
+			if l == nil || l.Type != tc.expected {
+				t.Fatalf("\nexpected: %s\ngot: %v", tc.expected, l)
+			}
+			if e.Dir != tc.dir || !reflect.DeepEqual(e.Files, tc.files) || len(e.Reasons) == 0 {
+				t.Fatalf("unexpected explanation: %+v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package license
+
+// Proprietary licenses, which grant no rights beyond those of a separate
+// agreement. They are not on the SPDX license list, so they are reported as a
+// LicenseRef.
+const LicenseProprietary = "LicenseRef-Proprietary"
+
+// Phrases which mark a text as proprietary, as normalized by GuessType
+var proprietaryMarkers = []string{
+	"proprietary and confidential",
+	"confidential and proprietary",
+	"strictly confidential",
+	"this end user license agreement",
+	"this end-user license agreement",
+	"licensed, not sold",
+}
+
+// Phrases which prohibit uses of a work, and so make a reservation of all
+// rights, which copyright notices commonly include, mark a text as proprietary
+var prohibitionPhrases = []string{
+	"may not", "do not", "not permitted", "prohibited", "unauthorized",
+	"without the prior written", "without the express written",
+}
+
+// Phrases which grant rights, and so keep a reservation of all rights from
+// marking a text as proprietary
+var grantPhrases = []string{
+	"permission is", "permission to", "hereby grant", "is granted", "are granted",
+	"licensed under", "free of charge", "redistribution and use", "free software",
+	"you may use", "you may copy", "you may redistribute", "you can redistribute",
+}
+
+// proprietaryMarker returns the phrase of the normalized text of a license
+// which marks it as proprietary, if any: a reservation of all rights with a
+// prohibition and without a grant, a confidentiality notice, or the language
+// of an EULA.
+func proprietaryMarker(comp string) (string, bool) {
+	for _, marker := range proprietaryMarkers {
+		if scan(comp, marker) {
+			return marker, true
+		}
+	}
+	if !scan(comp, "all rights reserved") {
+		return "", false
+	}
+	for _, grant := range grantPhrases {
+		if scan(comp, grant) {
+			return "", false
+		}
+	}
+	for _, prohibition := range prohibitionPhrases {
+		if scan(comp, prohibition) {
+			return "all rights reserved", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,38 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_Proprietary(t *testing.T) {
+	for _, text := range []string{
+		"Copyright (c) 2020 Acme Corp. All rights reserved.\n\nUnauthorized copying of this file, via any medium, is strictly prohibited.",
+		"PROPRIETARY AND CONFIDENTIAL. This file is the property of Acme Corp.",
+		"ACME END USER LICENSE AGREEMENT\n\nThis End User License Agreement is a legal agreement between you and Acme Corp.",
+		"The Software is licensed, not sold.",
+	} {
+		l := license.New("", text)
+		licenseType, confidence, err := l.GuessTypeWithConfidence()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if licenseType != license.LicenseProprietary || confidence != 0 {
+			t.Fatalf("%q\nexpected: %s with no confidence\ngot: %s with %f", text, license.LicenseProprietary, licenseType, confidence)
+		}
+		if !l.Recognized() || license.Info(l.Type).Category != license.CategoryProprietary {
+			t.Fatalf("unexpected metadata of %s: %+v", l.Type, license.Info(l.Type))
+		}
+	}
+
+	// Reserving all rights alone, or along with a grant, is not proprietary
+	for _, text := range []string{
+		"Copyright (c) 2020 Acme Corp. All rights reserved.",
+		"Copyright (c) 2020 Acme Corp. All rights reserved.\n\nPermission is granted to copy this file, which may not be sold.",
+	} {
+		if err := license.New("", text).GuessType(); err != license.ErrUnrecognizedLicense {
+			t.Fatalf("%q: expected ErrUnrecognizedLicense, got: %v", text, err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package license
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ErrModuleNotFound is returned when no module proxy has the requested module.
+var ErrModuleNotFound = errors.New("license: module not found")
+
+// DefaultGoProxy is the module proxy used when $GOPROXY is not set.
+const DefaultGoProxy = "https://proxy.golang.org"
+
+// NewFromModule will download the zip file of a module version from the Go
+// module proxy, and guess the types of the license files found at its root.
+// An empty version, or "latest", looks up the latest version first.
+//
+// Proxies are taken from $GOPROXY, as the go command does: proxies separated
+// by a comma are tried in turn if the module is not found, and those separated
+// by a pipe if any error occurs. "direct" entries are skipped, since VCS
+// lookups are not supported, and "off" disallows downloads.
+//
+// Results are cached by module version, as described by WithCacheDir, unless
+// the license file name patterns are changed.
+func NewFromModule(ctx context.Context, module, version string, opts ...Option) ([]*License, error) {
+	o := newOptions(opts)
+	escaped, err := escapeModulePath(module)
+	if err != nil {
+		return nil, err
+	}
+
+	var ls []*License
+	err = eachProxy(func(proxy string) error {
+		v := version
+		if v == "" || v == "latest" {
+			var info struct{ Version string }
+			if err := getJSON(ctx, o.client, proxy+"/"+escaped+"/@latest", &info); err != nil {
+				return moduleError(err)
+			}
+			v = info.Version
+		}
+		escapedVersion, err := escapeModulePath(v)
+		if err != nil {
+			return err
+		}
+
+		cache := o.cache()
+		if o.files != nil {
+			cache = ""
+		}
+		if cache != "" {
+			cache = filepath.Join(cache, filepath.FromSlash(escaped), "@v", escapedVersion+".json")
+			if ls, err = readModuleCache(cache); err == nil {
+				return nil
+			}
+		}
+
+		data, err := get(ctx, o.client, proxy+"/"+escaped+"/@v/"+escapedVersion+".zip")
+		if err != nil {
+			return moduleError(err)
+		}
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("license: invalid module zip for %s@%s: %w", module, v, err)
+		}
+		if ls, err = NewFromZipReader(r, opts...); err != nil {
+			return err
+		}
+		if cache != "" {
+			writeModuleCache(cache, ls)
+		}
+		return nil
+	})
+	return ls, err
+}
+
+// moduleError reports a module missing from a proxy as ErrModuleNotFound.
+func moduleError(err error) error {
+	if err == ErrNoLicenseFile {
+		return ErrModuleNotFound
+	}
+	return err
+}
+
+// eachProxy calls fetch with each proxy of $GOPROXY until one succeeds.
+func eachProxy(fetch func(proxy string) error) error {
+	list := os.Getenv("GOPROXY")
+	if list == "" {
+		list = DefaultGoProxy
+	}
+
+	err := ErrModuleNotFound
+	for list != "" {
+		proxy := list
+		fallback := false
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			proxy, fallback, list = list[:i], list[i] == '|', list[i+1:]
+		} else {
+			list = ""
+		}
+
+		switch proxy = strings.TrimSuffix(strings.TrimSpace(proxy), "/"); proxy {
+		case "", "direct":
+			continue
+		case "off":
+			return fmt.Errorf("license: module lookup disabled by GOPROXY=off")
+		}
+
+		err = fetch(proxy)
+		switch {
+		case err == nil:
+			return nil
+		case err == ErrModuleNotFound:
+		case !fallback:
+			return err
+		}
+	}
+	return err
+}
+
+// escapeModulePath escapes a module path or version for use in a proxy URL,
+// replacing upper-case letters by an exclamation mark followed by the letter
+// in lower case.
+func escapeModulePath(s string) (string, error) {
+	if s == "" {
+		return "", ErrModuleNotFound
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '!' || r >= unicode.MaxASCII:
+			return "", fmt.Errorf("license: invalid module path or version %q", s)
+		case unicode.IsUpper(r):
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+func readModuleCache(path string) ([]*License, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ls []*License
+	if err := json.Unmarshal(data, &ls); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+// writeModuleCache caches the licenses of a module version. Failing to do so
+// only means that they are downloaded again.
+func writeModuleCache(path string, ls []*License) {
+	data, err := json.Marshal(ls)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err == nil {
+		os.Rename(tmp, path)
+	}
+}
@@ -0,0 +1,84 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromModule(t *testing.T) {
+	zipData := buildZip(t, map[string]string{
+		"github.com/!foo/bar@v1.2.3/LICENSE": "MIT",
+		"github.com/!foo/bar@v1.2.3/main.go": "package main",
+	})
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/github.com/!foo/bar/@latest":
+			w.Write([]byte(`{"Version":"v1.2.3"}`))
+		case "/github.com/!foo/bar/@v/v1.2.3.zip":
+			w.Write(zipData)
+		case "/broken/github.com/!foo/bar/@v/v1.2.3.zip":
+			http.Error(w, "broken", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cache, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(cache)
+
+	// Missing modules fall through to the next proxy
+	t.Setenv("GOPROXY", server.URL+"/missing,direct,"+server.URL)
+	ls, err := license.NewFromModule(context.Background(), "github.com/Foo/bar", "latest", license.WithCacheDir(cache))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+	if requests != 3 {
+		t.Fatalf("\nexpected: 3 requests\ngot: %d", requests)
+	}
+
+	// Cached results are not downloaded again
+	requests = 0
+	t.Setenv("GOPROXY", server.URL)
+	ls, err = license.NewFromModule(context.Background(), "github.com/Foo/bar", "v1.2.3", license.WithCacheDir(cache))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseMIT || requests != 0 {
+		t.Fatalf("unexpected licenses: %v, after %d requests", ls, requests)
+	}
+
+	// Errors only fall through to the next proxy after a pipe
+	t.Setenv("GOPROXY", server.URL+"/broken,"+server.URL)
+	if _, err := license.NewFromModule(context.Background(), "github.com/Foo/bar", "v1.2.3", license.WithCacheDir("")); err == nil {
+		t.Fatalf("expected error from broken proxy")
+	}
+	t.Setenv("GOPROXY", server.URL+"/broken|"+server.URL)
+	if _, err := license.NewFromModule(context.Background(), "github.com/Foo/bar", "v1.2.3", license.WithCacheDir("")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Missing modules fail properly
+	_, err = license.NewFromModule(context.Background(), "github.com/foo/missing", "v1.0.0", license.WithCacheDir(""))
+	if err != license.ErrModuleNotFound {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrModuleNotFound, err)
+	}
+	t.Setenv("GOPROXY", "off")
+	if _, err := license.NewFromModule(context.Background(), "github.com/Foo/bar", "v1.2.3", license.WithCacheDir("")); err == nil {
+		t.Fatalf("expected error with GOPROXY=off")
+	}
+}
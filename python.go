@@ -0,0 +1,297 @@
+package license
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+var (
+	pythonRequirementRegexp = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pythonNameRegexp        = regexp.MustCompile(`[-_.]+`)
+)
+
+// pythonDist is the core metadata of an installed Python distribution, as
+// read from the METADATA file of its .dist-info directory, or the PKG-INFO
+// file of its .egg-info directory.
+type pythonDist struct {
+	name, version string
+	dir           string   // The metadata directory
+	metadata      string   // The path of the metadata file
+	expression    string   // The License-Expression field
+	license       string   // The License field
+	classifiers   []string // The Classifier fields of licenses
+	licenseFiles  []string // The License-File fields
+	requires      []string // The names of the distributions required, without extras
+}
+
+// NewPythonReport searches the site-packages directories of a Python
+// virtualenv, or a site-packages directory itself, for the .dist-info and
+// .egg-info directories of the distributions installed, and reports the
+// licenses of each of them in the same form as NewDependencyReport: the
+// license declared by the License-Expression, the license classifiers or the
+// License field of their metadata, or else those of the files of their
+// License-File fields, or of the license files of their metadata directory.
+// A License field which is not a license name or expression, such as the text
+// of the license, is guessed last. Since an environment does not tell which
+// distributions a project requires, distributions are direct if no other one
+// installed requires them.
+func NewPythonReport(ctx context.Context, dir string, opts ...Option) (*DependencyReport, error) {
+	o := newOptions(opts)
+
+	var dists []*pythonDist
+	for _, site := range sitePackagesDirs(dir) {
+		entries, err := os.ReadDir(site)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			var metadata string
+			switch {
+			case !e.IsDir():
+				continue
+			case strings.HasSuffix(e.Name(), ".dist-info"):
+				metadata = filepath.Join(site, e.Name(), "METADATA")
+			case strings.HasSuffix(e.Name(), ".egg-info"):
+				metadata = filepath.Join(site, e.Name(), "PKG-INFO")
+			default:
+				continue
+			}
+			d, err := readPythonDist(metadata)
+			switch {
+			case os.IsNotExist(err):
+				continue
+			case err != nil:
+				return nil, err
+			}
+			dists = append(dists, d)
+		}
+	}
+
+	required := make(map[string][]string)
+	for _, d := range dists {
+		for _, name := range d.requires {
+			key := pythonName(name)
+			required[key] = appendUnique(required[key], d.name)
+		}
+	}
+
+	report := &DependencyReport{Modules: []*ModuleDependency{}}
+	seen := make(map[string]bool)
+	for _, d := range dists {
+		key := pythonName(d.name) + "@" + d.version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		importedBy := required[pythonName(d.name)]
+		sort.Strings(importedBy)
+		m := &ModuleDependency{
+			Path:       d.name,
+			Version:    d.version,
+			Ecosystem:  "pypi",
+			Dir:        d.dir,
+			Direct:     len(importedBy) == 0,
+			Packages:   []string{d.name},
+			ImportedBy: importedBy,
+		}
+		var err error
+		if m.Licenses, err = d.licenses(ctx, o); err != nil {
+			return nil, fmt.Errorf("license: %s@%s: %w", d.name, d.version, err)
+		}
+		report.Modules = append(report.Modules, m)
+	}
+	sort.SliceStable(report.Modules, func(i, j int) bool {
+		return pythonName(report.Modules[i].Path) < pythonName(report.Modules[j].Path)
+	})
+	return report, nil
+}
+
+// licenses returns the licenses of a distribution, as described by
+// NewPythonReport.
+func (d *pythonDist) licenses(ctx context.Context, o *options) ([]*License, error) {
+	declared := []string{d.expression}
+	if d.expression == "" {
+		for _, c := range d.classifiers {
+			declared = append(declared, classifierLicense(c))
+		}
+	}
+	if d.expression == "" && len(d.classifiers) == 0 {
+		if e, err := spdx.Parse(d.license); err == nil && spdx.Validate(e) == nil {
+			declared = append(declared, d.license)
+		} else if id, err := NormalizeID(d.license); err == nil {
+			declared = append(declared, id)
+		}
+	}
+	if licenseType := declaredType(declared); licenseType != "" {
+		return []*License{{Type: licenseType, File: d.metadata}}, nil
+	}
+
+	var licenses []*License
+	for _, file := range d.licenseFiles {
+		// Since version 2.4 of the core metadata, License-File paths are
+		// relative to the licenses subdirectory
+		path := filepath.Join(d.dir, "licenses", filepath.FromSlash(file))
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(d.dir, filepath.FromSlash(file))
+		}
+		l, err := guessFromFile(ctx, path, o)
+		switch {
+		case err == nil:
+			licenses = append(licenses, l)
+		case err == ErrUnrecognizedLicense:
+			licenses = append(licenses, &License{Type: o.unrecognized(), File: path})
+		case err == ErrFileBudget, os.IsNotExist(err):
+		default:
+			return nil, err
+		}
+	}
+	if len(licenses) > 0 {
+		return licenses, nil
+	}
+
+	for _, dir := range []string{d.dir, filepath.Join(d.dir, "licenses")} {
+		ls, err := guessFromDir(ctx, dir, o)
+		switch {
+		case err == nil:
+			return ls, nil
+		case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, os.IsNotExist(err):
+		default:
+			return nil, err
+		}
+	}
+
+	if d.license != "" {
+		l := &License{Text: d.license, File: d.metadata}
+		if err := o.budgetedGuess(o.guesser(), l); err == nil {
+			return []*License{l}, nil
+		}
+	}
+	return nil, nil
+}
+
+// sitePackagesDirs returns the site-packages directories of a virtualenv, or
+// the directory itself if it has none.
+func sitePackagesDirs(dir string) []string {
+	var dirs []string
+	for _, pattern := range []string{
+		filepath.Join(dir, "lib", "python*", "site-packages"),
+		filepath.Join(dir, "lib64", "python*", "site-packages"),
+		filepath.Join(dir, "Lib", "site-packages"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if real, err := filepath.EvalSymlinks(m); err == nil && indexOf(dirs, real) < 0 {
+				dirs = append(dirs, real)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return []string{dir}
+	}
+	return dirs
+}
+
+// readPythonDist reads the core metadata of a distribution, made of header
+// fields followed by a description, whose continuation lines start with
+// whitespace, and a "|" in the License field as written by setuptools.
+func readPythonDist(path string) (*pythonDist, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &pythonDist{dir: filepath.Dir(path), metadata: path}
+	var key, value string
+	flush := func() {
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(key) {
+		case "name":
+			d.name = value
+		case "version":
+			d.version = value
+		case "license-expression":
+			d.expression = value
+		case "license":
+			d.license = value
+		case "license-file":
+			d.licenseFiles = append(d.licenseFiles, value)
+		case "classifier":
+			if strings.HasPrefix(value, "License ::") {
+				d.classifiers = append(d.classifiers, value)
+			}
+		case "requires-dist":
+			if m := pythonRequirementRegexp.FindStringSubmatch(value); m != nil && !strings.Contains(value, "extra ==") {
+				d.requires = append(d.requires, m[1])
+			}
+		}
+		key, value = "", ""
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, len(data)+1)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			line = strings.TrimSpace(line)
+			value += "\n" + strings.TrimSpace(strings.TrimPrefix(line, "|"))
+			continue
+		}
+		flush()
+		if colon := strings.Index(line, ":"); colon > 0 {
+			key, value = line[:colon], line[colon+1:]
+		}
+	}
+	flush()
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if d.name == "" {
+		return nil, fmt.Errorf("license: invalid metadata %s: no name", path)
+	}
+	return d, nil
+}
+
+// classifierLicense returns the license of a license classifier, such as
+// "License :: OSI Approved :: MIT License", by the name of its last component,
+// or else the identifier in parentheses which ends it, or "" for classifiers
+// not naming a license. Names are looked up as done by NormalizeID.
+func classifierLicense(classifier string) string {
+	parts := strings.Split(classifier, "::")
+	name := strings.TrimSpace(parts[len(parts)-1])
+	if name == "OSI Approved" {
+		return ""
+	}
+	var abbrev string
+	if open := strings.LastIndex(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+		abbrev, name = name[open+1:len(name)-1], strings.TrimSpace(name[:open])
+	}
+	if id := licenseFromName(name); id != name {
+		return id
+	}
+	if id, err := NormalizeID(abbrev); abbrev != "" && err == nil {
+		return id
+	}
+	return name
+}
+
+// pythonName normalizes the name of a distribution, as done by PEP 503.
+func pythonName(name string) string {
+	return pythonNameRegexp.ReplaceAllString(strings.ToLower(name), "-")
+}
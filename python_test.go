@@ -0,0 +1,73 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewPythonReport(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	bsd, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "BSD-2-Clause"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	site := "lib/python3.12/site-packages/"
+	writeFiles(t, d, map[string]string{
+		site + "requests/__init__.py": "",
+		site + "requests-2.31.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\nLicense: Apache 2.0\n" +
+			"Classifier: License :: OSI Approved :: Apache Software License\nClassifier: Programming Language :: Python\n" +
+			"Requires-Dist: urllib3 (<3,>=1.21.1)\nRequires-Dist: PySocks (!=1.5.7,>=1.5.6) ; extra == 'socks'\n\nRequests\n",
+		site + "urllib3-2.0.0.dist-info/METADATA": "Metadata-Version: 2.4\nName: urllib3\nVersion: 2.0.0\nLicense-Expression: MIT\n",
+		site + "gpl_tool-1.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: gpl-tool\nVersion: 1.0\n" +
+			"Classifier: License :: OSI Approved\nClassifier: License :: OSI Approved :: GNU General Public License v3 or later (GPLv3+)\n",
+		site + "old_gpl-1.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: old-gpl\nVersion: 1.0\n" +
+			"Classifier: License :: OSI Approved :: GNU General Public License (GPL)\n",
+		site + "attrs-23.1.0.dist-info/METADATA": "Metadata-Version: 2.4\nName: attrs\nVersion: 23.1.0\nLicense-File: LICENSE\n",
+		site + "Thing_Lib-0.1.egg-info/PKG-INFO": "Metadata-Version: 1.0\nName: Thing_Lib\nVersion: 0.1\n" +
+			"License: " + strings.Replace(strings.TrimSpace(string(bsd)), "\n", "\n        ", -1) + "\nRequires-Dist: urllib3\n",
+	})
+	copyFixture(t, "MIT", filepath.Join(d, site, "attrs-23.1.0.dist-info", "licenses", "LICENSE"))
+
+	r, err := license.NewPythonReport(context.Background(), d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type summary struct {
+		Path, Version string
+		Direct        bool
+		ImportedBy    []string
+		Type, File    string
+	}
+	expected := []summary{
+		{"attrs", "23.1.0", true, nil, "MIT", "attrs-23.1.0.dist-info/licenses/LICENSE"},
+		{"gpl-tool", "1.0", true, nil, "GPL-3.0-or-later", "gpl_tool-1.0.dist-info/METADATA"},
+		{"old-gpl", "1.0", true, nil, "GPL-1.0-or-later", "old_gpl-1.0.dist-info/METADATA"},
+		{"requests", "2.31.0", true, nil, "Apache-2.0", "requests-2.31.0.dist-info/METADATA"},
+		{"Thing_Lib", "0.1", true, nil, "BSD-2-Clause", "Thing_Lib-0.1.egg-info/PKG-INFO"},
+		{"urllib3", "2.0.0", false, []string{"Thing_Lib", "requests"}, "MIT", "urllib3-2.0.0.dist-info/METADATA"},
+	}
+	var got []summary
+	for _, m := range r.Modules {
+		if len(m.Licenses) != 1 || m.Ecosystem != "pypi" {
+			t.Fatalf("%s: unexpected licenses: %v", m.Path, m.Licenses)
+		}
+		file, _ := filepath.Rel(filepath.Join(d, site), m.Licenses[0].File)
+		got = append(got, summary{m.Path, m.Version, m.Direct, m.ImportedBy, m.Licenses[0].Type, filepath.ToSlash(file)})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, got)
+	}
+}
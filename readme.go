@@ -0,0 +1,190 @@
+package license
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// The confidence of each kind of license declaration found in a README. Each
+// is below that of a license file, since a declaration does not carry the
+// terms of the license.
+const (
+	readmeStatementScore = 0.6 // "Licensed under the MIT License"
+	readmeBadgeScore     = 0.5 // A shields.io license badge
+	readmeLinkScore      = 0.4 // A link to the text of the license
+)
+
+// The maximum number of words of a license name in a statement
+const maxStatementWords = 8
+
+// ErrNoReadmeFile is returned if a directory has no README file.
+var ErrNoReadmeFile = errors.New("license: unable to find any readme file")
+
+// DefaultReadmeFiles are the README file names searched by NewFromReadme.
+// Case does not matter.
+var DefaultReadmeFiles = []string{"readme*"}
+
+var (
+	// A statement of the license, such as "Released under the MIT License"
+	readmeStatementRegexp = regexp.MustCompile(`(?i)\b(?:licen[cs]ed|released|distributed|available)\s+under\s+(?:the\s+terms\s+of\s+)?([^\n]+)`)
+
+	// A "License: MIT" line, or a license heading followed by the license
+	readmeFieldRegexp   = regexp.MustCompile(`(?im)^[\s>*_-]*licen[cs]e\s*[:=]\s*(.+)$`)
+	readmeHeadingRegexp = regexp.MustCompile(`(?im)^(?:#+[ \t]*licen[cs]es?[ \t]*#*|licen[cs]es?[ \t]*\n[=-]+)[ \t]*\n(?:[ \t]*\n)*([^\n]+)`)
+
+	// A shields.io static badge labelled as a license, such as
+	// https://img.shields.io/badge/License-Apache%202.0-blue.svg
+	readmeBadgeRegexp = regexp.MustCompile(`(?i)img\.shields\.io/badge/licen[cs]e-((?:[^-/?#()\s]|--)+)-[^/?#()\s]+`)
+
+	// Markdown links and images, whose text is kept
+	markdownLinkRegexp = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+)
+
+// GuessReadmeType will guess the license declared by the text of a README,
+// and return it along with a confidence between 0 and 1, below that of
+// GuessTypeWithConfidence for a license file. Statements such as "Licensed
+// under the MIT License", "License: MIT" and the text following a "License"
+// heading score highest, then shields.io license badges, then links to a
+// license, such as https://opensource.org/licenses/MIT. The first declaration
+// of the highest scoring kind is returned.
+func GuessReadmeType(text string) (string, float64, error) {
+	licenseType, score, _ := guessReadme(text)
+	if licenseType == "" {
+		return "", 0, ErrUnrecognizedLicense
+	}
+	return licenseType, score, nil
+}
+
+// NewFromReadme is a fallback for directories without a license file, which
+// searches a directory for README files, as named by DefaultReadmeFiles, and
+// guesses the license each declares as done by GuessReadmeType. The license
+// with the highest confidence is returned, with the declaration as its text.
+// The file system given by WithFS is searched, if any.
+func NewFromReadme(dir string, opts ...Option) (*License, float64, error) {
+	o := newOptions(opts)
+	var files []string
+	var err error
+	if o.fsys != nil {
+		files, err = readFSDirectory(o.fsys, dir)
+	} else {
+		files, err = readDirectory(dir)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	compiled, err := complileLicensePatters(DefaultReadmeFiles)
+	if err != nil {
+		return nil, 0, err
+	}
+	readmes := matchLicenseFile(compiled, files)
+	if len(readmes) == 0 {
+		return nil, 0, ErrNoReadmeFile
+	}
+
+	var best *License
+	var score float64
+	for _, name := range readmes {
+		var file string
+		var data []byte
+		if o.fsys != nil {
+			file = path.Join(dir, name)
+			data, err = fs.ReadFile(o.fsys, file)
+		} else {
+			file = filepath.Join(dir, name)
+			data, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			continue
+		}
+		if licenseType, s, declaration := guessReadme(string(data)); licenseType != "" && s > score {
+			best = &License{Type: licenseType, Text: declaration, File: file}
+			score = s
+		}
+	}
+	if best == nil {
+		return nil, 0, ErrUnrecognizedLicense
+	}
+	return best, score, nil
+}
+
+// guessReadme returns the license declared by a README, its score, and the
+// declaration, or an empty type if there is none.
+func guessReadme(text string) (licenseType string, score float64, declaration string) {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+
+	start := len(text) + 1
+	found := func(id string, s float64, at int, decl string) {
+		if id != "" && (s > score || s == score && at < start) {
+			licenseType, score, start, declaration = id, s, at, strings.TrimSpace(decl)
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{readmeStatementRegexp, readmeFieldRegexp, readmeHeadingRegexp} {
+		for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+			found(statementLicense(text[m[2]:m[3]]), readmeStatementScore, m[0], text[m[0]:m[1]])
+		}
+	}
+	for _, m := range readmeBadgeRegexp.FindAllStringSubmatchIndex(text, -1) {
+		found(badgeLicense(text[m[2]:m[3]]), readmeBadgeScore, m[0], text[m[0]:m[1]])
+	}
+	if id, link, ok := referencedType(text); ok {
+		found(id, readmeLinkScore, strings.Index(text, link), link)
+	}
+	return licenseType, score, declaration
+}
+
+// statementLicense returns the license named at the start of a statement,
+// trying its longest prefix first, so that the rest of the sentence, as in
+// "the MIT License - see LICENSE for details", is left out.
+func statementLicense(statement string) string {
+	statement = markdownLinkRegexp.ReplaceAllString(statement, "$1")
+	words := strings.Fields(statement)
+	if len(words) > maxStatementWords {
+		words = words[:maxStatementWords]
+	}
+	for n := len(words); n > 0; n-- {
+		if words[n-1] == "-" || words[n-1] == "(" {
+			continue
+		}
+		name := strings.TrimRight(strings.Join(words[:n], " "), ".,;:!*_)")
+		name = strings.TrimLeft(name, "*_(")
+		if id := knownLicenseName(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// badgeLicense returns the license of the message of a shields.io badge, in
+// which "--" is a dash and "_" is a space.
+func badgeLicense(message string) string {
+	message = strings.Replace(message, "--", "\x00", -1)
+	message = strings.Replace(message, "__", "\x01", -1)
+	message = strings.Replace(message, "_", " ", -1)
+	message = strings.Replace(message, "\x00", "-", -1)
+	message = strings.Replace(message, "\x01", "_", -1)
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+	return knownLicenseName(message)
+}
+
+// knownLicenseName returns the SPDX license identifier of a license name or
+// identifier, or an empty string if it is not on the SPDX license list.
+func knownLicenseName(name string) string {
+	if name == "" {
+		return ""
+	}
+	if l, ok := spdx.Get(licenseFromName(name)); ok {
+		return l.ID
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package license_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGuessReadmeType(t *testing.T) {
+	tests := []struct {
+		text  string
+		id    string
+		score float64
+	}{
+		{"This project is licensed under the MIT License - see the LICENSE file for details.", license.LicenseMIT, 0.6},
+		{"Released under the terms of the Apache License, Version 2.0.", license.LicenseApache20, 0.6},
+		{"Distributed under the GPLv3. See COPYING.", "GPL-3.0-only", 0.6},
+		{"# foo\n\n## License\n\n[ISC](LICENSE) © Jane Doe\n", license.LicenseISC, 0.6},
+		{"License\n-------\n\nBSD 3-Clause License\n", license.LicenseBSD3Clause, 0.6},
+		{"* License: MPL-2.0\n", license.LicenseMPL20, 0.6},
+		{"[![License](https://img.shields.io/badge/License-Apache%202.0-blue.svg)](LICENSE)", license.LicenseApache20, 0.5},
+		{"![license](https://img.shields.io/badge/license-BSD--3--Clause-green)", license.LicenseBSD3Clause, 0.5},
+		{"See https://opensource.org/licenses/MIT for the terms.", license.LicenseMIT, 0.4},
+		{"<a href=\"https://www.apache.org/licenses/LICENSE-2.0\">terms</a>", license.LicenseApache20, 0.4},
+		{"http://www.gnu.org/licenses/lgpl-3.0.html", "LGPL-3.0", 0.4},
+		{"[![License: MIT](https://img.shields.io/badge/License-MIT-yellow.svg)](https://opensource.org/licenses/MIT)\n\n## License\n\nUnlicense", license.LicenseUnlicense, 0.6},
+	}
+	for _, test := range tests {
+		id, score, err := license.GuessReadmeType(test.text)
+		if err != nil {
+			t.Fatalf("err: %s (%q)", err, test.text)
+		}
+		if id != test.id || score != test.score {
+			t.Fatalf("\nexpected: %s (%.1f)\ngot: %s (%.1f)\ntext: %q", test.id, test.score, id, score, test.text)
+		}
+	}
+
+	for _, text := range []string{"# foo\n\nA tool with no license information.", "Licensed under the terms of my choosing."} {
+		if _, _, err := license.GuessReadmeType(text); err != license.ErrUnrecognizedLicense {
+			t.Fatalf("expected ErrUnrecognizedLicense, got: %v (%q)", err, text)
+		}
+	}
+}
+
+func TestNewFromReadme(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":        {Data: []byte("# foo\n\n[![License: MIT](https://img.shields.io/badge/License-MIT-yellow.svg)](LICENSE)\n")},
+		"docs/README":      {Data: []byte("Nothing to see here.")},
+		"lib/main.go":      {Data: []byte("package lib")},
+		"other/readme":     {Data: []byte("Old notes")},
+		"other/README.rst": {Data: []byte("Licensed under the ISC license.")},
+	}
+
+	l, score, err := license.NewFromReadme(".", license.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || l.File != "README.md" || score != 0.5 {
+		t.Fatalf("unexpected license: %s (%s, %.1f)", l.Type, l.File, score)
+	}
+	if l.Text != "img.shields.io/badge/License-MIT-yellow.svg" {
+		t.Fatalf("unexpected declaration: %q", l.Text)
+	}
+
+	l, _, err = license.NewFromReadme("other", license.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseISC || l.File != "other/README.rst" {
+		t.Fatalf("unexpected license: %s (%s)", l.Type, l.File)
+	}
+
+	if _, _, err := license.NewFromReadme("docs", license.WithFS(fsys)); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected ErrUnrecognizedLicense, got: %v", err)
+	}
+	if _, _, err := license.NewFromReadme("lib", license.WithFS(fsys)); err != license.ErrNoReadmeFile {
+		t.Fatalf("expected ErrNoReadmeFile, got: %v", err)
+	}
+}
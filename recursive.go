@@ -0,0 +1,336 @@
+package license
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NewFromDirRecursive will search a directory and all of its subdirectories
+// for well-known and accepted license file names, and guess the license type
+// of each one found. The result maps each directory containing license files
+// to the licenses found in it.
+func NewFromDirRecursive(dir string, opts ...Option) (map[string][]*License, error) {
+	return NewFromDirRecursiveCtx(newOptions(opts).ctx, dir, opts...)
+}
+
+// NewFromDirRecursiveCtx is like NewFromDirRecursive, but stops with the error
+// of ctx once it is done.
+func NewFromDirRecursiveCtx(ctx context.Context, dir string, opts ...Option) (map[string][]*License, error) {
+	return scanDirRecursive(ctx, dir, newOptions(opts))
+}
+
+// scanDirRecursive searches a directory of the file system set by WithFS, if
+// any, and its subdirectories for license files, as configured by o.
+func scanDirRecursive(ctx context.Context, dir string, o *options) (map[string][]*License, error) {
+	return walkDirRecursive(ctx, dir, o, func(dir string) ([]*License, error) {
+		return scanDir(ctx, dir, o)
+	})
+}
+
+// walkDirRecursive walks a directory of the file system set by WithFS, if any,
+// and its subdirectories, skipping those skipped by scanDirRecursive, and
+// guesses the licenses of each directory with guess.
+func walkDirRecursive(ctx context.Context, dir string, o *options, guess func(dir string) ([]*License, error)) (map[string][]*License, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.fsys != nil {
+		source := func(dir string) *licenseSource {
+			return fsSource(o.fsys, dir)
+		}
+		return walkLicenses(ctx, path.Clean(dir), o, fsWalk(o.fsys, o), slashDepth, source, guess)
+	}
+	return walkLicenses(ctx, filepath.Clean(dir), o, osWalk(o), depth, osSource, guess)
+}
+
+// osWalk returns the function which walks directory trees of the operating
+// system, following symlinks if configured by o.
+func osWalk(o *options) func(string, fs.WalkDirFunc) error {
+	if o.symlinks != SymlinkFollow {
+		return filepath.WalkDir
+	}
+	resolve := func(name string) string {
+		if resolved, err := filepath.EvalSymlinks(name); err == nil {
+			return resolved
+		}
+		return name
+	}
+	return func(root string, fn fs.WalkDirFunc) error {
+		return followWalk(root, os.ReadDir, os.Stat, filepath.Join, resolve, fn)
+	}
+}
+
+// fsWalk returns the function which walks directory trees of fsys, following
+// symlinks if configured by o.
+func fsWalk(fsys fs.FS, o *options) func(string, fs.WalkDirFunc) error {
+	readDir := func(name string) ([]fs.DirEntry, error) {
+		return fs.ReadDir(fsys, name)
+	}
+	stat := func(name string) (fs.FileInfo, error) {
+		return fs.Stat(fsys, name)
+	}
+	resolve := func(name string) string {
+		return resolveFS(fsys, name)
+	}
+	return func(root string, fn fs.WalkDirFunc) error {
+		if o.symlinks != SymlinkFollow {
+			return fs.WalkDir(fsys, root, fn)
+		}
+		return followWalk(root, readDir, stat, path.Join, resolve, fn)
+	}
+}
+
+// The maximum number of symlinks followed on the way to a directory, as for
+// the ELOOP limit of Linux, in case the file system cannot tell that two
+// directories are the same.
+const maxSymlinks = 40
+
+// dirKey identifies a directory visited by followWalk: by its device and
+// inode where the file system reports them, or else by its path with
+// symlinks resolved.
+type dirKey struct {
+	dev, ino uint64
+	path     string
+}
+
+// resolveFS returns the path of name in fsys with symlinks resolved, or name
+// itself if they cannot be.
+func resolveFS(fsys fs.FS, name string) string {
+	resolved := "."
+	rest := strings.Split(name, "/")
+	for links := 0; len(rest) > 0; {
+		elem := rest[0]
+		rest = rest[1:]
+		if elem == "" || elem == "." {
+			continue
+		}
+		next := path.Join(resolved, elem)
+		info, err := fs.Lstat(fsys, next)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		target, err := fs.ReadLink(fsys, next)
+		if links++; err != nil || links > maxSymlinks || path.IsAbs(target) {
+			return name
+		}
+		rest = append(strings.Split(target, "/"), rest...)
+	}
+	return resolved
+}
+
+// followWalk walks the directories of the tree at root as filepath.WalkDir
+// does, calling fn for root and each directory, but also descends into
+// symlinked directories. Each directory is visited once, even if reached
+// through several links, so that symlink loops end, and by its own path
+// rather than a link if both are in the same directory. Directories are told
+// apart by their device and inode, or else by their path resolved by resolve.
+func followWalk(root string, readDir func(string) ([]fs.DirEntry, error), stat func(string) (fs.FileInfo, error),
+	join func(...string) string, resolve func(string) string, fn fs.WalkDirFunc) error {
+
+	info, err := stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	visited := make(map[dirKey]bool)
+	key := func(name string, info fs.FileInfo) dirKey {
+		if dev, ino, ok := fileID(info); ok {
+			return dirKey{dev: dev, ino: ino}
+		}
+		return dirKey{path: resolve(name)}
+	}
+	var visit func(dir string, d fs.DirEntry, links int) error
+	visit = func(dir string, d fs.DirEntry, links int) error {
+		if err := fn(dir, d, nil); err != nil || !d.IsDir() {
+			if err == filepath.SkipDir {
+				return nil
+			}
+			return err
+		}
+		entries, err := readDir(dir)
+		if err != nil {
+			return fn(dir, d, err)
+		}
+		// Directories are visited before symlinks, to be found by their own path
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Type()&fs.ModeSymlink == 0 && entries[j].Type()&fs.ModeSymlink != 0
+		})
+		for _, entry := range entries {
+			name := join(dir, entry.Name())
+			sub := links
+			if entry.Type()&fs.ModeSymlink != 0 {
+				if sub++; sub > maxSymlinks {
+					continue
+				}
+				info, err := stat(name)
+				if err != nil {
+					continue
+				}
+				entry = fs.FileInfoToDirEntry(info)
+			}
+			if !entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			k := key(name, info)
+			if visited[k] {
+				continue
+			}
+			visited[k] = true
+			if err := visit(name, entry, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	visited[key(root, info)] = true
+	return visit(root, fs.FileInfoToDirEntry(info), 0)
+}
+
+// walkLicenses walks the directory tree at root with walk, and guesses the
+// licenses of each directory with guess, as described by NewFromDirRecursive,
+// as many directories at a time as set by WithConcurrency. The ignore files of
+// each directory are read from the source returned by source.
+func walkLicenses(ctx context.Context, root string, o *options, walk func(string, fs.WalkDirFunc) error,
+	depth func(root, path string) int, source func(dir string) *licenseSource,
+	guess func(dir string) ([]*License, error)) (map[string][]*License, error) {
+
+	rules, err := o.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		sem      chan struct{} // Limits the directories guessed at a time
+		guessErr error         // The first error of the directories guessed concurrently
+	)
+	if o.concurrency > 1 {
+		sem = make(chan struct{}, o.concurrency)
+	}
+	results := make(map[string][]*License)
+	collect := func(path string, rules ignoreRules) error {
+		ls, err := guess(path)
+		switch err {
+		case nil:
+			if len(ls) == 1 && ls[0].Type == LicenseNone && path != root {
+				return nil
+			}
+			if ls = rules.unignored(root, ls); len(ls) > 0 {
+				mu.Lock()
+				results[path] = ls
+				mu.Unlock()
+			}
+		case ErrNoLicenseFile, ErrUnrecognizedLicense:
+		default:
+			return err
+		}
+		return nil
+	}
+
+	err = walk(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mu.Lock()
+		err = guessErr
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if path == root {
+				return ErrNoLicenseFile
+			}
+			return nil
+		}
+		if path != root {
+			if o.skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			if o.maxDepth >= 0 && depth(root, path) > o.maxDepth {
+				return filepath.SkipDir
+			}
+		}
+		rel := relSlash(root, path)
+		if path != root && rules.ignored(rel, true) {
+			return filepath.SkipDir
+		}
+		if rel == "." {
+			rel = ""
+		}
+		src := source(path)
+		for _, name := range o.ignoreFiles() {
+			if data, err := src.read(src.join(name)); err == nil {
+				rules = append(rules, parseIgnore(rel, data)...)
+			}
+		}
+
+		if sem == nil {
+			return collect(path, rules)
+		}
+		// The rules of later directories are appended to a copy
+		rules := rules[:len(rules):len(rules)]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := collect(path, rules); err != nil {
+				mu.Lock()
+				if guessErr == nil {
+					guessErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+		return nil
+	})
+	wg.Wait()
+	if guessErr != nil {
+		return nil, guessErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoLicenseFile
+	}
+	return results, nil
+}
+
+// depth returns how many levels below root the given path is.
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// slashDepth returns how many levels below root the given slash-separated
+// path is.
+func slashDepth(root, name string) int {
+	if root == "." {
+		return strings.Count(name, "/") + 1
+	}
+	rel := strings.TrimPrefix(name, root+"/")
+	if rel == name {
+		return 0
+	}
+	return strings.Count(rel, "/") + 1
+}
@@ -0,0 +1,266 @@
+package license_test
+
+import (
+	"context"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// copyFixture writes the named license fixture to path, creating any missing
+// parent directories.
+func copyFixture(t *testing.T, ltype, path string) {
+	licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, licenseText, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestNewFromDirRecursive(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	// Fails properly if the tree contains no license files
+	if _, err := license.NewFromDirRecursive(d); err != license.ErrNoLicenseFile {
+		t.Fatalf("expected error loading empty directory, got: %v", err)
+	}
+
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+	copyFixture(t, "ISC", filepath.Join(d, "sub", "LICENSE"))
+	copyFixture(t, "Apache-2.0", filepath.Join(d, "sub", "deep", "COPYING"))
+	copyFixture(t, "GPL-2.0", filepath.Join(d, "vendor", "dep", "LICENSE"))
+	copyFixture(t, "GPL-3.0", filepath.Join(d, ".git", "LICENSE"))
+
+	results, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]string{
+		d:                               license.LicenseMIT,
+		filepath.Join(d, "sub"):         license.LicenseISC,
+		filepath.Join(d, "sub", "deep"): license.LicenseApache20,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	for dir, ltype := range expected {
+		ls := results[dir]
+		if len(ls) != 1 || ls[0].Type != ltype {
+			t.Fatalf("unexpected licenses in %s: %v", dir, ls)
+		}
+	}
+
+	// Depth is limited
+	results, err = license.NewFromDirRecursive(d, license.WithMaxDepth(1))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results[filepath.Join(d, "sub", "deep")] != nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	// Skip list is replaceable
+	results, err = license.NewFromDirRecursive(d, license.WithSkipDirs("sub"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results[filepath.Join(d, "vendor", "dep")] == nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	// Version control metadata is only descended into if asked for
+	results, err = license.NewFromDirRecursive(d, license.WithSkipDirs("sub"), license.WithVCSDirs())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 3 || results[filepath.Join(d, ".git")] == nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	// Fails properly if the directory does not exist
+	if _, err := license.NewFromDirRecursive("go-license-nonexistent"); err == nil {
+		t.Fatalf("expected error loading non-existent directory")
+	}
+
+	// Fails properly if the directory specified is actually a file
+	if _, err := license.NewFromDirRecursive(filepath.Join(d, "LICENSE")); err == nil {
+		t.Fatalf("expected error loading file as directory")
+	}
+}
+
+func TestNewFromDirRecursiveCtx(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	copyFixture(t, license.LicenseMIT, filepath.Join(d, "LICENSE"))
+	copyFixture(t, license.LicenseGPL30, filepath.Join(d, "sub", "COPYING"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := license.NewFromDirRecursiveCtx(ctx, d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	// Cancelled scans stop with the error of the context
+	cancel()
+	if _, err := license.NewFromDirRecursiveCtx(ctx, d); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+	if _, err := license.NewFromDirCtx(ctx, d); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+}
+
+func TestNewFromDirRecursive_Symlinks(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	ext, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(ext)
+
+	copyFixture(t, license.LicenseMIT, filepath.Join(d, "LICENSE"))
+	copyFixture(t, license.LicenseApache20, filepath.Join(d, "real", "sub", "LICENSE"))
+	copyFixture(t, license.LicenseBSD3Clause, filepath.Join(ext, "LICENSE"))
+	for link, target := range map[string]string{
+		filepath.Join(d, "link"):                filepath.Join(d, "real", "sub"),
+		filepath.Join(d, "ext"):                 ext,
+		filepath.Join(d, "real", "sub", "loop"): d,
+	} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks are not supported: %s", err)
+		}
+	}
+
+	// Symlinked directories are not descended into by default
+	found, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(found) != 2 || found[filepath.Join(d, "real", "sub")] == nil {
+		t.Fatalf("unexpected results: %v", found)
+	}
+
+	// Followed symlinks visit each directory once, by its own path
+	found, err = license.NewFromDirRecursive(d, license.WithSymlinks(license.SymlinkFollow))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(found) != 3 || found[filepath.Join(d, "real", "sub")] == nil || found[filepath.Join(d, "ext")] == nil {
+		t.Fatalf("unexpected results: %v", found)
+	}
+}
+
+func TestNewFromDirRecursive_SymlinksFS(t *testing.T) {
+	mit := []byte("Permission is hereby granted, free of charge, to any person obtaining a copy of this software")
+	fsys := fstest.MapFS{
+		"LICENSE":              {Data: mit},
+		"real/sub/LICENSE":     {Data: mit},
+		"link":                 {Data: []byte("real/sub"), Mode: fs.ModeSymlink},
+		"real/sub/loop":        {Data: []byte("../.."), Mode: fs.ModeSymlink},
+		"real/sub/nested/back": {Data: []byte("../../sub"), Mode: fs.ModeSymlink},
+	}
+
+	// The directories of file systems without inodes are told apart by their
+	// resolved paths, so that loops end at once
+	found, err := license.NewFromDirRecursive(".", license.WithFS(fsys), license.WithSymlinks(license.SymlinkFollow))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(found) != 2 || found["."] == nil || found["real/sub"] == nil {
+		t.Fatalf("unexpected results: %v", found)
+	}
+}
+
+func TestNewLicensesFromDir_Duplicates(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	copyFixture(t, license.LicenseMIT, filepath.Join(d, "LICENSE"))
+	if err := os.Symlink("LICENSE", filepath.Join(d, "COPYING")); err != nil {
+		t.Skipf("symlinks are not supported: %s", err)
+	}
+
+	for _, tc := range []struct {
+		opts  []license.Option
+		files []string
+	}{
+		{nil, []string{"COPYING"}},
+		{[]license.Option{license.WithDuplicates(license.DuplicatesKeep)}, []string{"COPYING", "LICENSE"}},
+		{[]license.Option{license.WithSymlinks(license.SymlinkNone)}, []string{"LICENSE"}},
+	} {
+		ls, err := license.NewLicensesFromDir(d, tc.opts...)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(ls) != len(tc.files) {
+			t.Fatalf("unexpected licenses: %v", ls)
+		}
+		for i, l := range ls {
+			if filepath.Base(l.File) != tc.files[i] || l.Type != license.LicenseMIT {
+				t.Fatalf("\nexpected: %s\ngot: %s (%s)", tc.files[i], l.File, l.Type)
+			}
+		}
+	}
+
+	// Names which differ only in case, as in archives
+	fsys := fstest.MapFS{
+		"LICENSE": {Data: []byte("Permission is hereby granted, free of charge, to any person obtaining a copy of this software")},
+		"license": {Data: []byte("Permission is hereby granted, free of charge, to any person obtaining a copy of this software")},
+	}
+	if ls, err := license.NewLicensesFromFS(fsys, "."); err != nil || len(ls) != 2 {
+		t.Fatalf("unexpected licenses: %v (%v)", ls, err)
+	}
+	if ls, err := license.NewLicensesFromFS(fsys, ".", license.WithDuplicates(license.DuplicatesFoldCase)); err != nil || len(ls) != 1 {
+		t.Fatalf("unexpected licenses: %v (%v)", ls, err)
+	}
+}
+
+func BenchmarkNewFromDirRecursive(b *testing.B) {
+	d := b.TempDir()
+	for _, ltype := range license.KnownLicenses {
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			b.Fatalf("err: %s", err)
+		}
+		dir := filepath.Join(d, "pkg", ltype)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "LICENSE"), lbytes, 0644); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := license.NewFromDirRecursive(d); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
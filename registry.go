@@ -0,0 +1,215 @@
+package license
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Registry holds the license types listed as known and the license file name
+// patterns searched for, starting from KnownLicenses and DefaultLicenseFiles,
+// and is safe to change while other goroutines scan. Scans given a Registry by
+// WithRegistry use a snapshot of it, taken when the options are applied, so
+// that changes made during a scan do not affect it.
+type Registry struct {
+	mu       sync.RWMutex
+	licenses []string
+	removed  []string
+	files    []string
+}
+
+// RegistrySnapshot is a copy of a Registry at one point in time, which does
+// not change.
+type RegistrySnapshot struct {
+	licenses []string
+	removed  []string
+	files    []string
+}
+
+// The snapshot used by scans without a Registry, copied from KnownLicenses and
+// DefaultLicenseFiles when the package is initialized
+var defaultRegistry = &RegistrySnapshot{
+	licenses: append([]string(nil), KnownLicenses...),
+	files:    append([]string(nil), DefaultLicenseFiles...),
+}
+
+// NewRegistry creates a Registry holding the license types and license file
+// name patterns used by default.
+func NewRegistry() *Registry {
+	return &Registry{
+		licenses: append([]string(nil), defaultRegistry.licenses...),
+		files:    append([]string(nil), defaultRegistry.files...),
+	}
+}
+
+// Add adds license types to those known, unless they are already.
+func (r *Registry) Add(licenseTypes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.licenses = addStrings(r.licenses, licenseTypes)
+	for _, licenseType := range licenseTypes {
+		r.removed = removeString(r.removed, licenseType)
+	}
+}
+
+// Remove removes license types from those known. Removed types are no longer
+// recognized, even if they are on the SPDX license list, and scans given the
+// registry report the files of those types as unrecognized.
+func (r *Registry) Remove(licenseTypes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, licenseType := range licenseTypes {
+		r.licenses = removeString(r.licenses, licenseType)
+	}
+	r.removed = addStrings(r.removed, licenseTypes)
+}
+
+// AddFilePatterns adds license file name patterns to those searched for,
+// unless they are already. Patterns are matched as described by
+// WithFilePatterns.
+func (r *Registry) AddFilePatterns(patterns ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files = addStrings(r.files, patterns)
+}
+
+// RemoveFilePatterns removes license file name patterns from those searched
+// for.
+func (r *Registry) RemoveFilePatterns(patterns ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pattern := range patterns {
+		r.files = removeString(r.files, pattern)
+	}
+}
+
+// Snapshot returns a copy of the registry as it is now.
+func (r *Registry) Snapshot() *RegistrySnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &RegistrySnapshot{
+		licenses: append([]string(nil), r.licenses...),
+		removed:  append([]string(nil), r.removed...),
+		files:    append([]string(nil), r.files...),
+	}
+}
+
+// Licenses returns the license types known.
+func (s *RegistrySnapshot) Licenses() []string {
+	return append([]string(nil), s.licenses...)
+}
+
+// FilePatterns returns the license file name patterns searched for.
+func (s *RegistrySnapshot) FilePatterns() []string {
+	return append([]string(nil), s.files...)
+}
+
+// Recognized determines if a license type is recognized, as done by
+// License.Recognized, but with the license types of the snapshot in place of
+// those known by default.
+func (s *RegistrySnapshot) Recognized(licenseType string) bool {
+	if s.excludes(licenseType) {
+		return false
+	}
+	return recognized(s.licenses, licenseType)
+}
+
+// excludes determines if a license type, or one of the licenses of an SPDX
+// expression, was removed from the registry.
+func (s *RegistrySnapshot) excludes(licenseType string) bool {
+	if len(s.removed) == 0 {
+		return false
+	}
+	ids := []string{licenseType}
+	if e, err := spdx.Parse(licenseType); err == nil {
+		ids = spdx.Licenses(e)
+	}
+	for _, id := range ids {
+		if license, _, ok := withException(id); ok {
+			id = license
+		}
+		for _, removed := range s.removed {
+			if strings.EqualFold(removed, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithRegistry scans with a snapshot of r, taken when the option is applied:
+// its license file name patterns are searched for in place of
+// DefaultLicenseFiles, and the license types removed from it are reported as
+// unrecognized. Patterns given by WithFilePatterns still take precedence.
+func WithRegistry(r *Registry) Option {
+	s := r.Snapshot()
+	return func(o *options) {
+		o.registry = s
+	}
+}
+
+// snapshot returns the registry snapshot of o, or the default one.
+func (o *options) snapshot() *RegistrySnapshot {
+	if o.registry != nil {
+		return o.registry
+	}
+	return defaultRegistry
+}
+
+// registeredGuess wraps guess to reject the license types removed from the
+// registry of o.
+func (o *options) registeredGuess(guess func(*License) error) func(*License) error {
+	return func(l *License) error {
+		if err := guess(l); err != nil {
+			return err
+		}
+		if o.snapshot().excludes(l.Type) {
+			l.Type, l.Language, l.Rider, l.Reference = "", "", "", false
+			return ErrUnrecognizedLicense
+		}
+		return nil
+	}
+}
+
+// guesser returns the function guessing the types of license texts with the
+// configuration of o.
+func (o *options) guesser() func(*License) error {
+	return o.registeredGuess(o.cachedGuess(o.strictGuess(o.guessType)))
+}
+
+// recognized determines if a license type is one of the known ones, as
+// described by License.Recognized.
+func recognized(known []string, licenseType string) bool {
+	for _, license := range known {
+		if license == licenseType {
+			return true
+		}
+	}
+	if _, ok := spdx.Get(licenseType); ok || licenseType == LicensePublicDomain || licenseType == LicenseProprietary {
+		return true
+	}
+	if license, exception, ok := withException(licenseType); ok {
+		_, isException := spdx.GetException(exception)
+		return isException && recognized(known, license)
+	}
+	_, ok := definedLicense(licenseType)
+	return ok
+}
+
+// addStrings appends the strings missing from list, ignoring case.
+func addStrings(list, add []string) []string {
+	for _, s := range add {
+		found := false
+		for _, e := range list {
+			if strings.EqualFold(e, s) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, s)
+		}
+	}
+	return list
+}
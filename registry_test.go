@@ -0,0 +1,114 @@
+package license_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestRegistry(t *testing.T) {
+	r := license.NewRegistry()
+	r.Add("Acme-1.0")
+	s := r.Snapshot()
+	r.Remove("Acme-1.0")
+
+	if !s.Recognized("Acme-1.0") {
+		t.Fatalf("expected Acme-1.0 to be recognized by the snapshot")
+	}
+	if r.Snapshot().Recognized("Acme-1.0") {
+		t.Fatalf("expected Acme-1.0 not to be recognized once removed")
+	}
+	if (&license.License{Type: "Acme-1.0"}).Recognized() {
+		t.Fatalf("expected KnownLicenses not to change")
+	}
+	if expected := len(license.KnownLicenses) + 1; len(s.Licenses()) != expected {
+		t.Fatalf("\nexpected: %d\ngot: %d", expected, len(s.Licenses()))
+	}
+}
+
+func TestWithRegistry(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LEGAL"))
+
+	r := license.NewRegistry()
+	if _, err := license.NewFromDir(d, license.WithRegistry(r)); err != license.ErrNoLicenseFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoLicenseFile, err)
+	}
+
+	r.AddFilePatterns("legal")
+	opt := license.WithRegistry(r)
+	r.RemoveFilePatterns("legal")
+	l, err := license.NewFromDir(d, opt)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, l.Type)
+	}
+}
+
+func TestWithRegistry_Removed(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+
+	r := license.NewRegistry()
+	r.Remove(license.LicenseMIT)
+	opt := license.WithRegistry(r)
+	if _, err := license.NewFromDir(d, opt); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnrecognizedLicense, err)
+	}
+	s, err := license.NewScanner(opt)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l := (&license.License{Type: license.LicenseMIT}); s.Recognized(l) || !l.Recognized() {
+		t.Fatalf("expected MIT to be recognized by default only")
+	}
+
+	r.Add(license.LicenseMIT)
+	l, err := license.NewFromDir(d, license.WithRegistry(r))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, l.Type)
+	}
+}
+
+func TestKnownLicenses_ReadOnly(t *testing.T) {
+	known := license.KnownLicenses
+	license.KnownLicenses = []string{"Acme-1.0"}
+	defer func() { license.KnownLicenses = known }()
+
+	if (&license.License{Type: "Acme-1.0"}).Recognized() {
+		t.Fatalf("expected changes to KnownLicenses to have no effect")
+	}
+	if s := license.NewRegistry().Snapshot(); len(s.Licenses()) != len(known) {
+		t.Fatalf("\nexpected: %d\ngot: %d", len(known), len(s.Licenses()))
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+
+	r := license.NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			r.AddFilePatterns("legal*")
+			r.RemoveFilePatterns("legal*")
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := license.NewFromDir(d, license.WithRegistry(r)); err != nil {
+				t.Errorf("err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
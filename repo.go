@@ -0,0 +1,340 @@
+package license
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidRepo is returned when a repository URL cannot be understood.
+	ErrInvalidRepo = errors.New("license: invalid repository URL")
+
+	// ErrCloneTooLarge is returned when a repository cloned grows past the
+	// limit set by WithMaxCloneSize.
+	ErrCloneTooLarge = errors.New("license: cloned repository too large")
+)
+
+// The interval at which the size of a repository being cloned is checked
+const cloneSizeInterval = 100 * time.Millisecond
+
+// The maximum size of a response of the API of a host, such as a listing of
+// a repository or a license file
+const maxResponseSize = 16 << 20
+
+// scpRepoRegexp matches the scp-like syntax of git, such as
+// git@github.com:owner/repo.git.
+var scpRepoRegexp = regexp.MustCompile(`^[\w.-]+@([\w.-]+):([^/].*)$`)
+
+// Base URLs of the APIs of the supported hosts
+const (
+	githubAPI    = "https://api.github.com"
+	gitlabAPI    = "https://gitlab.com/api/v4"
+	bitbucketAPI = "https://api.bitbucket.org/2.0"
+)
+
+// repoFetchers fetch the licenses of a repository, given its path on the host,
+// using the API of the host.
+var repoFetchers = map[string]func(ctx context.Context, o *options, repo string) ([]*License, error){
+	"github.com":    fetchGitHub,
+	"gitlab.com":    fetchGitLab,
+	"bitbucket.org": fetchBitbucket,
+}
+
+// NewFromRepo will fetch the license of a remote repository, and guess its
+// type locally. Repositories hosted on GitHub, GitLab and Bitbucket are looked
+// up through the API of the host, such as:
+//
+//	https://github.com/nfukasawa/go-license
+//	gitlab.com/group/subgroup/project
+//
+// Other repositories, or those the API of their host fails to look up, are
+// shallow cloned with git instead, which must then be installed. The URL of
+// the repository is recorded in the URL of the returned license.
+func NewFromRepo(ctx context.Context, repoURL string, opts ...Option) (*License, error) {
+	o := newOptions(opts)
+
+	host, repo, cloneURL, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var ls []*License
+	if fetch, ok := repoFetchers[host]; ok {
+		ls, err = fetch(ctx, o, repo)
+		if err != nil && err != ErrNoLicenseFile && err != ErrUnrecognizedLicense && ctx.Err() == nil {
+			if cloned, cloneErr := cloneLicenses(ctx, cloneURL, o); cloneErr == nil {
+				ls, err = cloned, nil
+			}
+		}
+	} else {
+		ls, err = cloneLicenses(ctx, cloneURL, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range ls {
+		if !undetermined(l.Type) {
+			l.URL = repoURL
+			return l, nil
+		}
+	}
+	return nil, ErrUnrecognizedLicense
+}
+
+// parseRepoURL splits a repository URL into its host and the path of the
+// repository on the host, without any ".git" suffix, and returns the URL to
+// clone it from. URLs without a scheme default to https.
+func parseRepoURL(repoURL string) (host, repo, cloneURL string, err error) {
+	if m := scpRepoRegexp.FindStringSubmatch(repoURL); m != nil {
+		return strings.ToLower(m[1]), strings.TrimSuffix(strings.Trim(m[2], "/"), ".git"), repoURL, nil
+	}
+	cloneURL = repoURL
+	if !strings.Contains(cloneURL, "://") {
+		cloneURL = "https://" + cloneURL
+	}
+	u, err := url.Parse(cloneURL)
+	if err != nil || u.Host == "" && u.Scheme != "file" {
+		return "", "", "", ErrInvalidRepo
+	}
+	repo = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if repo == "" {
+		return "", "", "", ErrInvalidRepo
+	}
+	return strings.ToLower(u.Host), repo, cloneURL, nil
+}
+
+// getJSON fetches a URL and decodes its JSON response into v, as described by
+// get.
+func getJSON(ctx context.Context, c *http.Client, u string, v interface{}) error {
+	data, err := get(ctx, c, u)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// get fetches a URL. A response of 404 Not Found or 410 Gone is reported as
+// ErrNoLicenseFile, and a response larger than maxResponseSize fails.
+func get(ctx context.Context, c *http.Client, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone:
+		return nil, ErrNoLicenseFile
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("license: GET %s: %s", u, resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxResponseSize {
+		return nil, fmt.Errorf("license: GET %s: response larger than %d bytes", u, maxResponseSize)
+	}
+	return data, nil
+}
+
+// guessFromHost guesses the licenses of the files of a repository, which are
+// fetched from the API of its host with fetch. Unlike files which are not
+// found, a file which fails to be fetched fails the guess, so that
+// NewFromRepo clones the repository instead of reporting ErrNoLicenseFile.
+func guessFromHost(ctx context.Context, files []string, o *options, fetch func(name string) ([]byte, error)) ([]*License, error) {
+	var fetchErr error
+	src := &licenseSource{
+		join: func(name string) string { return name },
+		read: func(name string) ([]byte, error) {
+			data, err := fetch(name)
+			if err != nil && err != ErrNoLicenseFile && fetchErr == nil {
+				fetchErr = err
+			}
+			return data, err
+		},
+	}
+	ls, err := guessFromFiles(ctx, files, o, src)
+	if fetchErr != nil && ctx.Err() == nil {
+		return nil, fetchErr
+	}
+	return ls, err
+}
+
+// fetchGitHub uses the license API of GitHub, which finds the license file of
+// the default branch.
+func fetchGitHub(ctx context.Context, o *options, repo string) ([]*License, error) {
+	var resp struct {
+		Path     string `json:"path"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := getJSON(ctx, o.client, githubAPI+"/repos/"+repo+"/license", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Encoding != "base64" {
+		return nil, fmt.Errorf("license: unknown GitHub content encoding %q", resp.Encoding)
+	}
+	text, err := base64.StdEncoding.DecodeString(strings.Replace(resp.Content, "\n", "", -1))
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := guessFromSource(ctx, readerSource(bytes.NewReader(text), o), resp.Path, o)
+	if err != nil {
+		return nil, err
+	}
+	return []*License{l}, nil
+}
+
+// fetchGitLab lists the root of the default branch, and fetches the files with
+// well-established license file names.
+func fetchGitLab(ctx context.Context, o *options, repo string) ([]*License, error) {
+	project := gitlabAPI + "/projects/" + url.PathEscape(repo)
+	var tree []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := getJSON(ctx, o.client, project+"/repository/tree?per_page=100", &tree); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range tree {
+		if entry.Type == "blob" {
+			files = append(files, entry.Name)
+		}
+	}
+
+	return guessFromHost(ctx, files, o, func(name string) ([]byte, error) {
+		return get(ctx, o.client, project+"/repository/files/"+url.PathEscape(name)+"/raw?ref=HEAD")
+	})
+}
+
+// fetchBitbucket lists the root of the main branch, and fetches the files
+// with well-established license file names.
+func fetchBitbucket(ctx context.Context, o *options, repo string) ([]*License, error) {
+	var listing struct {
+		Values []struct {
+			Path  string `json:"path"`
+			Type  string `json:"type"`
+			Links struct {
+				Self struct {
+					Href string `json:"href"`
+				} `json:"self"`
+			} `json:"links"`
+		} `json:"values"`
+	}
+	if err := getJSON(ctx, o.client, bitbucketAPI+"/repositories/"+repo+"/src/HEAD/?pagelen=100", &listing); err != nil {
+		return nil, err
+	}
+	links := make(map[string]string)
+	var files []string
+	for _, entry := range listing.Values {
+		if entry.Type == "commit_file" {
+			files = append(files, entry.Path)
+			links[entry.Path] = entry.Links.Self.Href
+		}
+	}
+
+	return guessFromHost(ctx, files, o, func(name string) ([]byte, error) {
+		return get(ctx, o.client, links[name])
+	})
+}
+
+// cloneLicenses shallow clones a repository with git into a temporary
+// directory, and searches its root for license files.
+func cloneLicenses(ctx context.Context, repoURL string, o *options) ([]*License, error) {
+	dir, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	cloneCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tooLarge := make(chan bool, 1)
+	if o.maxClone > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go watchCloneSize(dir, o.maxClone, done, func() {
+			tooLarge <- true
+			cancel()
+		})
+	}
+
+	cmd := exec.CommandContext(cloneCtx, "git", "clone", "--depth", "1", "--quiet", "--", repoURL, dir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		select {
+		case <-tooLarge:
+			return nil, ErrCloneTooLarge
+		default:
+		}
+		return nil, fmt.Errorf("license: git clone %s: %v: %s", repoURL, err, strings.TrimSpace(string(out)))
+	}
+	if o.maxClone > 0 && dirSize(dir) > o.maxClone {
+		return nil, ErrCloneTooLarge
+	}
+
+	ls, err := guessFromDir(ctx, dir, o)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range ls {
+		l.File = filepath.Base(l.File)
+	}
+	return ls, nil
+}
+
+// watchCloneSize calls abort once if the size of the files of a directory
+// grows past limit bytes, until done is closed.
+func watchCloneSize(dir string, limit int64, done <-chan struct{}, abort func()) {
+	t := time.NewTicker(cloneSizeInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+		}
+		if dirSize(dir) > limit {
+			abort()
+			return
+		}
+	}
+}
+
+// dirSize returns the total size of the regular files of a directory and its
+// subdirectories, skipping those which cannot be read.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,178 @@
+package license_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// hostTransport sends every request to a test server, whatever its host.
+type hostTransport struct {
+	server *url.URL
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.server.Scheme
+	req.URL.Host = t.server.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// zeros reads an endless stream of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func newRepoServer(t *testing.T) (*httptest.Server, *http.Client) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	apache, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseApache20))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/repos/owner/repo/license":
+			json.NewEncoder(w).Encode(map[string]string{
+				"path":     "LICENSE.md",
+				"content":  base64.StdEncoding.EncodeToString(mit),
+				"encoding": "base64",
+			})
+		case "/api/v4/projects/group%2Fsub%2Fproject/repository/tree?per_page=100":
+			w.Write([]byte(`[{"name":"README.md","type":"blob"},{"name":"COPYING","type":"blob"},{"name":"LICENSE","type":"tree"}]`))
+		case "/api/v4/projects/group%2Fsub%2Fproject/repository/files/COPYING/raw?ref=HEAD":
+			w.Write(apache)
+		case "/2.0/repositories/workspace/repo/src/HEAD/?pagelen=100":
+			w.Write([]byte(`{"values":[{"path":"LICENSE.txt","type":"commit_file",` +
+				`"links":{"self":{"href":"https://api.bitbucket.org/2.0/repositories/workspace/repo/src/abc123/LICENSE.txt"}}}]}`))
+		case "/2.0/repositories/workspace/repo/src/abc123/LICENSE.txt":
+			w.Write(mit)
+		case "/api/v4/projects/group%2Fbroken/repository/tree?per_page=100":
+			w.Write([]byte(`[{"name":"LICENSE","type":"blob"}]`))
+		case "/api/v4/projects/group%2Fbroken/repository/files/LICENSE/raw?ref=HEAD":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case "/2.0/repositories/workspace/huge/src/HEAD/?pagelen=100":
+			w.Write([]byte(`{"values":[{"path":"LICENSE","type":"commit_file",` +
+				`"links":{"self":{"href":"https://api.bitbucket.org/2.0/repositories/workspace/huge/src/abc123/LICENSE"}}}]}`))
+		case "/2.0/repositories/workspace/huge/src/abc123/LICENSE":
+			io.CopyN(w, zeros{}, 17<<20)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return server, &http.Client{Transport: &hostTransport{u}}
+}
+
+func TestNewFromRepo(t *testing.T) {
+	server, client := newRepoServer(t)
+	defer server.Close()
+
+	cases := []struct {
+		url, ltype, file string
+	}{
+		{"https://github.com/owner/repo", license.LicenseMIT, "LICENSE.md"},
+		{"github.com/owner/repo.git", license.LicenseMIT, "LICENSE.md"},
+		{"git@github.com:owner/repo.git", license.LicenseMIT, "LICENSE.md"},
+		{"https://gitlab.com/group/sub/project", license.LicenseApache20, "COPYING"},
+		{"https://bitbucket.org/workspace/repo", license.LicenseMIT, "LICENSE.txt"},
+	}
+	for _, c := range cases {
+		l, err := license.NewFromRepo(context.Background(), c.url, license.WithHTTPClient(client))
+		if err != nil {
+			t.Fatalf("err: %s: %s", c.url, err)
+		}
+		if l.Type != c.ltype || l.File != c.file || l.URL != c.url {
+			t.Fatalf("unexpected license for %s: %s %s %s", c.url, l.Type, l.File, l.URL)
+		}
+	}
+
+	// The license of the GitHub API is guessed with the options given
+	_, err := license.NewFromRepo(context.Background(), "https://github.com/owner/repo",
+		license.WithHTTPClient(client), license.WithMaxFileSize(100))
+	if err != license.ErrFileBudget {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrFileBudget, err)
+	}
+
+	// Repositories without a license fail properly
+	_, err = license.NewFromRepo(context.Background(), "https://github.com/owner/missing", license.WithHTTPClient(client))
+	if err != license.ErrNoLicenseFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoLicenseFile, err)
+	}
+
+	// Files which fail to be fetched are reported, once cloning fails too
+	t.Setenv("PATH", t.TempDir())
+	for _, u := range []string{"https://gitlab.com/group/broken", "https://bitbucket.org/workspace/huge"} {
+		_, err := license.NewFromRepo(context.Background(), u, license.WithHTTPClient(client))
+		if err == nil || err == license.ErrNoLicenseFile || !strings.Contains(err.Error(), "license: GET ") {
+			t.Fatalf("%s: unexpected error: %v", u, err)
+		}
+	}
+
+	// Invalid URLs fail properly
+	if _, err := license.NewFromRepo(context.Background(), "https://github.com/"); err != license.ErrInvalidRepo {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrInvalidRepo, err)
+	}
+}
+
+func TestNewFromRepo_Clone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	copyFixture(t, license.LicenseISC, filepath.Join(d, "LICENSE"))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "LICENSE"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "license"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = d
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("err: %s: %s", err, out)
+		}
+	}
+
+	u := "file://" + filepath.ToSlash(d)
+	l, err := license.NewFromRepo(context.Background(), u)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseISC || l.File != "LICENSE" || l.URL != u {
+		t.Fatalf("unexpected license: %s %s %s", l.Type, l.File, l.URL)
+	}
+
+	if _, err := license.NewFromRepo(context.Background(), u, license.WithMaxCloneSize(100)); err != license.ErrCloneTooLarge {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrCloneTooLarge, err)
+	}
+}
@@ -0,0 +1,190 @@
+package license
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Report aggregates the licenses found across files and directories, and
+// marshals to JSON or YAML with stable field names, as printed by the license
+// command with -format json or yaml.
+type Report struct {
+	Results []*Result `json:"results" yaml:"results"`
+}
+
+// Result is a license found in a directory. A result without a file records a
+// directory where no license was found.
+type Result struct {
+	Dir        string    `json:"dir" yaml:"dir"`                                   // The directory which was scanned
+	File       string    `json:"file,omitempty" yaml:"file,omitempty"`             // The license file, if any
+	Type       string    `json:"type,omitempty" yaml:"type,omitempty"`             // The license type, if any
+	Language   string    `json:"language,omitempty" yaml:"language,omitempty"`     // The language of the license, if it is a translation
+	Rider      string    `json:"rider,omitempty" yaml:"rider,omitempty"`           // Restrictive terms appended to the license, if any
+	Reference  bool      `json:"reference,omitempty" yaml:"reference,omitempty"`   // Whether the license file only links to the license
+	ThirdParty bool      `json:"thirdParty,omitempty" yaml:"thirdParty,omitempty"` // Whether the directory is within a vendored tree, as marked by NewReport
+	Decision   *Decision `json:"decision,omitempty" yaml:"decision,omitempty"`     // The policy decision, once evaluated
+}
+
+// NewReport creates a report from licenses keyed by directory, such as those
+// returned by NewFromDirRecursive. Results are ordered by directory. Those of
+// directories within vendored trees, such as vendor, node_modules, third_party
+// and external, are marked as third-party, with the licenses of other
+// projects.
+func NewReport(found map[string][]*License) *Report {
+	dirs := make([]string, 0, len(found))
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	r := new(Report)
+	for _, dir := range dirs {
+		r.Add(dir, found[dir]...)
+	}
+	r.markThirdParty(commonDir(dirs))
+	return r
+}
+
+// markThirdParty marks the results of directories within vendored trees,
+// relative to the project root dir is in, as third-party. A dir within a
+// vendored tree itself, such as the third_party directory of a project without
+// a license of its own, stands for the project the tree is bundled with.
+func (r *Report) markThirdParty(dir string) {
+	root := projectRoot(dir)
+	for _, result := range r.Results {
+		rel, err := filepath.Rel(root, result.Dir)
+		if err != nil {
+			rel = result.Dir
+		}
+		result.ThirdParty = isVendored(rel)
+	}
+}
+
+// Split separates the results of the project itself from those of the
+// third-party code bundled with it, so that the licenses of dependencies are
+// not mistaken for that of the project.
+func (r *Report) Split() (project, thirdParty *Report) {
+	project, thirdParty = new(Report), new(Report)
+	for _, result := range r.Results {
+		if result.ThirdParty {
+			thirdParty.Results = append(thirdParty.Results, result)
+		} else {
+			project.Results = append(project.Results, result)
+		}
+	}
+	return project, thirdParty
+}
+
+// Add appends a result for each license found in the directory, or a result
+// without a license if there is none.
+func (r *Report) Add(dir string, licenses ...*License) {
+	if len(licenses) == 0 {
+		r.Results = append(r.Results, &Result{Dir: dir})
+		return
+	}
+	for _, l := range licenses {
+		r.Results = append(r.Results, &Result{Dir: dir, File: l.File, Type: l.Type, Language: l.Language, Rider: l.Rider, Reference: l.Reference})
+	}
+}
+
+// Licenses returns the licenses found, in the order of the results.
+func (r *Report) Licenses() []*License {
+	var licenses []*License
+	for _, result := range r.Results {
+		if result.Type != "" {
+			licenses = append(licenses, &License{Type: result.Type, File: result.File, Language: result.Language, Rider: result.Rider, Reference: result.Reference})
+		}
+	}
+	return licenses
+}
+
+// Evaluate records the decision of the policy for every result, and returns
+// the worst verdict. A report without results is allowed.
+func (r *Report) Evaluate(p *Policy) Verdict {
+	worst := VerdictAllow
+	for _, result := range r.Results {
+		var l *License
+		if result.Type != "" {
+			l = &License{Type: result.Type, Rider: result.Rider}
+		}
+		d := p.Evaluate(l)
+		result.Decision = &d
+		if d.Verdict.rank() < worst.rank() {
+			worst = d.Verdict
+		}
+	}
+	return worst
+}
+
+// ReportDiff is the difference between the results of two reports, such as
+// those of two commits or two snapshots of the dependencies of a project.
+type ReportDiff struct {
+	Added   []*Result       `json:"added" yaml:"added"`     // The results only in the new report
+	Removed []*Result       `json:"removed" yaml:"removed"` // The results only in the old report
+	Changed []*ResultChange `json:"changed" yaml:"changed"` // The results whose license changed
+}
+
+// ResultChange is a result whose license differs between two reports.
+type ResultChange struct {
+	Old *Result `json:"old" yaml:"old"` // The result in the old report
+	New *Result `json:"new" yaml:"new"` // The result in the new report
+}
+
+// CompareReports compares the results of two reports, matching them by their
+// directory and file, and reports those only in one of them, and those whose
+// license type, language or rider differs. Policy decisions are not compared.
+// Added and changed results are in the order of the new report, and removed
+// ones in the order of the old one. A nil report has no results.
+func CompareReports(old, new *Report) *ReportDiff {
+	oldResults := make(map[resultKey]*Result)
+	if old != nil {
+		for _, result := range old.Results {
+			if _, ok := oldResults[result.key()]; !ok {
+				oldResults[result.key()] = result
+			}
+		}
+	}
+
+	d := &ReportDiff{}
+	newResults := make(map[resultKey]bool)
+	if new != nil {
+		for _, result := range new.Results {
+			key := result.key()
+			if newResults[key] {
+				continue
+			}
+			newResults[key] = true
+			prev, ok := oldResults[key]
+			switch {
+			case !ok:
+				d.Added = append(d.Added, result)
+			case prev.Type != result.Type || prev.Language != result.Language || prev.Rider != result.Rider ||
+				prev.Reference != result.Reference:
+				d.Changed = append(d.Changed, &ResultChange{Old: prev, New: result})
+			}
+		}
+	}
+	if old != nil {
+		for _, result := range old.Results {
+			key := result.key()
+			if !newResults[key] && oldResults[key] == result {
+				d.Removed = append(d.Removed, result)
+			}
+		}
+	}
+	return d
+}
+
+// Empty determines if the reports compared have the same results.
+func (d *ReportDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// resultKey identifies a result across reports.
+type resultKey struct {
+	dir, file string
+}
+
+func (r *Result) key() resultKey {
+	return resultKey{dir: r.Dir, file: r.File}
+}
@@ -0,0 +1,193 @@
+package license_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/internal/yaml"
+)
+
+func TestNewReport(t *testing.T) {
+	r := license.NewReport(map[string][]*license.License{
+		"b": {{Type: license.LicenseMIT, File: "b/LICENSE", Text: "text"}},
+		"a": {
+			{Type: license.LicenseApache20, File: "a/LICENSE"},
+			{Type: license.LicenseMIT, File: "a/COPYING"},
+		},
+	})
+	r.Add("c")
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `{"results":[` +
+		`{"dir":"a","file":"a/LICENSE","type":"Apache-2.0"},` +
+		`{"dir":"a","file":"a/COPYING","type":"MIT"},` +
+		`{"dir":"b","file":"b/LICENSE","type":"MIT"},` +
+		`{"dir":"c"}]}`
+	if string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+
+	if ls := r.Licenses(); len(ls) != 3 || ls[2].File != "b/LICENSE" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
+
+func TestReportSplit(t *testing.T) {
+	for name, found := range map[string]map[string][]*license.License{
+		"root license": {
+			"/src/p":                          {{Type: license.LicenseMIT, File: "/src/p/LICENSE"}},
+			"/src/p/cmd":                      {{Type: license.LicenseMIT, File: "/src/p/cmd/LICENSE"}},
+			"/src/p/third_party/zlib":         {{Type: license.LicenseZlib, File: "/src/p/third_party/zlib/LICENSE"}},
+			"/src/p/web/node_modules/leftpad": {{Type: license.LicenseISC, File: "/src/p/web/node_modules/leftpad/LICENSE"}},
+			"/src/p/External/boost":           {{Type: license.LicenseBSL10, File: "/src/p/External/boost/LICENSE_1_0.txt"}},
+		},
+		"no root license": {
+			"p/cmd":                  {{Type: license.LicenseMIT, File: "p/cmd/LICENSE"}},
+			"p/vendor/example.com/a": {{Type: license.LicenseZlib, File: "p/vendor/example.com/a/LICENSE"}},
+			"p/vendor/example.com/b": {{Type: license.LicenseISC, File: "p/vendor/example.com/b/LICENSE"}},
+			"p/vendor/example.com/c": {{Type: license.LicenseBSL10, File: "p/vendor/example.com/c/LICENSE"}},
+		},
+		"only third party": {
+			"third_party/a": {{Type: license.LicenseZlib, File: "third_party/a/LICENSE"}},
+			"third_party/b": {{Type: license.LicenseISC, File: "third_party/b/LICENSE"}},
+			"third_party/c": {{Type: license.LicenseBSL10, File: "third_party/c/LICENSE"}},
+		},
+	} {
+		project, thirdParty := license.NewReport(found).Split()
+		for _, result := range project.Results {
+			if result.ThirdParty || result.Type != license.LicenseMIT {
+				t.Fatalf("%s: unexpected project result: %v", name, result)
+			}
+		}
+		if len(thirdParty.Results) != 3 {
+			t.Fatalf("%s: unexpected third-party results: %v", name, thirdParty.Results)
+		}
+		for _, result := range thirdParty.Results {
+			if !result.ThirdParty || result.Type == license.LicenseMIT {
+				t.Fatalf("%s: unexpected third-party result: %v", name, result)
+			}
+		}
+	}
+}
+
+func TestReportSplit_VendoredOnly(t *testing.T) {
+	for name, found := range map[string]map[string][]*license.License{
+		"vendored siblings": {
+			"proj/vendor/github.com/x/y": {{Type: license.LicenseZlib, File: "proj/vendor/github.com/x/y/LICENSE"}},
+			"proj/vendor/github.com/x/z": {{Type: license.LicenseISC, File: "proj/vendor/github.com/x/z/LICENSE"}},
+		},
+		"single vendored directory": {
+			"proj/third_party/y": {{Type: license.LicenseZlib, File: "proj/third_party/y/LICENSE"}},
+		},
+	} {
+		project, thirdParty := license.NewReport(found).Split()
+		if len(project.Results) != 0 || len(thirdParty.Results) != len(found) {
+			t.Fatalf("%s: unexpected results: %v, %v", name, project.Results, thirdParty.Results)
+		}
+	}
+}
+
+func TestReportEvaluate(t *testing.T) {
+	p := &license.Policy{
+		Allow:   []string{license.LicenseMIT},
+		Deny:    []string{license.LicenseGPL30},
+		Default: license.VerdictReview,
+	}
+
+	r := new(license.Report)
+	r.Add("a", license.New(license.LicenseMIT, ""))
+	if v := r.Evaluate(p); v != license.VerdictAllow {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.VerdictAllow, v)
+	}
+
+	r.Add("b")
+	if v := r.Evaluate(p); v != license.VerdictReview {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.VerdictReview, v)
+	}
+
+	r.Add("c", license.New(license.LicenseGPL30, ""))
+	if v := r.Evaluate(p); v != license.VerdictDeny {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.VerdictDeny, v)
+	}
+
+	data, err := json.Marshal(r.Results[2].Decision)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := `{"verdict":"deny","reason":"GPL-3.0 is denied"}`; string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+}
+
+func TestReportMarshalYAML(t *testing.T) {
+	r := license.NewReport(map[string][]*license.License{
+		"a":             {{Type: license.LicenseMIT, File: "a/LICENSE", Rider: "Commons-Clause"}},
+		"a/vendor/x: y": {{Type: license.LicenseApache20, File: "a/vendor/x: y/COPYING", Reference: true}},
+		"a/empty":       nil,
+	})
+	r.Evaluate(&license.Policy{Allow: []string{license.LicenseMIT}, Default: license.VerdictReview})
+
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	got := new(license.Report)
+	if err := yaml.Unmarshal(data, got); err != nil {
+		t.Fatalf("err: %s\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Fatalf("\nexpected: %v\ngot: %v\n%s", r.Results, got.Results, data)
+	}
+}
+
+func TestLicenseMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(license.New(license.LicenseMIT, ""))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := `{"type":"MIT"}`; string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	old := license.NewReport(map[string][]*license.License{
+		".":   {{Type: license.LicenseMIT, File: "LICENSE"}},
+		"a":   {{Type: license.LicenseApache20, File: "a/LICENSE"}},
+		"b":   {{Type: license.LicenseISC, File: "b/LICENSE"}},
+		"old": {{Type: license.LicenseMIT, File: "old/LICENSE"}},
+	})
+	new := license.NewReport(map[string][]*license.License{
+		".":   {{Type: license.LicenseMIT, File: "LICENSE"}},
+		"a":   {{Type: license.LicenseApache20, File: "a/LICENSE", Rider: "No evil."}},
+		"b":   {{Type: license.LicenseGPL30, File: "b/LICENSE"}},
+		"new": {{Type: license.LicenseBSD3Clause, File: "new/LICENSE"}},
+	})
+	new.Add("empty")
+
+	d := license.CompareReports(old, new)
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `{"added":[{"dir":"new","file":"new/LICENSE","type":"BSD-3-Clause"},{"dir":"empty"}],` +
+		`"removed":[{"dir":"old","file":"old/LICENSE","type":"MIT"}],` +
+		`"changed":[` +
+		`{"old":{"dir":"a","file":"a/LICENSE","type":"Apache-2.0"},"new":{"dir":"a","file":"a/LICENSE","type":"Apache-2.0","rider":"No evil."}},` +
+		`{"old":{"dir":"b","file":"b/LICENSE","type":"ISC"},"new":{"dir":"b","file":"b/LICENSE","type":"GPL-3.0"}}]}`
+	if string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+
+	if d := license.CompareReports(old, old); !d.Empty() {
+		t.Fatalf("unexpected difference: %v", d)
+	}
+	if d := license.CompareReports(nil, old); len(d.Added) != 4 || len(d.Removed) != 0 {
+		t.Fatalf("unexpected difference: %v", d)
+	}
+}
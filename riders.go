@@ -0,0 +1,75 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The number of words at the end of a canonical license text which must be
+// found in a text for the words following them to be taken as a rider.
+const riderAnchorWords = 8
+
+// The minimum number of words of a rider, such as "no military use"
+const minRiderWords = 3
+
+// Terms which restrict the use of a work, as riders do
+var riderRegexp = regexp.MustCompile(`\b(may not|shall not|must not|will not|cannot|can not|` +
+	`does not grant|is not permitted|are not permitted|not be used|no (military|commercial|government)|` +
+	`without limiting|additional (terms|conditions|restrictions)|not evil)\b|\b(prohibit|restrict|forbid)`)
+
+// findRider returns the rider appended to a license text, prepared as done by
+// prepareText: the text following the end of the canonical text of the
+// license, if it restricts the use of the work and is not a license of its
+// own. Licenses without a canonical text, or with an exception, which may be
+// appended as well, have no rider.
+func findRider(text, licenseType string) string {
+	if _, _, ok := withException(licenseType); ok {
+		return ""
+	}
+	canonical, err := CanonicalText(licenseType)
+	if err != nil {
+		return ""
+	}
+	anchor := normalizeWords(canonical, true)
+	if len(anchor) < riderAnchorWords {
+		return ""
+	}
+	anchor = anchor[len(anchor)-riderAnchorWords:]
+	for _, word := range anchor {
+		if word == replaceable {
+			return ""
+		}
+	}
+
+	// The words of the text, along with the lines they are on
+	lines := strings.Split(text, "\n")
+	var words []string
+	var wordLines []int
+	for i, line := range lines {
+		for _, word := range normalizeWords(line, false) {
+			words = append(words, word)
+			wordLines = append(wordLines, i)
+		}
+	}
+
+	end := -1
+	for i := len(words) - len(anchor); i >= 0; i-- {
+		if equalWords(words[i:i+len(anchor)], anchor) {
+			end = wordLines[i+len(anchor)-1]
+			break
+		}
+	}
+	if end < 0 {
+		return ""
+	}
+
+	rider := strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+	if len(normalizeWords(rider, false)) < minRiderWords ||
+		!riderRegexp.MatchString(collapseSpace(strings.ToLower(rider))) {
+		return ""
+	}
+	if (&License{Text: rider}).GuessType() == nil {
+		return ""
+	}
+	return rider
+}
@@ -0,0 +1,75 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseRiders(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	mit := string(data)
+	commonsClause := `"Commons Clause" License Condition v1.0
+
+The Software is provided to you by the Licensor under the License, as
+defined below, subject to the following condition.
+
+Without limiting other conditions in the License, the grant of rights
+under the License will not include, and the License does not grant to
+you, the right to Sell the Software.
+`
+
+	cases := []struct {
+		text     string
+		expected string
+		rider    string
+	}{
+		{mit, license.LicenseMIT, ""},
+		{mit + "\nThe Software may not be used for military purposes.\n",
+			license.LicenseMIT, "The Software may not be used for military purposes."},
+		{mit + "\n---\n\nAdditional terms: the Software shall not be used to\ntrain surveillance systems.\n",
+			license.LicenseMIT, "---\n\nAdditional terms: the Software shall not be used to\ntrain surveillance systems."},
+
+		// Text which does not restrict the license, or is a license of its
+		// own, is not a rider
+		{mit + "\nThe logo is a trademark of Example, Inc.\n", license.LicenseMIT, ""},
+		{mit + "\nPortions are licensed under the Apache License, Version 2.0 (the\n" +
+			"\"License\"); you may not use these files except in compliance with the\n" +
+			"License. You may obtain a copy of the License at\n\n" +
+			"    http://www.apache.org/licenses/LICENSE-2.0\n", license.LicenseMIT, ""},
+
+		// The Commons Clause is reported with the license it restricts
+		{commonsClause + "\n" + mit, license.LicenseMIT + " AND " + license.LicenseCommonsClause, ""},
+		{mit + "\n" + commonsClause, license.LicenseMIT + " AND " + license.LicenseCommonsClause, ""},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if l.Rider != c.rider {
+			t.Fatalf("\nexpected: %q\ngot: %q", c.rider, l.Rider)
+		}
+	}
+}
+
+func TestPolicyEvaluate_Rider(t *testing.T) {
+	p := &license.Policy{Allow: []string{"MIT"}, Deny: []string{"GPL-3.0"}}
+
+	l := &license.License{Type: license.LicenseMIT, Rider: "No military use."}
+	if d := p.Evaluate(l); d.Verdict != license.VerdictReview || d.Reason != "MIT has a rider" {
+		t.Fatalf("unexpected decision: %s (%s)", d.Verdict, d.Reason)
+	}
+	l = &license.License{Type: "GPL-3.0", Rider: "No military use."}
+	if d := p.Evaluate(l); d.Verdict != license.VerdictDeny {
+		t.Fatalf("unexpected verdict: %s", d.Verdict)
+	}
+}
@@ -0,0 +1,194 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/nfukasawa/go-license/internal/yaml"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// The highest risk score of a license
+const maxRisk = 100
+
+// RiskWeights configures the risk scores of licenses. The score of a license
+// is the weight of its category, as returned by Info, plus the weight of each
+// of its obligations, as returned by Obligations, and of its rider, if any.
+type RiskWeights struct {
+	Categories       map[Category]float64 `json:"categories" yaml:"categories"`             // The weights of the license categories, where the unknown category weighs unrecognized licenses
+	IncludeCopyright float64              `json:"includeCopyright" yaml:"includeCopyright"` // The weight of having to include the copyright notice
+	DiscloseSource   float64              `json:"discloseSource" yaml:"discloseSource"`     // The weight of having to disclose the source
+	StateChanges     float64              `json:"stateChanges" yaml:"stateChanges"`         // The weight of having to state changes
+	NetworkUse       float64              `json:"networkUse" yaml:"networkUse"`             // The weight of network use counting as distribution
+	SameLicense      float64              `json:"sameLicense" yaml:"sameLicense"`           // The weight of having to keep the same license
+	Rider            float64              `json:"rider" yaml:"rider"`                       // The weight of a rider appended to the license
+}
+
+// DefaultRiskWeights returns weights which score permissive licenses lowest
+// and unrecognized licenses highest, with copyleft licenses, and those whose
+// obligations extend to network use, in between.
+func DefaultRiskWeights() *RiskWeights {
+	return &RiskWeights{
+		Categories: map[Category]float64{
+			CategoryPublicDomain:    0,
+			CategoryPermissive:      10,
+			CategoryData:            20,
+			CategoryWeakCopyleft:    30,
+			CategoryStrongCopyleft:  50,
+			CategorySourceAvailable: 70,
+			CategoryProprietary:     90,
+			CategoryUnknown:         100,
+		},
+		StateChanges:   5,
+		DiscloseSource: 10,
+		SameLicense:    10,
+		NetworkUse:     20,
+		Rider:          20,
+	}
+}
+
+// LoadRiskWeights reads risk weights from a JSON or YAML file, such as:
+//
+//	categories:
+//	  permissive: 0
+//	  strong-copyleft: 80
+//	discloseSource: 10
+//	networkUse: 30
+//
+// The format is chosen by the file extension, and defaults to YAML.
+func LoadRiskWeights(path string) (*RiskWeights, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := new(RiskWeights)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, w)
+	} else {
+		err = yaml.Unmarshal(data, w)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("license: invalid risk weights %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Score returns the risk score of a license, from 0 to 100. The license type
+// may be an SPDX license expression, in which case a license chosen by OR
+// scores the lowest of its alternatives, and licenses combined by AND score
+// the highest of their parts. A missing license scores as an unrecognized
+// one.
+func (w *RiskWeights) Score(l *License) float64 {
+	if l == nil || l.Type == "" {
+		return capRisk(w.Categories[CategoryUnknown])
+	}
+
+	var score float64
+	if e, err := spdx.Parse(l.Type); err == nil {
+		score = w.score(e)
+	} else {
+		score = w.scoreLicense(l.Type)
+	}
+	if l.Rider != "" {
+		score += w.Rider
+	}
+	return capRisk(score)
+}
+
+func (w *RiskWeights) score(e spdx.Expr) float64 {
+	switch e := e.(type) {
+	case *spdx.And:
+		left, right := w.score(e.Left), w.score(e.Right)
+		if right > left {
+			return right
+		}
+		return left
+	case *spdx.Or:
+		left, right := w.score(e.Left), w.score(e.Right)
+		if right < left {
+			return right
+		}
+		return left
+	default:
+		return w.scoreLicense(e.String())
+	}
+}
+
+// scoreLicense scores a single license, which may have an exception.
+func (w *RiskWeights) scoreLicense(license string) float64 {
+	score := w.Categories[Info(license).Category]
+	o, ok := Obligations(license)
+	if !ok {
+		return score
+	}
+	for _, obligation := range []struct {
+		applies bool
+		weight  float64
+	}{
+		{o.IncludeCopyright, w.IncludeCopyright},
+		{o.DiscloseSource, w.DiscloseSource},
+		{o.StateChanges, w.StateChanges},
+		{o.NetworkUse, w.NetworkUse},
+		{o.SameLicense, w.SameLicense},
+	} {
+		if obligation.applies {
+			score += obligation.weight
+		}
+	}
+	return score
+}
+
+// capRisk limits a risk score to the range of scores.
+func capRisk(score float64) float64 {
+	switch {
+	case score > maxRisk:
+		return maxRisk
+	case score < 0:
+		return 0
+	}
+	return score
+}
+
+// ResultRisk is the risk score of a result of a report.
+type ResultRisk struct {
+	Dir   string  `json:"dir" yaml:"dir"`                       // The directory which was scanned
+	File  string  `json:"file,omitempty" yaml:"file,omitempty"` // The license file, if any
+	Type  string  `json:"type,omitempty" yaml:"type,omitempty"` // The license type, if any
+	Score float64 `json:"score" yaml:"score"`                   // The risk score of the license
+}
+
+// ProjectRisk aggregates the risk scores of the licenses of a project.
+type ProjectRisk struct {
+	Score   float64       `json:"score" yaml:"score"`     // The highest risk score of the results
+	Average float64       `json:"average" yaml:"average"` // The average risk score of the results
+	Results []*ResultRisk `json:"results" yaml:"results"` // The risk score of each result
+}
+
+// ScoreReport scores every result of a report, where directories without a
+// license score as unrecognized licenses, and aggregates them into the score
+// of the project: that of its riskiest license. A report without results
+// scores 0.
+func (w *RiskWeights) ScoreReport(r *Report) *ProjectRisk {
+	p := &ProjectRisk{Results: []*ResultRisk{}}
+	var total float64
+	for _, result := range r.Results {
+		var l *License
+		if result.Type != "" {
+			l = &License{Type: result.Type, Rider: result.Rider}
+		}
+		score := w.Score(l)
+		p.Results = append(p.Results, &ResultRisk{Dir: result.Dir, File: result.File, Type: result.Type, Score: score})
+		if score > p.Score {
+			p.Score = score
+		}
+		total += score
+	}
+	if len(p.Results) > 0 {
+		p.Average = total / float64(len(p.Results))
+	}
+	return p
+}
@@ -0,0 +1,110 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestRiskWeightsScore(t *testing.T) {
+	w := license.DefaultRiskWeights()
+
+	cases := []struct {
+		license  *license.License
+		expected float64
+	}{
+		{license.New("MIT", ""), 10},
+		{license.New("Apache-2.0", ""), 15},
+		{license.New("GPL-3.0-only", ""), 75},
+		{license.New("AGPL-3.0", ""), 95},
+		{license.New("SSPL-1.0", ""), 100},
+		{license.New("CC0-1.0", ""), 0},
+		{license.New(license.LicenseUnrecognized, ""), 100},
+		{license.New("MIT OR GPL-3.0", ""), 10},
+		{license.New("MIT AND GPL-3.0", ""), 75},
+		{license.New("Apache-2.0 AND "+license.LicenseCommonsClause, ""), 70},
+		{&license.License{Type: "MIT", Rider: "No military use."}, 30},
+		{nil, 100},
+	}
+	for _, c := range cases {
+		if score := w.Score(c.license); score != c.expected {
+			t.Fatalf("%+v\nexpected: %v\ngot: %v", c.license, c.expected, score)
+		}
+	}
+
+	// Weights are configurable
+	w.Categories[license.CategoryStrongCopyleft] = 0
+	w.NetworkUse = 0
+	if score := w.Score(license.New("AGPL-3.0", "")); score != 25 {
+		t.Fatalf("unexpected score: %v", score)
+	}
+}
+
+func TestRiskWeightsScoreReport(t *testing.T) {
+	report := license.NewReport(map[string][]*license.License{
+		"a": {license.New("MIT", "")},
+		"b": {license.New("GPL-2.0", "")},
+		"c": nil,
+		"d": {license.New("CC0-1.0", "")},
+	})
+
+	expected := &license.ProjectRisk{
+		Score:   100,
+		Average: 46.25,
+		Results: []*license.ResultRisk{
+			{Dir: "a", Type: "MIT", Score: 10},
+			{Dir: "b", Type: "GPL-2.0", Score: 75},
+			{Dir: "c", Score: 100},
+			{Dir: "d", Type: "CC0-1.0", Score: 0},
+		},
+	}
+	if risk := license.DefaultRiskWeights().ScoreReport(report); !reflect.DeepEqual(risk, expected) {
+		t.Fatalf("\nexpected: %+v\ngot: %+v", expected, risk)
+	}
+	if risk := license.DefaultRiskWeights().ScoreReport(&license.Report{}); risk.Score != 0 || risk.Average != 0 {
+		t.Fatalf("unexpected risk: %+v", risk)
+	}
+}
+
+func TestLoadRiskWeights(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	expected := &license.RiskWeights{
+		Categories: map[license.Category]float64{
+			license.CategoryPermissive:     0,
+			license.CategoryStrongCopyleft: 80,
+		},
+		DiscloseSource: 10,
+		NetworkUse:     30,
+	}
+	files := map[string]string{
+		"risk.yaml": `categories:
+  permissive: 0
+  strong-copyleft: 80
+discloseSource: 10
+networkUse: 30
+`,
+		"risk.json": `{"categories": {"permissive": 0, "strong-copyleft": 80}, "discloseSource": 10, "networkUse": 30}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(d, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		w, err := license.LoadRiskWeights(path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(w, expected) {
+			t.Fatalf("\nexpected: %#v\ngot: %#v", expected, w)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package license
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+//go:generate go run gen.go
+
+// rulesJSON is the table of rules GuessType detects the built-in licenses by.
+// Adding a license is a matter of adding a rule to it, and regenerating the
+// corpus of samples the rules are tested against.
+//
+//go:embed rules.json
+var rulesJSON []byte
+
+// Grants of the versions of a license, stated by the text preceding its title
+const (
+	grantGNU  = "gnu"  // The "-only" or "-or-later" variant, as chosen by gnuVariant
+	grantGFDL = "gfdl" // The variant of a GNU Free Documentation License, as chosen by gfdlVariant
+)
+
+// guessRule is a rule of the table GuessType detects the built-in licenses
+// by, in order.
+type guessRule struct {
+	Type     string       `json:"type"`               // The license type guessed
+	Match    [][]string   `json:"match"`              // Alternative lists of phrases, all phrases of one of which identify the license
+	Grant    string       `json:"grant,omitempty"`    // How the version granted is stated, if the license has variants by version
+	Version  string       `json:"version,omitempty"`  // The version of the license, for the grant
+	Variants []*guessRule `json:"variants,omitempty"` // Variants of the license, tried in order once the rule matches
+	Comment  string       `json:"comment,omitempty"`  // Why the rule is where it is in the table
+}
+
+var (
+	rulesOnce  sync.Once
+	guessRules []*guessRule
+)
+
+// loadRules parses the rule table on first use.
+func loadRules() []*guessRule {
+	rulesOnce.Do(func() {
+		var table struct {
+			Rules []*guessRule `json:"rules"`
+		}
+		if err := json.Unmarshal(rulesJSON, &table); err != nil {
+			panic("license: malformed rule table: " + err.Error())
+		}
+		guessRules = table.Rules
+	})
+	return guessRules
+}
+
+// guess returns the license type the rule detects in the normalized text, if
+// it matches, along with the phrases which identified it.
+func (r *guessRule) guess(comp string) (string, []string, bool) {
+	phrases, ok := r.matches(comp)
+	if !ok {
+		return "", nil, false
+	}
+	for _, v := range r.Variants {
+		if licenseType, more, ok := v.guess(comp); ok {
+			return licenseType, append(phrases, more...), true
+		}
+	}
+	switch r.Grant {
+	case grantGNU:
+		return gnuVariant(r.Type, r.Version, preamble(comp, phrases[0])), phrases, true
+	case grantGFDL:
+		return gfdlVariant(r.Type, r.Version, preamble(comp, phrases[0])), phrases, true
+	}
+	return r.Type, phrases, true
+}
+
+// matches returns the first of the alternative lists of phrases of the rule
+// which are all found in the normalized text, if any.
+func (r *guessRule) matches(comp string) ([]string, bool) {
+	for _, phrases := range r.Match {
+		if scanAll(comp, phrases) {
+			return append([]string(nil), phrases...), true
+		}
+	}
+	return nil, false
+}
+
+// scanAll determines if all of the phrases are found in the normalized text.
+func scanAll(comp string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if !scan(comp, phrase) {
+			return false
+		}
+	}
+	return true
+}
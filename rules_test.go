@@ -0,0 +1,80 @@
+package license_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestGuessType_LicenseTexts(t *testing.T) {
+	// The full texts of the fixtures and of the embedded SPDX and LicenseRef
+	// texts are guessed as the license they are named after, ignoring case as
+	// SPDX does, whether or not they are exact copies recognized by their hash.
+	// The texts of exceptions are not licenses on their own.
+	for _, pattern := range []string{
+		filepath.Join("fixtures", "licenses", "*"),
+		filepath.Join("spdx", "text", "*.txt"),
+		filepath.Join("text", "*.txt"),
+	} {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(files) == 0 {
+			t.Fatalf("no files match %s", pattern)
+		}
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			expected := strings.TrimSuffix(filepath.Base(file), ".txt")
+			if _, ok := spdx.GetException(expected); ok {
+				continue
+			}
+			for _, text := range []string{string(data), "Project: example\n\n" + string(data)} {
+				l := license.New("", text)
+				if err := l.GuessType(); err != nil {
+					t.Fatalf("%s: err: %s", file, err)
+				}
+				if !strings.EqualFold(l.Type, expected) {
+					t.Fatalf("%s:\nexpected: %s\ngot: %s", file, expected, l.Type)
+				}
+			}
+		}
+	}
+}
+
+func TestGuessType_Corpus(t *testing.T) {
+	// Each rule of the table is detected by its own phrases, and not shadowed
+	// by the rules preceding it
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "corpus.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var samples []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &samples); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(samples) == 0 {
+		t.Fatalf("no samples")
+	}
+
+	for _, s := range samples {
+		l := license.New("", s.Text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("%q: err: %s", s.Text, err)
+		}
+		if l.Type != s.Type && !strings.HasPrefix(l.Type, s.Type+" WITH ") {
+			t.Fatalf("%q:\nexpected: %s\ngot: %s", s.Text, s.Type, l.Type)
+		}
+	}
+}
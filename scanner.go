@@ -0,0 +1,105 @@
+package license
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Scanner finds license files and guesses their types with a configuration of
+// its own, set by the options given to NewScanner, unlike the package
+// functions, which fall back on package-level defaults such as
+// DefaultLicenseFiles. Scanners with different configurations may be used
+// concurrently.
+type Scanner struct {
+	o *options
+}
+
+// NewScanner creates a Scanner. The defaults which are not overridden by
+// options, such as DefaultLicenseFiles and DefaultSkipDirs, are copied, so
+// that changing them later does not affect the Scanner. The licenses given by
+// WithLicenses replace those registered by RegisterLicenseDefinition, which
+// the Scanner does not recognize, and must be valid, as for
+// RegisterLicenseDefinition.
+func NewScanner(opts ...Option) (*Scanner, error) {
+	o := newOptions(opts)
+	if o.err != nil {
+		return nil, o.err
+	}
+	o.files = append([]string(nil), o.licenseFiles()...)
+	o.skipDirs = append([]string(nil), o.skipDirs...)
+	o.isolated = true
+
+	if _, err := complileLicensePatters(o.files); err != nil {
+		return nil, err
+	}
+	return &Scanner{o: o}, nil
+}
+
+// GuessType guesses the type of the license as done by License.GuessType,
+// recognizing the detectors and licenses of the Scanner first, in place of the
+// registered licenses, and rejecting guesses below its threshold, other than
+// translations, as strictly as set by WithStrictness. Guesses are cached in
+// the cache set by WithGuessCache, if any, and the license types removed from
+// the registry set by WithRegistry, if any, are not recognized.
+func (s *Scanner) GuessType(l *License) error {
+	return s.o.guesser()(l)
+}
+
+// Recognized determines if the license is recognized, as done by
+// License.Recognized, but with the license types of the registry set by
+// WithRegistry, if any.
+func (s *Scanner) Recognized(l *License) bool {
+	return s.o.snapshot().Recognized(l.Type)
+}
+
+// FromReader reads license text from r until EOF, and guesses its type, as
+// done by NewFromReader.
+func (s *Scanner) FromReader(r io.Reader) (*License, error) {
+	return guessFromSource(s.o.ctx, readerSource(r, s.o), "", s.o)
+}
+
+// FromFile loads a license from a file, and guesses its type, as done by
+// NewFromFile.
+func (s *Scanner) FromFile(name string) (*License, error) {
+	return guessFromFile(s.o.ctx, name, s.o)
+}
+
+// FromDir searches a directory for license files, and returns the first whose
+// type is guessed, as done by NewFromDirCtx.
+func (s *Scanner) FromDir(ctx context.Context, dir string) (*License, error) {
+	return s.o.firstRecognized(scanDir(ctx, dir, s.o))
+}
+
+// LicensesFromDir searches a directory for license files, and guesses the
+// type of each, as done by NewLicensesFromDirCtx.
+func (s *Scanner) LicensesFromDir(ctx context.Context, dir string) ([]*License, error) {
+	return scanDir(ctx, dir, s.o)
+}
+
+// FromDirRecursive searches a directory and its subdirectories for license
+// files, and guesses the type of each, as done by NewFromDirRecursiveCtx.
+func (s *Scanner) FromDirRecursive(ctx context.Context, dir string) (map[string][]*License, error) {
+	return scanDirRecursive(ctx, dir, s.o)
+}
+
+// Dirs returns the directories FromDirRecursive searches for license files:
+// dir, and those of its subdirectories which are not skipped, such as by
+// WithSkipDirs, WithMaxDepth or ignore rules. The order of the directories is
+// unspecified.
+func (s *Scanner) Dirs(ctx context.Context, dir string) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		dirs []string
+	)
+	_, err := walkDirRecursive(ctx, dir, s.o, func(dir string) ([]*License, error) {
+		mu.Lock()
+		dirs = append(dirs, dir)
+		mu.Unlock()
+		return nil, ErrNoLicenseFile
+	})
+	if err != nil && err != ErrNoLicenseFile {
+		return nil, err
+	}
+	return dirs, nil
+}
@@ -0,0 +1,152 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestScanner(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"LICENSE":                {Data: mit},
+		"lib/COPYING":            {Data: []byte("Acme internal terms")},
+		"lib/nested/deep/LEGAL":  {Data: mit},
+		"lib/nested/LICENSE.txt": {Data: mit},
+	}
+
+	s, err := license.NewScanner(
+		license.WithFS(fsys),
+		license.WithFilePatterns("license*", "copying*"),
+		license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Acme", Patterns: []string{"acme internal terms"}}),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l, err := s.FromDir(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || l.File != "LICENSE" {
+		t.Fatalf("unexpected license: %s (%s)", l.Type, l.File)
+	}
+
+	found, err := s.FromDirRecursive(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(found) != 3 || found["lib"][0].Type != "LicenseRef-Acme" || found["lib/nested"][0].File != "lib/nested/LICENSE.txt" {
+		t.Fatalf("unexpected results: %v", found)
+	}
+
+	// The licenses of a Scanner are not registered for other callers
+	if _, err := license.NewFromFS(fsys, "lib"); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected ErrUnrecognizedLicense, got: %v", err)
+	}
+
+	if _, err := license.NewScanner(license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Empty"})); err != license.ErrInvalidDefinition {
+		t.Fatalf("expected ErrInvalidDefinition, got: %v", err)
+	}
+}
+
+func TestScanner_RegisteredLicenses(t *testing.T) {
+	if err := license.RegisterLicense("LicenseRef-Registered", []string{"terms registered for every caller"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := "These are the terms registered for every caller."
+	if l := license.New("", text); l.GuessType() != nil || l.Type != "LicenseRef-Registered" {
+		t.Fatalf("unexpected guess: %s", l.Type)
+	}
+
+	s, err := license.NewScanner(license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-Own", Patterns: []string{"own terms"}}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := s.GuessType(license.New("", text)); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnrecognizedLicense, err)
+	}
+	if l := license.New("", "These are our own terms."); s.GuessType(l) != nil || l.Type != "LicenseRef-Own" {
+		t.Fatalf("unexpected guess: %s", l.Type)
+	}
+}
+
+func TestScanner_Concurrent(t *testing.T) {
+	a, err := license.NewScanner(license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-A", Patterns: []string{"shared terms"}}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	b, err := license.NewScanner(license.WithLicenses(&license.LicenseDefinition{ID: "LicenseRef-B", Patterns: []string{"shared terms"}}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for s, expected := range map[*license.Scanner]string{a: "LicenseRef-A", b: "LicenseRef-B"} {
+			wg.Add(1)
+			go func(s *license.Scanner, expected string) {
+				defer wg.Done()
+				l := license.New("", "Shared terms")
+				if err := s.GuessType(l); err != nil || l.Type != expected {
+					t.Errorf("\nexpected: %s\ngot: %s (%v)", expected, l.Type, err)
+				}
+			}(s, expected)
+		}
+	}
+	wg.Wait()
+}
+
+func TestScanner_Threshold(t *testing.T) {
+	s, err := license.NewScanner(license.WithThreshold(0.8))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	l, err := s.FromFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT {
+		t.Fatalf("\nexpected: %s\ngot: %s", license.LicenseMIT, l.Type)
+	}
+
+	// A mere mention of the license falls short of the threshold
+	l = license.New("", "Permission is hereby granted, free of charge, to any person obtaining a copy of this software")
+	if err := s.GuessType(l); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("expected ErrUnrecognizedLicense, got: %v", err)
+	}
+}
+
+func TestScanner_Dirs(t *testing.T) {
+	d := t.TempDir()
+	for _, dir := range []string{"src/pkg", "vendor/x", "testdata", ".git"} {
+		if err := os.MkdirAll(filepath.Join(d, dir), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	s, err := license.NewScanner(license.WithIgnorePatterns("testdata/"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	dirs, err := s.Dirs(context.Background(), d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	sort.Strings(dirs)
+	expected := []string{d, filepath.Join(d, "src"), filepath.Join(d, "src", "pkg")}
+	if !reflect.DeepEqual(dirs, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, dirs)
+	}
+}
@@ -0,0 +1,87 @@
+package license
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Scope is the subtree governed by the license files of a directory: the
+// directory and its subdirectories, but for those with license files of their
+// own, which govern their subtrees instead.
+type Scope struct {
+	Dir      string    `json:"dir" yaml:"dir"`                               // The directory of the license files
+	Results  []*Result `json:"results" yaml:"results"`                       // The results of the license files
+	Excludes []string  `json:"excludes,omitempty" yaml:"excludes,omitempty"` // The top-most subdirectories with license files of their own
+}
+
+// Scopes returns the subtree each directory with license files governs, by
+// the nearest-ancestor rule, in the order of the directories. Directories
+// with a license file whose type was not recognized govern their subtrees
+// too, since the terms of a project are those of its nearest license file,
+// whatever they are.
+func (r *Report) Scopes() []*Scope {
+	byDir := r.scopes()
+	scopes := make([]*Scope, 0, len(byDir))
+	for _, s := range byDir {
+		scopes = append(scopes, s)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].Dir < scopes[j].Dir
+	})
+
+	for _, s := range scopes {
+		dir := filepath.Clean(s.Dir)
+		if parent := filepath.Dir(dir); parent != dir {
+			if p := nearestScope(byDir, parent); p != nil {
+				p.Excludes = append(p.Excludes, s.Dir)
+			}
+		}
+	}
+	return scopes
+}
+
+// EffectiveLicense returns the results of the license files which apply to a
+// file or directory, those of the nearest directory with license files which
+// it is in, or is, or nil if no license file applies to it. The path is
+// matched against the directories of the results as they are, so that it
+// must be relative to the same directory, or be absolute if they are.
+func (r *Report) EffectiveLicense(path string) []*Result {
+	if s := nearestScope(r.scopes(), filepath.Clean(path)); s != nil {
+		return s.Results
+	}
+	return nil
+}
+
+// scopes returns the scopes of the directories with license files, by their
+// cleaned paths, without their exclusions.
+func (r *Report) scopes() map[string]*Scope {
+	byDir := make(map[string]*Scope)
+	for _, result := range r.Results {
+		if result.File == "" {
+			continue
+		}
+		dir := filepath.Clean(result.Dir)
+		s, ok := byDir[dir]
+		if !ok {
+			s = &Scope{Dir: result.Dir}
+			byDir[dir] = s
+		}
+		s.Results = append(s.Results, result)
+	}
+	return byDir
+}
+
+// nearestScope returns the scope of the nearest directory which dir is in, or
+// is, if any.
+func nearestScope(scopes map[string]*Scope, dir string) *Scope {
+	for {
+		if s, ok := scopes[dir]; ok {
+			return s
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
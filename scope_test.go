@@ -0,0 +1,59 @@
+package license_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestReportScopes(t *testing.T) {
+	r := license.NewReport(map[string][]*license.License{
+		".":        {{Type: license.LicenseMIT, File: "LICENSE"}},
+		"a":        {{Type: license.LicenseApache20, File: "a/LICENSE"}},
+		"a/b/c":    {{Type: license.LicenseGPL30, File: "a/b/c/COPYING"}},
+		"vendor/x": {{Type: license.LicenseUnrecognized, File: "vendor/x/LICENSE"}},
+	})
+	r.Add("docs")
+
+	data, err := json.Marshal(r.Scopes())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `[{"dir":".","results":[{"dir":".","file":"LICENSE","type":"MIT"}],"excludes":["a","vendor/x"]},` +
+		`{"dir":"a","results":[{"dir":"a","file":"a/LICENSE","type":"Apache-2.0"}],"excludes":["a/b/c"]},` +
+		`{"dir":"a/b/c","results":[{"dir":"a/b/c","file":"a/b/c/COPYING","type":"GPL-3.0"}]},` +
+		`{"dir":"vendor/x","results":[{"dir":"vendor/x","file":"vendor/x/LICENSE","type":"Unrecognized","thirdParty":true}]}]`
+	if string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+
+	for path, expected := range map[string]string{
+		"main.go":             "LICENSE",
+		"docs/index.md":       "LICENSE",
+		"a":                   "a/LICENSE",
+		"a/b/main.go":         "a/LICENSE",
+		"./a/b/c/../c/lib.go": "a/b/c/COPYING",
+		"vendor/x/y/z.go":     "vendor/x/LICENSE",
+	} {
+		results := r.EffectiveLicense(path)
+		if len(results) != 1 || results[0].File != expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %v", path, expected, results)
+		}
+	}
+}
+
+func TestReportEffectiveLicense_NoLicense(t *testing.T) {
+	r := license.NewReport(map[string][]*license.License{
+		"/src/a": {
+			{Type: license.LicenseMIT, File: "/src/a/LICENSE-MIT"},
+			{Type: license.LicenseApache20, File: "/src/a/LICENSE-APACHE"},
+		},
+	})
+	if results := r.EffectiveLicense("/src/a/main.go"); len(results) != 2 {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	if results := r.EffectiveLicense("/src/b/main.go"); results != nil {
+		t.Fatalf("unexpected results: %v", results)
+	}
+}
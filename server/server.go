@@ -0,0 +1,216 @@
+// Package server exposes license detection over HTTP and JSON, for services
+// written in languages which cannot link go-license. License texts, or the
+// URLs of repositories, are posted to the /detect endpoint:
+//
+//	POST /detect
+//	{"text": "Permission is hereby granted, free of charge, ..."}
+//
+//	200 OK
+//	{"licenses": [{"type": "MIT", "confidence": 0.97, "expression": "MIT"}]}
+//
+// Errors are returned as {"error": "..."}, with the status 400 for invalid
+// requests, 403 for repositories whose detection is not allowed, 422 for
+// unrecognized licenses and 502 for repositories which cannot be fetched. The
+// "license serve" command runs the server.
+//
+// Since fetching a repository makes the server connect to its host, and
+// possibly clone it with git, repositories are only detected on the hosts
+// allowed by WithRepoHosts, over https, and within the limits of
+// WithCloneLimits. The server is only exposed over HTTP: a gRPC service would
+// add a dependency on gRPC to the module, and is left to a separate package.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// MaxRequestSize is the maximum size of a request body, in bytes.
+const MaxRequestSize = 1 << 20
+
+// Limits of the repositories detected, unless WithCloneLimits sets others
+const (
+	DefaultMaxCloneSize = 100 << 20
+	DefaultCloneTimeout = time.Minute
+)
+
+// Option configures the handler of the detection endpoints.
+type Option func(*config)
+
+// config is the configuration of a handler.
+type config struct {
+	hosts    map[string]bool  // The hosts of the repositories detected, by their lower case
+	opts     []license.Option // The options of the detection
+	maxClone int64            // The maximum size of a repository cloned, in bytes
+	timeout  time.Duration    // The maximum time spent detecting a repository
+}
+
+// WithRepoHosts allows the detection of the repositories of the given hosts,
+// such as "github.com", fetched over https. Repositories are not detected by
+// default.
+func WithRepoHosts(hosts ...string) Option {
+	return func(c *config) {
+		for _, host := range hosts {
+			c.hosts[strings.ToLower(host)] = true
+		}
+	}
+}
+
+// WithLicenseOptions sets the options used to detect licenses and fetch
+// repositories, as done by license.NewFromRepo.
+func WithLicenseOptions(opts ...license.Option) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, opts...)
+	}
+}
+
+// WithCloneLimits limits the size on disk of the repositories cloned to size
+// bytes, and the time spent detecting each repository to timeout. The
+// defaults are DefaultMaxCloneSize and DefaultCloneTimeout.
+func WithCloneLimits(size int64, timeout time.Duration) Option {
+	return func(c *config) {
+		c.maxClone, c.timeout = size, timeout
+	}
+}
+
+// DetectRequest is the body of a request to the /detect endpoint. Exactly one
+// of its fields must be set.
+type DetectRequest struct {
+	Text string `json:"text,omitempty"` // The text of a license
+	Repo string `json:"repo,omitempty"` // The URL of a repository, as accepted by license.NewFromRepo
+}
+
+// DetectResponse is the body of a successful response of the /detect
+// endpoint.
+type DetectResponse struct {
+	Licenses []*Detection `json:"licenses"`
+}
+
+// Detection is a license detected by the server.
+type Detection struct {
+	Type       string  `json:"type"`                 // The license type
+	Confidence float64 `json:"confidence"`           // The confidence of the guess, as scored by License.GuessTypeWithConfidence
+	Expression string  `json:"expression,omitempty"` // The SPDX expression of the license, if it has one
+	File       string  `json:"file,omitempty"`       // The license file of a repository, if known
+	URL        string  `json:"url,omitempty"`        // The URL of a repository
+}
+
+// errorResponse is the body of a failed response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+var (
+	// errInvalidRequest is returned for requests which set neither or both of
+	// their fields.
+	errInvalidRequest = errors.New("server: exactly one of text and repo must be set")
+
+	// errRepoNotAllowed is returned for repositories which are not fetched
+	// over https from a host allowed by WithRepoHosts.
+	errRepoNotAllowed = errors.New("server: repository not allowed")
+)
+
+// NewHandler creates the handler of the detection endpoints.
+func NewHandler(opts ...Option) http.Handler {
+	c := &config{hosts: make(map[string]bool), maxClone: DefaultMaxCloneSize, timeout: DefaultCloneTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/detect", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, errors.New("server: method not allowed"))
+			return
+		}
+		var req DetectRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if (req.Text == "") == (req.Repo == "") {
+			writeError(w, http.StatusBadRequest, errInvalidRequest)
+			return
+		}
+
+		d, err := c.detect(r.Context(), &req)
+		switch {
+		case err == license.ErrUnrecognizedLicense || err == license.ErrNoLicenseFile:
+			writeError(w, http.StatusUnprocessableEntity, err)
+		case err == license.ErrInvalidRepo:
+			writeError(w, http.StatusBadRequest, err)
+		case err == errRepoNotAllowed:
+			writeError(w, http.StatusForbidden, err)
+		case err != nil:
+			writeError(w, http.StatusBadGateway, err)
+		default:
+			writeJSON(w, http.StatusOK, &DetectResponse{Licenses: []*Detection{d}})
+		}
+	})
+	return mux
+}
+
+// detect detects the license of a request.
+func (c *config) detect(ctx context.Context, req *DetectRequest) (*Detection, error) {
+	var l *license.License
+	var err error
+	if req.Repo != "" {
+		if err := c.checkRepo(req.Repo); err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		opts := append([]license.Option{license.WithMaxCloneSize(c.maxClone)}, c.opts...)
+		l, err = license.NewFromRepo(ctx, req.Repo, opts...)
+	} else {
+		l = &license.License{Text: req.Text}
+		err = l.GuessType()
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &Detection{Type: l.Type, File: l.File, URL: l.URL}
+	if licenseType, confidence, err := l.GuessTypeWithConfidence(); err == nil && licenseType == l.Type {
+		d.Confidence = confidence
+	}
+	if e, err := spdx.Parse(l.Type); err == nil && spdx.Validate(e) == nil {
+		d.Expression = e.String()
+	}
+	return d, nil
+}
+
+// checkRepo determines if a repository may be detected: its URL must be an
+// https URL, or a URL without a scheme, of an allowed host, without a port or
+// user information. The scp-like syntax of git, which is fetched over ssh, is
+// not allowed.
+func (c *config) checkRepo(repo string) error {
+	if !strings.Contains(repo, "://") {
+		repo = "https://" + repo
+	}
+	u, err := url.Parse(repo)
+	switch {
+	case err != nil:
+		return license.ErrInvalidRepo
+	case u.Scheme != "https" || u.User != nil || !c.hosts[strings.ToLower(u.Host)]:
+		return errRepoNotAllowed
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
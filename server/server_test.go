@@ -0,0 +1,110 @@
+package server_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/server"
+)
+
+// hostTransport sends every request to the test server.
+type hostTransport struct {
+	server *url.URL
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.server.Scheme
+	req.URL.Host = t.server.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func post(t *testing.T, handler http.Handler, body string) (int, string) {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/detect", strings.NewReader(body)))
+	return w.Code, strings.TrimSpace(w.Body.String())
+}
+
+func TestNewHandler(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/license" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"path":     "LICENSE",
+			"content":  base64.StdEncoding.EncodeToString(mit),
+			"encoding": "base64",
+		})
+	}))
+	defer github.Close()
+	u, err := url.Parse(github.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &http.Client{Transport: &hostTransport{u}}
+	handler := server.NewHandler(server.WithRepoHosts("GitHub.com"), server.WithLicenseOptions(license.WithHTTPClient(client)))
+
+	text, err := json.Marshal(&server.DetectRequest{Text: string(mit)})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	code, body := post(t, handler, string(text))
+	expected := `{"licenses":[{"type":"MIT","confidence":1,"expression":"MIT"}]}`
+	if code != http.StatusOK || body != expected {
+		t.Fatalf("\nexpected: %s\ngot: %d %s", expected, code, body)
+	}
+
+	code, body = post(t, handler, `{"repo":"https://github.com/owner/repo"}`)
+	expected = `{"licenses":[{"type":"MIT","confidence":1,"expression":"MIT","file":"LICENSE","url":"https://github.com/owner/repo"}]}`
+	if code != http.StatusOK || body != expected {
+		t.Fatalf("\nexpected: %s\ngot: %d %s", expected, code, body)
+	}
+
+	for _, c := range []struct {
+		body string
+		code int
+	}{
+		{`{"text":"All rights reserved."}`, http.StatusUnprocessableEntity},
+		{`{}`, http.StatusBadRequest},
+		{`{"text":"x","repo":"y"}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+		{`{"repo":"not a repository"}`, http.StatusBadRequest},
+		{`{"repo":"https://github.com/owner/missing"}`, http.StatusUnprocessableEntity},
+		{`{"repo":"file:///root/module"}`, http.StatusForbidden},
+		{`{"repo":"http://github.com/owner/repo"}`, http.StatusForbidden},
+		{`{"repo":"https://github.com:8443/owner/repo"}`, http.StatusForbidden},
+		{`{"repo":"https://user@github.com/owner/repo"}`, http.StatusForbidden},
+		{`{"repo":"https://gitlab.com/owner/repo"}`, http.StatusForbidden},
+		{`{"repo":"git@github.com:owner/repo.git"}`, http.StatusBadRequest},
+	} {
+		code, body := post(t, handler, c.body)
+		if code != c.code || !strings.HasPrefix(body, `{"error":`) {
+			t.Fatalf("unexpected response to %s: %d %s", c.body, code, body)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/detect", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+
+	// Repositories are not detected unless their hosts are allowed
+	code, body = post(t, server.NewHandler(server.WithLicenseOptions(license.WithHTTPClient(client))), `{"repo":"https://github.com/owner/repo"}`)
+	if code != http.StatusForbidden {
+		t.Fatalf("unexpected response: %d %s", code, body)
+	}
+}
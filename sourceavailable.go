@@ -0,0 +1,20 @@
+package license
+
+// Source-available licenses, which publish the source code of a work but
+// restrict its use, such as for competing services or commercial purposes, so
+// they are not open source licenses. The Commons Clause and the PolyForm
+// licenses other than those on the SPDX license list are reported as
+// LicenseRefs.
+const (
+	LicenseBUSL11                   = "BUSL-1.1"
+	LicenseElastic20                = "Elastic-2.0"
+	LicenseSSPL10                   = "SSPL-1.0"
+	LicenseCommonsClause            = "LicenseRef-Commons-Clause"
+	LicensePolyFormNoncommercial100 = "PolyForm-Noncommercial-1.0.0"
+	LicensePolyFormSmallBusiness100 = "PolyForm-Small-Business-1.0.0"
+	LicensePolyFormFreeTrial100     = "LicenseRef-PolyForm-Free-Trial-1.0.0"
+	LicensePolyFormInternalUse100   = "LicenseRef-PolyForm-Internal-Use-1.0.0"
+	LicensePolyFormPerimeter100     = "LicenseRef-PolyForm-Perimeter-1.0.0"
+	LicensePolyFormShield100        = "LicenseRef-PolyForm-Shield-1.0.0"
+	LicensePolyFormStrict100        = "LicenseRef-PolyForm-Strict-1.0.0"
+)
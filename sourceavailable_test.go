@@ -0,0 +1,68 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseTypes_SourceAvailable(t *testing.T) {
+	polyForm := func(name string) string {
+		return "# PolyForm " + name + " License 1.0.0\n\n" +
+			"<https://polyformproject.org/licenses/>\n\n" +
+			"## Acceptance\n\n" +
+			"In order to get any license under these terms, you must agree\n" +
+			"to them as both strict obligations and conditions to all\n" +
+			"your licenses.\n"
+	}
+
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{`Business Source License 1.1
+
+Parameters
+
+Licensor:             Example, Inc.
+Licensed Work:        Example 1.0
+Change Date:          2030-01-01
+Change License:       Apache License, Version 2.0`, license.LicenseBUSL11},
+		{`Elastic License 2.0
+
+URL: https://www.elastic.co/licensing/elastic-license
+
+## Acceptance
+
+By using the software, you agree to all of the terms and conditions below.`, license.LicenseElastic20},
+		{`                     Server Side Public License
+                     VERSION 1, OCTOBER 16, 2018
+
+                    Copyright © 2018 MongoDB, Inc.`, license.LicenseSSPL10},
+		{polyForm("Noncommercial"), license.LicensePolyFormNoncommercial100},
+		{polyForm("Small Business"), license.LicensePolyFormSmallBusiness100},
+		{polyForm("Shield"), license.LicensePolyFormShield100},
+		{polyForm("Perimeter"), license.LicensePolyFormPerimeter100},
+		{polyForm("Internal Use"), license.LicensePolyFormInternalUse100},
+		{polyForm("Free Trial"), license.LicensePolyFormFreeTrial100},
+		{polyForm("Strict"), license.LicensePolyFormStrict100},
+
+		{`"Commons Clause" License Condition v1.0
+
+Without limiting other conditions in the License, the grant of rights
+under the License will not include, and the License does not grant to
+you, the right to Sell the Software.`, license.LicenseCommonsClause},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if l.Type != c.expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", c.expected, l.Type)
+		}
+		if info := license.Info(l.Type); info.Category != license.CategorySourceAvailable || info.OSIApproved {
+			t.Fatalf("%s: unexpected info: %+v", l.Type, info)
+		}
+	}
+}
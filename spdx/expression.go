@@ -0,0 +1,309 @@
+// Package spdx implements parsing, evaluation and normalization of SPDX
+// license expressions, such as "MIT OR (Apache-2.0 AND BSD-3-Clause)".
+package spdx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// Various errors
+	ErrInvalidExpression = errors.New("spdx: invalid license expression")
+	ErrUnknownLicense    = errors.New("spdx: unknown license identifier")
+	ErrUnknownException  = errors.New("spdx: unknown license exception identifier")
+)
+
+// Expr is a node of a parsed license expression.
+type Expr interface {
+	// String returns the normalized form of the expression.
+	String() string
+
+	// Satisfied reports whether the expression holds when each individual
+	// license, including any exception, is accepted or rejected by accept.
+	Satisfied(accept func(license string) bool) bool
+}
+
+// Identifier is a single license identifier, such as "MIT" or "GPL-2.0+".
+type Identifier struct {
+	ID      string // The license identifier
+	OrLater bool   // Whether the identifier carries the "+" operator
+}
+
+// With is a license identifier paired with a license exception, such as
+// "GPL-2.0 WITH Classpath-exception-2.0".
+type With struct {
+	License   *Identifier // The license the exception applies to
+	Exception string      // The exception identifier
+}
+
+// And is a conjunctive expression; both sides apply.
+type And struct {
+	Left, Right Expr
+}
+
+// Or is a disjunctive expression; either side may be chosen.
+type Or struct {
+	Left, Right Expr
+}
+
+func (e *Identifier) String() string {
+	if e.OrLater {
+		return e.ID + "+"
+	}
+	return e.ID
+}
+
+func (e *With) String() string {
+	return e.License.String() + " WITH " + e.Exception
+}
+
+func (e *And) String() string {
+	return group(e.Left, e) + " AND " + group(e.Right, e)
+}
+
+func (e *Or) String() string {
+	return e.Left.String() + " OR " + e.Right.String()
+}
+
+func (e *Identifier) Satisfied(accept func(string) bool) bool {
+	return accept(e.String())
+}
+
+func (e *With) Satisfied(accept func(string) bool) bool {
+	return accept(e.String())
+}
+
+func (e *And) Satisfied(accept func(string) bool) bool {
+	return e.Left.Satisfied(accept) && e.Right.Satisfied(accept)
+}
+
+func (e *Or) Satisfied(accept func(string) bool) bool {
+	return e.Left.Satisfied(accept) || e.Right.Satisfied(accept)
+}
+
+// group wraps an operand in parentheses if it binds more loosely than its
+// parent.
+func group(e, parent Expr) string {
+	if _, ok := e.(*Or); ok {
+		if _, ok := parent.(*And); ok {
+			return "(" + e.String() + ")"
+		}
+	}
+	return e.String()
+}
+
+// Parse parses a license expression into its syntax tree. The operators are
+// matched case-insensitively, with WITH binding tighter than AND, which binds
+// tighter than OR.
+func Parse(expression string) (Expr, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return e, nil
+}
+
+// Normalize parses a license expression and returns it in its normalized
+// form, with upper-case operators, single spaces and only the parentheses
+// required to preserve its meaning.
+func Normalize(expression string) (string, error) {
+	e, err := Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// Satisfies parses a license expression and reports whether it holds when
+// only the given licenses are acceptable.
+func Satisfies(expression string, accepted []string) (bool, error) {
+	e, err := Parse(expression)
+	if err != nil {
+		return false, err
+	}
+	return e.Satisfied(func(license string) bool {
+		for _, a := range accepted {
+			if strings.EqualFold(a, license) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// Licenses returns the individual licenses, with any exceptions, that appear
+// in the expression, in order of appearance.
+func Licenses(e Expr) []string {
+	switch e := e.(type) {
+	case *And:
+		return append(Licenses(e.Left), Licenses(e.Right)...)
+	case *Or:
+		return append(Licenses(e.Left), Licenses(e.Right)...)
+	default:
+		return []string{e.String()}
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenID
+	tokenAnd
+	tokenOr
+	tokenWith
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// tokenize splits an expression into operators, parentheses and identifiers.
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenOpen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenClose, ")", i})
+			i++
+		case isIDChar(c):
+			start := i
+			for i < len(expression) && isIDChar(expression[i]) {
+				i++
+			}
+			if i < len(expression) && expression[i] == '+' {
+				i++
+			}
+			text := expression[start:i]
+			kind := tokenID
+			switch strings.ToUpper(text) {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "WITH":
+				kind = tokenWith
+			}
+			tokens = append(tokens, token{kind, text, start})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at offset %d",
+				ErrInvalidExpression, c, i)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(expression)}), nil
+}
+
+func isIDChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' || c == '-' || c == '.' || c == ':'
+}
+
+// parser is a recursive descent parser over a list of tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+	}
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidExpression,
+		fmt.Sprintf(format, args...), tok.offset)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseWith()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseWith()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseWith() (Expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenOpen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenClose {
+			return nil, p.errorf(tok, "expected \")\" but found %q", tok.text)
+		}
+		return e, nil
+	case tokenID:
+	default:
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	id := &Identifier{ID: tok.text}
+	if strings.HasSuffix(id.ID, "+") {
+		id.ID, id.OrLater = strings.TrimSuffix(id.ID, "+"), true
+	}
+	if p.peek().kind != tokenWith {
+		return id, nil
+	}
+	p.next()
+	exception := p.next()
+	if exception.kind != tokenID || strings.HasSuffix(exception.text, "+") {
+		return nil, p.errorf(exception, "expected exception but found %q", exception.text)
+	}
+	return &With{License: id, Exception: exception.text}, nil
+}
@@ -0,0 +1,125 @@
+package spdx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestParse(t *testing.T) {
+	e, err := spdx.Parse("MIT OR (Apache-2.0 AND BSD-3-Clause)")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	or, ok := e.(*spdx.Or)
+	if !ok {
+		t.Fatalf("unexpected expression: %#v", e)
+	}
+	if id, ok := or.Left.(*spdx.Identifier); !ok || id.ID != "MIT" {
+		t.Fatalf("unexpected left operand: %#v", or.Left)
+	}
+	and, ok := or.Right.(*spdx.And)
+	if !ok {
+		t.Fatalf("unexpected right operand: %#v", or.Right)
+	}
+	if and.Left.String() != "Apache-2.0" || and.Right.String() != "BSD-3-Clause" {
+		t.Fatalf("unexpected operands: %s, %s", and.Left, and.Right)
+	}
+
+	e, err = spdx.Parse("GPL-2.0+ WITH Classpath-exception-2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	with, ok := e.(*spdx.With)
+	if !ok {
+		t.Fatalf("unexpected expression: %#v", e)
+	}
+	if with.License.ID != "GPL-2.0" || !with.License.OrLater {
+		t.Fatalf("unexpected license: %#v", with.License)
+	}
+	if with.Exception != "Classpath-exception-2.0" {
+		t.Fatalf("unexpected exception: %s", with.Exception)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"MIT OR",
+		"AND MIT",
+		"(MIT",
+		"MIT)",
+		"MIT Apache-2.0",
+		"MIT WITH",
+		"MIT WITH (Classpath-exception-2.0)",
+		"MIT / Apache-2.0",
+	} {
+		if _, err := spdx.Parse(expr); !errors.Is(err, spdx.ErrInvalidExpression) {
+			t.Fatalf("expected error parsing %q, got: %v", expr, err)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"MIT":                                   "MIT",
+		"mit or  apache-2.0":                    "mit OR apache-2.0",
+		"((MIT))":                               "MIT",
+		"(MIT AND ISC) OR Apache-2.0":           "MIT AND ISC OR Apache-2.0",
+		"MIT AND (ISC OR Apache-2.0)":           "MIT AND (ISC OR Apache-2.0)",
+		"(MIT OR ISC) AND (Zlib OR 0BSD)":       "(MIT OR ISC) AND (Zlib OR 0BSD)",
+		"GPL-2.0+ with Classpath-exception-2.0": "GPL-2.0+ WITH Classpath-exception-2.0",
+	}
+	for in, expected := range cases {
+		out, err := spdx.Normalize(in)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if out != expected {
+			t.Fatalf("\nexpected: %s\ngot: %s", expected, out)
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	cases := []struct {
+		expr     string
+		accepted []string
+		expected bool
+	}{
+		{"MIT", []string{"MIT"}, true},
+		{"MIT", []string{"mit"}, true},
+		{"MIT", []string{"ISC"}, false},
+		{"MIT OR (Apache-2.0 AND BSD-3-Clause)", []string{"Apache-2.0"}, false},
+		{"MIT OR (Apache-2.0 AND BSD-3-Clause)", []string{"Apache-2.0", "BSD-3-Clause"}, true},
+		{"GPL-2.0 WITH Classpath-exception-2.0", []string{"GPL-2.0"}, false},
+		{"GPL-2.0 WITH Classpath-exception-2.0", []string{"GPL-2.0 WITH Classpath-exception-2.0"}, true},
+	}
+	for _, c := range cases {
+		ok, err := spdx.Satisfies(c.expr, c.accepted)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if ok != c.expected {
+			t.Fatalf("%q with %v: expected %v", c.expr, c.accepted, c.expected)
+		}
+	}
+}
+
+func TestLicenses(t *testing.T) {
+	e, err := spdx.Parse("MIT OR (Apache-2.0 AND GPL-2.0+ WITH Classpath-exception-2.0)")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ls := spdx.Licenses(e)
+	expected := []string{"MIT", "Apache-2.0", "GPL-2.0+ WITH Classpath-exception-2.0"}
+	if len(ls) != len(expected) {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+	for i := range ls {
+		if ls[i] != expected[i] {
+			t.Fatalf("\nexpected: %s\ngot: %s", expected[i], ls[i])
+		}
+	}
+}
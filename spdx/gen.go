@@ -0,0 +1,164 @@
+//go:build ignore
+
+// This program generates licenses.json, and optionally exceptions.json, from
+// the JSON data published by the SPDX license list project, trimming it down
+// to the fields used by this package. Canonical texts already present in the
+// text directory are refreshed as well, and the texts of the licenses and
+// exceptions given by -add are added to it, such as those recognized by the
+// license package. It is invoked by go generate:
+//
+//	go run gen.go -src https://raw.githubusercontent.com/spdx/license-list-data/main/json/licenses.json \
+//		-exceptions https://raw.githubusercontent.com/spdx/license-list-data/main/json/exceptions.json \
+//		-add EUPL-1.1,EUPL-1.2,EPL-2.0,CDDL-1.1,...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type entry struct {
+	ID          string `json:"licenseId"`
+	Name        string `json:"name"`
+	Deprecated  bool   `json:"isDeprecatedLicenseId"`
+	OSIApproved bool   `json:"isOsiApproved"`
+	FSFLibre    bool   `json:"isFsfLibre"`
+}
+
+type list struct {
+	Version  string  `json:"licenseListVersion"`
+	Licenses []entry `json:"licenses"`
+}
+
+type exceptionEntry struct {
+	ID         string `json:"licenseExceptionId"`
+	Name       string `json:"name"`
+	Deprecated bool   `json:"isDeprecatedLicenseId"`
+}
+
+type exceptionList struct {
+	Version    string           `json:"licenseListVersion"`
+	Exceptions []exceptionEntry `json:"exceptions"`
+}
+
+func main() {
+	src := flag.String("src", "", "path or URL of the SPDX licenses.json")
+	text := flag.String("text", "https://raw.githubusercontent.com/spdx/license-list-data/main/text",
+		"path or URL of the SPDX text directory, or empty to skip texts")
+	out := flag.String("out", "licenses.json", "output file")
+	exceptions := flag.String("exceptions", "", "path or URL of the SPDX exceptions.json, or empty to skip exceptions")
+	exceptionsOut := flag.String("exceptions-out", "exceptions.json", "output file of the exceptions")
+	add := flag.String("add", "", "comma-separated identifiers of licenses and exceptions whose texts are added to the text directory")
+	flag.Parse()
+
+	if *src == "" {
+		log.Fatal("missing -src")
+	}
+
+	data, err := fetch(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var l list
+	if err := json.Unmarshal(data, &l); err != nil {
+		log.Fatal(err)
+	}
+	sort.Slice(l.Licenses, func(i, j int) bool {
+		return strings.ToLower(l.Licenses[i].ID) < strings.ToLower(l.Licenses[j].ID)
+	})
+
+	data, err = json.MarshalIndent(l, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if *exceptions != "" {
+		data, err := fetch(*exceptions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var e exceptionList
+		if err := json.Unmarshal(data, &e); err != nil {
+			log.Fatal(err)
+		}
+		sort.Slice(e.Exceptions, func(i, j int) bool {
+			return strings.ToLower(e.Exceptions[i].ID) < strings.ToLower(e.Exceptions[j].ID)
+		})
+		data, err = json.MarshalIndent(e, "", "\t")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*exceptionsOut, append(data, '\n'), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *text == "" {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join("text", "*.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, id := range strings.Split(*add, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if file := filepath.Join("text", id+".txt"); !contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".txt")
+		data, err := fetch(*text + "/" + id + ".txt")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// contains determines if the file is one of the files.
+func contains(files []string, file string) bool {
+	for _, f := range files {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch reads a local file or downloads a URL.
+func fetch(src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return ioutil.ReadFile(src)
+	}
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+}
+
+func init() {
+	log.SetFlags(0)
+	log.SetOutput(os.Stderr)
+}
@@ -0,0 +1,162 @@
+package spdx
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+//go:generate go run gen.go -src https://raw.githubusercontent.com/spdx/license-list-data/main/json/licenses.json -exceptions https://raw.githubusercontent.com/spdx/license-list-data/main/json/exceptions.json -add EUPL-1.1,EUPL-1.2,EPL-2.0,CDDL-1.1,AFL-3.0,Artistic-1.0,MS-RL,OpenSSL,OFL-1.1,OFL-1.1-RFN,Ubuntu-font-1.0,JSON,Beerware,blessing,GFDL-1.1,CC-BY-3.0,CC-BY-4.0,CC-BY-SA-3.0,CC-BY-SA-4.0,CC-BY-NC-3.0,CC-BY-NC-4.0,CC-BY-NC-SA-3.0,CC-BY-NC-SA-4.0,CC-BY-ND-3.0,CC-BY-ND-4.0,CC-BY-NC-ND-3.0,CC-BY-NC-ND-4.0,GPL-1.0,BUSL-1.1,Elastic-2.0,SSPL-1.0,PolyForm-Noncommercial-1.0.0,PolyForm-Small-Business-1.0.0,CDLA-Permissive-1.0,CDLA-Permissive-2.0,CDLA-Sharing-1.0,CERN-OHL-P-2.0,CERN-OHL-W-2.0,CERN-OHL-S-2.0,ODC-By-1.0,ODbL-1.0,LLVM-exception,Classpath-exception-2.0,GCC-exception-3.1,SHL-2.1,Linux-syscall-note
+
+//go:embed licenses.json
+var listData []byte
+
+//go:embed exceptions.json
+var exceptionData []byte
+
+//go:embed text
+var textFS embed.FS
+
+// License describes an entry of the SPDX license list.
+type License struct {
+	ID          string `json:"id" yaml:"id"`                         // The SPDX license identifier
+	Name        string `json:"name" yaml:"name"`                     // The full name of the license
+	Deprecated  bool   `json:"deprecated" yaml:"deprecated"`         // Whether the identifier is deprecated
+	OSIApproved bool   `json:"osiApproved" yaml:"osiApproved"`       // Whether the OSI approved the license
+	FSFLibre    bool   `json:"fsfLibre" yaml:"fsfLibre"`             // Whether the FSF considers the license free
+	Text        string `json:"text,omitempty" yaml:"text,omitempty"` // The canonical license text, if embedded
+}
+
+// Exception describes an entry of the SPDX license exception list, which
+// grants additional permissions to a license in a WITH expression.
+type Exception struct {
+	ID         string `json:"id" yaml:"id"`                         // The SPDX exception identifier
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"` // The full name of the exception
+	Deprecated bool   `json:"deprecated" yaml:"deprecated"`         // Whether the identifier is deprecated
+	Text       string `json:"text,omitempty" yaml:"text,omitempty"` // The canonical exception text, if embedded
+}
+
+var (
+	listOnce    sync.Once
+	listVersion string
+	licenses    []License
+	licenseIDs  map[string]int
+
+	exceptionsOnce sync.Once
+	exceptions     []Exception
+	exceptionIDs   map[string]int
+)
+
+// loadList decodes the embedded license list on first use.
+func loadList() {
+	listOnce.Do(func() {
+		var data struct {
+			Version  string `json:"licenseListVersion"`
+			Licenses []struct {
+				ID          string `json:"licenseId"`
+				Name        string `json:"name"`
+				Deprecated  bool   `json:"isDeprecatedLicenseId"`
+				OSIApproved bool   `json:"isOsiApproved"`
+				FSFLibre    bool   `json:"isFsfLibre"`
+			} `json:"licenses"`
+		}
+		if err := json.Unmarshal(listData, &data); err != nil {
+			panic("spdx: malformed license list: " + err.Error())
+		}
+
+		listVersion = data.Version
+		licenses = make([]License, len(data.Licenses))
+		licenseIDs = make(map[string]int, len(data.Licenses))
+		for i, l := range data.Licenses {
+			text, _ := textFS.ReadFile("text/" + l.ID + ".txt")
+			licenses[i] = License{
+				ID:          l.ID,
+				Name:        l.Name,
+				Deprecated:  l.Deprecated,
+				OSIApproved: l.OSIApproved,
+				FSFLibre:    l.FSFLibre,
+				Text:        string(text),
+			}
+			licenseIDs[strings.ToLower(l.ID)] = i
+		}
+	})
+}
+
+// Version returns the version of the embedded SPDX license list.
+func Version() string {
+	loadList()
+	return listVersion
+}
+
+// List returns every license of the SPDX license list, ordered by identifier.
+func List() []*License {
+	loadList()
+	out := make([]*License, len(licenses))
+	for i := range licenses {
+		l := licenses[i]
+		out[i] = &l
+	}
+	return out
+}
+
+// Get looks up a license of the SPDX license list by its identifier. The
+// identifier is matched case-insensitively, as required by the SPDX
+// specification.
+func Get(id string) (*License, bool) {
+	loadList()
+	i, ok := licenseIDs[strings.ToLower(id)]
+	if !ok {
+		return nil, false
+	}
+	l := licenses[i]
+	return &l, true
+}
+
+// loadExceptions decodes the embedded exception list on first use.
+func loadExceptions() {
+	exceptionsOnce.Do(func() {
+		var data struct {
+			Exceptions []struct {
+				ID         string `json:"licenseExceptionId"`
+				Name       string `json:"name"`
+				Deprecated bool   `json:"isDeprecatedLicenseId"`
+			} `json:"exceptions"`
+		}
+		if err := json.Unmarshal(exceptionData, &data); err != nil {
+			panic("spdx: malformed exception list: " + err.Error())
+		}
+
+		exceptions = make([]Exception, len(data.Exceptions))
+		exceptionIDs = make(map[string]int, len(data.Exceptions))
+		for i, e := range data.Exceptions {
+			text, _ := textFS.ReadFile("text/" + e.ID + ".txt")
+			exceptions[i] = Exception{ID: e.ID, Name: e.Name, Deprecated: e.Deprecated, Text: string(text)}
+			exceptionIDs[strings.ToLower(e.ID)] = i
+		}
+	})
+}
+
+// Exceptions returns every exception of the SPDX license exception list,
+// ordered by identifier.
+func Exceptions() []*Exception {
+	loadExceptions()
+	out := make([]*Exception, len(exceptions))
+	for i := range exceptions {
+		e := exceptions[i]
+		out[i] = &e
+	}
+	return out
+}
+
+// GetException looks up an exception of the SPDX license exception list by
+// its identifier, matched case-insensitively.
+func GetException(id string) (*Exception, bool) {
+	loadExceptions()
+	i, ok := exceptionIDs[strings.ToLower(id)]
+	if !ok {
+		return nil, false
+	}
+	e := exceptions[i]
+	return &e, true
+}
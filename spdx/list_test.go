@@ -0,0 +1,85 @@
+package spdx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestList(t *testing.T) {
+	if spdx.Version() == "" {
+		t.Fatalf("missing license list version")
+	}
+
+	ls := spdx.List()
+	if len(ls) < 500 {
+		t.Fatalf("unexpected number of licenses: %d", len(ls))
+	}
+	for i := 1; i < len(ls); i++ {
+		if strings.ToLower(ls[i-1].ID) >= strings.ToLower(ls[i].ID) {
+			t.Fatalf("licenses out of order: %s, %s", ls[i-1].ID, ls[i].ID)
+		}
+	}
+
+	// Modifying the result does not affect the list
+	ls[0].ID = "Modified"
+	if spdx.List()[0].ID == "Modified" {
+		t.Fatalf("license list was modified")
+	}
+}
+
+func TestGet(t *testing.T) {
+	for _, id := range []string{"0BSD", "BSL-1.0", "CC0-1.0", "Artistic-2.0", "WTFPL"} {
+		if _, ok := spdx.Get(id); !ok {
+			t.Fatalf("missing license: %s", id)
+		}
+	}
+
+	l, ok := spdx.Get("mit")
+	if !ok {
+		t.Fatalf("missing license: mit")
+	}
+	if l.ID != "MIT" || l.Name != "MIT License" || l.Deprecated {
+		t.Fatalf("unexpected license: %#v", l)
+	}
+	if !strings.Contains(l.Text, "Permission is hereby granted, free of charge") {
+		t.Fatalf("unexpected license text: %s", l.Text)
+	}
+
+	l, ok = spdx.Get("GPL-2.0+")
+	if !ok || !l.Deprecated {
+		t.Fatalf("unexpected license: %#v", l)
+	}
+
+	if _, ok := spdx.Get("MyLicense"); ok {
+		t.Fatalf("fake license was found")
+	}
+}
+
+func TestGetException(t *testing.T) {
+	for _, id := range []string{"Classpath-exception-2.0", "LLVM-exception", "GCC-exception-3.1", "Linux-syscall-note"} {
+		if _, ok := spdx.GetException(id); !ok {
+			t.Fatalf("missing exception: %s", id)
+		}
+	}
+
+	e, ok := spdx.GetException("llvm-EXCEPTION")
+	if !ok || e.ID != "LLVM-exception" || e.Deprecated {
+		t.Fatalf("unexpected exception: %#v", e)
+	}
+	if !strings.Contains(e.Text, "LLVM Exceptions to the Apache 2.0 License") {
+		t.Fatalf("missing exception text: %q", e.Text)
+	}
+
+	if _, ok := spdx.GetException("MIT"); ok {
+		t.Fatalf("license was found as an exception")
+	}
+
+	es := spdx.Exceptions()
+	for i := 1; i < len(es); i++ {
+		if strings.ToLower(es[i-1].ID) >= strings.ToLower(es[i].ID) {
+			t.Fatalf("exceptions out of order: %s, %s", es[i-1].ID, es[i].ID)
+		}
+	}
+}
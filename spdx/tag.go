@@ -0,0 +1,152 @@
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var (
+	tagRegexp = regexp.MustCompile(`(?i)spdx-license-identifier:[ \t]*`)
+
+	// Comment terminators which may follow a tag on the same line
+	commentEnds = []string{"*/", "-->", "*)", "-}", "#}", "%>", "?>"}
+)
+
+// Tag is an SPDX-License-Identifier tag found in a text.
+type Tag struct {
+	Value  string // The expression as written after the tag
+	Expr   Expr   // The parsed expression, or nil if it is invalid
+	Err    error  // The error parsing or validating the expression, if any
+	Offset int    // The byte offset of the tag in the text
+	Line   int    // The line of the tag, starting at 1
+	Column int    // The column of the tag in bytes, starting at 1
+}
+
+// MarshalJSON encodes the tag with its expression in normalized form, and its
+// error as a string.
+func (t *Tag) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Value      string `json:"value"`
+		Expression string `json:"expression,omitempty"`
+		Error      string `json:"error,omitempty"`
+		Offset     int    `json:"offset"`
+		Line       int    `json:"line"`
+		Column     int    `json:"column"`
+	}{
+		Value:  t.Value,
+		Offset: t.Offset,
+		Line:   t.Line,
+		Column: t.Column,
+	}
+	if t.Expr != nil {
+		v.Expression = t.Expr.String()
+	}
+	if t.Err != nil {
+		v.Error = t.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// FindTags will scan text for SPDX-License-Identifier tags, parse the license
+// expression of each one, and validate the license identifiers used against
+// the SPDX license list. Tags with an invalid expression are returned as well,
+// with Err describing why it is invalid.
+func FindTags(text string) []*Tag {
+	var tags []*Tag
+	offset := 0
+	for i, line := range strings.SplitAfter(text, "\n") {
+		if loc := tagRegexp.FindStringIndex(line); loc != nil {
+			value := strings.TrimSpace(line[loc[1]:])
+			for _, end := range commentEnds {
+				if pos := strings.Index(value, end); pos >= 0 {
+					value = strings.TrimSpace(value[:pos])
+				}
+			}
+
+			tag := &Tag{
+				Value:  value,
+				Offset: offset + loc[0],
+				Line:   i + 1,
+				Column: loc[0] + 1,
+			}
+			if tag.Expr, tag.Err = Parse(value); tag.Err == nil {
+				if tag.Err = Validate(tag.Expr); tag.Err != nil {
+					tag.Expr = nil
+				}
+			}
+			tags = append(tags, tag)
+		}
+		offset += len(line)
+	}
+	return tags
+}
+
+// FindTagsInFile will read a file and scan it for SPDX-License-Identifier tags,
+// as described by FindTags.
+func FindTagsInFile(path string) ([]*Tag, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FindTags(string(data)), nil
+}
+
+// Validate checks that every license identifier in the expression is on the
+// SPDX license list, or is a user defined LicenseRef, and that every exception
+// is on the SPDX license exception list, or is a user defined AdditionRef.
+func Validate(e Expr) error {
+	switch e := e.(type) {
+	case *And:
+		if err := Validate(e.Left); err != nil {
+			return err
+		}
+		return Validate(e.Right)
+	case *Or:
+		if err := Validate(e.Left); err != nil {
+			return err
+		}
+		return Validate(e.Right)
+	case *With:
+		if err := Validate(e.License); err != nil {
+			return err
+		}
+		if !isAdditionRef(e.Exception) {
+			if _, ok := GetException(e.Exception); !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownException, e.Exception)
+			}
+		}
+	case *Identifier:
+		if isLicenseRef(e.ID) {
+			return nil
+		}
+		if _, ok := Get(e.ID); !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownLicense, e.ID)
+		}
+	}
+	return nil
+}
+
+// isLicenseRef determines if the identifier refers to a license defined by
+// the user rather than one on the SPDX license list.
+func isLicenseRef(id string) bool {
+	if strings.HasPrefix(id, "DocumentRef-") {
+		if i := strings.Index(id, ":"); i >= 0 {
+			id = id[i+1:]
+		}
+	}
+	return strings.HasPrefix(id, "LicenseRef-")
+}
+
+// isAdditionRef determines if the identifier refers to an exception defined by
+// the user rather than one on the SPDX license exception list.
+func isAdditionRef(id string) bool {
+	if strings.HasPrefix(id, "DocumentRef-") {
+		if i := strings.Index(id, ":"); i >= 0 {
+			id = id[i+1:]
+		}
+	}
+	return strings.HasPrefix(id, "AdditionRef-")
+}
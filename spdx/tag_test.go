@@ -0,0 +1,130 @@
+package spdx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestFindTags(t *testing.T) {
+	text := `// Copyright 2020 The Example Authors
+// SPDX-License-Identifier: MIT OR Apache-2.0
+
+/* spdx-license-identifier: (GPL-2.0+ WITH Linux-syscall-note) */
+# SPDX-License-Identifier: LicenseRef-Proprietary
+<!-- SPDX-License-Identifier: MyLicense -->
+-- SPDX-License-Identifier: MIT AND
+`
+	tags := spdx.FindTags(text)
+	if len(tags) != 5 {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	tag := tags[0]
+	if tag.Value != "MIT OR Apache-2.0" || tag.Err != nil || tag.Expr.String() != "MIT OR Apache-2.0" {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+	if tag.Line != 2 || tag.Column != 4 || tag.Offset != 41 {
+		t.Fatalf("unexpected tag position: %#v", tag)
+	}
+
+	tag = tags[1]
+	if tag.Value != "(GPL-2.0+ WITH Linux-syscall-note)" || tag.Err != nil {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+	if tag.Line != 4 || tag.Column != 4 {
+		t.Fatalf("unexpected tag position: %#v", tag)
+	}
+
+	if tag := tags[2]; tag.Err != nil || tag.Expr.String() != "LicenseRef-Proprietary" {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+
+	// Unknown identifiers are invalid
+	if tag := tags[3]; !errors.Is(tag.Err, spdx.ErrUnknownLicense) || tag.Expr != nil || tag.Value != "MyLicense" {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+
+	// Malformed expressions are invalid
+	if tag := tags[4]; !errors.Is(tag.Err, spdx.ErrInvalidExpression) || tag.Expr != nil {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+
+	if tags := spdx.FindTags("No tags here"); len(tags) != 0 {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+}
+
+func TestFindTagsInFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("#!/bin/sh\n# SPDX-License-Identifier: BSD-3-Clause\n")
+	f.Close()
+
+	tags, err := spdx.FindTagsInFile(f.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(tags) != 1 || tags[0].Err != nil || tags[0].Line != 2 {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	// Fails properly if the file doesn't exist
+	if _, err := spdx.FindTagsInFile("/tmp/go-license-nonexistent"); err == nil {
+		t.Fatalf("expected error loading non-existent file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, expr := range []string{
+		"MIT",
+		"mit OR apache-2.0",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"DocumentRef-spdx-tool:LicenseRef-MIT-Style-2",
+		"Apache-2.0 WITH AdditionRef-Acme-exception",
+	} {
+		e, err := spdx.Parse(expr)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := spdx.Validate(e); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	e, err := spdx.Parse("MIT AND (ISC OR MyLicense)")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := spdx.Validate(e); !errors.Is(err, spdx.ErrUnknownLicense) {
+		t.Fatalf("expected error validating unknown license, got: %v", err)
+	}
+
+	e, err = spdx.Parse("Apache-2.0 WITH MyException")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := spdx.Validate(e); !errors.Is(err, spdx.ErrUnknownException) {
+		t.Fatalf("expected error validating unknown exception, got: %v", err)
+	}
+}
+
+func TestTagMarshalJSON(t *testing.T) {
+	tags := spdx.FindTags("// SPDX-License-Identifier: mit or Apache-2.0\n// SPDX-License-Identifier: MyLicense\n")
+	data, err := json.Marshal(tags)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `[{"value":"mit or Apache-2.0","expression":"mit OR Apache-2.0","offset":3,"line":1,"column":4},` +
+		`{"value":"MyLicense","error":"spdx: unknown license identifier: MyLicense","offset":49,"line":2,"column":4}]`
+	if string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, data)
+	}
+}
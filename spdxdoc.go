@@ -0,0 +1,345 @@
+package license
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxDataLicense = "CC0-1.0"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxCreator     = "Tool: go-license"
+	spdxNoAssertion = LicenseNoAssertion
+	spdxNone        = LicenseNone
+)
+
+// SPDXDocument is an SPDX 2.3 document describing a report, with a package
+// for each directory and a file for each license file. It marshals to the
+// SPDX JSON serialization, and WriteTagValue writes the tag-value one.
+type SPDXDocument struct {
+	SPDXVersion       string              `json:"spdxVersion"`
+	DataLicense       string              `json:"dataLicense"`
+	SPDXID            string              `json:"SPDXID"`
+	Name              string              `json:"name"`
+	DocumentNamespace string              `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo    `json:"creationInfo"`
+	Packages          []*SPDXPackage      `json:"packages"`
+	Files             []*SPDXFile         `json:"files,omitempty"`
+	Relationships     []*SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo records when and by what an SPDX document was created.
+type SPDXCreationInfo struct {
+	Creators []string  `json:"creators"`
+	Created  time.Time `json:"created"`
+}
+
+// SPDXPackage is a directory described by an SPDX document.
+type SPDXPackage struct {
+	Name                 string                   `json:"name"`
+	SPDXID               string                   `json:"SPDXID"`
+	DownloadLocation     string                   `json:"downloadLocation"`
+	FilesAnalyzed        bool                     `json:"filesAnalyzed"`
+	VerificationCode     *SPDXPackageVerification `json:"packageVerificationCode,omitempty"`
+	LicenseConcluded     string                   `json:"licenseConcluded"`
+	LicenseInfoFromFiles []string                 `json:"licenseInfoFromFiles,omitempty"`
+	LicenseDeclared      string                   `json:"licenseDeclared"`
+	CopyrightText        string                   `json:"copyrightText"`
+	HasFiles             []string                 `json:"hasFiles,omitempty"`
+}
+
+// SPDXPackageVerification is the verification code of the files of a package.
+type SPDXPackageVerification struct {
+	Value string `json:"packageVerificationCodeValue"`
+}
+
+// SPDXFile is a license file described by an SPDX document.
+type SPDXFile struct {
+	FileName           string          `json:"fileName"`
+	SPDXID             string          `json:"SPDXID"`
+	Checksums          []*SPDXChecksum `json:"checksums"`
+	LicenseConcluded   string          `json:"licenseConcluded"`
+	LicenseInfoInFiles []string        `json:"licenseInfoInFiles"`
+	CopyrightText      string          `json:"copyrightText"`
+}
+
+// SPDXChecksum is the checksum of a file.
+type SPDXChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+// SPDXRelationship relates two elements of an SPDX document, such as a
+// package which CONTAINS a file.
+type SPDXRelationship struct {
+	Element        string `json:"spdxElementId"`
+	Type           string `json:"relationshipType"`
+	RelatedElement string `json:"relatedSpdxElement"`
+}
+
+// NewSPDXDocument creates an SPDX document from the results of a report. The
+// license files are read again to compute their checksums and copyright text.
+// The namespace must be a URI unique to this document, as required by SPDX.
+//
+// License types which are not valid SPDX license expressions are recorded as
+// NOASSERTION, and directories reported as LicenseNone, as by WithSPDXResults,
+// declare NONE.
+func NewSPDXDocument(r *Report, name, namespace string) (*SPDXDocument, error) {
+	d := &SPDXDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxDataLicense,
+		SPDXID:            spdxDocumentID,
+		Name:              name,
+		DocumentNamespace: namespace,
+		CreationInfo: SPDXCreationInfo{
+			Creators: []string{spdxCreator},
+			Created:  time.Now().UTC().Truncate(time.Second),
+		},
+	}
+
+	packages := make(map[string]*SPDXPackage)
+	concluded := make(map[*SPDXPackage][]string)
+	for _, result := range r.Results {
+		p, ok := packages[result.Dir]
+		if !ok {
+			p = &SPDXPackage{
+				Name:             filepath.ToSlash(result.Dir),
+				SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", len(d.Packages)+1),
+				DownloadLocation: spdxNoAssertion,
+				LicenseConcluded: spdxNoAssertion,
+				LicenseDeclared:  spdxNoAssertion,
+				CopyrightText:    spdxNoAssertion,
+			}
+			packages[result.Dir] = p
+			d.Packages = append(d.Packages, p)
+			d.relate(spdxDocumentID, "DESCRIBES", p.SPDXID)
+		}
+		if result.File == "" {
+			if result.Type == LicenseNone {
+				p.LicenseConcluded, p.LicenseDeclared = spdxNone, spdxNone
+			}
+			continue
+		}
+
+		f, err := newSPDXFile(result, fmt.Sprintf("SPDXRef-File-%d", len(d.Files)+1))
+		if err != nil {
+			return nil, err
+		}
+		d.Files = append(d.Files, f)
+		d.relate(p.SPDXID, "CONTAINS", f.SPDXID)
+
+		p.FilesAnalyzed = true
+		p.HasFiles = append(p.HasFiles, f.SPDXID)
+		for _, id := range f.LicenseInfoInFiles {
+			if id != spdxNoAssertion && !containsType(p.LicenseInfoFromFiles, id) {
+				p.LicenseInfoFromFiles = append(p.LicenseInfoFromFiles, id)
+			}
+		}
+		if f.LicenseConcluded != spdxNoAssertion && !containsType(concluded[p], f.LicenseConcluded) {
+			concluded[p] = append(concluded[p], f.LicenseConcluded)
+		}
+	}
+
+	for _, p := range d.Packages {
+		if !p.FilesAnalyzed {
+			continue
+		}
+		p.VerificationCode = &SPDXPackageVerification{d.verificationCode(p)}
+		if len(p.LicenseInfoFromFiles) == 0 {
+			p.LicenseInfoFromFiles = []string{spdxNoAssertion}
+			continue
+		}
+		// The package is declared under the full expressions of its files,
+		// while the license information lists their individual licenses
+		exprs := concluded[p]
+		declared := make([]string, len(exprs))
+		for i, e := range exprs {
+			declared[i] = e
+			if len(exprs) > 1 && strings.Contains(e, " ") {
+				declared[i] = "(" + e + ")"
+			}
+		}
+		p.LicenseDeclared = strings.Join(declared, " AND ")
+	}
+	return d, nil
+}
+
+// newSPDXFile describes the license file of a result.
+func newSPDXFile(result *Result, id string) (*SPDXFile, error) {
+	data, err := ioutil.ReadFile(result.File)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(data)
+
+	name := result.File
+	if rel, err := filepath.Rel(result.Dir, result.File); err == nil && !strings.HasPrefix(rel, "..") {
+		name = rel
+	}
+
+	copyrights := ExtractCopyrights(string(data))
+	copyright := spdxNone
+	if len(copyrights) > 0 {
+		statements := make([]string, len(copyrights))
+		for i, c := range copyrights {
+			statements[i] = c.Statement
+		}
+		copyright = strings.Join(statements, "\n")
+	}
+
+	return &SPDXFile{
+		FileName:           "./" + filepath.ToSlash(name),
+		SPDXID:             id,
+		Checksums:          []*SPDXChecksum{{"SHA1", hex.EncodeToString(sum[:])}},
+		LicenseConcluded:   spdxExpression(result.Type),
+		LicenseInfoInFiles: spdxLicenseInfo(result.Type),
+		CopyrightText:      copyright,
+	}, nil
+}
+
+// spdxLicenseInfo returns the individual license identifiers of the license
+// type, without operators or exceptions, or NOASSERTION if it is not a valid
+// SPDX license expression.
+func spdxLicenseInfo(licenseType string) []string {
+	e, err := spdx.Parse(licenseType)
+	if err != nil || spdx.Validate(e) != nil {
+		return []string{spdxNoAssertion}
+	}
+	var ids []string
+	for _, id := range spdx.Licenses(e) {
+		if i := strings.Index(id, " WITH "); i >= 0 {
+			id = id[:i]
+		}
+		if !containsType(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// spdxExpression returns the license type as a normalized SPDX license
+// expression, or NOASSERTION if it is not a valid one.
+func spdxExpression(licenseType string) string {
+	e, err := spdx.Parse(licenseType)
+	if err != nil || spdx.Validate(e) != nil {
+		return spdxNoAssertion
+	}
+	return e.String()
+}
+
+func (d *SPDXDocument) relate(element, relationship, related string) {
+	d.Relationships = append(d.Relationships, &SPDXRelationship{element, relationship, related})
+}
+
+// verificationCode computes the verification code of a package from the
+// SHA1 checksums of its files, as described by the SPDX specification.
+func (d *SPDXDocument) verificationCode(p *SPDXPackage) string {
+	var sums []string
+	for _, f := range d.Files {
+		if containsType(p.HasFiles, f.SPDXID) {
+			sums = append(sums, f.Checksums[0].Value)
+		}
+	}
+	sort.Strings(sums)
+	sum := sha1.Sum([]byte(strings.Join(sums, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteJSON writes the document in the SPDX JSON serialization.
+func (d *SPDXDocument) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteTagValue writes the document in the SPDX tag-value serialization.
+// The files of each package follow it.
+func (d *SPDXDocument) WriteTagValue(w io.Writer) error {
+	tw := &tagWriter{w: w}
+	tw.tag("SPDXVersion", d.SPDXVersion)
+	tw.tag("DataLicense", d.DataLicense)
+	tw.tag("SPDXID", d.SPDXID)
+	tw.tag("DocumentName", d.Name)
+	tw.tag("DocumentNamespace", d.DocumentNamespace)
+	for _, creator := range d.CreationInfo.Creators {
+		tw.tag("Creator", creator)
+	}
+	tw.tag("Created", d.CreationInfo.Created.Format(time.RFC3339))
+
+	files := make(map[string]*SPDXFile, len(d.Files))
+	for _, f := range d.Files {
+		files[f.SPDXID] = f
+	}
+	for _, p := range d.Packages {
+		tw.line("")
+		tw.tag("PackageName", p.Name)
+		tw.tag("SPDXID", p.SPDXID)
+		tw.tag("PackageDownloadLocation", p.DownloadLocation)
+		tw.tag("FilesAnalyzed", fmt.Sprint(p.FilesAnalyzed))
+		if p.VerificationCode != nil {
+			tw.tag("PackageVerificationCode", p.VerificationCode.Value)
+		}
+		tw.tag("PackageLicenseConcluded", p.LicenseConcluded)
+		for _, id := range p.LicenseInfoFromFiles {
+			tw.tag("PackageLicenseInfoFromFiles", id)
+		}
+		tw.tag("PackageLicenseDeclared", p.LicenseDeclared)
+		tw.tag("PackageCopyrightText", p.CopyrightText)
+
+		for _, id := range p.HasFiles {
+			f := files[id]
+			tw.line("")
+			tw.tag("FileName", f.FileName)
+			tw.tag("SPDXID", f.SPDXID)
+			for _, c := range f.Checksums {
+				tw.tag("FileChecksum", c.Algorithm+": "+c.Value)
+			}
+			tw.tag("LicenseConcluded", f.LicenseConcluded)
+			for _, id := range f.LicenseInfoInFiles {
+				tw.tag("LicenseInfoInFile", id)
+			}
+			tw.tag("FileCopyrightText", f.CopyrightText)
+		}
+	}
+
+	if len(d.Relationships) > 0 {
+		tw.line("")
+	}
+	for _, r := range d.Relationships {
+		tw.tag("Relationship", r.Element+" "+r.Type+" "+r.RelatedElement)
+	}
+	return tw.err
+}
+
+// tagWriter writes tag-value pairs, keeping the first error.
+type tagWriter struct {
+	w   io.Writer
+	err error
+}
+
+// tag writes a tag-value pair. Multi-line values, and free-form text which
+// may contain a colon, are wrapped in <text> tags.
+func (tw *tagWriter) tag(tag, value string) {
+	if strings.Contains(value, "\n") || strings.HasSuffix(tag, "CopyrightText") &&
+		value != spdxNone && value != spdxNoAssertion {
+		value = "<text>" + value + "</text>"
+	}
+	tw.line(tag + ": " + value)
+}
+
+func (tw *tagWriter) line(s string) {
+	if tw.err == nil {
+		_, tw.err = io.WriteString(tw.w, s+"\n")
+	}
+}
@@ -0,0 +1,217 @@
+package license_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewSPDXDocument(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", license.LicenseMIT))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := "Copyright (c) 2020 Jane Doe\n\n" + string(lbytes)
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), []byte(text), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	copyFixture(t, license.LicenseGPL30, filepath.Join(d, "sub", "COPYING"))
+	if err := os.MkdirAll(filepath.Join(d, "empty"), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	found, err := license.NewFromDirRecursive(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	r := license.NewReport(found)
+	r.Add(filepath.Join(d, "empty"))
+
+	doc, err := license.NewSPDXDocument(r, "example", "https://example.com/spdx/example")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	doc.CreationInfo.Created = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	sum := func(file string) string {
+		data, err := ioutil.ReadFile(filepath.Join(d, file))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		s := sha1.Sum(data)
+		return hex.EncodeToString(s[:])
+	}
+	code := func(file string) string {
+		s := sha1.Sum([]byte(sum(file)))
+		return hex.EncodeToString(s[:])
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTagValue(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: example
+DocumentNamespace: https://example.com/spdx/example
+Creator: Tool: go-license
+Created: 2020-01-02T03:04:05Z
+
+PackageName: ` + filepath.ToSlash(d) + `
+SPDXID: SPDXRef-Package-1
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: true
+PackageVerificationCode: ` + code("LICENSE") + `
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseInfoFromFiles: MIT
+PackageLicenseDeclared: MIT
+PackageCopyrightText: NOASSERTION
+
+FileName: ./LICENSE
+SPDXID: SPDXRef-File-1
+FileChecksum: SHA1: ` + sum("LICENSE") + `
+LicenseConcluded: MIT
+LicenseInfoInFile: MIT
+FileCopyrightText: <text>Copyright (c) 2020 Jane Doe</text>
+
+PackageName: ` + filepath.ToSlash(filepath.Join(d, "sub")) + `
+SPDXID: SPDXRef-Package-2
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: true
+PackageVerificationCode: ` + code(filepath.Join("sub", "COPYING")) + `
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseInfoFromFiles: GPL-3.0
+PackageLicenseDeclared: GPL-3.0
+PackageCopyrightText: NOASSERTION
+
+FileName: ./COPYING
+SPDXID: SPDXRef-File-2
+FileChecksum: SHA1: ` + sum(filepath.Join("sub", "COPYING")) + `
+LicenseConcluded: GPL-3.0
+LicenseInfoInFile: GPL-3.0
+FileCopyrightText: NONE
+
+PackageName: ` + filepath.ToSlash(filepath.Join(d, "empty")) + `
+SPDXID: SPDXRef-Package-3
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: false
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: NOASSERTION
+PackageCopyrightText: NOASSERTION
+
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-1
+Relationship: SPDXRef-Package-1 CONTAINS SPDXRef-File-1
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-2
+Relationship: SPDXRef-Package-2 CONTAINS SPDXRef-File-2
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-3
+`
+	if buf.String() != expected {
+		t.Fatalf("\nexpected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := doc.WriteJSON(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if decoded["spdxVersion"] != "SPDX-2.3" || decoded["creationInfo"].(map[string]interface{})["created"] != "2020-01-02T03:04:05Z" {
+		t.Fatalf("unexpected document: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"packageVerificationCodeValue": "`+code("LICENSE")+`"`) {
+		t.Fatalf("missing verification code: %s", buf.String())
+	}
+}
+
+func TestNewSPDXDocument_Unrecognized(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+	file := filepath.Join(d, "LICENSE")
+	if err := ioutil.WriteFile(file, []byte("My terms"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := new(license.Report)
+	r.Add(d, &license.License{Type: license.LicenseUnrecognized, File: file})
+	doc, err := license.NewSPDXDocument(r, "example", "https://example.com/spdx/example")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if f := doc.Files[0]; f.LicenseConcluded != "NOASSERTION" {
+		t.Fatalf("\nexpected: NOASSERTION\ngot: %s", f.LicenseConcluded)
+	}
+	if p := doc.Packages[0]; p.LicenseDeclared != "NOASSERTION" || p.LicenseInfoFromFiles[0] != "NOASSERTION" {
+		t.Fatalf("unexpected package: %v", p)
+	}
+
+	// Directories without license files declare none
+	r.Add("empty", &license.License{Type: license.LicenseNone})
+	doc, err = license.NewSPDXDocument(r, "example", "https://example.com/spdx/example")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p := doc.Packages[1]; p.LicenseDeclared != "NONE" || p.LicenseConcluded != "NONE" {
+		t.Fatalf("unexpected package: %v", p)
+	}
+
+	// Missing license files fail properly
+	r.Add(d, &license.License{Type: license.LicenseMIT, File: filepath.Join(d, "missing")})
+	if _, err := license.NewSPDXDocument(r, "example", "https://example.com/spdx/example"); err == nil {
+		t.Fatalf("expected error reading missing license file")
+	}
+}
+
+func TestNewSPDXDocument_Expressions(t *testing.T) {
+	d := t.TempDir()
+	for _, name := range []string{"LICENSE", "COPYING"} {
+		if err := ioutil.WriteFile(filepath.Join(d, name), []byte("My terms"), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	r := new(license.Report)
+	r.Add(d,
+		&license.License{Type: "MIT OR Apache-2.0", File: filepath.Join(d, "LICENSE")},
+		&license.License{Type: "GPL-2.0-or-later WITH Classpath-exception-2.0", File: filepath.Join(d, "COPYING")},
+	)
+	doc, err := license.NewSPDXDocument(r, "example", "https://example.com/spdx/example")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Files list their individual licenses, and conclude the full expression
+	if f := doc.Files[0]; f.LicenseConcluded != "MIT OR Apache-2.0" || strings.Join(f.LicenseInfoInFiles, ",") != "MIT,Apache-2.0" {
+		t.Fatalf("unexpected file: %v", f)
+	}
+	if f := doc.Files[1]; strings.Join(f.LicenseInfoInFiles, ",") != "GPL-2.0-or-later" {
+		t.Fatalf("unexpected file: %v", f)
+	}
+	p := doc.Packages[0]
+	if expected := "MIT,Apache-2.0,GPL-2.0-or-later"; strings.Join(p.LicenseInfoFromFiles, ",") != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, strings.Join(p.LicenseInfoFromFiles, ","))
+	}
+	if expected := "(MIT OR Apache-2.0) AND (GPL-2.0-or-later WITH Classpath-exception-2.0)"; p.LicenseDeclared != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, p.LicenseDeclared)
+	}
+}
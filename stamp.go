@@ -0,0 +1,273 @@
+package license
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// Actions taken on the license header of a source file by FixHeaders
+const (
+	HeaderAdded      = "added"      // A header was added to a file without one
+	HeaderNormalized = "normalized" // A deviating header was replaced
+)
+
+// HeaderChange is a change to the license header of a source file, made by
+// FixHeaders, or proposed by it with WithDryRun.
+type HeaderChange struct {
+	File   string `json:"file" yaml:"file"`     // The path of the source file
+	Action string `json:"action" yaml:"action"` // HeaderAdded or HeaderNormalized
+	Patch  string `json:"patch" yaml:"patch"`   // The change, in the unified diff format
+}
+
+// WithDryRun makes the functions which change files, such as FixHeaders, only
+// report the changes they would make.
+func WithDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+	}
+}
+
+// SourceHeader returns the license header of a source file declaring an SPDX
+// license expression, with the copyright statement of a holder and year, if
+// the holder is not empty, in the line comments of the language of the file,
+// as read by ScanSourceFile, or block comments for languages without them:
+//
+//	// Copyright 2025 Jane Doe
+//	// SPDX-License-Identifier: MIT
+//
+// Source files of an unknown type return ErrUnknownSourceType.
+func SourceHeader(name, expression, holder string, year int) (string, error) {
+	style, ok := sourceStyle(name)
+	if !ok {
+		return "", ErrUnknownSourceType
+	}
+	expr, err := headerExpression(expression)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(headerLines(style, expr, copyrightStatements(holder, year)), "\n") + "\n", nil
+}
+
+// FixHeaders adds the license header of SourceHeader to the source files of
+// a known type in a directory and its subdirectories which have none,
+// and normalizes deviating headers, which declare another license, or the
+// license without an SPDX-License-Identifier tag. Normalized headers keep
+// their copyright statements. The header of a file is its first comment, after
+// any "#!" line or opening tag such as "<?php", if it declares a license or
+// copyright. Directories are
+// skipped as when scanning recursively. The changes made are returned in the
+// order of the paths of the files, and with WithDryRun, no file is changed:
+// the patches of the changes together make a patch of the tree.
+func FixHeaders(dir, expression, holder string, year int, opts ...Option) ([]*HeaderChange, error) {
+	o := newOptions(opts)
+	expr, err := headerExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := o.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Clean(dir)
+	var changes []*HeaderChange
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := o.ctx.Err(); err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel := relSlash(root, path)
+		if d.IsDir() {
+			if o.skipDir(d.Name()) || o.maxDepth >= 0 && depth(root, path) > o.maxDepth || rules.ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		style, ok := sourceStyle(path)
+		if !ok || !d.Type().IsRegular() || rules.ignored(rel, false) {
+			return nil
+		}
+
+		change, err := fixHeader(path, style, expr, copyrightStatements(holder, year), o.dryRun)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// fixHeader adds or normalizes the license header of a source file, as
+// described by FixHeaders, and returns the change made, if any.
+func fixHeader(path string, style *commentStyle, expr string, copyrights []string, dryRun bool) (*HeaderChange, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	old := strings.Split(string(data), "\n")
+
+	start := 0
+	if style.prolog(strings.TrimSpace(old[0])) {
+		start = 1
+	}
+	n := commentLines(old[start:], style)
+	comment := strings.TrimSpace(extractHeader(strings.Join(old[start:start+n], "\n"), style))
+
+	action := HeaderAdded
+	replaced := 0
+	if declaresLicense(comment) {
+		tags := spdx.FindTags(comment)
+		if len(tags) == 1 && tags[0].Expr != nil && tags[0].Expr.String() == expr {
+			return nil, nil
+		}
+		action, replaced = HeaderNormalized, n
+		if existing := ExtractCopyrights(comment); len(existing) > 0 {
+			copyrights = nil
+			for _, c := range existing {
+				copyrights = append(copyrights, c.Statement)
+			}
+		}
+	}
+
+	header := headerLines(style, expr, copyrights)
+	if replaced == 0 && start+n < len(old) && !(n == 0 && strings.TrimSpace(old[start]) == "") {
+		header = append(header, "")
+	}
+	if strings.HasSuffix(old[0], "\r") {
+		for i := range header {
+			header[i] += "\r"
+		}
+	}
+	updated := append(append(append([]string{}, old[:start]...), header...), old[start+replaced:]...)
+
+	change := &HeaderChange{
+		File:   path,
+		Action: action,
+		Patch:  linePatch(filepath.ToSlash(path), old, updated, start+replaced, start+len(header)),
+	}
+	if !dryRun {
+		if err := ioutil.WriteFile(path, []byte(strings.Join(updated, "\n")), info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+	return change, nil
+}
+
+// headerExpression validates and normalizes the license expression of a
+// header.
+func headerExpression(expression string) (string, error) {
+	e, err := spdx.Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	if err := spdx.Validate(e); err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// copyrightStatements returns the copyright statement of a header for a holder
+// and year, if the holder is not empty.
+func copyrightStatements(holder string, year int) []string {
+	if holder == "" {
+		return nil
+	}
+	if year <= 0 {
+		return []string{"Copyright " + holder}
+	}
+	return []string{"Copyright " + strconv.Itoa(year) + " " + holder}
+}
+
+// headerLines returns the lines of a license header in a comment style, in
+// line comments, or in a block comment per line for languages without them.
+func headerLines(style *commentStyle, expr string, copyrights []string) []string {
+	var lines []string
+	for _, line := range append(append([]string{}, copyrights...), "SPDX-License-Identifier: "+expr) {
+		if len(style.line) == 0 {
+			lines = append(lines, style.blockStart+" "+line+" "+style.blockEnd)
+		} else {
+			lines = append(lines, style.line[0]+" "+line)
+		}
+	}
+	return lines
+}
+
+// commentLines returns the number of lines of the comment which lines start
+// with, if any, up to the first blank line.
+func commentLines(lines []string, style *commentStyle) int {
+	inBlock := false
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			inBlock = !strings.Contains(line, style.blockEnd)
+			continue
+		case line == "":
+			return i
+		case style.blockStart != "" && strings.HasPrefix(line, style.blockStart):
+			inBlock = !strings.Contains(line[len(style.blockStart):], style.blockEnd)
+			continue
+		}
+		comment := false
+		for _, prefix := range style.line {
+			if strings.HasPrefix(line, prefix) {
+				comment = true
+				break
+			}
+		}
+		if !comment {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// declaresLicense determines if the text of a comment declares a license or a
+// copyright.
+func declaresLicense(comment string) bool {
+	if comment == "" {
+		return false
+	}
+	if len(spdx.FindTags(comment)) > 0 || len(ExtractCopyrights(comment)) > 0 {
+		return true
+	}
+	return (&License{Text: comment}).guessHeaderType() == nil
+}
+
+// linePatch formats the change of a file from old to new lines in the unified
+// diff format, given how many of the first lines of each were changed, the
+// rest being the same.
+func linePatch(name string, old, new []string, oldChanged, newChanged int) string {
+	lines := func(ls []string, n int) []diffLine {
+		if n += diffContext; n > len(ls) {
+			n = len(ls)
+		}
+		out := make([]diffLine, n)
+		for i, l := range ls[:n] {
+			out[i] = diffLine{l, l}
+		}
+		return out
+	}
+	return unifiedDiff("a/"+name, "b/"+name, diffLines(lines(old, oldChanged), lines(new, newChanged)))
+}
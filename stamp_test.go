@@ -0,0 +1,109 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestSourceHeader(t *testing.T) {
+	header, err := license.SourceHeader("main.go", "MIT or (Apache-2.0)", "Jane Doe", 2025)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "// Copyright 2025 Jane Doe\n// SPDX-License-Identifier: MIT OR Apache-2.0\n"; header != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, header)
+	}
+
+	// Languages without line comments get a block comment per line
+	header, err = license.SourceHeader("index.html", "MIT", "Jane Doe", 2025)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "<!-- Copyright 2025 Jane Doe -->\n<!-- SPDX-License-Identifier: MIT -->\n"; header != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, header)
+	}
+	header, err = license.SourceHeader("Dockerfile", "MIT", "", 0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "# SPDX-License-Identifier: MIT\n"; header != expected {
+		t.Fatalf("\nexpected: %q\ngot: %q", expected, header)
+	}
+
+	if _, err := license.SourceHeader("README", "MIT", "", 0); err != license.ErrUnknownSourceType {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnknownSourceType, err)
+	}
+	if _, err := license.SourceHeader("main.go", "MIT AND", "", 0); err == nil {
+		t.Fatalf("expected an error for an invalid expression")
+	}
+}
+
+func TestFixHeaders(t *testing.T) {
+	d := t.TempDir()
+	files := map[string]string{
+		"main.go":       "// Package main is the command.\npackage main\n",
+		"ok.go":         "// Copyright 2019 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"other.go":      "// Copyright 2019 Acme Inc.\n// SPDX-License-Identifier: BSD-3-Clause\n\npackage main\n",
+		"run.sh":        "#!/bin/sh\necho hello\n",
+		"README.md":     "# Project\n",
+		"vendor/x/x.go": "package x\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(d, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	changes, err := license.FixHeaders(d, "MIT", "Jane Doe", 2025, license.WithDryRun())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(changes) != 3 || changes[0].Action != license.HeaderAdded || changes[1].Action != license.HeaderNormalized {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	name := filepath.ToSlash(filepath.Join(d, "main.go"))
+	expected := "--- a/" + name + "\n+++ b/" + name + "\n" +
+		"@@ -1,2 +1,5 @@\n" +
+		"+// Copyright 2025 Jane Doe\n" +
+		"+// SPDX-License-Identifier: MIT\n" +
+		"+\n" +
+		" // Package main is the command.\n" +
+		" package main\n"
+	if changes[0].Patch != expected {
+		t.Fatalf("\nexpected: %s\ngot: %s", expected, changes[0].Patch)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(d, "main.go")); string(data) != files["main.go"] {
+		t.Fatalf("expected no change in a dry run, got:\n%s", data)
+	}
+
+	if _, err := license.FixHeaders(d, "MIT", "Jane Doe", 2025); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	updated := map[string]string{
+		"main.go":  "// Copyright 2025 Jane Doe\n// SPDX-License-Identifier: MIT\n\n// Package main is the command.\npackage main\n",
+		"ok.go":    files["ok.go"],
+		"other.go": "// Copyright 2019 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"run.sh":   "#!/bin/sh\n# Copyright 2025 Jane Doe\n# SPDX-License-Identifier: MIT\n\necho hello\n",
+	}
+	for name, data := range updated {
+		b, err := ioutil.ReadFile(filepath.Join(d, name))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(b) != data {
+			t.Fatalf("%s\nexpected: %q\ngot: %q", name, data, b)
+		}
+	}
+
+	if changes, err := license.FixHeaders(d, "MIT", "Jane Doe", 2025); err != nil || len(changes) != 0 {
+		t.Fatalf("unexpected changes: %v (%v)", changes, err)
+	}
+}
@@ -0,0 +1,138 @@
+package license
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrFileBudget is returned for license files which exceed the limits set by
+// WithMaxFileSize and WithMaxFileTime.
+var ErrFileBudget = errors.New("license: license file exceeds its budget")
+
+// DefaultMaxFileSize is the size limit of license files, in bytes, unless
+// WithMaxFileSize sets another. The longest licenses are below 100 KB.
+const DefaultMaxFileSize = 10 << 20
+
+// DetectorStats counts the work done by the scans given it by WithStats, so
+// that servers can monitor the cost of scans. Its counters are updated
+// atomically, and may be read while scans run by Snapshot.
+type DetectorStats struct {
+	Files    int64         `json:"files" yaml:"files"`       // The license files guessed
+	Bytes    int64         `json:"bytes" yaml:"bytes"`       // The bytes of license text guessed
+	Skipped  int64         `json:"skipped" yaml:"skipped"`   // The license files skipped for exceeding their budget
+	Duration time.Duration `json:"duration" yaml:"duration"` // The time spent guessing license types
+}
+
+// Snapshot returns a copy of the counters.
+func (s *DetectorStats) Snapshot() DetectorStats {
+	return DetectorStats{
+		Files:    atomic.LoadInt64(&s.Files),
+		Bytes:    atomic.LoadInt64(&s.Bytes),
+		Skipped:  atomic.LoadInt64(&s.Skipped),
+		Duration: time.Duration(atomic.LoadInt64((*int64)(&s.Duration))),
+	}
+}
+
+// WithStats counts the license files guessed by scans, the bytes of their
+// texts and the time spent guessing them in s, which may be shared by
+// concurrent scans.
+func WithStats(s *DetectorStats) Option {
+	return func(o *options) {
+		o.stats = s
+	}
+}
+
+// WithMaxFileSize skips license files larger than size bytes, which are left
+// out of the results as unreadable files are, so that scans of untrusted
+// content are bounded. The default is DefaultMaxFileSize, and a size of 0
+// removes the limit.
+func WithMaxFileSize(size int64) Option {
+	return func(o *options) {
+		o.maxFileSize = size
+	}
+}
+
+// WithTruncatedFiles guesses the types of license files larger than the size
+// limit from their first bytes, up to the limit, instead of skipping them,
+// for license files with long appendices. Only those bytes are read.
+func WithTruncatedFiles() Option {
+	return func(o *options) {
+		o.truncate = true
+	}
+}
+
+// WithMaxFileTime gives up on guessing the type of a license file once it has
+// taken longer than d, leaving the file out of the results as unreadable files
+// are, so that scans keep to a latency budget. The abandoned guess still runs
+// to completion in the background. A duration of 0 removes the limit.
+func WithMaxFileTime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxFileTime = d
+	}
+}
+
+// exceedsMaxSize determines if a license file read from src is larger than
+// the size limit, when its size is known before reading it, and is not
+// truncated.
+func (o *options) exceedsMaxSize(src *licenseSource, file string) bool {
+	if o.maxFileSize <= 0 || o.truncate || src.stat == nil {
+		return false
+	}
+	info, err := src.stat(file)
+	return err == nil && info.Size() > o.maxFileSize
+}
+
+// budgetedGuess guesses the type of a license text with guess, within the
+// limits of its size and time, and counts the guess in the stats of o.
+func (o *options) budgetedGuess(guess func(*License) error, l *License) error {
+	if o.maxFileSize > 0 && !o.truncate && int64(len(l.Text)) > o.maxFileSize {
+		o.skipFile()
+		return ErrFileBudget
+	}
+
+	start := time.Now()
+	err := o.timedGuess(guess, l)
+	if o.stats != nil {
+		atomic.AddInt64((*int64)(&o.stats.Duration), int64(time.Since(start)))
+	}
+	if err == ErrFileBudget {
+		o.skipFile()
+		return err
+	}
+	if o.stats != nil {
+		atomic.AddInt64(&o.stats.Files, 1)
+		atomic.AddInt64(&o.stats.Bytes, int64(len(l.Text)))
+	}
+	return err
+}
+
+// timedGuess guesses the type of a license text with guess, giving up once
+// the time limit passes.
+func (o *options) timedGuess(guess func(*License) error, l *License) error {
+	if o.maxFileTime <= 0 {
+		return guess(l)
+	}
+
+	g := *l
+	done := make(chan error, 1)
+	go func() {
+		done <- guess(&g)
+	}()
+	timer := time.NewTimer(o.maxFileTime)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		*l = g
+		return err
+	case <-timer.C:
+		return ErrFileBudget
+	}
+}
+
+// skipFile counts a license file skipped for exceeding its budget.
+func (o *options) skipFile() {
+	if o.stats != nil {
+		atomic.AddInt64(&o.stats.Skipped, 1)
+	}
+}
@@ -0,0 +1,122 @@
+package license_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestWithStats(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+	copyFixture(t, "Apache-2.0", filepath.Join(d, "sub", "LICENSE"))
+	if err := ioutil.WriteFile(filepath.Join(d, "sub", "COPYING"), []byte("All rights reserved."), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	stats := new(license.DetectorStats)
+	if _, err := license.NewFromDirRecursive(d, license.WithStats(stats)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	s := stats.Snapshot()
+	if s.Files != 3 || s.Skipped != 0 || s.Duration <= 0 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+	mit, _ := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	apache, _ := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "Apache-2.0"))
+	if expected := int64(len(mit) + len(apache) + len("All rights reserved.")); s.Bytes != expected {
+		t.Fatalf("\nexpected: %d\ngot: %d", expected, s.Bytes)
+	}
+}
+
+func TestWithMaxFileSize(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+	copyFixture(t, "GPL-3.0", filepath.Join(d, "COPYING"))
+
+	stats := new(license.DetectorStats)
+	ls, err := license.NewLicensesFromDirCtx(context.Background(), d, license.WithMaxFileSize(4096), license.WithStats(stats))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+	if s := stats.Snapshot(); s.Files != 1 || s.Skipped != 1 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+
+	// The entries of archives above the limit are not read
+	data := buildTar(t, map[string]string{"LICENSE": "MIT", "COPYING": "GPL-3.0"}, false)
+	results, err := license.NewFromTar(bytes.NewReader(data), license.WithMaxFileSize(4096), license.WithPartialResults())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	found := make(map[string]string)
+	for _, l := range results["."] {
+		found[l.File] = l.Type
+	}
+	if found["LICENSE"] != license.LicenseMIT || found["COPYING"] != license.LicenseUnrecognized {
+		t.Fatalf("unexpected licenses: %v", found)
+	}
+}
+
+func TestWithTruncatedFiles(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	appendix := bytes.Repeat([]byte("Appendix: the list of contributors.\n"), 1000)
+	d := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(d, "LICENSE"), append(mit, appendix...), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	size := int64(len(mit))
+	if _, err := license.NewFromDir(d, license.WithMaxFileSize(size)); err != license.ErrUnrecognizedLicense {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnrecognizedLicense, err)
+	}
+	l, err := license.NewFromDir(d, license.WithMaxFileSize(size), license.WithTruncatedFiles())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l.Type != license.LicenseMIT || int64(len(l.Text)) != size {
+		t.Fatalf("unexpected license: %s (%d bytes)", l.Type, len(l.Text))
+	}
+
+	data := buildTar(t, map[string]string{"LICENSE": "MIT"}, false)
+	results, err := license.NewFromTar(bytes.NewReader(data), license.WithMaxFileSize(size/2), license.WithTruncatedFiles())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if l := results["."][0]; int64(len(l.Text)) != size/2 {
+		t.Fatalf("\nexpected: %d\ngot: %d", size/2, len(l.Text))
+	}
+}
+
+func TestWithMaxFileTime(t *testing.T) {
+	d := t.TempDir()
+	copyFixture(t, "GPL-3.0", filepath.Join(d, "COPYING"))
+
+	stats := new(license.DetectorStats)
+	_, err := license.NewLicensesFromDirCtx(context.Background(), d, license.WithMaxFileTime(time.Nanosecond), license.WithStats(stats))
+	if err != license.ErrUnrecognizedLicense {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnrecognizedLicense, err)
+	}
+	if s := stats.Snapshot(); s.Files != 0 || s.Skipped != 1 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+
+	ls, err := license.NewLicensesFromDirCtx(context.Background(), d, license.WithMaxFileTime(time.Minute))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseGPL30 {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
@@ -0,0 +1,146 @@
+package license
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// The default maximum size of the text held by DetectStream, 1 MiB.
+const DefaultChunkSize = 1 << 20
+
+// StreamMatch is a license found by DetectStream.
+type StreamMatch struct {
+	Type  string `json:"type" yaml:"type"`   // The license type
+	Start int64  `json:"start" yaml:"start"` // The byte offset of the start of the license text
+	End   int64  `json:"end" yaml:"end"`     // The byte offset of the end of the license text
+}
+
+// DetectStream will scan license text which may contain many licenses, such as
+// a bundle of third-party licenses, and call fn with each license as it is
+// found, in order of appearance. The text is split into licenses the way
+// GuessTypes does, but is read from r one line at a time, so only the license
+// being read is held in memory, up to the chunk size set by WithChunkSize.
+// Unlike GuessTypes, every license found is reported, including duplicates.
+//
+// Scanning stops at the first error returned by fn, or when reading fails.
+func DetectStream(r io.Reader, fn func(*StreamMatch) error, opts ...Option) error {
+	o := newOptions(opts)
+	d := &streamDetector{fn: fn, chunk: o.chunk}
+	br := bufio.NewReader(r)
+
+	var offset int64
+	continued := false
+	for {
+		line, err := br.ReadSlice('\n')
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return err
+		}
+		start := offset
+		offset += int64(len(line))
+
+		if err := d.line(string(line), start, continued); err != nil {
+			return err
+		}
+		continued = err == bufio.ErrBufferFull
+		if err == io.EOF {
+			break
+		}
+	}
+	if err := d.endParagraph(); err != nil {
+		return err
+	}
+	return d.endSegment()
+}
+
+// streamDetector holds the paragraph and the segment being read by
+// DetectStream. Segments are formed as done by segmentLicenses.
+type streamDetector struct {
+	fn    func(*StreamMatch) error
+	chunk int
+
+	paragraph          strings.Builder
+	paraStart, paraEnd int64
+	segment            strings.Builder
+	segmentType        string
+	segStart, segEnd   int64
+}
+
+// line adds a line of text, starting at the byte offset start. A continued
+// line is the rest of a line too long to be read at once.
+func (d *streamDetector) line(line string, start int64, continued bool) error {
+	if !continued {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return d.endParagraph()
+		}
+		if separatorRegexp.MatchString(trimmed) {
+			if err := d.endParagraph(); err != nil {
+				return err
+			}
+			return d.endSegment()
+		}
+	}
+	if line == "" {
+		return nil
+	}
+
+	if d.paragraph.Len() == 0 {
+		d.paraStart = start + int64(len(line)-len(strings.TrimLeft(line, " \t")))
+	}
+	d.paragraph.WriteString(line)
+	d.paraEnd = start + int64(len(strings.TrimRight(line, "\r\n")))
+	if d.paragraph.Len() >= d.chunk {
+		return d.endParagraph()
+	}
+	return nil
+}
+
+// endParagraph adds the paragraph read to the segment, after ending the
+// segment if the paragraph is on its own guessed to be a different license.
+func (d *streamDetector) endParagraph() error {
+	if d.paragraph.Len() == 0 {
+		return nil
+	}
+	text := d.paragraph.String()
+	d.paragraph.Reset()
+
+	p := &License{Text: text}
+	if p.GuessType() == nil {
+		if d.segmentType != "" && p.Type != d.segmentType {
+			if err := d.endSegment(); err != nil {
+				return err
+			}
+		}
+		if d.segmentType == "" {
+			d.segmentType = p.Type
+		}
+	}
+
+	if d.segment.Len() == 0 {
+		d.segStart = d.paraStart
+	}
+	d.segment.WriteString(text)
+	d.segment.WriteString("\n")
+	d.segEnd = d.paraEnd
+	if d.segment.Len() >= d.chunk {
+		return d.endSegment()
+	}
+	return nil
+}
+
+// endSegment guesses the type of the segment read, and reports it if it is
+// recognized.
+func (d *streamDetector) endSegment() error {
+	if d.segment.Len() == 0 {
+		return nil
+	}
+	s := &License{Text: d.segment.String()}
+	d.segment.Reset()
+	d.segmentType = ""
+
+	if s.GuessType() != nil {
+		return nil
+	}
+	return d.fn(&StreamMatch{Type: s.Type, Start: d.segStart, End: d.segEnd})
+}
@@ -0,0 +1,75 @@
+package license_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestDetectStream(t *testing.T) {
+	var bundle bytes.Buffer
+	expected := []string{
+		license.LicenseMIT, license.LicenseApache20, license.LicenseBSD3Clause, license.LicenseMIT,
+	}
+	for _, ltype := range expected {
+		data, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		bundle.WriteString("third-party/" + ltype + "\n")
+		bundle.WriteString("--------------------------------------------------\n\n")
+		bundle.Write(data)
+		bundle.WriteString("\n\n")
+	}
+	text := bundle.String()
+
+	for _, size := range []int{license.DefaultChunkSize, 4096} {
+		var matches []*license.StreamMatch
+		err := license.DetectStream(strings.NewReader(text), func(m *license.StreamMatch) error {
+			matches = append(matches, m)
+			return nil
+		}, license.WithChunkSize(size))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if size != license.DefaultChunkSize {
+			// Licenses longer than the chunk size are guessed in parts
+			if len(matches) < len(expected) {
+				t.Fatalf("unexpected matches: %d", len(matches))
+			}
+			continue
+		}
+
+		if len(matches) != len(expected) {
+			t.Fatalf("unexpected matches: %d", len(matches))
+		}
+		for i, m := range matches {
+			if m.Type != expected[i] {
+				t.Fatalf("\nexpected: %s\ngot: %s", expected[i], m.Type)
+			}
+			l := license.New("", text[m.Start:m.End])
+			if err := l.GuessType(); err != nil || l.Type != m.Type {
+				t.Fatalf("unexpected type of matched text: %s (%v)", l.Type, err)
+			}
+			if strings.TrimSpace(text[m.Start:m.End]) != text[m.Start:m.End] {
+				t.Fatalf("unexpected offsets: %d-%d", m.Start, m.End)
+			}
+		}
+	}
+
+	// Stops at the first error
+	stop := errors.New("stop")
+	calls := 0
+	err := license.DetectStream(strings.NewReader(text), func(m *license.StreamMatch) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Fatalf("expected the error of the callback, got: %v (%d calls)", err, calls)
+	}
+}
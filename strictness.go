@@ -0,0 +1,83 @@
+package license
+
+// Strictness is how closely a license text must match a license for the
+// license to be recognized when searching for license files.
+type Strictness int
+
+const (
+	// StrictnessNormal recognizes the phrases identifying a license, as
+	// GuessType does, and additionally texts matching the template of a
+	// license, as done by MatchTemplate. This is the default.
+	StrictnessNormal Strictness = iota
+	// StrictnessStrict only recognizes near-exact copies of the canonical text
+	// of a license: copies of the text, differing only in their copyright
+	// notices, case and whitespace, and texts matching its template.
+	// Modified licenses, licenses with exceptions or riders appended, and
+	// licenses without a canonical text are reported as unrecognized.
+	StrictnessStrict
+	// StrictnessLenient additionally recognizes short declarations of a
+	// license, such as SPDX-License-Identifier tags, license headers like
+	// those read by ScanSourceFile, and the statements and badges of READMEs
+	// read by GuessReadmeType.
+	StrictnessLenient
+)
+
+// WithStrictness sets how closely license files must match a license to be
+// recognized, such as StrictnessStrict for CI which requires unmodified
+// licenses, or StrictnessLenient for exploratory tools.
+func WithStrictness(s Strictness) Option {
+	return func(o *options) {
+		o.strictness = s
+	}
+}
+
+// strictGuess wraps guess to recognize license texts as closely as set by the
+// strictness of o.
+func (o *options) strictGuess(guess func(*License) error) func(*License) error {
+	return func(l *License) error {
+		err := guess(l)
+		switch {
+		case o.strictness == StrictnessStrict:
+			if err != nil {
+				return err
+			}
+			exact, ok := exactType(l.Text)
+			if l.Rider == "" && (ok && exact == l.Type || matchesTemplate(l.Type, l.Text)) {
+				return nil
+			}
+			l.Type, l.Language, l.Rider, l.Reference = "", "", "", false
+			return ErrUnrecognizedLicense
+		case err != ErrUnrecognizedLicense:
+			return err
+		}
+
+		if id, score, tErr := MatchTemplate(l.Text); tErr == nil && score >= 1 {
+			l.Type = id
+			return nil
+		}
+		if o.strictness != StrictnessLenient {
+			return err
+		}
+		header := &License{Text: l.Text}
+		if header.guessHeaderType() == nil {
+			l.Type = header.Type
+			return nil
+		}
+		if licenseType, _, rErr := GuessReadmeType(l.Text); rErr == nil {
+			l.Type = licenseType
+			return nil
+		}
+		return err
+	}
+}
+
+// matchesTemplate determines if a text matches the template of a license
+// type.
+func matchesTemplate(licenseType, text string) bool {
+	for _, t := range loadTemplates() {
+		if t.id == licenseType {
+			return t.match(normalizeWords(prepareText(text), false))
+		}
+	}
+	return false
+}
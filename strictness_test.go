@@ -0,0 +1,96 @@
+package license_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestWithStrictness(t *testing.T) {
+	mit, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "MIT"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	texts := map[string]string{
+		"exact":    "Copyright (c) 2020 Acme\n\n" + string(mit),
+		"modified": string(mit) + "\nThe Software shall be used for Good, not Evil.\n",
+		"header":   "SPDX-License-Identifier: Apache-2.0\n",
+		"badge":    "[![License: MIT](https://img.shields.io/badge/License-MIT-yellow.svg)](LICENSE)\n",
+	}
+	fsys := fstest.MapFS{}
+	for dir, text := range texts {
+		fsys[dir+"/LICENSE"] = &fstest.MapFile{Data: []byte(text)}
+	}
+
+	for _, c := range []struct {
+		strictness license.Strictness
+		expected   map[string]string
+	}{
+		{license.StrictnessStrict, map[string]string{
+			"exact":    license.LicenseMIT,
+			"modified": license.LicenseUnrecognized,
+			"header":   license.LicenseUnrecognized,
+			"badge":    license.LicenseUnrecognized,
+		}},
+		{license.StrictnessNormal, map[string]string{
+			"exact":    license.LicenseMIT,
+			"modified": license.LicenseMIT,
+			"header":   license.LicenseUnrecognized,
+			"badge":    license.LicenseUnrecognized,
+		}},
+		{license.StrictnessLenient, map[string]string{
+			"exact":    license.LicenseMIT,
+			"modified": license.LicenseMIT,
+			"header":   license.LicenseApache20,
+			"badge":    license.LicenseMIT,
+		}},
+	} {
+		s, err := license.NewScanner(license.WithFS(fsys), license.WithPartialResults(), license.WithStrictness(c.strictness))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		results, err := s.FromDirRecursive(context.Background(), ".")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for dir, expected := range c.expected {
+			if ls := results[dir]; len(ls) != 1 || ls[0].Type != expected {
+				t.Fatalf("strictness %d, %s:\nexpected: %s\ngot: %v", c.strictness, dir, expected, ls)
+			}
+		}
+	}
+}
+
+func TestWithStrictness_FullTexts(t *testing.T) {
+	for _, id := range []string{license.LicenseCC010, license.LicenseArtistic20} {
+		text, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", id))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		// Excerpts of the texts are no longer exact copies
+		excerpt := strings.Join(strings.SplitAfter(string(text), "\n")[:20], "")
+		fsys := fstest.MapFS{
+			"full/LICENSE":    {Data: text},
+			"excerpt/LICENSE": {Data: []byte(excerpt)},
+		}
+		s, err := license.NewScanner(license.WithFS(fsys), license.WithPartialResults(), license.WithStrictness(license.StrictnessStrict))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		results, err := s.FromDirRecursive(context.Background(), ".")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if ls := results["full"]; len(ls) != 1 || ls[0].Type != id {
+			t.Fatalf("unexpected licenses of the full text of %s: %v", id, ls)
+		}
+		if ls := results["excerpt"]; len(ls) != 1 || ls[0].Type != license.LicenseUnrecognized {
+			t.Fatalf("unexpected licenses of an excerpt of %s: %v", id, ls)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package license
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedCompression is returned for archives compressed by a format
+// other than gzip.
+var ErrUnsupportedCompression = errors.New("license: unsupported archive compression")
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// NewFromTar will read a tar archive, which may be compressed by gzip, such as
+// a source tarball from a release page, and guess the license type of each
+// license file found in it, without unpacking it to disk. The result maps
+// each directory containing license files, as a slash-separated path relative
+// to the root of the archive, to the licenses found in it. Directories are
+// skipped and the depth is limited as when scanning recursively, and the
+// license files read can be limited by WithPathPatterns. Only regular files
+// are read, so symlinked license files are left out.
+func NewFromTar(r io.Reader, opts ...Option) (map[string][]*License, error) {
+	return NewFromTarCtx(newOptions(opts).ctx, r, opts...)
+}
+
+// NewFromTarCtx is like NewFromTar, but stops with the error of ctx once it is
+// done.
+func NewFromTarCtx(ctx context.Context, r io.Reader, opts ...Option) (map[string][]*License, error) {
+	results, err := guessFromTar(ctx, r, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNoLicenseFile
+	}
+	return results, nil
+}
+
+// tarReader returns a reader of the tar archive in r, which may be compressed
+// by gzip.
+func tarReader(r io.Reader) (*tar.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(zr), nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		return nil, ErrUnsupportedCompression
+	}
+	return tar.NewReader(br), nil
+}
+
+// guessFromTar reads a tar archive, which may be compressed by gzip, and
+// guesses the licenses of the license files found in each of its
+// directories, as described by NewFromTar, but without failing if there are
+// none.
+func guessFromTar(ctx context.Context, r io.Reader, o *options) (map[string][]*License, error) {
+	tr, err := tarReader(r)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := complileLicensePatters(o.licenseFiles())
+	if err != nil {
+		return nil, err
+	}
+	rules, err := o.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	files := make(map[string][]string) // The license files of each directory
+	data := make(map[string][]byte)    // The content of each license file
+	infos := make(map[string]fs.FileInfo)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		name := tarPath(hdr.Name)
+		dir, base := path.Split(name)
+		dir = path.Clean("/" + dir)[1:]
+		if dir == "" {
+			dir = "."
+		}
+		if !o.tarDir(dir) || len(matchLicenseFile(compiled, []string{base})) == 0 || rules.excludes(name) {
+			continue
+		}
+		if ok, err := o.matchPath(name); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		// License files above the size limit are not read, unless truncated
+		var content []byte
+		switch {
+		case o.maxFileSize > 0 && o.truncate:
+			content, err = ioutil.ReadAll(io.LimitReader(tr, o.maxFileSize))
+		case o.maxFileSize <= 0 || hdr.Size <= o.maxFileSize:
+			content, err = ioutil.ReadAll(tr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos[name] = hdr.FileInfo()
+		if _, ok := files[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		if _, ok := data[name]; !ok {
+			files[dir] = append(files[dir], base)
+		}
+		data[name] = content
+	}
+
+	results := make(map[string][]*License)
+	for _, dir := range dirs {
+		src := &licenseSource{
+			join: func(name string) string {
+				return path.Join(dir, name)
+			},
+			read: func(name string) ([]byte, error) {
+				return data[name], nil
+			},
+			stat: func(name string) (fs.FileInfo, error) {
+				return infos[name], nil
+			},
+		}
+		ls, err := guessFromFiles(ctx, files[dir], o, src)
+		switch err {
+		case nil:
+			results[dir] = ls
+		case ErrNoLicenseFile, ErrUnrecognizedLicense:
+		default:
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// tarPath cleans the name of a file in a tar archive into a slash-separated
+// path relative to its root.
+func tarPath(name string) string {
+	name = path.Clean("/" + name)
+	if name == "/" {
+		return "."
+	}
+	return name[1:]
+}
+
+// tarDir determines if the license files of a directory of an archive are
+// searched for, as configured by o.
+func (o *options) tarDir(dir string) bool {
+	if dir == "." {
+		return true
+	}
+	if o.maxDepth >= 0 && slashDepth(".", dir) > o.maxDepth {
+		return false
+	}
+	for _, name := range strings.Split(dir, "/") {
+		if o.skipDir(name) {
+			return false
+		}
+	}
+	return true
+}
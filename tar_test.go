@@ -0,0 +1,96 @@
+package license_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromTar(t *testing.T) {
+	files := map[string]string{
+		"project-1.2.3/LICENSE":               "MIT",
+		"project-1.2.3/main.go":               "package main",
+		"project-1.2.3/third_party/x/COPYING": "GPL-2.0",
+		"project-1.2.3/vendor/y/LICENSE":      "ISC",
+	}
+	for _, compress := range []bool{false, true} {
+		results, err := license.NewFromTar(bytes.NewReader(buildTar(t, files, compress)))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("unexpected results: %v", results)
+		}
+		if ls := results["project-1.2.3"]; len(ls) != 1 || ls[0].Type != license.LicenseMIT || ls[0].File != "project-1.2.3/LICENSE" {
+			t.Fatalf("unexpected licenses: %v", ls)
+		}
+		if ls := results["project-1.2.3/third_party/x"]; len(ls) != 1 || ls[0].Type != license.LicenseGPL20 {
+			t.Fatalf("unexpected licenses: %v", ls)
+		}
+	}
+}
+
+func TestNewFromTar_PathPatterns(t *testing.T) {
+	data := buildTar(t, map[string]string{
+		"project-1.2.3/LICENSE":               "MIT",
+		"project-1.2.3/third_party/x/COPYING": "GPL-2.0",
+		"project-1.2.3/third_party/y/LICENSE": "ISC",
+	}, true)
+
+	results, err := license.NewFromTar(bytes.NewReader(data), license.WithPathPatterns("*/LICENSE"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 1 || len(results["project-1.2.3"]) != 1 {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	results, err = license.NewFromTar(bytes.NewReader(data), license.WithPathPatterns("*/third_party"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 2 || results["project-1.2.3/third_party/y"][0].Type != license.LicenseISC {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	if _, err := license.NewFromTar(bytes.NewReader(data), license.WithPathPatterns("[")); err == nil {
+		t.Fatalf("expected error for invalid pattern")
+	}
+	if _, err := license.NewFromTar(bytes.NewReader(data), license.WithPathPatterns("docs/*")); err != license.ErrNoLicenseFile {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrNoLicenseFile, err)
+	}
+}
+
+func TestNewFromTarCtx_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := buildTar(t, map[string]string{"LICENSE": "MIT"}, false)
+	if _, err := license.NewFromTarCtx(ctx, bytes.NewReader(data)); err != context.Canceled {
+		t.Fatalf("\nexpected: %s\ngot: %v", context.Canceled, err)
+	}
+}
+
+func TestNewFromTar_Zstd(t *testing.T) {
+	data := []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0}
+	if _, err := license.NewFromTar(bytes.NewReader(data)); err != license.ErrUnsupportedCompression {
+		t.Fatalf("\nexpected: %s\ngot: %v", license.ErrUnsupportedCompression, err)
+	}
+}
+
+func BenchmarkNewFromTar(b *testing.B) {
+	files := make(map[string]string)
+	for _, ltype := range license.KnownLicenses {
+		files["project-1.2.3/third_party/"+ltype+"/LICENSE"] = ltype
+	}
+	data := buildTar(b, files, true)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := license.NewFromTar(bytes.NewReader(data)); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package license
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+// The maximum number of words which may stand in for replaceable text, such
+// as the name of the copyright holder, or precede or follow the license text.
+const maxReplaceable = 30
+
+// replaceable marks the position of replaceable text in a template.
+const replaceable = "\x00"
+
+var (
+	copyrightRegexp   = regexp.MustCompile(`^(copyright\s*(\(c\)|©|\d)|\(c\)|©)`)
+	placeholderRegexp = regexp.MustCompile(`<[^<>:@]*>|\[[^\[\]]*\]`)
+
+	// Varietal spellings, as described by the SPDX matching guidelines
+	equivalentWords = map[string]string{
+		"licence":    "license",
+		"licences":   "licenses",
+		"https":      "http",
+		"copyrights": "copyright",
+		"analyse":    "analyze",
+		"authorise":  "authorize",
+		"authorised": "authorized",
+		"organise":   "organize",
+		"organised":  "organized",
+		"favour":     "favor",
+		"behaviour":  "behavior",
+	}
+)
+
+// template is a canonical license text compiled for matching, with the
+// normalizations of the SPDX matching guidelines.
+type template struct {
+	id      string              // The license type
+	runs    [][]string          // Literal words, separated by replaceable text
+	size    int                 // The number of literal words
+	bigrams map[string]struct{} // Word bigrams of the literal words
+}
+
+var (
+	templatesOnce sync.Once
+	templates     []*template
+)
+
+// MatchTemplate will compare text against the canonical text of every license
+// on the SPDX license list which has one, with the normalizations of the SPDX
+// matching guidelines: case, whitespace, punctuation, bullets, quote and hyphen
+// styles, varietal spellings and copyright notices are ignored. Of the text
+// the guidelines let vary, only the title of the license may be left out, and
+// placeholders such as <copyright holders> match up to 30 words of
+// replacement text. Everything else must match the canonical text.
+//
+// If the text matches a template, its license type is returned with a score
+// of 1. Otherwise the license whose template is most similar is returned with
+// a score below 1, and an error is only returned if the text is not similar to
+// any template.
+func MatchTemplate(text string) (id string, score float64, err error) {
+	words := normalizeWords(prepareText(text), false)
+	set := wordBigrams(words)
+
+	for _, t := range loadTemplates() {
+		if t.match(words) {
+			return t.id, 1, nil
+		}
+		if s := dice(set, t.bigrams); s > score {
+			id, score = t.id, s
+		}
+	}
+	if id == "" {
+		return "", 0, ErrUnrecognizedLicense
+	}
+	if score >= 1 {
+		score = 0.99
+	}
+	return id, score, nil
+}
+
+// loadTemplates compiles the templates on first use, longest first so that
+// the most specific template matches.
+func loadTemplates() []*template {
+	templatesOnce.Do(func() {
+		for _, l := range spdx.List() {
+			if l.Text != "" {
+				templates = append(templates, compileTemplate(knownType(l.ID), l.Text))
+			}
+		}
+		sort.SliceStable(templates, func(i, j int) bool {
+			return templates[i].size > templates[j].size
+		})
+	})
+	return templates
+}
+
+// compileTemplate turns a canonical license text into a template. Its title,
+// if any, is omittable, and its placeholders in angle or square brackets are
+// replaceable. The canonical texts are plain text, so the optional and variable
+// text of SPDX templates, marked up as <<beginOptional>> and <<var>>, is not
+// known, and is matched literally.
+func compileTemplate(id, text string) *template {
+	words := normalizeWords(text, true)
+
+	// An initial short paragraph naming the license is its title.
+	if title := strings.SplitN(strings.TrimSpace(text), "\n\n", 2)[0]; len(title) < 80 {
+		if lower := strings.ToLower(title); strings.Contains(lower, "license") ||
+			strings.Contains(lower, "licence") {
+			words = words[len(normalizeWords(title, true)):]
+		}
+	}
+
+	t := &template{id: id, runs: [][]string{nil}}
+	var literal []string
+	for _, word := range words {
+		if word == replaceable {
+			if len(t.runs[len(t.runs)-1]) > 0 {
+				t.runs = append(t.runs, nil)
+			}
+			continue
+		}
+		t.runs[len(t.runs)-1] = append(t.runs[len(t.runs)-1], word)
+		literal = append(literal, word)
+	}
+	t.size, t.bigrams = len(literal), wordBigrams(literal)
+	return t
+}
+
+// match determines if the normalized words match the template. Texts too
+// short or too long to match are rejected without scanning them.
+func (t *template) match(words []string) bool {
+	if len(words) < t.size || len(words) > t.size+(len(t.runs)+1)*maxReplaceable {
+		return false
+	}
+	return matchRuns(t.runs, words)
+}
+
+// matchRuns determines if the literal runs appear in order in words, each
+// preceded by at most maxReplaceable words of replaceable text, and followed
+// by at most maxReplaceable words. The positions where the remaining runs were
+// found not to match are remembered, so that texts repeating the words of a
+// template take time linear in their length, rather than exponential.
+func matchRuns(runs [][]string, words []string) bool {
+	failed := make(map[[2]int]bool)
+	var match func(i, pos int) bool
+	match = func(i, pos int) bool {
+		if i == len(runs) {
+			return len(words)-pos <= maxReplaceable
+		}
+		if failed[[2]int{i, pos}] {
+			return false
+		}
+		run := runs[i]
+		for skip := 0; skip <= maxReplaceable && pos+skip+len(run) <= len(words); skip++ {
+			start := pos + skip
+			if equalWords(words[start:start+len(run)], run) && match(i+1, start+len(run)) {
+				return true
+			}
+		}
+		failed[[2]int{i, pos}] = true
+		return false
+	}
+	return match(0, 0)
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeWords prepares text for matching. Copyright notices are dropped
+// entirely, and all punctuation, including quotes, hyphens and bullets, is
+// ignored. When compiling a template, placeholders are replaced by the
+// replaceable marker.
+func normalizeWords(text string, isTemplate bool) []string {
+	text = strings.ToLower(text)
+
+	var words []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if copyrightRegexp.MatchString(line) || line == "all rights reserved." {
+			if isTemplate {
+				words = append(words, replaceable)
+			}
+			continue
+		}
+		if isTemplate {
+			line = placeholderRegexp.ReplaceAllLiteralString(line, " "+replaceable+" ")
+		}
+		for _, word := range strings.FieldsFunc(line, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != 0
+		}) {
+			if equivalent, ok := equivalentWords[word]; ok {
+				word = equivalent
+			}
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// wordBigrams returns the set of adjacent word pairs.
+func wordBigrams(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = struct{}{}
+	}
+	return set
+}
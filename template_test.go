@@ -0,0 +1,105 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/spdx"
+)
+
+func TestMatchTemplate(t *testing.T) {
+	for _, ltype := range license.KnownLicenses {
+		if l, ok := spdx.Get(ltype); !ok || l.Text == "" {
+			continue
+		}
+		lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		id, score, err := license.MatchTemplate(string(lbytes))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if id != ltype || score != 1 {
+			t.Fatalf("\nexpected: %s\ngot: %s (%f)", ltype, id, score)
+		}
+	}
+}
+
+func TestMatchTemplate_Variations(t *testing.T) {
+	// Rewrapped, with a different title, copyright line, and punctuation
+	text := `MIT License
+
+Copyright © 2014-2016 Jane Doe <jane@example.com>
+Copyright (c) 2017 Example, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+	id, score, err := license.MatchTemplate(text)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != license.LicenseMIT || score != 1 {
+		t.Fatalf("\nexpected: %s\ngot: %s (%f)", license.LicenseMIT, id, score)
+	}
+
+	// Placeholders are replaceable, and bullets may differ
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "BSD-3-Clause"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text = strings.NewReplacer(
+		"<year>, <copyright holder>", "2009 The Go Authors",
+		"<organization>", "Google Inc.",
+		"<COPYRIGHT HOLDER>", "THE GO AUTHORS",
+		"1. ", "* ", "2. ", "* ", "3. ", "* ",
+	).Replace(string(lbytes))
+	id, score, err = license.MatchTemplate(text)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != license.LicenseBSD3Clause || score != 1 {
+		t.Fatalf("\nexpected: %s\ngot: %s (%f)", license.LicenseBSD3Clause, id, score)
+	}
+}
+
+func TestMatchTemplate_Modified(t *testing.T) {
+	// Modified texts do not match, but are reported with their closest license
+	lbytes, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", "ISC"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	text := strings.Replace(string(lbytes), "with or without fee", "for a fee", 1)
+	id, score, err := license.MatchTemplate(text)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != license.LicenseISC || score >= 1 || score < 0.8 {
+		t.Fatalf("\nexpected: %s\ngot: %s (%f)", license.LicenseISC, id, score)
+	}
+
+	// Fails properly if the text does not resemble any license
+	if _, _, err := license.MatchTemplate("Read me"); err == nil {
+		t.Fatalf("expected error matching non-license text")
+	}
+}
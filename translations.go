@@ -0,0 +1,148 @@
+package license
+
+import (
+	"strings"
+	"unicode"
+)
+
+// translation is a translation of a license, identified by phrases which must
+// all be found in the normalized text.
+type translation struct {
+	language    string // The language of the translation, as a BCP 47 tag
+	licenseType string
+	phrases     []string
+}
+
+// Official and common translations of the known licenses. As translations of
+// the GNU licenses have no version in common, they are told apart by the year
+// of their date.
+var translations = []translation{
+	// The GNU licenses, of which unofficial translations are published by
+	// the Free Software Foundation
+	{"de", LicenseGPL20, []string{"gnu allgemeine öffentliche lizenz", "1991"}},
+	{"de", LicenseGPL30, []string{"gnu allgemeine öffentliche lizenz", "2007"}},
+	{"es", LicenseLGPL21, []string{"licencia pública general menor de gnu", "1999"}},
+	{"es", LicenseLGPL30, []string{"licencia pública general reducida de gnu", "2007"}},
+	{"es", LicenseAGPL30, []string{"licencia pública general affero de gnu", "2007"}},
+	{"es", LicenseGPL20, []string{"licencia pública general de gnu", "1991"}},
+	{"es", LicenseGPL30, []string{"licencia pública general de gnu", "2007"}},
+	{"fr", LicenseLGPL21, []string{"licence publique générale limitée gnu", "1999"}},
+	{"fr", LicenseLGPL30, []string{"licence publique générale amoindrie gnu", "2007"}},
+	{"fr", LicenseAGPL30, []string{"licence publique générale affero gnu", "2007"}},
+	{"fr", LicenseGPL20, []string{"licence publique générale gnu", "1991"}},
+	{"fr", LicenseGPL30, []string{"licence publique générale gnu", "2007"}},
+	{"it", LicenseGPL20, []string{"licenza pubblica generica", "gnu", "1991"}},
+	{"it", LicenseGPL30, []string{"licenza pubblica generica", "gnu", "2007"}},
+	{"ja", LicenseLGPL21, []string{"劣等一般公衆利用許諾", "1999"}},
+	{"ja", LicenseLGPL30, []string{"劣等一般公衆利用許諾", "2007"}},
+	{"ja", LicenseAGPL30, []string{"affero", "一般公衆利用許諾", "2007"}},
+	{"ja", LicenseGPL20, []string{"一般公衆利用許諾", "gnu", "1991"}},
+	{"ja", LicenseGPL30, []string{"一般公衆利用許諾", "gnu", "2007"}},
+	{"pt", LicenseGPL20, []string{"licença pública geral", "gnu", "1991"}},
+	{"pt", LicenseGPL30, []string{"licença pública geral", "gnu", "2007"}},
+	{"ru", LicenseGPL20, []string{"стандартная общественная лицензия gnu", "1991"}},
+	{"ru", LicenseGPL30, []string{"стандартная общественная лицензия gnu", "2007"}},
+	{"zh", LicenseLGPL30, []string{"较宽松通用公共许可证", "2007"}},
+	{"zh", LicenseGPL20, []string{"通用公共许可证", "gnu", "1991"}},
+	{"zh", LicenseGPL30, []string{"通用公共许可证", "gnu", "2007"}},
+
+	// The EUPL, which is published by the European Commission in the official
+	// languages of the European Union, is told apart by the copyright
+	// statement of its text, "EUPL © the European Union 2007, 2016" for
+	// version 1.2, and "EUPL © the European Community 2007" for version 1.1,
+	// in the language of the translation. Version 1.1 has no Croatian or Irish
+	// translation.
+	{"bg", LicenseEUPL11, []string{"европейска общност 2007", "eupl"}},
+	{"cs", LicenseEUPL11, []string{"evropské společenství 2007", "eupl"}},
+	{"da", LicenseEUPL11, []string{"europæiske fællesskab 2007", "eupl"}},
+	{"de", LicenseEUPL11, []string{"europäische gemeinschaft 2007", "eupl"}},
+	{"el", LicenseEUPL11, []string{"ευρωπαϊκή κοινότητα 2007", "eupl"}},
+	{"es", LicenseEUPL11, []string{"comunidad europea 2007", "eupl"}},
+	{"et", LicenseEUPL11, []string{"euroopa ühendus 2007", "eupl"}},
+	{"fi", LicenseEUPL11, []string{"euroopan yhteisö 2007", "eupl"}},
+	{"fr", LicenseEUPL11, []string{"communauté européenne 2007", "eupl"}},
+	{"hu", LicenseEUPL11, []string{"európai közösség 2007", "eupl"}},
+	{"it", LicenseEUPL11, []string{"comunità europea 2007", "eupl"}},
+	{"lt", LicenseEUPL11, []string{"europos bendrija 2007", "eupl"}},
+	{"lv", LicenseEUPL11, []string{"eiropas kopiena 2007", "eupl"}},
+	{"mt", LicenseEUPL11, []string{"komunità ewropea 2007", "eupl"}},
+	{"nl", LicenseEUPL11, []string{"europese gemeenschap 2007", "eupl"}},
+	{"pl", LicenseEUPL11, []string{"wspólnota europejska 2007", "eupl"}},
+	{"pt", LicenseEUPL11, []string{"comunidade europeia 2007", "eupl"}},
+	{"ro", LicenseEUPL11, []string{"comunitatea europeană 2007", "eupl"}},
+	{"sk", LicenseEUPL11, []string{"európske spoločenstvo 2007", "eupl"}},
+	{"sl", LicenseEUPL11, []string{"evropska skupnost 2007", "eupl"}},
+	{"sv", LicenseEUPL11, []string{"europeiska gemenskapen 2007", "eupl"}},
+	{"bg", LicenseEUPL12, []string{"европейски съюз 2007, 2016", "eupl"}},
+	{"cs", LicenseEUPL12, []string{"evropská unie 2007, 2016", "eupl"}},
+	{"da", LicenseEUPL12, []string{"europæiske union 2007, 2016", "eupl"}},
+	{"de", LicenseEUPL12, []string{"europäische union 2007, 2016", "eupl"}},
+	{"el", LicenseEUPL12, []string{"ευρωπαϊκή ένωση 2007, 2016", "eupl"}},
+	{"es", LicenseEUPL12, []string{"unión europea 2007, 2016", "eupl"}},
+	{"et", LicenseEUPL12, []string{"euroopa liit 2007, 2016", "eupl"}},
+	{"fi", LicenseEUPL12, []string{"euroopan unioni 2007, 2016", "eupl"}},
+	{"fr", LicenseEUPL12, []string{"union européenne 2007, 2016", "eupl"}},
+	{"ga", LicenseEUPL12, []string{"aontas eorpach 2007, 2016", "eupl"}},
+	{"hr", LicenseEUPL12, []string{"europska unija 2007, 2016", "eupl"}},
+	{"hu", LicenseEUPL12, []string{"európai unió 2007, 2016", "eupl"}},
+	{"it", LicenseEUPL12, []string{"unione europea 2007, 2016", "eupl"}},
+	{"lt", LicenseEUPL12, []string{"europos sąjunga 2007, 2016", "eupl"}},
+	{"lv", LicenseEUPL12, []string{"eiropas savienība 2007, 2016", "eupl"}},
+	{"mt", LicenseEUPL12, []string{"unjoni ewropea 2007, 2016", "eupl"}},
+	{"nl", LicenseEUPL12, []string{"europese unie 2007, 2016", "eupl"}},
+	{"pl", LicenseEUPL12, []string{"unia europejska 2007, 2016", "eupl"}},
+	{"pt", LicenseEUPL12, []string{"união europeia 2007, 2016", "eupl"}},
+	{"ro", LicenseEUPL12, []string{"uniunea europeană 2007, 2016", "eupl"}},
+	{"sk", LicenseEUPL12, []string{"európska únia 2007, 2016", "eupl"}},
+	{"sl", LicenseEUPL12, []string{"evropska unija 2007, 2016", "eupl"}},
+	{"sv", LicenseEUPL12, []string{"europeiska unionen 2007, 2016", "eupl"}},
+
+	// The Japanese translation of the MIT license by the Open Source Group
+	// Japan
+	{"ja", LicenseMIT, []string{"本ソフトウェアおよび関連文書のファイル", "無償で許可します"}},
+}
+
+// guessTranslation returns the translation of a known license the normalized
+// text is written in, if any. Translations may mention other licenses, as the
+// GPL does the LGPL, so the translation whose first phrase, its name, is found
+// first in the text is returned.
+func guessTranslation(comp string) (*translation, bool) {
+	comp = joinCJK(comp)
+	var best *translation
+	first := len(comp)
+	for i := range translations {
+		t := &translations[i]
+		found := true
+		for _, phrase := range t.phrases {
+			if !scan(comp, phrase) {
+				found = false
+				break
+			}
+		}
+		if at := strings.Index(comp, t.phrases[0]); found && at < first {
+			best, first = t, at
+		}
+	}
+	return best, best != nil
+}
+
+// joinCJK removes the spaces between Chinese and Japanese characters, which
+// are not separated by spaces, but are where the text is wrapped.
+func joinCJK(comp string) string {
+	runes := []rune(comp)
+	out := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if r == ' ' && i > 0 && i+1 < len(runes) && isCJK(runes[i-1]) && isCJK(runes[i+1]) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// isCJK determines if r is a Chinese or Japanese character or punctuation
+// mark.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		r >= 0x3000 && r <= 0x303f || r >= 0xff00 && r <= 0xffef
+}
@@ -0,0 +1,65 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestGuessType_Translations(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+		language string
+	}{
+		{"LICENCE PUBLIQUE GÉNÉRALE GNU\nVersion 3, du 29 juin 2007.\n\nCe programme est un logiciel libre...\n" +
+			"utilisez plutôt la Licence Publique Générale Amoindrie GNU.", license.LicenseGPL30, "fr"},
+		{"LICENCE PUBLIQUE GÉNÉRALE AMOINDRIE GNU\nVersion 3, du 29 juin 2007.", license.LicenseLGPL30, "fr"},
+		{"GNU ALLGEMEINE ÖFFENTLICHE LIZENZ\nVersion 2, Juni 1991", license.LicenseGPL20, "de"},
+		{"LICENCIA PÚBLICA GENERAL DE GNU\nVersión 3, 29 de junio de 2007", license.LicenseGPL30, "es"},
+		{"GNU 劣等一般公衆利用許諾書\nバージョン 3, 2007年6月29日", license.LicenseLGPL30, "ja"},
+		{"GNU 一般公衆利用許諾書\nバージョン 3, 2007年6月29日\n\nGNU 劣等一般公衆利用許諾書を使用してください。", license.LicenseGPL30, "ja"},
+		{"LICENCE PUBLIQUE DE L'UNION EUROPÉENNE v. 1.2\nEUPL © l'Union européenne 2007, 2016", license.LicenseEUPL12, "fr"},
+		{"EUROPEAN UNION PUBLIC LICENCE v. 1.2\nEUPL © the European Union 2007, 2016", license.LicenseEUPL12, ""},
+		{"European Union Public Licence V. 1.1\nEUPL © the European Community 2007", license.LicenseEUPL11, ""},
+		{"Lizenz für die öffentliche Nutzung der Europäischen Union v. 1.1\nEUPL © Europäische Gemeinschaft 2007", license.LicenseEUPL11, "de"},
+		{"Licencia Pública de la Unión Europea v. 1.2\nEUPL © Unión Europea 2007, 2016", license.LicenseEUPL12, "es"},
+		{"VEŘEJNÁ LICENCE EVROPSKÉ UNIE v. 1.2\nEUPL © Evropská unie 2007, 2016", license.LicenseEUPL12, "cs"},
+		{"LICENCJA PUBLICZNA UNII EUROPEJSKIEJ v. 1.2\nEUPL © Unia Europejska 2007, 2016", license.LicenseEUPL12, "pl"},
+		{"ΆΔΕΙΑ ΔΗΜΟΣΙΑΣ ΧΡΗΣΗΣ ΤΗΣ ΕΥΡΩΠΑΪΚΗΣ ΈΝΩΣΗΣ v. 1.2\nEUPL © Ευρωπαϊκή Ένωση 2007, 2016", license.LicenseEUPL12, "el"},
+		{"JAVNA LICENCIJA EUROPSKE UNIJE v. 1.2\nEUPL © Europska unija 2007, 2016", license.LicenseEUPL12, "hr"},
+		{"CEADÚNAS POIBLÍ AN AONTAIS EORPAIGH v. 1.2\nEUPL © an tAontas Eorpach 2007, 2016", license.LicenseEUPL12, "ga"},
+		{"EUROPOS SĄJUNGOS VIEŠOJI LICENCIJA v. 1.1\nEUPL © Europos bendrija 2007", license.LicenseEUPL11, "lt"},
+		{"Copyright (c) 2024 Acme\n\n以下に定める条件に従い、本ソフトウェアおよび関連文\n書のファイル（以下「ソフトウェア」）の複製を取得する" +
+			"すべての人に対し、ソフトウェアを無制限に扱うことを無償で許可します。", license.LicenseMIT, "ja"},
+	}
+	for _, test := range tests {
+		l := license.New("", test.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s (%q)", err, test.text)
+		}
+		if l.Type != test.expected || l.Language != test.language {
+			t.Fatalf("\nexpected: %s (%s)\ngot: %s (%s)", test.expected, test.language, l.Type, l.Language)
+		}
+	}
+
+	// Mentions of the European Union and of a version number are not the EUPL
+	for _, text := range []string{
+		"Ten program je financován Evropskou unií, verze 1.2.",
+		"Projekt współfinansowany przez Unię Europejską. Wersja 1.2 programu EUPL-kompatybilna.",
+	} {
+		if l := license.New("", text); l.GuessType() == nil && l.Language != "" {
+			t.Fatalf("unexpected guess: %s (%s) for %q", l.Type, l.Language, text)
+		}
+	}
+
+	// Guessing the type of an English text again clears the language
+	l := license.New("", "LICENCE PUBLIQUE GÉNÉRALE GNU\nVersion 2, juin 1991")
+	if err := l.GuessType(); err != nil || l.Language != "fr" {
+		t.Fatalf("unexpected guess: %s (%s), err: %v", l.Type, l.Language, err)
+	}
+	l.Text = "Permission is hereby granted, free of charge, to any person obtaining a copy of this software"
+	if err := l.GuessType(); err != nil || l.Type != license.LicenseMIT || l.Language != "" {
+		t.Fatalf("unexpected guess: %s (%s), err: %v", l.Type, l.Language, err)
+	}
+}
@@ -0,0 +1,90 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Links to the texts of licenses, on the sites of their stewards, of the Open
+// Source Initiative and of the SPDX license list
+var licenseURLs = []struct {
+	re      *regexp.Regexp
+	license func(m []string) string // The license of the submatches of re, if it is known
+}{
+	{regexp.MustCompile(`(?i)(?:opensource\.org/licenses?|spdx\.org/licenses|choosealicense\.com/licenses)/([\w.+-]+?)(?:-license)?(?:\.html|\.php|\.txt|\.json)?/?(?:[\s)"'#?>\]]|$)`),
+		func(m []string) string { return linkLicense(m[0]) }},
+	{regexp.MustCompile(`(?i)apache\.org/licenses/(LICENSE-\d\.\d)`),
+		func(m []string) string { return linkLicense(m[0]) }},
+	{regexp.MustCompile(`(?i)gnu\.org/licenses/(?:old-licenses/)?((?:a|l)?gpl-\d\.\d|fdl-\d\.\d)`),
+		func(m []string) string { return linkLicense(m[0]) }},
+	{regexp.MustCompile(`(?i)gnu\.org/licenses/((?:a|l)?gpl|fdl)\.(?:html|txt)`),
+		func(m []string) string { return linkLicense(m[0]) }},
+	{regexp.MustCompile(`(?i)creativecommons\.org/licenses/(by(?:-nc)?(?:-nd|-sa)?)/(\d\.\d)`),
+		func(m []string) string { return knownLicenseName("CC-" + m[0] + "-" + m[1]) }},
+	{regexp.MustCompile(`(?i)creativecommons\.org/publicdomain/zero/(1\.0)`),
+		func(m []string) string { return LicenseCC010 }},
+	{regexp.MustCompile(`(?i)mozilla\.org/(?:en-US/)?MPL/(\d\.\d)`),
+		func(m []string) string { return knownLicenseName("MPL-" + m[0]) }},
+	{regexp.MustCompile(`(?i)eclipse\.org/legal/epl-v?(\d)\.?(\d)`),
+		func(m []string) string { return knownLicenseName("EPL-" + m[0] + "." + m[1]) }},
+	{regexp.MustCompile(`(?i)boost\.org/LICENSE_(1_0)`),
+		func(m []string) string { return LicenseBSL10 }},
+}
+
+// Last elements of links which do not name their license by its identifier
+var licenseURLAliases = map[string]string{
+	"license-2.0": "Apache-2.0",
+	"bsd":         "BSD-3-Clause",
+
+	"license-1.0": "Apache-1.0",
+	"license-1.1": "Apache-1.1",
+	"gpl":         "GPL-3.0",
+	"lgpl":        "LGPL-3.0",
+	"agpl":        "AGPL-3.0",
+	"fdl":         "GFDL-1.3",
+	"fdl-1.1":     "GFDL-1.1",
+	"fdl-1.2":     "GFDL-1.2",
+	"fdl-1.3":     "GFDL-1.3",
+}
+
+// LicenseFromURL returns the SPDX license identifier of the license a URL
+// refers to, such as MIT for https://opensource.org/licenses/MIT, or
+// ErrUnrecognizedLicense if the URL is not a known link to a license. Links to
+// the texts of licenses on opensource.org, spdx.org, gnu.org, apache.org,
+// creativecommons.org and the sites of other license stewards are known.
+func LicenseFromURL(u string) (string, error) {
+	if licenseType, _, ok := referencedType(u); ok {
+		return licenseType, nil
+	}
+	return "", ErrUnrecognizedLicense
+}
+
+// referencedType returns the license of the first known link to a license in
+// a text, along with the link as matched, if there is one.
+func referencedType(text string) (string, string, bool) {
+	licenseType, link, start := "", "", len(text)+1
+	for _, u := range licenseURLs {
+		for _, m := range u.re.FindAllStringSubmatchIndex(text, -1) {
+			if m[0] >= start {
+				break
+			}
+			groups := make([]string, 0, len(m)/2-1)
+			for i := 2; i < len(m); i += 2 {
+				groups = append(groups, text[m[i]:m[i+1]])
+			}
+			if id := u.license(groups); id != "" {
+				licenseType, link, start = id, text[m[0]:m[len(m)-1]], m[0]
+				break
+			}
+		}
+	}
+	return licenseType, link, licenseType != ""
+}
+
+// linkLicense returns the license of the last element of a link to it.
+func linkLicense(name string) string {
+	if id, ok := licenseURLAliases[strings.ToLower(name)]; ok {
+		return id
+	}
+	return knownLicenseName(name)
+}
@@ -0,0 +1,80 @@
+package license_test
+
+import (
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestLicenseFromURL(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected string
+	}{
+		{"https://opensource.org/licenses/MIT", license.LicenseMIT},
+		{"https://opensource.org/license/bsd-2-clause/", license.LicenseBSD2Clause},
+		{"https://spdx.org/licenses/MPL-2.0.html", license.LicenseMPL20},
+		{"http://www.apache.org/licenses/LICENSE-2.0.txt", license.LicenseApache20},
+		{"https://www.gnu.org/licenses/old-licenses/gpl-2.0.html", "GPL-2.0"},
+		{"https://www.gnu.org/licenses/agpl.html", "AGPL-3.0"},
+		{"https://www.gnu.org/licenses/fdl-1.3.html", license.LicenseGFDL13},
+		{"https://creativecommons.org/licenses/by-sa/4.0/", license.LicenseCCBYSA40},
+		{"https://creativecommons.org/publicdomain/zero/1.0/", license.LicenseCC010},
+		{"https://mozilla.org/MPL/2.0/", license.LicenseMPL20},
+		{"https://www.eclipse.org/legal/epl-v10.html", license.LicenseEPL10},
+		{"https://www.boost.org/LICENSE_1_0.txt", license.LicenseBSL10},
+	}
+	for _, c := range cases {
+		id, err := license.LicenseFromURL(c.url)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.url, err)
+		}
+		if id != c.expected {
+			t.Fatalf("%s:\nexpected: %s\ngot: %s", c.url, c.expected, id)
+		}
+	}
+
+	for _, u := range []string{"https://opensource.org/licenses/NoSuchLicense", "https://github.com/example/project/blob/main/LICENSE"} {
+		if _, err := license.LicenseFromURL(u); err != license.ErrUnrecognizedLicense {
+			t.Fatalf("%s:\nexpected: %s\ngot: %v", u, license.ErrUnrecognizedLicense, err)
+		}
+	}
+}
+
+func TestGuessType_Reference(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"Licensed under https://opensource.org/licenses/MIT", license.LicenseMIT},
+		{"This work is licensed under [CC BY 4.0](https://creativecommons.org/licenses/by/4.0/).", license.LicenseCCBY40},
+		{"Copyright 2020 Jane Doe\n\nSee <http://www.apache.org/licenses/LICENSE-2.0> for the terms.", license.LicenseApache20},
+		{"\"Commons Clause\" License Condition v1.0\n\nthe right to Sell the Software.\n\nLicense: https://spdx.org/licenses/MIT.html",
+			license.LicenseMIT + " AND " + license.LicenseCommonsClause},
+	}
+	for _, c := range cases {
+		l := license.New("", c.text)
+		if err := l.GuessType(); err != nil {
+			t.Fatalf("err: %s (%q)", err, c.text)
+		}
+		if l.Type != c.expected || !l.Reference {
+			t.Fatalf("\nexpected: %s (reference)\ngot: %s (reference: %t)", c.expected, l.Type, l.Reference)
+		}
+	}
+
+	// References are explained by the link
+	e := license.New("", "Licensed under https://opensource.org/licenses/MIT").Explain()
+	if e.Method != license.MethodReference || len(e.Matches) != 1 || e.Matches[0].Phrase != "opensource.org/licenses/mit" {
+		t.Fatalf("\nexpected: %s\ngot: %s %v", license.MethodReference, e.Method, e.Matches)
+	}
+
+	// License texts are not references, even if they link to others
+	mit := license.New("", "Permission is hereby granted, free of charge, to any person obtaining a copy of this software. "+
+		"See also https://www.apache.org/licenses/LICENSE-2.0")
+	if err := mit.GuessType(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if mit.Type != license.LicenseMIT || mit.Reference {
+		t.Fatalf("\nexpected: %s\ngot: %s (reference: %t)", license.LicenseMIT, mit.Type, mit.Reference)
+	}
+}
@@ -0,0 +1,103 @@
+package license
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewFromVendorDir will read the vendor/modules.txt manifest written by
+// "go mod vendor" in the given vendor directory, and search the directory of
+// each vendored module for license files. The result maps each module, as
+// "path@version", to the licenses found for it. Modules without a version,
+// such as those replaced by a local directory, are keyed by path alone, and
+// modules without a license file map to no licenses.
+func NewFromVendorDir(dir string, opts ...Option) (map[string][]*License, error) {
+	return NewFromVendorDirCtx(newOptions(opts).ctx, dir, opts...)
+}
+
+// NewFromVendorDirCtx is like NewFromVendorDir, but stops with the error of ctx
+// once it is done.
+func NewFromVendorDirCtx(ctx context.Context, dir string, opts ...Option) (map[string][]*License, error) {
+	o := newOptions(opts)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "modules.txt"))
+	if err != nil {
+		return nil, err
+	}
+	modules, err := parseVendorModules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]*License, len(modules))
+	for _, m := range modules {
+		ls, err := guessFromDir(ctx, filepath.Join(dir, filepath.FromSlash(m.path)), o)
+		switch {
+		case err == nil:
+		case err == ErrNoLicenseFile, err == ErrUnrecognizedLicense, os.IsNotExist(err):
+			ls = nil
+		default:
+			return nil, err
+		}
+		results[m.key()] = ls
+	}
+	return results, nil
+}
+
+// vendorModule is a module listed by vendor/modules.txt.
+type vendorModule struct {
+	path    string
+	version string
+}
+
+func (m vendorModule) key() string {
+	if m.version == "" {
+		return m.path
+	}
+	return m.path + "@" + m.version
+}
+
+// parseVendorModules reads the module lines of vendor/modules.txt, such as:
+//
+//	# golang.org/x/text v0.3.7
+//	# example.com/fork v1.0.0 => example.com/other v1.1.0
+//	# example.com/local => ../local
+//
+// Package lines, and "##" annotations, are ignored.
+func parseVendorModules(data []byte) ([]vendorModule, error) {
+	var modules []vendorModule
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(line[2:])
+		if i := indexOf(fields, "=>"); i >= 0 {
+			fields = fields[:i]
+		}
+		switch len(fields) {
+		case 1:
+			modules = append(modules, vendorModule{path: fields[0]})
+		case 2:
+			modules = append(modules, vendorModule{path: fields[0], version: fields[1]})
+		default:
+			return nil, fmt.Errorf("license: invalid vendor/modules.txt: line %d: %q", n, line)
+		}
+	}
+	return modules, s.Err()
+}
+
+func indexOf(fields []string, s string) int {
+	for i, f := range fields {
+		if f == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestNewFromVendorDir(t *testing.T) {
+	d, err := ioutil.TempDir("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(d)
+
+	manifest := `# example.com/a v1.0.0
+## explicit; go 1.16
+example.com/a
+example.com/a/sub
+# example.com/b v0.2.0 => example.com/fork v0.2.1
+## explicit
+example.com/b
+# example.com/local => ../local
+example.com/local
+# example.com/none v1.1.0
+example.com/none
+`
+	if err := ioutil.WriteFile(filepath.Join(d, "modules.txt"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	copyFixture(t, license.LicenseMIT, filepath.Join(d, "example.com", "a", "LICENSE"))
+	copyFixture(t, license.LicenseGPL30, filepath.Join(d, "example.com", "a", "sub", "COPYING"))
+	copyFixture(t, license.LicenseApache20, filepath.Join(d, "example.com", "b", "LICENSE"))
+	copyFixture(t, license.LicenseISC, filepath.Join(d, "example.com", "local", "LICENSE"))
+
+	results, err := license.NewFromVendorDir(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	types := make(map[string][]string)
+	for module, ls := range results {
+		types[module] = []string{}
+		for _, l := range ls {
+			types[module] = append(types[module], l.Type)
+		}
+	}
+	expected := map[string][]string{
+		"example.com/a@v1.0.0":    {license.LicenseMIT},
+		"example.com/b@v0.2.0":    {license.LicenseApache20},
+		"example.com/local":       {license.LicenseISC},
+		"example.com/none@v1.1.0": {},
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, types)
+	}
+
+	// Fails properly without a manifest, or with an invalid one
+	if _, err := license.NewFromVendorDir(filepath.Join(d, "example.com")); err == nil {
+		t.Fatalf("expected error reading missing vendor/modules.txt")
+	}
+	if err := ioutil.WriteFile(filepath.Join(d, "modules.txt"), []byte("# a b c\n"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := license.NewFromVendorDir(d); err == nil {
+		t.Fatalf("expected error reading invalid vendor/modules.txt")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package watch
+
+import (
+	"os"
+	"syscall"
+)
+
+// The events of the directories of a tree which may change its licenses
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF | syscall.IN_ONLYDIR
+
+// inotify watches the directories of a tree with the inotify API of Linux.
+type inotify struct {
+	file *os.File
+	c    chan struct{}
+}
+
+// systemNotifier returns an inotify instance, which is read until it is
+// closed.
+func systemNotifier() (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// The descriptor is non-blocking, so that closing the file ends reads
+	n := &inotify{file: os.NewFile(uintptr(fd), "inotify"), c: make(chan struct{}, 1)}
+	go n.read()
+	return n, nil
+}
+
+// read signals every change until the instance is closed. The events are not
+// parsed, since any of them calls for a scan of the tree.
+func (n *inotify) read() {
+	defer close(n.c)
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		if _, err := n.file.Read(buf); err != nil {
+			return
+		}
+		select {
+		case n.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// watch watches the directories. Watching a directory again has no effect,
+// and the directories which cannot be watched are left to the scans at the
+// interval.
+func (n *inotify) watch(dirs []string) {
+	rc, err := n.file.SyscallConn()
+	if err != nil {
+		return
+	}
+	rc.Control(func(fd uintptr) {
+		for _, dir := range dirs {
+			syscall.InotifyAddWatch(int(fd), dir, inotifyMask)
+		}
+	})
+}
+
+// changes returns the channel signaled once the tree changed.
+func (n *inotify) changes() <-chan struct{} {
+	return n.c
+}
+
+// close stops watching the tree.
+func (n *inotify) close() error {
+	return n.file.Close()
+}
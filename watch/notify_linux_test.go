@@ -0,0 +1,49 @@
+//go:build linux
+
+package watch_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/watch"
+)
+
+func TestWatch_Notifications(t *testing.T) {
+	d := t.TempDir()
+	writeFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+
+	// The interval is never reached, so changes are noticed by inotify alone
+	w, err := watch.New(d, time.Hour)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := w.Watch(ctx)
+
+	if e := next(t, events); e.Dir != d || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	writeFixture(t, "ISC", filepath.Join(d, "LICENSE"))
+	if e := next(t, events); e.Dir != d || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseISC {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	// Directories created after the watch started are watched too
+	sub := filepath.Join(d, "sub", "pkg")
+	writeFixture(t, "Apache-2.0", filepath.Join(sub, "COPYING"))
+	if e := next(t, events); e.Dir != sub || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseApache20 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	writeFixture(t, "MIT", filepath.Join(sub, "COPYING"))
+	if e := next(t, events); e.Dir != sub || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	cancel()
+	for range events {
+	}
+}
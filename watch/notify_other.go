@@ -0,0 +1,11 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// systemNotifier fails, since file system notifications are only used on
+// Linux, and trees are scanned at the interval elsewhere.
+func systemNotifier() (notifier, error) {
+	return nil, errors.New("watch: file system notifications are not supported")
+}
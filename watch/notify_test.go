@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// fakeNotifier records the directories watched, and is signaled by the test.
+type fakeNotifier struct {
+	mu   sync.Mutex
+	dirs []string
+	c    chan struct{}
+}
+
+func (n *fakeNotifier) watch(dirs []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.dirs = append(n.dirs[:0], dirs...)
+}
+
+func (n *fakeNotifier) changes() <-chan struct{} {
+	return n.c
+}
+
+func (n *fakeNotifier) close() error {
+	return nil
+}
+
+func (n *fakeNotifier) watched() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string{}, n.dirs...)
+}
+
+func useFakeNotifier(t *testing.T) *fakeNotifier {
+	n := &fakeNotifier{c: make(chan struct{}, 1)}
+	newNotifier = func() (notifier, error) { return n, nil }
+	t.Cleanup(func() { newNotifier = systemNotifier })
+	return n
+}
+
+func writeLicense(t *testing.T, ltype, path string) {
+	text, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "licenses", ltype))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, text, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatalf("unexpected end of events")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for an event")
+	}
+	return Event{}
+}
+
+func TestWatch_NotifierClosed(t *testing.T) {
+	n := useFakeNotifier(t)
+	d := t.TempDir()
+	writeLicense(t, "MIT", filepath.Join(d, "LICENSE"))
+
+	w, err := New(d, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := w.Watch(ctx)
+	if e := nextEvent(t, events); e.Dir != d {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	// The notifier closing while the tree settles leaves the interval scans
+	n.c <- struct{}{}
+	close(n.c)
+	writeLicense(t, "ISC", filepath.Join(d, "LICENSE"))
+	if e := nextEvent(t, events); e.Dir != d || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseISC {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestWatch_SkippedDirs(t *testing.T) {
+	n := useFakeNotifier(t)
+	d := t.TempDir()
+	writeLicense(t, "MIT", filepath.Join(d, "LICENSE"))
+	for _, dir := range []string{"src", "build", "testdata", "node_modules"} {
+		if err := os.MkdirAll(filepath.Join(d, dir), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	w, err := New(d, time.Hour, license.WithSkipDirs("build"), license.WithIgnorePatterns("testdata/"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	nextEvent(t, w.Watch(ctx))
+
+	// Only the directories the scans search are watched, which include
+	// node_modules once WithSkipDirs replaces the default ones
+	watched := make(map[string]bool)
+	for _, dir := range n.watched() {
+		watched[dir] = true
+	}
+	if len(watched) != 3 || !watched[d] || !watched[filepath.Join(d, "src")] || !watched[filepath.Join(d, "node_modules")] {
+		t.Fatalf("unexpected watched directories: %v", n.watched())
+	}
+}
@@ -0,0 +1,191 @@
+// Package watch monitors a directory tree for changes to its licenses, for
+// build daemons and editors which show the license status of a project live.
+// On Linux, trees are watched with inotify, and scanned again as soon as they
+// change. Elsewhere, trees are scanned at an interval. The interval scans also
+// catch the changes that notifications miss, such as those on network file
+// systems. The package uses the standard syscall package rather than
+// fsnotify, so that it has no dependencies beyond those of go-license:
+//
+//	w, err := watch.New(".", 2*time.Second)
+//	for e := range w.Watch(ctx) {
+//		fmt.Println(e.Dir, e.Licenses, e.Err)
+//	}
+package watch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// The number of license texts whose guesses are cached between scans.
+const cacheSize = 1024
+
+// How long a tree must be quiet after a change before it is scanned again, so
+// that a burst of changes, such as a checkout, is scanned once
+const settle = 50 * time.Millisecond
+
+// ErrInvalidInterval is returned by New for intervals which are not positive.
+var ErrInvalidInterval = errors.New("watch: interval must be positive")
+
+// Event reports the licenses of a directory once they change.
+type Event struct {
+	Dir      string             // The directory whose licenses changed
+	Licenses []*license.License // The licenses now found in the directory, or none if they were removed
+	Err      error              // The error scanning the tree, if the scan failed
+}
+
+// Watcher scans a directory tree recursively once it changes, or at an
+// interval, and reports the directories whose licenses changed between scans.
+type Watcher struct {
+	dir      string
+	interval time.Duration
+	scanner  *license.Scanner
+}
+
+// New creates a Watcher of the tree at dir, scanned with the given options, as
+// done by license.Scanner.FromDirRecursive, once it changes where file system
+// notifications are supported, and at least every interval. Guesses are
+// cached between scans, unless a cache is given by license.WithGuessCache, so
+// that only changed license texts are guessed again.
+func New(dir string, interval time.Duration, opts ...license.Option) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	opts = append([]license.Option{license.WithGuessCache(license.NewLRUCache(cacheSize, nil))}, opts...)
+	s, err := license.NewScanner(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{dir: dir, interval: interval, scanner: s}, nil
+}
+
+// Watch scans the tree until ctx is done, and sends an event on the channel
+// returned for every directory whose licenses changed since the previous
+// scan. The first scan reports every directory with licenses. A failed scan
+// is reported by an event for the tree with its error, and the tree is
+// scanned again once it changes or at the next interval. The channel is
+// closed once ctx is done.
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		var changed <-chan struct{}
+		n, err := newNotifier()
+		if err == nil {
+			defer n.close()
+			changed = n.changes()
+		}
+
+		var prev map[string][]*license.License
+		for {
+			// Directories are watched before the scan, so that the changes
+			// made during it are not missed. They are those the scan
+			// searches, without those it skips.
+			if changed != nil {
+				if dirs, err := w.scanner.Dirs(ctx, w.dir); err == nil {
+					n.watch(dirs)
+				}
+			}
+			cur, err := w.scanner.FromDirRecursive(ctx, w.dir)
+			if err == license.ErrNoLicenseFile {
+				cur, err = nil, nil
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			var diff []Event
+			if err != nil {
+				diff = []Event{{Dir: w.dir, Err: err}}
+			} else {
+				diff = changes(prev, cur)
+				prev = cur
+			}
+			for _, e := range diff {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case _, ok := <-changed:
+				if !ok {
+					changed = nil
+					continue
+				}
+				if !wait(ctx, changed) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// notifier watches the directories of a tree with file system notifications.
+type notifier interface {
+	watch(dirs []string)      // Watches the directories which are not watched yet
+	changes() <-chan struct{} // Signaled once the tree changed, and closed if it can no longer be watched
+	close() error             // Stops watching the tree
+}
+
+// newNotifier creates the notifier of the platform, if it has one.
+var newNotifier = systemNotifier
+
+// wait waits until the tree has been quiet for a while after a change, and
+// reports whether ctx is still going. It returns early if the tree can no
+// longer be watched.
+func wait(ctx context.Context, changed <-chan struct{}) bool {
+	timer := time.NewTimer(settle)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-changed:
+			if !ok {
+				return ctx.Err() == nil
+			}
+			timer.Reset(settle)
+		case <-timer.C:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// changes returns an event for each directory whose licenses differ between
+// two scans, ordered by directory.
+func changes(prev, cur map[string][]*license.License) []Event {
+	diff := license.CompareReports(license.NewReport(prev), license.NewReport(cur))
+	dirs := make(map[string]bool)
+	for _, result := range diff.Added {
+		dirs[result.Dir] = true
+	}
+	for _, result := range diff.Removed {
+		dirs[result.Dir] = true
+	}
+	for _, change := range diff.Changed {
+		dirs[change.New.Dir] = true
+	}
+
+	events := make([]Event, 0, len(dirs))
+	for dir := range dirs {
+		events = append(events, Event{Dir: dir, Licenses: cur[dir]})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Dir < events[j].Dir
+	})
+	return events
+}
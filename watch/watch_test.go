@@ -0,0 +1,85 @@
+package watch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	license "github.com/nfukasawa/go-license"
+	"github.com/nfukasawa/go-license/watch"
+)
+
+func writeFixture(t *testing.T, ltype, path string) {
+	text, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "licenses", ltype))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, text, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func next(t *testing.T, events <-chan watch.Event) watch.Event {
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatalf("unexpected end of events")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for an event")
+	}
+	return watch.Event{}
+}
+
+func TestWatch(t *testing.T) {
+	d := t.TempDir()
+	writeFixture(t, "MIT", filepath.Join(d, "LICENSE"))
+
+	w, err := watch.New(d, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := w.Watch(ctx)
+
+	// The first scan reports the licenses found
+	if e := next(t, events); e.Dir != d || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseMIT {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	// Changed and added licenses are reported
+	writeFixture(t, "ISC", filepath.Join(d, "LICENSE"))
+	if e := next(t, events); e.Dir != d || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseISC {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	sub := filepath.Join(d, "sub")
+	writeFixture(t, "Apache-2.0", filepath.Join(sub, "COPYING"))
+	if e := next(t, events); e.Dir != sub || len(e.Licenses) != 1 || e.Licenses[0].Type != license.LicenseApache20 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	// Removed licenses are reported without any
+	if err := os.RemoveAll(sub); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if e := next(t, events); e.Dir != sub || len(e.Licenses) != 0 || e.Err != nil {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestNew_Interval(t *testing.T) {
+	if _, err := watch.New(".", 0); err != watch.ErrInvalidInterval {
+		t.Fatalf("\nexpected: %s\ngot: %v", watch.ErrInvalidInterval, err)
+	}
+}
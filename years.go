@@ -0,0 +1,163 @@
+package license
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nfukasawa/go-license/internal/charset"
+)
+
+// UpdateCopyrightText extends the years of the copyright statements of a text
+// up to year, such as "Copyright (c) 2019 Jane Doe" to "2019-2025", or
+// "Copyright 2014-2016, 2018 Jane Doe" to "2014-2016, 2018-2025", and reports
+// whether it changed. The rest of the text is kept as it is, as are
+// statements whose years already reach year or end in "present", and those of
+// license templates with placeholders.
+func UpdateCopyrightText(text string, year int) (string, bool) {
+	lines := strings.Split(text, "\n")
+	changed := updateCopyrightLines(lines, year)
+	return strings.Join(lines, "\n"), changed
+}
+
+// UpdateCopyrightYear extends the years of the copyright statements of a file
+// up to year, as done by UpdateCopyrightText, and reports whether it changed.
+// In source files of a known type, as read by ScanSourceFile, only the
+// statements of the header are updated. Files which are not UTF-8 are left as
+// they are, and binary files fail with ErrBinaryFile.
+func UpdateCopyrightYear(path string, year int) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if charset.Binary(data) {
+		return false, ErrBinaryFile
+	}
+	if !utf8.Valid(data) {
+		return false, nil
+	}
+
+	text := string(data)
+	lines := strings.Split(text, "\n")
+	header := lines
+	if style, ok := sourceStyle(path); ok {
+		_, n := splitHeader(text, style)
+		header = lines[:n]
+	}
+	if !updateCopyrightLines(header, year) {
+		return false, nil
+	}
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateCopyrightYears extends the years of the copyright statements of the
+// license files and the headers of the source files in a directory and its
+// subdirectories up to year, as done by UpdateCopyrightYear, and returns the
+// paths of the files changed. Directories are skipped as when scanning
+// recursively, and license files are found by the patterns of
+// WithFilePatterns. Binary license files are left out.
+func UpdateCopyrightYears(dir string, year int, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	compiled, err := complileLicensePatters(o.licenseFiles())
+	if err != nil {
+		return nil, err
+	}
+	rules, err := o.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Clean(dir)
+	var changed []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := o.ctx.Err(); err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel := relSlash(root, path)
+		if d.IsDir() {
+			if o.skipDir(d.Name()) || o.maxDepth >= 0 && depth(root, path) > o.maxDepth || rules.ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		_, source := sourceStyle(path)
+		if !d.Type().IsRegular() || rules.ignored(rel, false) ||
+			!source && len(matchLicenseFile(compiled, []string{d.Name()})) == 0 {
+			return nil
+		}
+
+		ok, err := UpdateCopyrightYear(path, year)
+		switch {
+		case err == ErrBinaryFile:
+		case err != nil:
+			return err
+		case ok:
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// updateCopyrightLines extends the years of the copyright statements of lines
+// up to year, in place, and reports whether any changed.
+func updateCopyrightLines(lines []string, year int) bool {
+	changed := false
+	for i, line := range lines {
+		if updated, ok := updateCopyrightLine(line, year); ok {
+			lines[i], changed = updated, true
+		}
+	}
+	return changed
+}
+
+// updateCopyrightLine extends the last year or year range of the copyright
+// statement of a line up to year, keeping two-digit range ends as such.
+func updateCopyrightLine(line string, year int) (string, bool) {
+	_, offset, ok := parseCopyright(line)
+	if !ok || offset < 0 {
+		return line, false
+	}
+	years := strings.TrimRight(copyrightYearsRegexp.FindString(line[offset:]), " \t,")
+	ms := copyrightYearRegexp.FindAllStringSubmatchIndex(years, -1)
+	if len(ms) == 0 {
+		return line, false
+	}
+	m := ms[len(ms)-1]
+	r := parseYears(years[m[0]:m[1]])[0]
+	if r.To == 0 || r.To >= year {
+		return line, false
+	}
+
+	var updated string
+	switch {
+	case m[4] < 0:
+		updated = years[:m[1]] + "-" + strconv.Itoa(year)
+	case m[5]-m[4] == 2 && year/100 == r.From/100:
+		updated = years[:m[4]] + fmt.Sprintf("%02d", year%100)
+	default:
+		updated = years[:m[4]] + strconv.Itoa(year)
+	}
+	return line[:offset] + updated + years[m[1]:] + line[offset+len(years):], true
+}
@@ -0,0 +1,79 @@
+package license_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+func TestUpdateCopyrightText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Copyright (c) 2019 Jane Doe", "Copyright (c) 2019-2025 Jane Doe"},
+		{"Copyright 2014-2016, 2018 Jane Doe", "Copyright 2014-2016, 2018-2025 Jane Doe"},
+		{"Copyright © 2009 – 2024 The Go Authors. All rights reserved.", "Copyright © 2009 – 2025 The Go Authors. All rights reserved."},
+		{" * (C) 2019-24 by John Smith", " * (C) 2019-25 by John Smith"},
+		{" * (C) 1999-02 by John Smith", " * (C) 1999-2025 by John Smith"},
+		{"Copyright 2015-present Example, Inc.", "Copyright 2015-present Example, Inc."},
+		{"Copyright (c) 2025 Jane Doe", "Copyright (c) 2025 Jane Doe"},
+		{"Copyright (c) <year> <copyright holders>", "Copyright (c) <year> <copyright holders>"},
+		{"Portions copyright Acme Corp.", "Portions copyright Acme Corp."},
+	}
+	for _, test := range tests {
+		text, changed := license.UpdateCopyrightText(test.text, 2025)
+		if text != test.expected || changed != (test.text != test.expected) {
+			t.Fatalf("\nexpected: %q\ngot: %q (%t)", test.expected, text, changed)
+		}
+	}
+}
+
+func TestUpdateCopyrightYears(t *testing.T) {
+	d := t.TempDir()
+	files := map[string]string{
+		"LICENSE":            "MIT License\r\n\r\nCopyright (c) 2020 Jane Doe\r\n",
+		"main.go":            "// Copyright 2021 Jane Doe\n// SPDX-License-Identifier: MIT\n\npackage main\n\n// Copyright 2021 in a string, not a header\n",
+		"README.md":          "Copyright 2020 Jane Doe\n",
+		"lib/script.py":      "#!/usr/bin/env python\n# Copyright 2024 Jane Doe\n",
+		"lib/current.go":     "// Copyright 2019-2025 Jane Doe\npackage lib\n",
+		"vendor/dep/LICENSE": "Copyright 2010 Someone Else\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(d, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	changed, err := license.UpdateCopyrightYears(d, 2025)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []string{filepath.Join(d, "LICENSE"), filepath.Join(d, "lib", "script.py"), filepath.Join(d, "main.go")}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("\nexpected: %v\ngot: %v", expected, changed)
+	}
+
+	updated := map[string]string{
+		"LICENSE":       "MIT License\r\n\r\nCopyright (c) 2020-2025 Jane Doe\r\n",
+		"main.go":       "// Copyright 2021-2025 Jane Doe\n// SPDX-License-Identifier: MIT\n\npackage main\n\n// Copyright 2021 in a string, not a header\n",
+		"lib/script.py": "#!/usr/bin/env python\n# Copyright 2024-2025 Jane Doe\n",
+	}
+	for name, data := range updated {
+		b, err := ioutil.ReadFile(filepath.Join(d, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(b) != data {
+			t.Fatalf("\nexpected: %q\ngot: %q", data, b)
+		}
+	}
+}
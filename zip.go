@@ -0,0 +1,53 @@
+package license
+
+import (
+	"archive/zip"
+	"strings"
+)
+
+// NewFromZip will open a zip archive on disk, such as a module zip file
+// downloaded from the Go module proxy, and guess the types of the license
+// files found at its root.
+func NewFromZip(path string, opts ...Option) ([]*License, error) {
+	rc, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return NewFromZipReader(&rc.Reader, opts...)
+}
+
+// NewFromZipReader will search the root of a zip archive for well-known and
+// accepted license file names, and guess the types of the licenses found. If
+// every file in the archive is prefixed by a module path and version, as in
+// module zip files, the license files are searched for below that prefix
+// instead. The File of each license is its path within the archive.
+func NewFromZipReader(r *zip.Reader, opts ...Option) ([]*License, error) {
+	o := newOptions(opts)
+	return guessFromFS(o.ctx, r, zipRoot(r), o)
+}
+
+// zipRoot returns the "module@version" prefix shared by all files in a module
+// zip archive, or "." if there is none.
+func zipRoot(r *zip.Reader) string {
+	if len(r.File) == 0 {
+		return "."
+	}
+	name := r.File[0].Name
+	at := strings.Index(name, "@")
+	if at < 0 {
+		return "."
+	}
+	end := strings.Index(name[at:], "/")
+	if end < 0 {
+		return "."
+	}
+	prefix := name[:at+end+1]
+	for _, f := range r.File {
+		if !strings.HasPrefix(f.Name, prefix) {
+			return "."
+		}
+	}
+	return strings.TrimSuffix(prefix, "/")
+}
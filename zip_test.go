@@ -0,0 +1,113 @@
+package license_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	license "github.com/nfukasawa/go-license"
+)
+
+// buildZip creates an in-memory zip archive of the given files, where each
+// value names the fixture to use as the file content.
+func buildZip(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, ltype := range files {
+		licenseText, err := ioutil.ReadFile(filepath.Join("fixtures", "licenses", ltype))
+		if err != nil {
+			licenseText = []byte(ltype)
+		}
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, err := f.Write(licenseText); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewFromZipReader(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"github.com/foo/bar@v1.2.3/LICENSE":         "MIT",
+		"github.com/foo/bar@v1.2.3/main.go":         "package main",
+		"github.com/foo/bar@v1.2.3/sub/LICENSE":     "GPL-2.0",
+		"github.com/foo/bar@v1.2.3/LICENSE.apache2": "Apache-2.0",
+	})
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ls, err := license.NewFromZipReader(r)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 2 {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+	found := make(map[string]string)
+	for _, l := range ls {
+		found[l.File] = l.Type
+	}
+	if found["github.com/foo/bar@v1.2.3/LICENSE"] != license.LicenseMIT {
+		t.Fatalf("unexpected licenses: %v", found)
+	}
+	if found["github.com/foo/bar@v1.2.3/LICENSE.apache2"] != license.LicenseApache20 {
+		t.Fatalf("unexpected licenses: %v", found)
+	}
+
+	// Archives without a module prefix are searched from the root
+	data = buildZip(t, map[string]string{
+		"LICENSE":     "ISC",
+		"src/main.go": "package main",
+	})
+	r, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ls, err = license.NewFromZipReader(r)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseISC || ls[0].File != "LICENSE" {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+}
+
+func TestNewFromZip(t *testing.T) {
+	f, err := ioutil.TempFile("", "go-license")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	data := buildZip(t, map[string]string{
+		"example.com/mod@v0.1.0/COPYING": "GPL-3.0",
+	})
+	if _, err := f.Write(data); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	ls, err := license.NewFromZip(f.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(ls) != 1 || ls[0].Type != license.LicenseGPL30 {
+		t.Fatalf("unexpected licenses: %v", ls)
+	}
+
+	// Fails properly if the archive doesn't exist
+	if _, err := license.NewFromZip("/tmp/go-license-nonexistent.zip"); err == nil {
+		t.Fatalf("expected error loading non-existent archive")
+	}
+}